@@ -100,12 +100,23 @@ func Run() {
 		metricsEP,
 		metricsPath,
 		chop.Config().ClickHouse.Metrics.Timeouts.Collect,
+		chop.Config().ClickHouse.Metrics.Intervals.Collect,
 
 		chiListEP,
 		chiListPath,
+		&chop.Config().Metrics.API,
+		kubeClient,
 	)
 
-	exporter.DiscoveryWatchedCHIs(kubeClient, chopClient)
+	keeperClient := chop.GetKeeperClient(kubeConfigFile, masterURL)
+	if chop.Config().Metrics.API.IsReadOnly() {
+		// The operator does not inform about watched CRs, keep them in sync with informers
+		exporter.WatchCRs(ctx, kubeClient, chopClient, keeperClient)
+	} else {
+		exporter.DiscoveryWatchedCHIs(kubeClient, chopClient)
+		exporter.DiscoveryWatchedCHKs(keeperClient)
+	}
+	go exporter.Run(ctx)
 
 	<-ctx.Done()
 }
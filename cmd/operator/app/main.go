@@ -77,6 +77,7 @@ func Run() {
 	launchClickHouse(ctx, &wg)
 	launchClickHouseReconcilerMetricsExporter(ctx, &wg)
 	launchKeeper(ctx, &wg)
+	launchWebhook(ctx, &wg)
 
 	// Wait for completion
 	<-ctx.Done()
@@ -103,6 +104,12 @@ func launchClickHouseReconcilerMetricsExporter(ctx context.Context, wg *sync.Wai
 
 func launchKeeper(ctx context.Context, wg *sync.WaitGroup) {
 	keeperErr := initKeeper(ctx)
+	if keeperErr == nil {
+		// Backup controllers share manager with keeper
+		if backupErr := initBackup(ctx); backupErr != nil {
+			log.Warning("Init backup skipped due to err: %v", backupErr)
+		}
+	}
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -120,6 +127,21 @@ func launchKeeper(ctx context.Context, wg *sync.WaitGroup) {
 	}()
 }
 
+func launchWebhook(ctx context.Context, wg *sync.WaitGroup) {
+	if err := initWebhook(ctx); err != nil {
+		log.V(1).Info("Starting webhook skipped due to: %v", err)
+		return
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		log.Info("Starting webhook")
+		if err := runWebhook(ctx); err != nil {
+			log.Warning("Webhook FAILED with err: %v", err)
+		}
+	}()
+}
+
 // setupSignalsNotification sets up OS signals
 func setupSignalsNotification(cancel context.CancelFunc) {
 	stopChan := make(chan os.Signal, 2)
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlRuntime "sigs.k8s.io/controller-runtime"
+
+	apiBackup "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-backup.altinity.com/v1"
+	apiChi "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	controller "github.com/altinity/clickhouse-operator/pkg/controller/backup"
+)
+
+// initBackup registers backup and restore controllers within the manager created by initKeeper
+func initBackup(ctx context.Context) error {
+	var err error
+
+	if err = apiBackup.AddToScheme(scheme); err != nil {
+		logger.Error(err, "init backup - unable to apiBackup.AddToScheme")
+		return err
+	}
+	if err = apiChi.AddToScheme(scheme); err != nil {
+		logger.Error(err, "init backup - unable to apiChi.AddToScheme")
+		return err
+	}
+
+	// Backup CRDs are optional, manager would fail to start watching kinds which are not installed
+	gk := schema.GroupKind{Group: apiBackup.SchemeGroupVersion.Group, Kind: apiBackup.ClickHouseBackupCRDResourceKind}
+	if _, err = manager.GetRESTMapper().RESTMapping(gk, apiBackup.SchemeGroupVersion.Version); err != nil {
+		logger.Error(err, "init backup - backup CRDs are not installed")
+		return err
+	}
+
+	err = ctrlRuntime.
+		NewControllerManagedBy(manager).
+		For(&apiBackup.ClickHouseBackup{}).
+		Complete(
+			&controller.BackupController{
+				Client: manager.GetClient(),
+				Scheme: manager.GetScheme(),
+			},
+		)
+	if err != nil {
+		logger.Error(err, "init backup - unable to ctrlRuntime.NewControllerManagedBy for backup")
+		return err
+	}
+
+	err = ctrlRuntime.
+		NewControllerManagedBy(manager).
+		For(&apiBackup.ClickHouseRestore{}).
+		Complete(
+			&controller.RestoreController{
+				Client: manager.GetClient(),
+				Scheme: manager.GetScheme(),
+			},
+		)
+	if err != nil {
+		logger.Error(err, "init backup - unable to ctrlRuntime.NewControllerManagedBy for restore")
+		return err
+	}
+
+	// Initialization successful
+	return nil
+}
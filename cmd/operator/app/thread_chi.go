@@ -87,8 +87,10 @@ func initClickHouse(ctx context.Context) {
 		chopClient,
 		extClient,
 		kubeClient,
+		chop.GetKeeperClient(kubeConfigFile, masterURL),
 		chopInformerFactory,
 		kubeInformerFactory,
+		chopInformerFactoryResyncPeriod,
 	)
 
 	// Start Informers
@@ -13,7 +13,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlManager "sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	//	ctrl "sigs.k8s.io/controller-runtime/pkg/controller"
@@ -57,6 +59,10 @@ func initKeeper(ctx context.Context) error {
 		return err
 	}
 
+	keeperController := &controller.Controller{
+		Client: manager.GetClient(),
+		Scheme: manager.GetScheme(),
+	}
 	err = ctrlRuntime.
 		NewControllerManagedBy(manager).
 		For(
@@ -64,17 +70,18 @@ func initKeeper(ctx context.Context) error {
 			builder.WithPredicates(keeperPredicate()),
 		).
 		Owns(&apps.StatefulSet{}).
-		Complete(
-			&controller.Controller{
-				Client: manager.GetClient(),
-				Scheme: manager.GetScheme(),
-			},
-		)
+		WatchesRawSource(keeperController.MonitorSource(), &handler.EnqueueRequestForObject{}).
+		Complete(keeperController)
 	if err != nil {
 		logger.Error(err, "init keeper - unable to ctrlRuntime.NewControllerManagedBy")
 		return err
 	}
 
+	if err = manager.Add(ctrlManager.RunnableFunc(keeperController.RunMonitor)); err != nil {
+		logger.Error(err, "init keeper - unable to manager.Add monitor")
+		return err
+	}
+
 	// Initialization successful
 	return nil
 }
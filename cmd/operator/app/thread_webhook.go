@@ -0,0 +1,96 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"strconv"
+
+	admissionRegistration "k8s.io/api/admissionregistration/v1"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/webhook"
+)
+
+// Admission webhook defaults
+const (
+	defaultWebhookEndpoint      = ":9443"
+	defaultWebhookService       = "clickhouse-operator-metrics"
+	defaultWebhookFailurePolicy = string(admissionRegistration.Ignore)
+	webhookConfigurationName    = "clickhouse-operator-webhook"
+)
+
+// CLI parameter variables
+var (
+	// webhookEnabled enables admission webhook
+	webhookEnabled bool
+	// webhookEP defines admission webhook end-point IP address
+	webhookEP string
+	// webhookService defines name of the service admission webhook is reachable by
+	webhookService string
+	// webhookFailurePolicy defines how API server treats webhook call failures
+	webhookFailurePolicy string
+)
+
+func init() {
+	flag.BoolVar(&webhookEnabled, "webhook-enabled", false, "Enable validating and defaulting admission webhook.")
+	flag.StringVar(&webhookEP, "webhook-endpoint", defaultWebhookEndpoint, "The admission webhook endpoint.")
+	flag.StringVar(&webhookService, "webhook-service", defaultWebhookService, "The service admission webhook is reachable by.")
+	flag.StringVar(&webhookFailurePolicy, "webhook-failure-policy", defaultWebhookFailurePolicy, "The admission webhook failure policy. One of: Ignore, Fail.")
+}
+
+var webhookServer *webhook.Server
+
+// initWebhook is an entry point of the admission webhook
+func initWebhook(ctx context.Context) error {
+	if !webhookEnabled {
+		return fmt.Errorf("webhook is not enabled")
+	}
+
+	failurePolicy := admissionRegistration.FailurePolicyType(webhookFailurePolicy)
+	switch failurePolicy {
+	case admissionRegistration.Ignore, admissionRegistration.Fail:
+	default:
+		return fmt.Errorf("unknown webhook failure policy: %s", webhookFailurePolicy)
+	}
+
+	_, portStr, err := net.SplitHostPort(webhookEP)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.ParseInt(portStr, 10, 32)
+	if err != nil {
+		return err
+	}
+
+	namespace, _ := chop.GetRuntimeParam(deployment.OPERATOR_POD_NAMESPACE)
+	kubeClient, _, _ := chop.GetClientset(kubeConfigFile, masterURL)
+	registrar := webhook.NewRegistrar(kubeClient, webhookConfigurationName, namespace, webhookService, int32(port), failurePolicy)
+	webhookServer = webhook.NewServer(webhookEP, registrar)
+	return nil
+}
+
+// runWebhook is an entry point of the admission webhook
+func runWebhook(ctx context.Context) error {
+	log.S().P()
+	defer log.E().P()
+
+	return webhookServer.Run(ctx)
+}
@@ -0,0 +1,178 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+// BackupSpec defines spec section of ClickHouseBackup resource
+type BackupSpec struct {
+	// CHI specifies name of the ClickHouseInstallation to be backed up. CHI is looked up in the namespace of the backup
+	CHI *types.String `json:"chi,omitempty"         yaml:"chi,omitempty"`
+	// Cluster limits backup to the specified cluster. All clusters of the CHI are backed up when not specified
+	Cluster *types.String `json:"cluster,omitempty"     yaml:"cluster,omitempty"`
+	// Databases limits backup to the listed databases
+	Databases []string `json:"databases,omitempty"   yaml:"databases,omitempty"`
+	// Tables limits backup to the listed tables in 'database.table' form
+	Tables []string `json:"tables,omitempty"      yaml:"tables,omitempty"`
+	// Destination specifies where backup is stored
+	Destination *Destination `json:"destination,omitempty" yaml:"destination,omitempty"`
+	// Settings specifies additional settings of the BACKUP statement
+	Settings map[string]string `json:"settings,omitempty"    yaml:"settings,omitempty"`
+	// History specifies how many backups of the CHI are kept
+	History *History `json:"history,omitempty"     yaml:"history,omitempty"`
+}
+
+// GetCHI gets CHI name
+func (spec *BackupSpec) GetCHI() *types.String {
+	if spec == nil {
+		return (*types.String)(nil)
+	}
+	return spec.CHI
+}
+
+// GetCluster gets cluster name
+func (spec *BackupSpec) GetCluster() *types.String {
+	if spec == nil {
+		return (*types.String)(nil)
+	}
+	return spec.Cluster
+}
+
+// GetDestination gets destination
+func (spec *BackupSpec) GetDestination() *Destination {
+	if spec == nil {
+		return (*Destination)(nil)
+	}
+	return spec.Destination
+}
+
+// GetHistory gets history
+func (spec *BackupSpec) GetHistory() *History {
+	if spec == nil {
+		return (*History)(nil)
+	}
+	return spec.History
+}
+
+// RestoreSpec defines spec section of ClickHouseRestore resource
+type RestoreSpec struct {
+	// Backup specifies name of the ClickHouseBackup to restore from. Backup is looked up in the namespace of the restore
+	Backup *types.String `json:"backup,omitempty"    yaml:"backup,omitempty"`
+	// CHI specifies name of the ClickHouseInstallation to restore into. Defaults to the CHI of the backup
+	CHI *types.String `json:"chi,omitempty"       yaml:"chi,omitempty"`
+	// Cluster limits restore to the specified cluster. All clusters found in the backup are restored when not specified
+	Cluster *types.String `json:"cluster,omitempty"   yaml:"cluster,omitempty"`
+	// Databases limits restore to the listed databases
+	Databases []string `json:"databases,omitempty" yaml:"databases,omitempty"`
+	// Tables limits restore to the listed tables in 'database.table' form
+	Tables []string `json:"tables,omitempty"    yaml:"tables,omitempty"`
+	// Settings specifies additional settings of the RESTORE statement
+	Settings map[string]string `json:"settings,omitempty"  yaml:"settings,omitempty"`
+}
+
+// GetBackup gets backup name
+func (spec *RestoreSpec) GetBackup() *types.String {
+	if spec == nil {
+		return (*types.String)(nil)
+	}
+	return spec.Backup
+}
+
+// GetCHI gets CHI name
+func (spec *RestoreSpec) GetCHI() *types.String {
+	if spec == nil {
+		return (*types.String)(nil)
+	}
+	return spec.CHI
+}
+
+// GetCluster gets cluster name
+func (spec *RestoreSpec) GetCluster() *types.String {
+	if spec == nil {
+		return (*types.String)(nil)
+	}
+	return spec.Cluster
+}
+
+// Destination specifies backup storage.
+// Exactly one of the storage kinds is expected to be specified.
+type Destination struct {
+	Disk *DiskDestination `json:"disk,omitempty" yaml:"disk,omitempty"`
+	S3   *S3Destination   `json:"s3,omitempty"   yaml:"s3,omitempty"`
+}
+
+// HasDisk checks whether disk destination is specified
+func (d *Destination) HasDisk() bool {
+	if d == nil {
+		return false
+	}
+	return d.Disk != nil
+}
+
+// HasS3 checks whether S3 destination is specified
+func (d *Destination) HasS3() bool {
+	if d == nil {
+		return false
+	}
+	return d.S3 != nil
+}
+
+// DiskDestination specifies backup stored on a ClickHouse disk.
+// The disk has to be listed in backups.allowed_disk server setting.
+type DiskDestination struct {
+	// Name specifies name of the ClickHouse disk
+	Name *types.String `json:"name,omitempty" yaml:"name,omitempty"`
+	// Path specifies path prefix on the disk
+	Path *types.String `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// S3Destination specifies backup stored in an S3-compatible object storage.
+// When no credentials are specified, ClickHouse server-side S3 configuration is used.
+type S3Destination struct {
+	// Endpoint specifies URL of the bucket and path prefix
+	Endpoint *types.String `json:"endpoint,omitempty"        yaml:"endpoint,omitempty"`
+	// AccessKeyID specifies where to take access key id from
+	AccessKeyID *types.DataSource `json:"accessKeyID,omitempty"     yaml:"accessKeyID,omitempty"`
+	// SecretAccessKey specifies where to take secret access key from
+	SecretAccessKey *types.DataSource `json:"secretAccessKey,omitempty" yaml:"secretAccessKey,omitempty"`
+}
+
+// HasCredentials checks whether credentials are specified
+func (s *S3Destination) HasCredentials() bool {
+	if s == nil {
+		return false
+	}
+	return (s.AccessKeyID != nil) && (s.SecretAccessKey != nil)
+}
+
+// History limits number of completed backups of the same CHI, in the same manner
+// as history limits of CronJob limit number of Jobs.
+// Backup data is deleted from the destination along with the ClickHouseBackup resource.
+// ClickHouse is not able to delete backups, so data is deleted by the operator,
+// which requires S3 destination with credentials.
+type History struct {
+	// KeepLast specifies how many latest completed backups of the CHI should be kept
+	KeepLast *types.Int32 `json:"keepLast,omitempty" yaml:"keepLast,omitempty"`
+}
+
+// GetKeepLast gets number of backup resources to keep
+func (r *History) GetKeepLast() *types.Int32 {
+	if r == nil {
+		return (*types.Int32)(nil)
+	}
+	return r.KeepLast
+}
@@ -0,0 +1,222 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/altinity/clickhouse-operator/pkg/version"
+)
+
+// Possible backup/restore statuses
+const (
+	StatusPending    = "Pending"
+	StatusInProgress = "InProgress"
+	StatusCompleted  = "Completed"
+	StatusFailed     = "Failed"
+)
+
+// Possible per-host statuses, as reported by ClickHouse in system.backups
+const (
+	// HostStatusPending is set by the operator, operation is planned but not issued on the host yet
+	HostStatusPending          = "PENDING"
+	HostStatusCreatingBackup   = "CREATING_BACKUP"
+	HostStatusBackupCreated    = "BACKUP_CREATED"
+	HostStatusBackupFailed     = "BACKUP_FAILED"
+	HostStatusBackupCancelled  = "BACKUP_CANCELLED"
+	HostStatusRestoring        = "RESTORING"
+	HostStatusRestored         = "RESTORED"
+	HostStatusRestoreFailed    = "RESTORE_FAILED"
+	HostStatusRestoreCancelled = "RESTORE_CANCELLED"
+)
+
+// Status defines status section of ClickHouseBackup and ClickHouseRestore resources
+type Status struct {
+	CHOpVersion    string        `json:"chop-version,omitempty"   yaml:"chop-version,omitempty"`
+	Status         string        `json:"status,omitempty"         yaml:"status,omitempty"`
+	Error          string        `json:"error,omitempty"          yaml:"error,omitempty"`
+	StartTime      *meta.Time    `json:"startTime,omitempty"      yaml:"startTime,omitempty"`
+	CompletionTime *meta.Time    `json:"completionTime,omitempty" yaml:"completionTime,omitempty"`
+	Hosts          []*HostStatus `json:"hosts,omitempty"          yaml:"hosts,omitempty"`
+}
+
+// HostStatus defines progress of the backup/restore operation on a host
+type HostStatus struct {
+	// Host is FQDN of the host the operation runs on
+	Host string `json:"host,omitempty"      yaml:"host,omitempty"`
+	// Cluster and Shard specify which part of the CHI the host represents
+	Cluster string `json:"cluster,omitempty"   yaml:"cluster,omitempty"`
+	Shard   string `json:"shard,omitempty"     yaml:"shard,omitempty"`
+	// ID is the id of the operation in system.backups
+	ID string `json:"id,omitempty"        yaml:"id,omitempty"`
+	// Location is the path of the shard's backup relative to the destination
+	Location  string `json:"location,omitempty"  yaml:"location,omitempty"`
+	Status    string `json:"status,omitempty"    yaml:"status,omitempty"`
+	Error     string `json:"error,omitempty"     yaml:"error,omitempty"`
+	NumFiles  int64  `json:"numFiles,omitempty"  yaml:"numFiles,omitempty"`
+	TotalSize int64  `json:"totalSize,omitempty" yaml:"totalSize,omitempty"`
+	StartTime string `json:"startTime,omitempty" yaml:"startTime,omitempty"`
+	EndTime   string `json:"endTime,omitempty"   yaml:"endTime,omitempty"`
+}
+
+// IsCompleted checks whether operation on the host completed successfully
+func (h *HostStatus) IsCompleted() bool {
+	if h == nil {
+		return false
+	}
+	switch h.Status {
+	case HostStatusBackupCreated, HostStatusRestored:
+		return true
+	}
+	return false
+}
+
+// IsFailed checks whether operation on the host failed
+func (h *HostStatus) IsFailed() bool {
+	if h == nil {
+		return false
+	}
+	switch h.Status {
+	case HostStatusBackupFailed, HostStatusBackupCancelled, HostStatusRestoreFailed, HostStatusRestoreCancelled:
+		return true
+	}
+	return false
+}
+
+// IsPending checks whether operation is not issued on the host yet
+func (h *HostStatus) IsPending() bool {
+	if h == nil {
+		return false
+	}
+	return h.Status == HostStatusPending
+}
+
+// IsFinished checks whether operation on the host is over, successfully or not
+func (h *HostStatus) IsFinished() bool {
+	return h.IsCompleted() || h.IsFailed()
+}
+
+// Start marks operation start
+func (s *Status) Start() {
+	if s == nil {
+		return
+	}
+	now := meta.Now()
+	s.CHOpVersion = version.Version
+	s.Status = StatusInProgress
+	s.Error = ""
+	s.StartTime = &now
+	s.CompletionTime = nil
+	s.Hosts = nil
+}
+
+// Fail marks operation as failed with specified error
+func (s *Status) Fail(err string) {
+	if s == nil {
+		return
+	}
+	now := meta.Now()
+	s.Status = StatusFailed
+	s.Error = err
+	s.CompletionTime = &now
+}
+
+// IsStarted checks whether operation has been started already
+func (s *Status) IsStarted() bool {
+	if s == nil {
+		return false
+	}
+	return s.Status != "" && s.Status != StatusPending
+}
+
+// IsFinished checks whether operation is over, successfully or not
+func (s *Status) IsFinished() bool {
+	if s == nil {
+		return false
+	}
+	return s.Status == StatusCompleted || s.Status == StatusFailed
+}
+
+// IsCompleted checks whether operation completed successfully
+func (s *Status) IsCompleted() bool {
+	if s == nil {
+		return false
+	}
+	return s.Status == StatusCompleted
+}
+
+// PushHost appends host to the list of hosts the operation runs on
+func (s *Status) PushHost(host *HostStatus) {
+	if s == nil {
+		return
+	}
+	s.Hosts = append(s.Hosts, host)
+}
+
+// HasPendingHosts checks whether operation is not issued on some hosts yet
+func (s *Status) HasPendingHosts() bool {
+	if s == nil {
+		return false
+	}
+	for _, host := range s.Hosts {
+		if host.IsPending() {
+			return true
+		}
+	}
+	return false
+}
+
+// FindHost finds host status by cluster and shard names
+func (s *Status) FindHost(cluster, shard string) *HostStatus {
+	if s == nil {
+		return nil
+	}
+	for _, host := range s.Hosts {
+		if (host.Cluster == cluster) && (host.Shard == shard) {
+			return host
+		}
+	}
+	return nil
+}
+
+// Aggregate computes the whole operation status out of per-host statuses
+func (s *Status) Aggregate() {
+	if s == nil {
+		return
+	}
+	failed := 0
+	for _, host := range s.Hosts {
+		if !host.IsFinished() {
+			// At least one host is still in progress
+			s.Status = StatusInProgress
+			return
+		}
+		if host.IsFailed() {
+			failed++
+		}
+	}
+
+	// All hosts are finished
+	now := meta.Now()
+	s.CompletionTime = &now
+	if failed > 0 {
+		s.Status = StatusFailed
+		if s.Error == "" {
+			s.Error = "operation failed on some hosts"
+		}
+	} else {
+		s.Status = StatusCompleted
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClickHouseBackup defines a backup of a ClickHouseInstallation.
+// Each shard of the installation is backed up from one of its replicas into the specified destination.
+type ClickHouseBackup struct {
+	meta.TypeMeta   `json:",inline"            yaml:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Spec   BackupSpec `json:"spec"             yaml:"spec"`
+	Status *Status    `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClickHouseBackupList defines a list of ClickHouseBackup resources
+type ClickHouseBackupList struct {
+	meta.TypeMeta `json:",inline"  yaml:",inline"`
+	meta.ListMeta `json:"metadata" yaml:"metadata"`
+	Items         []ClickHouseBackup `json:"items" yaml:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClickHouseRestore defines a restore of a ClickHouseBackup into a ClickHouseInstallation.
+// Each shard of the installation is restored on one of its replicas from the backup of the shard with the same name.
+type ClickHouseRestore struct {
+	meta.TypeMeta   `json:",inline"            yaml:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Spec   RestoreSpec `json:"spec"             yaml:"spec"`
+	Status *Status     `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClickHouseRestoreList defines a list of ClickHouseRestore resources
+type ClickHouseRestoreList struct {
+	meta.TypeMeta `json:",inline"  yaml:",inline"`
+	meta.ListMeta `json:"metadata" yaml:"metadata"`
+	Items         []ClickHouseRestore `json:"items" yaml:"items"`
+}
+
+// EnsureStatus ensures status
+func (b *ClickHouseBackup) EnsureStatus() *Status {
+	if b == nil {
+		return nil
+	}
+	if b.Status == nil {
+		b.Status = &Status{}
+	}
+	return b.Status
+}
+
+// GetStatusT gets status
+func (b *ClickHouseBackup) GetStatusT() *Status {
+	if b == nil {
+		return nil
+	}
+	return b.Status
+}
+
+// EnsureStatus ensures status
+func (r *ClickHouseRestore) EnsureStatus() *Status {
+	if r == nil {
+		return nil
+	}
+	if r.Status == nil {
+		r.Status = &Status{}
+	}
+	return r.Status
+}
+
+// GetStatusT gets status
+func (r *ClickHouseRestore) GetStatusT() *Status {
+	if r == nil {
+		return nil
+	}
+	return r.Status
+}
@@ -23,6 +23,7 @@ type Configuration struct {
 	Settings *apiChi.Settings `json:"settings,omitempty"  yaml:"settings,omitempty"`
 	Files    *apiChi.Settings `json:"files,omitempty"     yaml:"files,omitempty"`
 	Clusters []*Cluster       `json:"clusters,omitempty"  yaml:"clusters,omitempty"`
+	TLS      *apiChi.TLS      `json:"tls,omitempty"       yaml:"tls,omitempty"`
 }
 
 // NewConfiguration creates new ChkConfiguration objects
@@ -63,6 +64,13 @@ func (c *Configuration) GetFiles() *apiChi.Settings {
 	return c.Files
 }
 
+func (c *Configuration) GetTLS() *apiChi.TLS {
+	if c == nil {
+		return nil
+	}
+	return c.TLS
+}
+
 // MergeFrom merges from specified source
 func (c *Configuration) MergeFrom(from *Configuration, _type apiChi.MergeType) *Configuration {
 	if from == nil {
@@ -73,6 +81,7 @@ func (c *Configuration) MergeFrom(from *Configuration, _type apiChi.MergeType) *
 
 	c.Settings = c.Settings.MergeFrom(from.Settings)
 	c.Files = c.Files.MergeFrom(from.Files)
+	c.TLS = c.TLS.MergeFrom(from.TLS)
 
 	// TODO merge clusters
 	// Copy Clusters for now
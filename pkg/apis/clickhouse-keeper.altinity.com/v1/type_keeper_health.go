@@ -0,0 +1,50 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// KeeperHealth describes health of a Keeper host as reported by four-letter-word commands
+type KeeperHealth struct {
+	Host                string `json:"host,omitempty"                yaml:"host,omitempty"`
+	Healthy             bool   `json:"healthy"                       yaml:"healthy"`
+	Role                string `json:"role,omitempty"                yaml:"role,omitempty"`
+	Zxid                int64  `json:"zxid,omitempty"                yaml:"zxid,omitempty"`
+	ZxidLag             int64  `json:"zxidLag,omitempty"             yaml:"zxidLag,omitempty"`
+	OutstandingRequests int64  `json:"outstandingRequests,omitempty" yaml:"outstandingRequests,omitempty"`
+	EphemeralsCount     int64  `json:"ephemeralsCount,omitempty"     yaml:"ephemeralsCount,omitempty"`
+	Error               string `json:"error,omitempty"               yaml:"error,omitempty"`
+	// RestartHeldBack specifies restart of the host is held back, as the host is an unhealthy follower
+	RestartHeldBack bool `json:"restartHeldBack,omitempty" yaml:"restartHeldBack,omitempty"`
+}
+
+// KeeperRoleLeader, KeeperRoleFollower specify roles of the Keeper host in the ensemble
+const (
+	KeeperRoleLeader   = "leader"
+	KeeperRoleFollower = "follower"
+)
+
+// IsFollower checks whether the host is a follower of the ensemble
+func (h *KeeperHealth) IsFollower() bool {
+	return (h != nil) && (h.Role == KeeperRoleFollower)
+}
+
+// IsUnhealthyFollower checks whether the host is a follower, which does not keep up with the leader
+func (h *KeeperHealth) IsUnhealthyFollower() bool {
+	return h.IsFollower() && !h.Healthy
+}
+
+// IsRestartHeldBack checks whether restart of the host is held back
+func (h *KeeperHealth) IsRestartHeldBack() bool {
+	return (h != nil) && h.RestartHeldBack
+}
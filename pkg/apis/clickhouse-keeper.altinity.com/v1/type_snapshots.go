@@ -0,0 +1,172 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"strings"
+	"time"
+
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/util/cron"
+)
+
+// Snapshots specifies how Keeper snapshots are copied from the coordination directory of the Keeper hosts
+// into a storage and how a new CR is bootstrapped from a stored snapshot
+type Snapshots struct {
+	// Schedule is a cron expression specifying when snapshot of each cluster is taken, such as "0 */6 * * *".
+	// Snapshots are not taken in case schedule is not specified
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	// Image specifies image of the containers copying snapshots. The image has to provide shell
+	// and, for S3 storage, AWS CLI
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+	// Storage specifies where snapshots are stored
+	Storage *SnapshotStorage `json:"storage,omitempty" yaml:"storage,omitempty"`
+	// Restore specifies snapshot the Keeper hosts are bootstrapped from
+	Restore *SnapshotRestore `json:"restore,omitempty" yaml:"restore,omitempty"`
+}
+
+// DefaultSnapshotImage specifies image of the containers copying snapshots, used in case no image is specified
+const DefaultSnapshotImage = "amazon/aws-cli:2.15.0"
+
+// GetSchedule parses schedule of the snapshots
+func (s *Snapshots) GetSchedule() (*cron.Schedule, error) {
+	return cron.Parse(s.Schedule)
+}
+
+// HasSchedule checks whether snapshots are scheduled
+func (s *Snapshots) HasSchedule() bool {
+	if s == nil {
+		return false
+	}
+	return (s.Schedule != "") && s.Storage.IsValid()
+}
+
+// HasRestore checks whether hosts are to be bootstrapped from a snapshot
+func (s *Snapshots) HasRestore() bool {
+	if s == nil {
+		return false
+	}
+	return s.Restore.HasSnapshot() && s.Storage.IsValid()
+}
+
+// GetImage gets image of the containers copying snapshots
+func (s *Snapshots) GetImage() string {
+	if (s == nil) || (s.Image == "") {
+		return DefaultSnapshotImage
+	}
+	return s.Image
+}
+
+// IsDue checks whether snapshot of the cluster is due at the specified time,
+// considering the last time snapshot of the cluster was attempted.
+// Malformed schedule is never due
+func (s *Snapshots) IsDue(last, now time.Time) bool {
+	if !s.HasSchedule() {
+		return false
+	}
+	schedule, err := s.GetSchedule()
+	if err != nil {
+		return false
+	}
+	next := schedule.Next(last)
+	return !next.IsZero() && !next.After(now)
+}
+
+// SnapshotStorage specifies snapshots storage.
+// Exactly one of the storage kinds is expected to be specified.
+// Snapshots are stored under <namespace>/<name>/<cluster>/<time> path of the storage
+type SnapshotStorage struct {
+	S3  *SnapshotS3Storage  `json:"s3,omitempty"  yaml:"s3,omitempty"`
+	PVC *SnapshotPVCStorage `json:"pvc,omitempty" yaml:"pvc,omitempty"`
+}
+
+// IsValid checks whether exactly one of the storage kinds is specified
+func (s *SnapshotStorage) IsValid() bool {
+	if s == nil {
+		return false
+	}
+	return (s.S3 != nil) != (s.PVC != nil)
+}
+
+// SnapshotS3Storage specifies snapshots stored in an S3-compatible object storage
+type SnapshotS3Storage struct {
+	// URL specifies bucket and path prefix, such as s3://bucket/keeper
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+	// EndpointURL specifies endpoint of an S3-compatible storage, AWS S3 is used by default
+	EndpointURL string `json:"endpointURL,omitempty" yaml:"endpointURL,omitempty"`
+	// Region specifies region of the bucket
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+	// AccessKeyID specifies where to take access key id from
+	AccessKeyID *types.DataSource `json:"accessKeyID,omitempty" yaml:"accessKeyID,omitempty"`
+	// SecretAccessKey specifies where to take secret access key from
+	SecretAccessKey *types.DataSource `json:"secretAccessKey,omitempty" yaml:"secretAccessKey,omitempty"`
+}
+
+// GetURL gets bucket URL without trailing slash
+func (s *SnapshotS3Storage) GetURL() string {
+	return strings.TrimSuffix(s.URL, "/")
+}
+
+// SnapshotPVCStorage specifies snapshots stored on a PersistentVolumeClaim.
+// The claim has to be mountable by all Keeper hosts of the CR, such as ReadWriteMany claim
+type SnapshotPVCStorage struct {
+	// ClaimName specifies name of the PersistentVolumeClaim in the namespace of the CR
+	ClaimName string `json:"claimName,omitempty" yaml:"claimName,omitempty"`
+	// Path specifies path prefix on the volume
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// SnapshotRestore specifies snapshot the Keeper hosts are bootstrapped from.
+// Snapshot is restored into hosts with empty snapshots directory only, so running hosts are not affected
+type SnapshotRestore struct {
+	// Snapshot specifies path of the snapshot in the storage, as reported in status.snapshots
+	Snapshot string `json:"snapshot,omitempty" yaml:"snapshot,omitempty"`
+}
+
+// HasSnapshot checks whether snapshot to restore is specified
+func (r *SnapshotRestore) HasSnapshot() bool {
+	if r == nil {
+		return false
+	}
+	return r.Snapshot != ""
+}
+
+// KeeperSnapshot describes the last snapshot of a cluster
+type KeeperSnapshot struct {
+	// Cluster specifies name of the cluster
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	// Snapshot specifies path of the last successful snapshot in the storage
+	Snapshot string `json:"snapshot,omitempty" yaml:"snapshot,omitempty"`
+	// Time specifies when the last successful snapshot was taken
+	Time string `json:"time,omitempty" yaml:"time,omitempty"`
+	// Error specifies error of the last snapshot attempt, in case the attempt has failed
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+	// ErrorTime specifies when the last snapshot attempt has failed
+	ErrorTime string `json:"errorTime,omitempty" yaml:"errorTime,omitempty"`
+}
+
+// LastAttempt gets time of the last snapshot attempt, successful or not
+func (s *KeeperSnapshot) LastAttempt() time.Time {
+	var last time.Time
+	if s == nil {
+		return last
+	}
+	for _, str := range []string{s.Time, s.ErrorTime} {
+		if t, err := time.Parse(time.RFC3339, str); (err == nil) && t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
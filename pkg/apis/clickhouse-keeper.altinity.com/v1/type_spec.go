@@ -30,6 +30,7 @@ type ChkSpec struct {
 	Defaults               *apiChi.Defaults     `json:"defaults,omitempty"               yaml:"defaults,omitempty"`
 	Configuration          *Configuration       `json:"configuration,omitempty"          yaml:"configuration,omitempty"`
 	Templates              *apiChi.Templates    `json:"templates,omitempty"              yaml:"templates,omitempty"`
+	Snapshots              *Snapshots           `json:"snapshots,omitempty"              yaml:"snapshots,omitempty"`
 }
 
 // HasTaskID checks whether task id is specified
@@ -83,6 +84,14 @@ func (spec *ChkSpec) GetTemplates() *apiChi.Templates {
 	return spec.Templates
 }
 
+// GetSnapshots gets snapshots section
+func (spec *ChkSpec) GetSnapshots() *Snapshots {
+	if spec == nil {
+		return (*Snapshots)(nil)
+	}
+	return spec.Snapshots
+}
+
 // MergeFrom merges from spec
 func (spec *ChkSpec) MergeFrom(from *ChkSpec, _type apiChi.MergeType) {
 	if from == nil {
@@ -127,4 +136,7 @@ func (spec *ChkSpec) MergeFrom(from *ChkSpec, _type apiChi.MergeType) {
 	spec.Defaults = spec.Defaults.MergeFrom(from.Defaults, _type)
 	spec.Configuration = spec.Configuration.MergeFrom(from.Configuration, _type)
 	spec.Templates = spec.Templates.MergeFrom(from.Templates, _type)
+	if (spec.Snapshots == nil) || ((_type == apiChi.MergeTypeOverrideByNonEmptyValues) && (from.Snapshots != nil)) {
+		spec.Snapshots = from.Snapshots.DeepCopy()
+	}
 }
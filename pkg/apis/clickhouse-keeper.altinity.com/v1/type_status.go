@@ -18,6 +18,9 @@ import (
 	"sort"
 	"sync"
 
+	apiMeta "k8s.io/apimachinery/pkg/api/meta"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	chi "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
 	"github.com/altinity/clickhouse-operator/pkg/util"
@@ -79,6 +82,9 @@ type Status struct {
 	HostsWithTablesCreated   []string                      `json:"hostsWithTablesCreated,omitempty"   yaml:"hostsWithTablesCreated,omitempty"`
 	HostsWithReplicaCaughtUp []string                      `json:"hostsWithReplicaCaughtUp,omitempty" yaml:"hostsWithReplicaCaughtUp,omitempty"`
 	UsedTemplates            []*chi.TemplateRef            `json:"usedTemplates,omitempty"            yaml:"usedTemplates,omitempty"`
+	Conditions               []meta.Condition              `json:"conditions,omitempty"               yaml:"conditions,omitempty"`
+	Keepers                  []*KeeperHealth               `json:"keepers,omitempty"                  yaml:"keepers,omitempty"`
+	Snapshots                []*KeeperSnapshot             `json:"snapshots,omitempty"                yaml:"snapshots,omitempty"`
 
 	mu sync.RWMutex `json:"-" yaml:"-"`
 }
@@ -207,6 +213,33 @@ func (s *Status) GetUsedTemplatesCount() int {
 	})
 }
 
+// SetCondition sets condition of the specified type. Transition time is updated only in case condition status changes
+func (s *Status) SetCondition(condition meta.Condition) {
+	doWithWriteLock(s, func(s *Status) {
+		apiMeta.SetStatusCondition(&s.Conditions, condition)
+	})
+}
+
+// SetKeepers sets health of the Keeper hosts
+func (s *Status) SetKeepers(keepers []*KeeperHealth) {
+	doWithWriteLock(s, func(s *Status) {
+		s.Keepers = keepers
+	})
+}
+
+// SetSnapshot sets the last snapshot of the cluster
+func (s *Status) SetSnapshot(snapshot *KeeperSnapshot) {
+	doWithWriteLock(s, func(s *Status) {
+		for i := range s.Snapshots {
+			if s.Snapshots[i].Cluster == snapshot.Cluster {
+				s.Snapshots[i] = snapshot
+				return
+			}
+		}
+		s.Snapshots = append(s.Snapshots, snapshot)
+	})
+}
+
 // SetAction action setter
 func (s *Status) SetAction(action string) {
 	doWithWriteLock(s, func(s *Status) {
@@ -354,6 +387,9 @@ func prepareOptions(opts types.CopyStatusOptions) types.CopyStatusOptions {
 		opts.Copy.Actions = true
 		opts.Copy.Errors = true
 		opts.Copy.UsedTemplates = true
+		opts.Copy.Conditions = true
+		opts.Copy.Keepers = true
+		opts.Copy.Snapshots = true
 	}
 
 	if opts.FieldGroupActions {
@@ -397,6 +433,9 @@ func prepareOptions(opts types.CopyStatusOptions) types.CopyStatusOptions {
 		opts.Copy.NormalizedCR = true
 		opts.Copy.ActionPlan = true
 		opts.Copy.UsedTemplates = true
+		opts.Copy.Conditions = true
+		opts.Copy.Keepers = true
+		opts.Copy.Snapshots = true
 	}
 
 	if opts.FieldGroupNormalized {
@@ -435,6 +474,9 @@ func prepareOptions(opts types.CopyStatusOptions) types.CopyStatusOptions {
 		opts.Copy.NormalizedCRCompleted = true
 		opts.Copy.ActionPlan = true
 		opts.Copy.UsedTemplates = true
+		opts.Copy.Conditions = true
+		opts.Copy.Keepers = true
+		opts.Copy.Snapshots = true
 	}
 
 	return opts
@@ -559,6 +601,24 @@ func (s *Status) CopyFrom(f *Status, opts types.CopyStatusOptions) {
 					s.UsedTemplates = append(s.UsedTemplates, from.UsedTemplates...)
 				}
 			}
+			if opts.Copy.Conditions {
+				// Merge conditions in order to keep transition time of the conditions not changed
+				for _, condition := range from.Conditions {
+					apiMeta.SetStatusCondition(&s.Conditions, *condition.DeepCopy())
+				}
+			}
+			if opts.Copy.Keepers {
+				s.Keepers = nil
+				for _, keeper := range from.Keepers {
+					s.Keepers = append(s.Keepers, keeper.DeepCopy())
+				}
+			}
+			if opts.Copy.Snapshots {
+				s.Snapshots = nil
+				for _, snapshot := range from.Snapshots {
+					s.Snapshots = append(s.Snapshots, snapshot.DeepCopy())
+				}
+			}
 		})
 	})
 }
@@ -795,6 +855,66 @@ func (s *Status) GetHostsWithReplicaCaughtUp() []string {
 	})
 }
 
+// GetCondition gets copy of the condition of the specified type
+func (s *Status) GetCondition(conditionType string) *meta.Condition {
+	var condition *meta.Condition
+	doWithReadLock(s, func(s *Status) {
+		if c := apiMeta.FindStatusCondition(s.Conditions, conditionType); c != nil {
+			condition = c.DeepCopy()
+		}
+	})
+	return condition
+}
+
+// GetKeeper gets copy of the health of the specified Keeper host
+func (s *Status) GetKeeper(host string) *KeeperHealth {
+	var keeper *KeeperHealth
+	doWithReadLock(s, func(s *Status) {
+		for _, k := range s.Keepers {
+			if k.Host == host {
+				keeper = k.DeepCopy()
+			}
+		}
+	})
+	return keeper
+}
+
+// GetKeepers gets copy of the health of the Keeper hosts
+func (s *Status) GetKeepers() (keepers []*KeeperHealth) {
+	doWithReadLock(s, func(s *Status) {
+		for _, k := range s.Keepers {
+			keepers = append(keepers, k.DeepCopy())
+		}
+	})
+	return keepers
+}
+
+// HasRestartHeldBack checks whether restart of any Keeper host is held back
+func (s *Status) HasRestartHeldBack() bool {
+	found := false
+	doWithReadLock(s, func(s *Status) {
+		for _, k := range s.Keepers {
+			if k.IsRestartHeldBack() {
+				found = true
+			}
+		}
+	})
+	return found
+}
+
+// GetSnapshot gets copy of the last snapshot of the specified cluster
+func (s *Status) GetSnapshot(cluster string) *KeeperSnapshot {
+	var snapshot *KeeperSnapshot
+	doWithReadLock(s, func(s *Status) {
+		for _, sn := range s.Snapshots {
+			if sn.Cluster == cluster {
+				snapshot = sn.DeepCopy()
+			}
+		}
+	})
+	return snapshot
+}
+
 // Begin helpers
 
 func doWithWriteLock(s *Status, f func(*Status)) {
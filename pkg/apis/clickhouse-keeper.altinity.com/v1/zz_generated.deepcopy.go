@@ -24,6 +24,7 @@ package v1
 import (
 	clickhousealtinitycomv1 "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	types "github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -328,6 +329,11 @@ func (in *ChkSpec) DeepCopyInto(out *ChkSpec) {
 		*out = new(clickhousealtinitycomv1.Templates)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = new(Snapshots)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -519,6 +525,11 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 			}
 		}
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(clickhousealtinitycomv1.TLS)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -568,6 +579,146 @@ func (in *FillStatusParams) DeepCopy() *FillStatusParams {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeeperHealth) DeepCopyInto(out *KeeperHealth) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeeperHealth.
+func (in *KeeperHealth) DeepCopy() *KeeperHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(KeeperHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeeperSnapshot) DeepCopyInto(out *KeeperSnapshot) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeeperSnapshot.
+func (in *KeeperSnapshot) DeepCopy() *KeeperSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(KeeperSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotPVCStorage) DeepCopyInto(out *SnapshotPVCStorage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotPVCStorage.
+func (in *SnapshotPVCStorage) DeepCopy() *SnapshotPVCStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotPVCStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRestore) DeepCopyInto(out *SnapshotRestore) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRestore.
+func (in *SnapshotRestore) DeepCopy() *SnapshotRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotS3Storage) DeepCopyInto(out *SnapshotS3Storage) {
+	*out = *in
+	if in.AccessKeyID != nil {
+		in, out := &in.AccessKeyID, &out.AccessKeyID
+		*out = (*in).DeepCopy()
+	}
+	if in.SecretAccessKey != nil {
+		in, out := &in.SecretAccessKey, &out.SecretAccessKey
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotS3Storage.
+func (in *SnapshotS3Storage) DeepCopy() *SnapshotS3Storage {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotS3Storage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotStorage) DeepCopyInto(out *SnapshotStorage) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(SnapshotS3Storage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(SnapshotPVCStorage)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotStorage.
+func (in *SnapshotStorage) DeepCopy() *SnapshotStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Snapshots) DeepCopyInto(out *Snapshots) {
+	*out = *in
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(SnapshotStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(SnapshotRestore)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Snapshots.
+func (in *Snapshots) DeepCopy() *Snapshots {
+	if in == nil {
+		return nil
+	}
+	out := new(Snapshots)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Status) DeepCopyInto(out *Status) {
 	*out = *in
@@ -647,6 +798,35 @@ func (in *Status) DeepCopyInto(out *Status) {
 			}
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Keepers != nil {
+		in, out := &in.Keepers, &out.Keepers
+		*out = make([]*KeeperHealth, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(KeeperHealth)
+				**out = **in
+			}
+		}
+	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = make([]*KeeperSnapshot, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(KeeperSnapshot)
+				**out = **in
+			}
+		}
+	}
 	out.mu = in.mu
 	return
 }
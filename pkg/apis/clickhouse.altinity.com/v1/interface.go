@@ -79,6 +79,7 @@ type IConfiguration interface {
 	GetQuotas() *Settings
 	GetSettings() *Settings
 	GetFiles() *Settings
+	GetTLS() *TLS
 }
 
 type ICustomResourceRuntime interface {
@@ -107,6 +108,9 @@ type IStatus interface {
 	HostAdded()
 	HostFailed()
 	HostCompleted()
+
+	SetCondition(condition meta.Condition)
+	GetCondition(conditionType string) *meta.Condition
 }
 
 type ICluster interface {
@@ -0,0 +1,157 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+// Possible autoscaling metric types
+const (
+	// AutoscalingMetricConcurrentQueries is a number of queries being executed, metric.Query
+	AutoscalingMetricConcurrentQueries = "concurrentQueries"
+	// AutoscalingMetricCPU is a CPU utilization ratio normalized by the number of cores, metric.OSUserTimeNormalized
+	AutoscalingMetricCPU = "cpu"
+	// AutoscalingMetricQueryQueue is a number of queries waiting due to priority, metric.QueryPreempted
+	AutoscalingMetricQueryQueue = "queryQueue"
+	// AutoscalingMetricCustom is any metric reported by the metrics exporter, specified by name
+	AutoscalingMetricCustom = "custom"
+)
+
+const (
+	defaultAutoscalingMinReplicas = 1
+	defaultAutoscalingCooldown    = 5 * time.Minute
+	// autoscalingHistoryLength is a max number of scaling decisions kept in the status
+	autoscalingHistoryLength = 20
+)
+
+// ClusterAutoscaling defines how number of replicas of the cluster is adjusted to the load
+type ClusterAutoscaling struct {
+	// Enabled turns on autoscaling of the cluster's replicasCount
+	Enabled *types.StringBool `json:"enabled,omitempty"     yaml:"enabled,omitempty"`
+	// MinReplicas specifies min number of replicas in each shard
+	MinReplicas *types.Int32 `json:"minReplicas,omitempty" yaml:"minReplicas,omitempty"`
+	// MaxReplicas specifies max number of replicas in each shard
+	MaxReplicas *types.Int32 `json:"maxReplicas,omitempty" yaml:"maxReplicas,omitempty"`
+	// Cooldown specifies min duration between two scaling decisions, such as 5m
+	Cooldown string `json:"cooldown,omitempty"    yaml:"cooldown,omitempty"`
+	// Metrics specifies metrics the cluster is scaled by. The largest number of replicas is used
+	Metrics []AutoscalingMetric `json:"metrics,omitempty"     yaml:"metrics,omitempty"`
+}
+
+// IsEnabled checks whether autoscaling is enabled
+func (a *ClusterAutoscaling) IsEnabled() bool {
+	if a == nil {
+		return false
+	}
+	return a.Enabled.Value()
+}
+
+// GetMinReplicas gets min number of replicas
+func (a *ClusterAutoscaling) GetMinReplicas() int {
+	if (a == nil) || !a.MinReplicas.HasValue() || (a.MinReplicas.IntValue() < 1) {
+		return defaultAutoscalingMinReplicas
+	}
+	return a.MinReplicas.IntValue()
+}
+
+// GetMaxReplicas gets max number of replicas
+func (a *ClusterAutoscaling) GetMaxReplicas() int {
+	if (a == nil) || !a.MaxReplicas.HasValue() || (a.MaxReplicas.IntValue() < a.GetMinReplicas()) {
+		return a.GetMinReplicas()
+	}
+	return a.MaxReplicas.IntValue()
+}
+
+// GetCooldown gets min duration between two scaling decisions
+func (a *ClusterAutoscaling) GetCooldown() time.Duration {
+	if a == nil {
+		return defaultAutoscalingCooldown
+	}
+	cooldown, err := time.ParseDuration(a.Cooldown)
+	if err != nil || cooldown < 0 {
+		return defaultAutoscalingCooldown
+	}
+	return cooldown
+}
+
+// GetMetrics gets metrics
+func (a *ClusterAutoscaling) GetMetrics() []AutoscalingMetric {
+	if a == nil {
+		return nil
+	}
+	return a.Metrics
+}
+
+// AutoscalingMetric defines metric the cluster is scaled by
+type AutoscalingMetric struct {
+	// Type specifies what metric is used
+	Type string `json:"type,omitempty"   yaml:"type,omitempty"`
+	// Name specifies metric name as reported by the metrics exporter, such as metric.QueryThread.
+	// Used by the custom type only
+	Name string `json:"name,omitempty"   yaml:"name,omitempty"`
+	// Target specifies desired average value of the metric per replica
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+// GetName gets name of the metric as reported by the metrics exporter
+func (m *AutoscalingMetric) GetName() string {
+	switch m.Type {
+	case AutoscalingMetricConcurrentQueries:
+		return "metric.Query"
+	case AutoscalingMetricCPU:
+		return "metric.OSUserTimeNormalized"
+	case AutoscalingMetricQueryQueue:
+		return "metric.QueryPreempted"
+	case AutoscalingMetricCustom:
+		return m.Name
+	}
+	return ""
+}
+
+// GetTarget gets target value of the metric. Returns 0 in case target is not a positive number
+func (m *AutoscalingMetric) GetTarget() float64 {
+	target, err := strconv.ParseFloat(m.Target, 64)
+	if err != nil || target <= 0 {
+		return 0
+	}
+	return target
+}
+
+// AutoscalingEvent describes scaling decision made by the operator
+type AutoscalingEvent struct {
+	// Time of the decision in RFC3339 format
+	Time string `json:"time,omitempty"    yaml:"time,omitempty"`
+	// Cluster which is scaled
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	// From specifies number of replicas before scaling
+	From int `json:"from,omitempty"    yaml:"from,omitempty"`
+	// To specifies number of replicas after scaling
+	To int `json:"to,omitempty"      yaml:"to,omitempty"`
+	// Reason describes metric values which led to the decision
+	Reason string `json:"reason,omitempty"  yaml:"reason,omitempty"`
+}
+
+// GetTime gets time of the decision
+func (e *AutoscalingEvent) GetTime() time.Time {
+	if e == nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339, e.Time)
+	return t
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"strings"
+
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+// Possible ways to seed data of a clone
+const (
+	// CloneDataRemote copies data of MergeTree tables from the source hosts with remote() table function.
+	// Tables are copied one by one, thus writes into the source have to be stopped. Copy of a shard,
+	// which source takes writes meanwhile, is failed and retried
+	CloneDataRemote = "remote"
+	// CloneDataNone replays schema only, tables of the clone are empty
+	CloneDataNone = "none"
+)
+
+// Possible clone statuses
+const (
+	CloneStatusInProgress = "InProgress"
+	CloneStatusCompleted  = "Completed"
+	CloneStatusFailed     = "Failed"
+)
+
+// CloneZookeeperRootPrefix is appended to the zookeeper root of the source in order to build zookeeper root of the clone
+const CloneZookeeperRootPrefix = "/clones"
+
+// Clone defines a source CHI this CHI is a copy of.
+// Topology is copied from the source when the CHI does not specify clusters of its own,
+// schema is replayed from the source hosts and data is seeded as specified by Data.
+// Shard is seeded only with data which matches a point in time of the source shard
+type Clone struct {
+	// Source refers to the CHI to be cloned
+	Source *CloneSource `json:"source,omitempty"   yaml:"source,omitempty"`
+	// Data specifies how data is seeded, one of remote or none
+	Data string `json:"data,omitempty"     yaml:"data,omitempty"`
+	// User is a user of the source, which is used by the clone hosts in order to read data from the source hosts
+	User string `json:"user,omitempty"     yaml:"user,omitempty"`
+	// Password of the user
+	Password *types.DataSource `json:"password,omitempty" yaml:"password,omitempty"`
+}
+
+// CloneSource refers to a CHI
+type CloneSource struct {
+	Name      string `json:"name,omitempty"      yaml:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+// HasSource checks whether clone refers to a source CHI
+func (c *Clone) HasSource() bool {
+	if c == nil {
+		return false
+	}
+	return (c.Source != nil) && (c.Source.Name != "")
+}
+
+// GetSourceNamespace gets namespace of the source, which defaults to the namespace of the clone
+func (c *Clone) GetSourceNamespace(namespace string) string {
+	if c.HasSource() && (c.Source.Namespace != "") {
+		return c.Source.Namespace
+	}
+	return namespace
+}
+
+// IsDataCopied checks whether data has to be copied from the source
+func (c *Clone) IsDataCopied() bool {
+	if !c.HasSource() {
+		return false
+	}
+	return c.Data != CloneDataNone
+}
+
+// GetZookeeperRoot builds zookeeper root of the clone out of zookeeper root of the source,
+// so replication paths of the clone never collide with replication paths of the source
+func (c *Clone) GetZookeeperRoot(sourceRoot, namespace, name string) string {
+	return strings.TrimRight(sourceRoot, "/") + CloneZookeeperRootPrefix + "/" + namespace + "/" + name
+}
+
+// CloneStatus defines progress of cloning of a cluster
+type CloneStatus struct {
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	// Source is namespace/name of the source CHI
+	Source string `json:"source,omitempty"  yaml:"source,omitempty"`
+	Status string `json:"status,omitempty"  yaml:"status,omitempty"`
+	// Tables lists tables, which data is copied already, as database.table
+	Tables []string `json:"tables,omitempty"  yaml:"tables,omitempty"`
+	Error  string   `json:"error,omitempty"   yaml:"error,omitempty"`
+	Time   string   `json:"time,omitempty"    yaml:"time,omitempty"`
+}
+
+// IsCompleted checks whether cloning of the cluster is completed
+func (s *CloneStatus) IsCompleted() bool {
+	if s == nil {
+		return false
+	}
+	return s.Status == CloneStatusCompleted
+}
+
+// HasTable checks whether data of the table is copied already
+func (s *CloneStatus) HasTable(table string) bool {
+	if s == nil {
+		return false
+	}
+	for _, t := range s.Tables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
@@ -20,19 +20,22 @@ import (
 
 // Cluster defines item of a clusters section of .configuration
 type Cluster struct {
-	Name              string            `json:"name,omitempty"              yaml:"name,omitempty"`
-	Zookeeper         *ZookeeperConfig  `json:"zookeeper,omitempty"         yaml:"zookeeper,omitempty"`
-	Settings          *Settings         `json:"settings,omitempty"          yaml:"settings,omitempty"`
-	Files             *Settings         `json:"files,omitempty"             yaml:"files,omitempty"`
-	Templates         *TemplatesList    `json:"templates,omitempty"         yaml:"templates,omitempty"`
-	SchemaPolicy      *SchemaPolicy     `json:"schemaPolicy,omitempty"      yaml:"schemaPolicy,omitempty"`
-	Insecure          *types.StringBool `json:"insecure,omitempty"          yaml:"insecure,omitempty"`
-	Secure            *types.StringBool `json:"secure,omitempty"            yaml:"secure,omitempty"`
-	Secret            *ClusterSecret    `json:"secret,omitempty"            yaml:"secret,omitempty"`
-	PDBManaged        *types.StringBool `json:"pdbManaged,omitempty"        yaml:"pdbManaged,omitempty"`
-	PDBMaxUnavailable *types.Int32      `json:"pdbMaxUnavailable,omitempty" yaml:"pdbMaxUnavailable,omitempty"`
-	Reconcile         *ClusterReconcile `json:"reconcile,omitempty"         yaml:"reconcile,omitempty"`
-	Layout            *ChiClusterLayout `json:"layout,omitempty"            yaml:"layout,omitempty"`
+	Name                 string                       `json:"name,omitempty"                 yaml:"name,omitempty"`
+	Zookeeper            *ZookeeperConfig             `json:"zookeeper,omitempty"            yaml:"zookeeper,omitempty"`
+	Settings             *Settings                    `json:"settings,omitempty"             yaml:"settings,omitempty"`
+	Files                *Settings                    `json:"files,omitempty"                yaml:"files,omitempty"`
+	Templates            *TemplatesList               `json:"templates,omitempty"            yaml:"templates,omitempty"`
+	SchemaPolicy         *SchemaPolicy                `json:"schemaPolicy,omitempty"         yaml:"schemaPolicy,omitempty"`
+	Insecure             *types.StringBool            `json:"insecure,omitempty"             yaml:"insecure,omitempty"`
+	Secure               *types.StringBool            `json:"secure,omitempty"               yaml:"secure,omitempty"`
+	Secret               *ClusterSecret               `json:"secret,omitempty"               yaml:"secret,omitempty"`
+	PDBManaged           *types.StringBool            `json:"pdbManaged,omitempty"           yaml:"pdbManaged,omitempty"`
+	PDBMaxUnavailable    *types.Int32                 `json:"pdbMaxUnavailable,omitempty"    yaml:"pdbMaxUnavailable,omitempty"`
+	Reconcile            *ClusterReconcile            `json:"reconcile,omitempty"            yaml:"reconcile,omitempty"`
+	Layout               *ChiClusterLayout            `json:"layout,omitempty"               yaml:"layout,omitempty"`
+	Autoscaling          *ClusterAutoscaling          `json:"autoscaling,omitempty"          yaml:"autoscaling,omitempty"`
+	ResourcesRecommender *ClusterResourcesRecommender `json:"resourcesRecommender,omitempty" yaml:"resourcesRecommender,omitempty"`
+	ReplicaGC            *ClusterReplicaGC            `json:"replicaGC,omitempty"            yaml:"replicaGC,omitempty"`
 
 	Runtime ChiClusterRuntime `json:"-" yaml:"-"`
 }
@@ -40,6 +43,8 @@ type Cluster struct {
 type ChiClusterRuntime struct {
 	Address ChiClusterAddress       `json:"-" yaml:"-"`
 	CHI     *ClickHouseInstallation `json:"-" yaml:"-" testdiff:"ignore"`
+	// KeptShards lists shards removed from the cluster, which are kept in the cluster until their data is drained
+	KeptShards []string `json:"-" yaml:"-"`
 }
 
 func (r *ChiClusterRuntime) GetAddress() IClusterAddress {
@@ -151,6 +156,30 @@ func (cluster *Cluster) GetReconcile() *ClusterReconcile {
 	return cluster.Reconcile
 }
 
+// GetAutoscaling is a getter
+func (cluster *Cluster) GetAutoscaling() *ClusterAutoscaling {
+	if cluster == nil {
+		return nil
+	}
+	return cluster.Autoscaling
+}
+
+// GetResourcesRecommender is a getter
+func (cluster *Cluster) GetResourcesRecommender() *ClusterResourcesRecommender {
+	if cluster == nil {
+		return nil
+	}
+	return cluster.ResourcesRecommender
+}
+
+// GetReplicaGC is a getter
+func (cluster *Cluster) GetReplicaGC() *ClusterReplicaGC {
+	if cluster == nil {
+		return nil
+	}
+	return cluster.ReplicaGC
+}
+
 // GetRuntime is a getter
 func (cluster *Cluster) GetRuntime() IClusterRuntime {
 	return &cluster.Runtime
@@ -0,0 +1,69 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types reported in the status of the custom resource
+const (
+	// ConditionTypeReady reports all hosts are reconciled and ready to serve
+	ConditionTypeReady = "Ready"
+	// ConditionTypeReconciling reports reconcile is in progress
+	ConditionTypeReconciling = "Reconciling"
+	// ConditionTypeDegraded reports some hosts are not ready or replication is unhealthy
+	ConditionTypeDegraded = "Degraded"
+	// ConditionTypeReplicationHealthy reports replicas are not read-only, have active sessions and are not lagging
+	ConditionTypeReplicationHealthy = "ReplicationHealthy"
+	// ConditionTypeSchemaInSync reports declarative schema is in sync with all hosts
+	ConditionTypeSchemaInSync = "SchemaInSync"
+	// ConditionTypeSuspended reports reconcile is suspended
+	ConditionTypeSuspended = "Suspended"
+)
+
+// Condition reasons
+const (
+	ConditionReasonReconcileStarted   = "ReconcileStarted"
+	ConditionReasonReconcileCompleted = "ReconcileCompleted"
+	ConditionReasonReconcileFailed    = "ReconcileFailed"
+	ConditionReasonReconcileAborted   = "ReconcileAborted"
+	ConditionReasonSuspended          = "Suspended"
+	ConditionReasonNotSuspended       = "NotSuspended"
+	ConditionReasonStopped            = "Stopped"
+	ConditionReasonHostsReady         = "HostsReady"
+	ConditionReasonHostsNotReady      = "HostsNotReady"
+	ConditionReasonReplicasHealthy    = "ReplicasHealthy"
+	ConditionReasonReplicasUnhealthy  = "ReplicasUnhealthy"
+	ConditionReasonQuorumLost         = "QuorumLost"
+	ConditionReasonSchemaInSync       = "SchemaInSync"
+	ConditionReasonSchemaDrifted      = "SchemaDrifted"
+	ConditionReasonSchemaNotManaged   = "SchemaNotManaged"
+)
+
+// NewCondition creates condition of the specified type observed on the specified generation of the custom resource
+func NewCondition(conditionType string, status bool, reason, message string, generation int64) meta.Condition {
+	conditionStatus := meta.ConditionFalse
+	if status {
+		conditionStatus = meta.ConditionTrue
+	}
+	return meta.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	}
+}
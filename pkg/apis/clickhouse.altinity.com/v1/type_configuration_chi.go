@@ -51,6 +51,8 @@ type Configuration struct {
 	Settings  *Settings        `json:"settings,omitempty"  yaml:"settings,omitempty"`
 	Files     *Settings        `json:"files,omitempty"     yaml:"files,omitempty"`
 	Clusters  []*Cluster       `json:"clusters,omitempty"  yaml:"clusters,omitempty"`
+	Schema    *Schema          `json:"schema,omitempty"    yaml:"schema,omitempty"`
+	TLS       *TLS             `json:"tls,omitempty"       yaml:"tls,omitempty"`
 }
 
 // NewConfiguration creates new Configuration objects
@@ -100,6 +102,34 @@ func (c *Configuration) GetFiles() *Settings {
 	return c.Files
 }
 
+func (c *Configuration) GetSchema() *Schema {
+	if c == nil {
+		return nil
+	}
+	return c.Schema
+}
+
+func (c *Configuration) GetTLS() *TLS {
+	if c == nil {
+		return nil
+	}
+	return c.TLS
+}
+
+func (c *Configuration) GetZookeeper() *ZookeeperConfig {
+	if c == nil {
+		return nil
+	}
+	return c.Zookeeper
+}
+
+func (c *Configuration) GetClusters() []*Cluster {
+	if c == nil {
+		return nil
+	}
+	return c.Clusters
+}
+
 // MergeFrom merges from specified source
 func (c *Configuration) MergeFrom(from *Configuration, _type MergeType) *Configuration {
 	if from == nil {
@@ -114,6 +144,8 @@ func (c *Configuration) MergeFrom(from *Configuration, _type MergeType) *Configu
 	c.Quotas = c.Quotas.MergeFrom(from.Quotas)
 	c.Settings = c.Settings.MergeFrom(from.Settings)
 	c.Files = c.Files.MergeFrom(from.Files)
+	c.Schema = c.Schema.MergeFrom(from.Schema)
+	c.TLS = c.TLS.MergeFrom(from.TLS)
 
 	// TODO merge clusters
 	// Copy Clusters for now
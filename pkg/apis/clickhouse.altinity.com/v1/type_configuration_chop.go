@@ -78,9 +78,16 @@ const (
 	// defaultTimeoutCollect specifies default timeout to collect metrics from the ClickHouse instance. In seconds
 	defaultTimeoutCollect = 8
 
+	// defaultIntervalCollect specifies default interval between metrics collections from the ClickHouse instance. In seconds
+	defaultIntervalCollect = 30
+
 	// defaultMetricsTablesRegexp specifies default regexp to match tables in system database to fetch metrics from
 	defaultMetricsTablesRegexp = "^(metrics|custom_metrics)$"
 
+	// defaultMetricsQueryLogTopQueries specifies default number of normalized queries,
+	// which have latency histograms of their own
+	defaultMetricsQueryLogTopQueries = 20
+
 	// defaultReconcileCHIsThreadsNumber specifies default number of controller threads running concurrently.
 	// Used in case no other specified in config
 	defaultReconcileCHIsThreadsNumber = 1
@@ -110,6 +117,9 @@ const (
 	defaultRevisionHistoryLimit = 10
 )
 
+// defaultMetricsQueryLogBuckets specifies default upper bounds of query latency histogram buckets. In seconds
+var defaultMetricsQueryLogBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
 // Username/password replacers
 const (
 	UsernameReplacer = "***"
@@ -374,13 +384,107 @@ type OperatorConfigClickHouse struct {
 		Timeouts struct {
 			Collect time.Duration `json:"collect" yaml:"collect"`
 		} `json:"timeouts" yaml:"timeouts"`
+		// Intervals used to schedule background metrics collection
+		Intervals struct {
+			Collect time.Duration `json:"collect" yaml:"collect"`
+		} `json:"intervals" yaml:"intervals"`
 		// TablesRegexp specifies regexp to match tables in system database to fetch metrics from.
 		// Multiple tables can be matched using regexp. Matched tables are merged using merge() table function.
 		// Default is "^(metrics|custom_metrics)$" which fetches from both system.metrics and system.custom_metrics.
 		TablesRegexp string `json:"tablesRegexp" yaml:"tablesRegexp"`
+		// Queries specifies custom SQL queries metrics exporter fetches additional metrics with
+		Queries []*OperatorConfigMetricsQuery `json:"queries" yaml:"queries"`
+		// QueryLog specifies query latency histograms metrics exporter builds from system.query_log
+		QueryLog OperatorConfigMetricsQueryLog `json:"queryLog" yaml:"queryLog"`
 	} `json:"metrics" yaml:"metrics"`
 }
 
+// OperatorConfigMetricsQuery specifies custom SQL query metrics exporter fetches metrics from ClickHouse hosts with
+type OperatorConfigMetricsQuery struct {
+	// Name of the query. Metrics are named after the query
+	Name string `json:"name" yaml:"name"`
+	// SQL to run on each host
+	SQL string `json:"sql" yaml:"sql"`
+	// Labels lists columns, which values are reported as labels of the metrics
+	Labels []string `json:"labels" yaml:"labels"`
+	// Values lists columns, which values are reported as metrics, each column as a separate metric
+	Values []string `json:"values" yaml:"values"`
+	// Type of the metrics - either gauge or counter
+	Type string `json:"type" yaml:"type"`
+	// Description of the metrics
+	Description string `json:"description" yaml:"description"`
+	// Timeout used to limit the query. In seconds
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+	// CHISelector selects CHIs, which hosts the query is run on, by labels. All CHIs are selected by default
+	CHISelector TargetSelector `json:"chiSelector" yaml:"chiSelector"`
+}
+
+// OperatorConfigMetricsQueryLog specifies how metrics exporter builds query latency histograms from system.query_log
+type OperatorConfigMetricsQueryLog struct {
+	// Enabled specifies whether query latency histograms are collected
+	Enabled *types.StringBool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// Buckets specifies upper bounds of the latency histogram buckets. In seconds
+	Buckets []float64 `json:"buckets" yaml:"buckets"`
+	// TopQueries specifies number of normalized queries, which have latency histograms of their own.
+	// Queries are ranked by their recent counts. Latencies of the rest of the queries are accounted under "other" query hash
+	TopQueries int `json:"topQueries" yaml:"topQueries"`
+}
+
+// IsEnabled checks whether query latency histograms are collected
+func (q *OperatorConfigMetricsQueryLog) IsEnabled() bool {
+	if q == nil {
+		return false
+	}
+	return q.Enabled.Value()
+}
+
+// Metrics query types
+const (
+	MetricsQueryTypeGauge   = "gauge"
+	MetricsQueryTypeCounter = "counter"
+)
+
+// IsValid checks whether the query is complete
+func (q *OperatorConfigMetricsQuery) IsValid() bool {
+	if q == nil {
+		return false
+	}
+	return (q.Name != "") && (q.SQL != "") && (len(q.Values) > 0)
+}
+
+// GetName gets name of the query
+func (q *OperatorConfigMetricsQuery) GetName() string {
+	if q == nil {
+		return ""
+	}
+	return q.Name
+}
+
+// IsCounter checks whether the query reports counters
+func (q *OperatorConfigMetricsQuery) IsCounter() bool {
+	if q == nil {
+		return false
+	}
+	return q.Type == MetricsQueryTypeCounter
+}
+
+// GetMetricName gets name of the metric reported from the value column.
+// Query with single value column reports the metric named after the query
+func (q *OperatorConfigMetricsQuery) GetMetricName(value string) string {
+	if len(q.Values) == 1 {
+		return q.Name
+	}
+	return q.Name + "_" + value
+}
+
+// Selects checks whether the query is run on hosts of the CHI with specified labels
+func (q *OperatorConfigMetricsQuery) Selects(labels map[string]string) bool {
+	if q == nil {
+		return false
+	}
+	return q.CHISelector.Matches(labels)
+}
+
 // OperatorConfigKeeper specifies Keeper section
 type OperatorConfigKeeper struct {
 	Config OperatorConfigConfig `json:"configuration" yaml:"configuration"`
@@ -668,6 +772,70 @@ type OperatorConfigLabelRuntime struct {
 
 type OperatorConfigMetrics struct {
 	Labels OperatorConfigMetricsLabels `json:"labels" yaml:"labels"`
+	// API specifies protection of the metrics exporter REST API, the operator informs exporter about watched CRs with
+	API OperatorConfigMetricsAPI `json:"api" yaml:"api"`
+}
+
+// OperatorConfigMetricsAPI specifies protection of the metrics exporter REST API
+type OperatorConfigMetricsAPI struct {
+	// Authentication of the REST API requests. Either "none" or "token"
+	Authentication string `json:"authentication" yaml:"authentication"`
+	// ServiceAccounts lists service accounts, in "namespace/name" format, allowed to call REST API with token authentication.
+	// Service account of the operator is allowed by default
+	ServiceAccounts []string `json:"serviceAccounts" yaml:"serviceAccounts"`
+	// TLS specifies certificates REST API is served with
+	TLS OperatorConfigMetricsAPITLS `json:"tls" yaml:"tls"`
+	// ReadOnly specifies whether the exporter discovers watched CRs with informers solely,
+	// so REST API does not accept modifications of the watched CRs
+	ReadOnly *types.StringBool `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+}
+
+// OperatorConfigMetricsAPITLS specifies certificates metrics exporter REST API is served with
+type OperatorConfigMetricsAPITLS struct {
+	// CertFile and KeyFile specify certificate REST API is served with.
+	// The operator presents the same certificate as a client certificate in case of mutual TLS
+	CertFile string `json:"certFile" yaml:"certFile"`
+	KeyFile  string `json:"keyFile"  yaml:"keyFile"`
+	// CAFile specifies CA certificates are verified with. Client certificates are required in case CA is specified
+	CAFile string `json:"caFile" yaml:"caFile"`
+}
+
+// Metrics exporter REST API authentication types
+const (
+	MetricsAPIAuthenticationNone  = "none"
+	MetricsAPIAuthenticationToken = "token"
+)
+
+// IsTokenAuthentication checks whether REST API requests are authenticated with service account tokens
+func (a *OperatorConfigMetricsAPI) IsTokenAuthentication() bool {
+	if a == nil {
+		return false
+	}
+	return a.Authentication == MetricsAPIAuthenticationToken
+}
+
+// IsTLS checks whether REST API is served over TLS
+func (a *OperatorConfigMetricsAPI) IsTLS() bool {
+	if a == nil {
+		return false
+	}
+	return (a.TLS.CertFile != "") && (a.TLS.KeyFile != "")
+}
+
+// IsMutualTLS checks whether REST API requires client certificates
+func (a *OperatorConfigMetricsAPI) IsMutualTLS() bool {
+	if a == nil {
+		return false
+	}
+	return a.IsTLS() && (a.TLS.CAFile != "")
+}
+
+// IsReadOnly checks whether REST API does not accept modifications of the watched CRs
+func (a *OperatorConfigMetricsAPI) IsReadOnly() bool {
+	if a == nil {
+		return false
+	}
+	return a.ReadOnly.Value()
 }
 
 type OperatorConfigMetricsLabels struct {
@@ -1145,9 +1313,54 @@ func (c *OperatorConfig) normalizeSectionClickHouseMetrics() {
 	// Adjust seconds to time.Duration
 	c.ClickHouse.Metrics.Timeouts.Collect = c.ClickHouse.Metrics.Timeouts.Collect * time.Second
 
+	if c.ClickHouse.Metrics.Intervals.Collect == 0 {
+		c.ClickHouse.Metrics.Intervals.Collect = defaultIntervalCollect
+	}
+	// Adjust seconds to time.Duration
+	c.ClickHouse.Metrics.Intervals.Collect = c.ClickHouse.Metrics.Intervals.Collect * time.Second
+
+	for _, query := range c.ClickHouse.Metrics.Queries {
+		if query == nil {
+			continue
+		}
+		if query.Type != MetricsQueryTypeCounter {
+			query.Type = MetricsQueryTypeGauge
+		}
+		if query.Timeout == 0 {
+			// Query is limited by the collection timeout by default
+			query.Timeout = c.ClickHouse.Metrics.Timeouts.Collect
+		} else {
+			// Adjust seconds to time.Duration
+			query.Timeout = query.Timeout * time.Second
+		}
+	}
+
 	if c.ClickHouse.Metrics.TablesRegexp == "" {
 		c.ClickHouse.Metrics.TablesRegexp = defaultMetricsTablesRegexp
 	}
+
+	if len(c.ClickHouse.Metrics.QueryLog.Buckets) == 0 {
+		c.ClickHouse.Metrics.QueryLog.Buckets = defaultMetricsQueryLogBuckets
+	}
+	if c.ClickHouse.Metrics.QueryLog.TopQueries <= 0 {
+		c.ClickHouse.Metrics.QueryLog.TopQueries = defaultMetricsQueryLogTopQueries
+	}
+}
+
+func (c *OperatorConfig) normalizeSectionMetrics() {
+	c.Metrics.API.Authentication = strings.ToLower(strings.TrimSpace(c.Metrics.API.Authentication))
+	if c.Metrics.API.Authentication != MetricsAPIAuthenticationToken {
+		c.Metrics.API.Authentication = MetricsAPIAuthenticationNone
+	}
+
+	if len(c.Metrics.API.ServiceAccounts) == 0 {
+		// Service account of the operator is allowed by default
+		namespace := os.Getenv(deployment.OPERATOR_POD_NAMESPACE)
+		serviceAccount := os.Getenv(deployment.OPERATOR_POD_SERVICE_ACCOUNT)
+		if (namespace != "") && (serviceAccount != "") {
+			c.Metrics.API.ServiceAccounts = []string{namespace + "/" + serviceAccount}
+		}
+	}
 }
 
 func (c *OperatorConfig) normalizeSectionLogger() {
@@ -1207,6 +1420,7 @@ func (c *OperatorConfig) normalize() {
 	c.normalizeSectionClickHouseConfigurationUserDefault()
 	c.normalizeSectionClickHouseAccess()
 	c.normalizeSectionClickHouseMetrics()
+	c.normalizeSectionMetrics()
 	c.normalizeSectionKeeperConfigurationFile()
 	c.normalizeSectionTemplate()
 	c.normalizeSectionReconcileRuntime()
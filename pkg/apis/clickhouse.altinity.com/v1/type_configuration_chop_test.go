@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeMetricsQueries(t *testing.T) {
+	c := &OperatorConfig{}
+	c.ClickHouse.Metrics.Queries = []*OperatorConfigMetricsQuery{
+		{Name: "inserts", SQL: "SELECT 1 AS v", Values: []string{"v"}, Type: "counter", Timeout: 3},
+		{Name: "lag", SQL: "SELECT 1 AS a, 2 AS b", Values: []string{"a", "b"}},
+		nil,
+	}
+	c.normalizeSectionClickHouseMetrics()
+
+	inserts, lag := c.ClickHouse.Metrics.Queries[0], c.ClickHouse.Metrics.Queries[1]
+	require.True(t, inserts.IsCounter())
+	require.Equal(t, 3*time.Second, inserts.Timeout)
+	require.Equal(t, "inserts", inserts.GetMetricName("v"))
+
+	require.False(t, lag.IsCounter())
+	require.Equal(t, c.ClickHouse.Metrics.Timeouts.Collect, lag.Timeout)
+	require.Equal(t, "lag_b", lag.GetMetricName("b"))
+}
+
+func TestMetricsQuerySelects(t *testing.T) {
+	query := &OperatorConfigMetricsQuery{Name: "q", SQL: "SELECT 1 AS v", Values: []string{"v"}}
+	require.True(t, query.IsValid())
+	require.True(t, query.Selects(nil))
+
+	query.CHISelector = TargetSelector{"env": "prod"}
+	require.True(t, query.Selects(map[string]string{"env": "prod", "team": "a"}))
+	require.False(t, query.Selects(map[string]string{"env": "dev"}))
+
+	require.False(t, (&OperatorConfigMetricsQuery{Name: "q"}).IsValid())
+}
@@ -0,0 +1,204 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+// Possible roles of a CHI in disaster recovery
+const (
+	// DisasterRecoveryRolePrimary specifies CHI is replicated by at least one DR CHI
+	DisasterRecoveryRolePrimary = "Primary"
+	// DisasterRecoveryRoleReplica specifies CHI is an async DR replica of the primary CHI
+	DisasterRecoveryRoleReplica = "Replica"
+	// DisasterRecoveryRolePromoting specifies DR CHI is promoted, but replicated tables are not moved to own ZooKeeper root yet
+	DisasterRecoveryRolePromoting = "Promoting"
+	// DisasterRecoveryRoleStandalone specifies DR CHI is promoted and does not replicate the primary any more
+	DisasterRecoveryRoleStandalone = "Standalone"
+)
+
+// DisasterRecoveryClusterNameSuffix is appended to the name of a cluster in order to build the name of the cluster,
+// which lays over hosts of the cluster in both primary and DR installations
+const DisasterRecoveryClusterNameSuffix = "-dr"
+
+// DisasterRecoveryPromotedZookeeperRoot is appended to ZooKeeper root of the promoted DR CHI along with its namespace and name
+const DisasterRecoveryPromotedZookeeperRoot = "disaster-recovery"
+
+// DisasterRecovery makes the CHI an async disaster recovery replica of the primary CHI.
+// Hosts of the DR CHI are replicas of the same shards of the same-named clusters of the primary,
+// so replicated tables share ZooKeeper paths of the primary
+type DisasterRecovery struct {
+	// Primary refers to the CHI to be replicated
+	Primary *DisasterRecoveryPrimary `json:"primary,omitempty" yaml:"primary,omitempty"`
+	// Promote turns the DR CHI into a standalone installation, which does not replicate the primary any more.
+	// Replicated tables of the promoted CHI are moved to own ZooKeeper root
+	Promote *types.StringBool `json:"promote,omitempty" yaml:"promote,omitempty"`
+}
+
+// DisasterRecoveryPrimary refers to the primary CHI
+type DisasterRecoveryPrimary struct {
+	Name      string `json:"name,omitempty"      yaml:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+// HasPrimary checks whether primary CHI is specified
+func (dr *DisasterRecovery) HasPrimary() bool {
+	if dr == nil {
+		return false
+	}
+	return (dr.Primary != nil) && (dr.Primary.Name != "")
+}
+
+// IsPromoted checks whether DR CHI is promoted to a standalone installation
+func (dr *DisasterRecovery) IsPromoted() bool {
+	if !dr.HasPrimary() {
+		return false
+	}
+	return dr.Promote.Value()
+}
+
+// GetPromotedZookeeperRoot builds ZooKeeper root of the promoted DR CHI of the specified namespace and name.
+// Promoted CHI moves replicated tables to own ZooKeeper root, so they do not share ZooKeeper paths with the primary
+func (dr *DisasterRecovery) GetPromotedZookeeperRoot(root, namespace, name string) string {
+	return path.Join("/", root, DisasterRecoveryPromotedZookeeperRoot, namespace, name)
+}
+
+// IsReplica checks whether CHI replicates the primary
+func (dr *DisasterRecovery) IsReplica() bool {
+	return dr.HasPrimary() && !dr.IsPromoted()
+}
+
+// GetPrimaryNamespace gets namespace of the primary, which defaults to the namespace of the DR CHI
+func (dr *DisasterRecovery) GetPrimaryNamespace(namespace string) string {
+	if dr.HasPrimary() && (dr.Primary.Namespace != "") {
+		return dr.Primary.Namespace
+	}
+	return namespace
+}
+
+// IsReplicaOf checks whether CHI of the specified namespace replicates the specified primary CHI
+func (dr *DisasterRecovery) IsReplicaOf(namespace, primaryNamespace, primaryName string) bool {
+	return dr.IsReplica() && (dr.Primary.Name == primaryName) && (dr.GetPrimaryNamespace(namespace) == primaryNamespace)
+}
+
+// DisasterRecoveryStatus defines disaster recovery state of the CHI
+type DisasterRecoveryStatus struct {
+	Role string `json:"role,omitempty"            yaml:"role,omitempty"`
+	// Primary is namespace/name of the primary CHI
+	Primary string `json:"primary,omitempty"         yaml:"primary,omitempty"`
+	// Replicas lists namespace/name of the DR CHIs replicating the CHI
+	Replicas []string `json:"replicas,omitempty"        yaml:"replicas,omitempty"`
+	// MaxReplicaDelay is max replication delay of the hosts of the DR CHI in seconds
+	MaxReplicaDelay int `json:"maxReplicaDelay,omitempty" yaml:"maxReplicaDelay,omitempty"`
+	// LagTime is time replication delay was checked last time
+	LagTime string `json:"lagTime,omitempty"         yaml:"lagTime,omitempty"`
+	// LagError explains why replication delay of some hosts is unknown
+	LagError string `json:"lagError,omitempty"        yaml:"lagError,omitempty"`
+	// Error explains why the primary is not replicated
+	Error string `json:"error,omitempty"           yaml:"error,omitempty"`
+}
+
+// DisasterRecoveryRuntime keeps installations related to the CHI by disaster recovery.
+// Installations are normalized, so their hosts are known
+type DisasterRecoveryRuntime struct {
+	// Primary is the primary CHI in case the CHI replicates it
+	Primary *ClickHouseInstallation
+	// Replicas are DR CHIs replicating the CHI
+	Replicas []*ClickHouseInstallation
+	// Error explains why the primary is not replicated
+	Error string
+}
+
+// GetPrimary gets the primary CHI
+func (r *DisasterRecoveryRuntime) GetPrimary() *ClickHouseInstallation {
+	if r == nil {
+		return nil
+	}
+	return r.Primary
+}
+
+// GetError gets error explaining why the primary is not replicated
+func (r *DisasterRecoveryRuntime) GetError() string {
+	if r == nil {
+		return ""
+	}
+	return r.Error
+}
+
+// GetPeers gets all installations related to the CHI
+func (r *DisasterRecoveryRuntime) GetPeers() []*ClickHouseInstallation {
+	if r == nil {
+		return nil
+	}
+	if r.Primary != nil {
+		return append([]*ClickHouseInstallation{r.Primary}, r.Replicas...)
+	}
+	return r.Replicas
+}
+
+// CheckDisasterRecoveryPeers checks whether hosts of the replica CHI can safely be replicas of the primary CHI.
+// Same-named clusters are expected to have the same number of shards and the same ZooKeeper config,
+// replica names of the hosts, provided by replicaName, are expected not to collide
+func CheckDisasterRecoveryPeers(primary, replica *ClickHouseInstallation, replicaName func(host *Host) string) error {
+	names := make(map[string]bool)
+	for _, cluster := range primary.GetSpecT().Configuration.GetClusters() {
+		if cluster.Layout == nil {
+			continue
+		}
+		for _, shard := range cluster.Layout.Shards {
+			for _, host := range shard.Hosts {
+				names[replicaName(host)] = true
+			}
+		}
+	}
+
+	for _, cluster := range replica.GetSpecT().Configuration.GetClusters() {
+		primaryCluster := findClusterByName(primary, cluster.GetName())
+		if (primaryCluster == nil) || (primaryCluster.Layout == nil) || (cluster.Layout == nil) {
+			// Clusters without layout have no hosts to replicate each other
+			continue
+		}
+		if len(cluster.Layout.Shards) != len(primaryCluster.Layout.Shards) {
+			return fmt.Errorf("cluster %s has %d shards, primary cluster has %d shards",
+				cluster.GetName(), len(cluster.Layout.Shards), len(primaryCluster.Layout.Shards))
+		}
+		if !cluster.Zookeeper.Equals(primaryCluster.Zookeeper) {
+			return fmt.Errorf("cluster %s has zookeeper config different from the primary cluster", cluster.GetName())
+		}
+		for _, shard := range cluster.Layout.Shards {
+			for _, host := range shard.Hosts {
+				if names[replicaName(host)] {
+					return fmt.Errorf("replica name %s of cluster %s is used by the primary", replicaName(host), cluster.GetName())
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// findClusterByName finds cluster of the CHI by name
+func findClusterByName(chi *ClickHouseInstallation, name string) *Cluster {
+	for _, cluster := range chi.GetSpecT().Configuration.GetClusters() {
+		if cluster.GetName() == name {
+			return cluster
+		}
+	}
+	return nil
+}
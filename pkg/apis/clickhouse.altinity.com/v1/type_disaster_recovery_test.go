@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+func newDisasterRecoveryTestCHI(name string, shards ...[]string) *ClickHouseInstallation {
+	layout := &ChiClusterLayout{}
+	for _, hosts := range shards {
+		shard := &ChiShard{}
+		for _, host := range hosts {
+			shard.Hosts = append(shard.Hosts, &Host{Name: host})
+		}
+		layout.Shards = append(layout.Shards, shard)
+	}
+	chi := &ClickHouseInstallation{}
+	chi.Name = name
+	chi.Spec.Configuration = &Configuration{
+		Clusters: []*Cluster{
+			{
+				Name:   "main",
+				Layout: layout,
+			},
+		},
+	}
+	return chi
+}
+
+func TestCheckDisasterRecoveryPeers(t *testing.T) {
+	replicaName := func(host *Host) string {
+		return host.Name
+	}
+	primary := newDisasterRecoveryTestCHI("primary", []string{"p-0-0", "p-0-1"}, []string{"p-1-0", "p-1-1"})
+
+	replica := newDisasterRecoveryTestCHI("replica", []string{"r-0-0"}, []string{"r-1-0"})
+	require.NoError(t, CheckDisasterRecoveryPeers(primary, replica, replicaName))
+
+	replica = newDisasterRecoveryTestCHI("replica", []string{"r-0-0"})
+	require.Error(t, CheckDisasterRecoveryPeers(primary, replica, replicaName))
+
+	replica = newDisasterRecoveryTestCHI("replica", []string{"r-0-0"}, []string{"p-1-1"})
+	require.Error(t, CheckDisasterRecoveryPeers(primary, replica, replicaName))
+
+	replica = newDisasterRecoveryTestCHI("replica", []string{"r-0-0"}, []string{"r-1-0"})
+	replica.Spec.Configuration.Clusters[0].Zookeeper = &ZookeeperConfig{Root: "/other"}
+	require.Error(t, CheckDisasterRecoveryPeers(primary, replica, replicaName))
+
+	replica = newDisasterRecoveryTestCHI("replica", []string{"r-0-0"}, []string{"r-1-0"})
+	replica.Spec.Configuration.Clusters[0].Layout = nil
+	require.NoError(t, CheckDisasterRecoveryPeers(primary, replica, replicaName))
+	require.NoError(t, CheckDisasterRecoveryPeers(replica, primary, replicaName))
+}
+
+func TestDisasterRecoveryIsReplicaOf(t *testing.T) {
+	var dr *DisasterRecovery
+	require.False(t, dr.IsReplica())
+
+	dr = &DisasterRecovery{
+		Primary: &DisasterRecoveryPrimary{
+			Name: "primary",
+		},
+	}
+	require.True(t, dr.IsReplicaOf("dr", "dr", "primary"))
+	require.False(t, dr.IsReplicaOf("dr", "prod", "primary"))
+
+	dr.Primary.Namespace = "prod"
+	require.True(t, dr.IsReplicaOf("dr", "prod", "primary"))
+
+	dr.Promote = types.NewStringBool(true)
+	require.False(t, dr.IsReplica())
+	require.False(t, dr.IsReplicaOf("dr", "prod", "primary"))
+}
+
+func TestDisasterRecoveryGetPromotedZookeeperRoot(t *testing.T) {
+	dr := &DisasterRecovery{}
+	require.Equal(t, "/disaster-recovery/dr/production-dr", dr.GetPromotedZookeeperRoot("", "dr", "production-dr"))
+	require.Equal(t, "/clickhouse/disaster-recovery/dr/production-dr", dr.GetPromotedZookeeperRoot("/clickhouse/", "dr", "production-dr"))
+}
@@ -30,6 +30,10 @@ type Host struct {
 	HostPorts    `json:",inline" yaml:",inline"`
 	HostSettings `json:",inline" yaml:",inline"`
 	Templates    *TemplatesList `json:"templates,omitempty"           yaml:"templates,omitempty"`
+	// Maintenance excludes host from the cluster and from the traffic until cleared
+	Maintenance *types.StringBool `json:"maintenance,omitempty"         yaml:"maintenance,omitempty"`
+	// KubeContext names remote kube context the host lives in. Host lives in the local cluster by default
+	KubeContext string `json:"kubeContext,omitempty"         yaml:"kubeContext,omitempty"`
 
 	Runtime HostRuntime `json:"-" yaml:"-"`
 }
@@ -64,6 +68,10 @@ type HostRuntime struct {
 	reconcileAttributes *types.ReconcileAttributes `json:"-" yaml:"-" testdiff:"ignore"`
 	replicas            *types.Int32               `json:"-" yaml:"-"`
 	hasData             bool                       `json:"-" yaml:"-"`
+	// TLSVersion is a fingerprint of the certificate provisioned for the host by the operator
+	TLSVersion string `json:"-" yaml:"-" testdiff:"ignore"`
+	// Resources are CPU and memory recommended for the host, which override resources of the pod template
+	Resources *core.ResourceRequirements `json:"-" yaml:"-" testdiff:"ignore"`
 
 	// CurStatefulSet is a current stateful set, fetched from k8s
 	CurStatefulSet *apps.StatefulSet `json:"-" yaml:"-" testdiff:"ignore"`
@@ -196,6 +204,16 @@ func (host *Host) InheritTemplatesFrom(sources ...any) {
 	host.GetTemplates().HandleDeprecatedFields()
 }
 
+// InheritKubeContextFrom inherits kube context from specified replica
+func (host *Host) InheritKubeContextFrom(replica IReplica) {
+	if (host == nil) || (host.KubeContext != "") {
+		return
+	}
+	if typed, ok := replica.(*ChiReplica); ok && (typed != nil) {
+		host.KubeContext = typed.KubeContext
+	}
+}
+
 // MergeFrom merges from specified host
 func (host *Host) MergeFrom(from *Host) {
 	if (host == nil) || (from == nil) {
@@ -204,6 +222,10 @@ func (host *Host) MergeFrom(from *Host) {
 
 	host.Insecure = host.Insecure.MergeFrom(from.Insecure)
 	host.Secure = host.Secure.MergeFrom(from.Secure)
+	host.Maintenance = host.Maintenance.MergeFrom(from.Maintenance)
+	if host.KubeContext == "" {
+		host.KubeContext = from.KubeContext
+	}
 
 	if !host.TCPPort.HasValue() {
 		host.TCPPort.MergeFrom(from.TCPPort)
@@ -380,6 +402,22 @@ func (host *Host) IsTroubleshoot() bool {
 	return host.GetCR().IsTroubleshoot()
 }
 
+// GetKubeContext gets name of remote kube context the host lives in. Empty name stands for the local cluster
+func (host *Host) GetKubeContext() string {
+	if host == nil {
+		return ""
+	}
+	return host.KubeContext
+}
+
+// IsInMaintenance checks whether host is in maintenance mode
+func (host *Host) IsInMaintenance() bool {
+	if host == nil {
+		return false
+	}
+	return host.Maintenance.IsTrue()
+}
+
 // IsInNewCluster checks whether host is in a new cluster
 // TODO unify with model HostIsNewOne
 func (host *Host) IsInNewCluster() bool {
@@ -659,6 +697,8 @@ func (host *Host) ShouldIncludeIntoCluster() bool {
 	switch {
 	case host.IsStopped():
 		return false
+	case host.IsInMaintenance():
+		return false
 	case host.GetCluster().HostsCount() < 2:
 		return false
 	default:
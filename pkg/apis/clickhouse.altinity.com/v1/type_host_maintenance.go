@@ -0,0 +1,42 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "time"
+
+// HostMaintenance describes host being in maintenance mode - excluded from cluster and from traffic
+type HostMaintenance struct {
+	// Host is a name of the host in maintenance
+	Host string `json:"host,omitempty"  yaml:"host,omitempty"`
+	// Since is a time the host entered maintenance
+	Since string `json:"since,omitempty" yaml:"since,omitempty"`
+}
+
+// NewHostMaintenance creates new maintenance record of the host
+func NewHostMaintenance(host string, since time.Time) *HostMaintenance {
+	return &HostMaintenance{
+		Host:  host,
+		Since: since.Format(time.RFC3339),
+	}
+}
+
+// GetSince gets time the host entered maintenance
+func (m *HostMaintenance) GetSince() time.Time {
+	if m == nil {
+		return time.Time{}
+	}
+	since, _ := time.Parse(time.RFC3339, m.Since)
+	return since
+}
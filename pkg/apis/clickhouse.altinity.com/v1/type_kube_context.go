@@ -0,0 +1,97 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// KubeContext defines a remote Kubernetes cluster, where hosts of the CHI may live.
+// Hosts and replicas refer to the kube context by name. Objects of such hosts are created
+// in the same-named namespace of the remote Kubernetes cluster
+type KubeContext struct {
+	// Name of the kube context, which hosts refer to
+	Name string `json:"name,omitempty"                   yaml:"name,omitempty"`
+	// Kubeconfig refers to a key of a secret in the namespace of the CHI, which keeps kubeconfig of the remote cluster
+	Kubeconfig *types.DataSource `json:"kubeconfig,omitempty"             yaml:"kubeconfig,omitempty"`
+	// NamespaceDomainPattern specifies domain hosts of the remote cluster are reachable by from other clusters,
+	// such as "%s.svc.clusterset.local". Overrides namespace domain pattern of the CHI
+	NamespaceDomainPattern *types.String `json:"namespaceDomainPattern,omitempty" yaml:"namespaceDomainPattern,omitempty"`
+}
+
+// GetName gets name of the kube context
+func (kc *KubeContext) GetName() string {
+	if kc == nil {
+		return ""
+	}
+	return kc.Name
+}
+
+// HasKubeconfig checks whether kubeconfig secret is specified
+func (kc *KubeContext) HasKubeconfig() bool {
+	if kc == nil {
+		return false
+	}
+	return (kc.Kubeconfig != nil) && (kc.Kubeconfig.SecretKeyRef != nil)
+}
+
+// GetNamespaceDomainPattern gets namespace domain pattern of the kube context
+func (kc *KubeContext) GetNamespaceDomainPattern() *types.String {
+	if kc == nil {
+		return (*types.String)(nil)
+	}
+	return kc.NamespaceDomainPattern
+}
+
+// mergeKubeContexts merges kube contexts by name
+func mergeKubeContexts(to, from []*KubeContext, _type MergeType) []*KubeContext {
+	for _, fromContext := range from {
+		if fromContext == nil {
+			continue
+		}
+		found := false
+		for i, toContext := range to {
+			if toContext.GetName() != fromContext.GetName() {
+				continue
+			}
+			found = true
+			if _type == MergeTypeOverrideByNonEmptyValues {
+				to[i] = fromContext.DeepCopy()
+			}
+		}
+		if !found {
+			to = append(to, fromContext.DeepCopy())
+		}
+	}
+	return to
+}
+
+// GetHostsKubeContexts gets names of remote kube contexts hosts of the CHI live in, in order of hosts
+func (cr *ClickHouseInstallation) GetHostsKubeContexts() []string {
+	var names []string
+	cr.WalkHosts(func(host *Host) error {
+		if name := host.GetKubeContext(); (name != "") && !util.InArray(name, names) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	return names
+}
+
+// IsMultiKubeContext checks whether hosts of the CHI live in remote kube contexts
+func (cr *ClickHouseInstallation) IsMultiKubeContext() bool {
+	return len(cr.GetHostsKubeContexts()) > 0
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"time"
+
+	"github.com/altinity/clickhouse-operator/pkg/util/cron"
+)
+
+// Possible deferred actions
+const (
+	// DeferredActionRestart is a restart of the host
+	DeferredActionRestart = "restart"
+	// DeferredActionStatefulSet is an update or re-creation of the StatefulSet of the host, which rolls the pod
+	DeferredActionStatefulSet = "statefulSet"
+)
+
+// MaintenanceWindow defines time window when disruptive reconcile actions are allowed
+type MaintenanceWindow struct {
+	// Schedule is a cron expression of the window start, such as "0 2 * * 6"
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	// Duration is a duration of the window, such as 4h
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	// Timezone is an IANA time zone name the schedule is specified in, such as Europe/Berlin. UTC by default
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+// GetSchedule parses schedule of the window
+func (w *MaintenanceWindow) GetSchedule() (*cron.Schedule, error) {
+	return cron.Parse(w.Schedule)
+}
+
+// GetDuration parses duration of the window
+func (w *MaintenanceWindow) GetDuration() (time.Duration, error) {
+	return time.ParseDuration(w.Duration)
+}
+
+// GetLocation gets location the schedule is specified in
+func (w *MaintenanceWindow) GetLocation() (*time.Location, error) {
+	return time.LoadLocation(w.Timezone)
+}
+
+// IsOpen checks whether the window is open at the specified time.
+// Malformed window is never open
+func (w *MaintenanceWindow) IsOpen(now time.Time) bool {
+	if w == nil {
+		return false
+	}
+	schedule, err1 := w.GetSchedule()
+	duration, err2 := w.GetDuration()
+	loc, err3 := w.GetLocation()
+	if (err1 != nil) || (err2 != nil) || (err3 != nil) || (duration <= 0) {
+		return false
+	}
+	// The window is open in case it has started after the moment which is the whole duration ago
+	start := schedule.Next(now.In(loc).Add(-duration).Add(time.Nanosecond))
+	return !start.IsZero() && !start.After(now)
+}
+
+// NextOpen finds the time the window opens next time after the specified time
+func (w *MaintenanceWindow) NextOpen(now time.Time) time.Time {
+	if w == nil {
+		return time.Time{}
+	}
+	schedule, err1 := w.GetSchedule()
+	loc, err2 := w.GetLocation()
+	if (err1 != nil) || (err2 != nil) {
+		return time.Time{}
+	}
+	return schedule.Next(now.In(loc))
+}
+
+// DeferredAction describes disruptive actions on the host deferred until a maintenance window opens
+type DeferredAction struct {
+	// Host is a name of the host
+	Host string `json:"host,omitempty"    yaml:"host,omitempty"`
+	// Actions lists deferred actions, such as restart or statefulSet
+	Actions []string `json:"actions,omitempty" yaml:"actions,omitempty"`
+	// Since is a time the actions were deferred first time
+	Since string `json:"since,omitempty"   yaml:"since,omitempty"`
+	// Until is a time the next maintenance window opens
+	Until string `json:"until,omitempty"   yaml:"until,omitempty"`
+}
+
+// HasAction checks whether the action is deferred
+func (a *DeferredAction) HasAction(action string) bool {
+	if a == nil {
+		return false
+	}
+	for _, _action := range a.Actions {
+		if _action == action {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceWindowIsOpen(t *testing.T) {
+	// Saturdays 02:00-06:00 Berlin time, which is UTC+1 in winter
+	window := &MaintenanceWindow{
+		Schedule: "0 2 * * 6",
+		Duration: "4h",
+		Timezone: "Europe/Berlin",
+	}
+
+	require.False(t, window.IsOpen(time.Date(2024, 1, 13, 0, 59, 0, 0, time.UTC)))
+	require.True(t, window.IsOpen(time.Date(2024, 1, 13, 1, 0, 0, 0, time.UTC)))
+	require.True(t, window.IsOpen(time.Date(2024, 1, 13, 4, 59, 59, 0, time.UTC)))
+	require.False(t, window.IsOpen(time.Date(2024, 1, 13, 5, 0, 0, 0, time.UTC)))
+	require.False(t, window.IsOpen(time.Date(2024, 1, 14, 2, 0, 0, 0, time.UTC)))
+	require.Equal(t,
+		time.Date(2024, 1, 20, 1, 0, 0, 0, time.UTC),
+		window.NextOpen(time.Date(2024, 1, 13, 5, 0, 0, 0, time.UTC)).UTC(),
+	)
+
+	// Malformed window is never open
+	require.False(t, (&MaintenanceWindow{Schedule: "0 2 * * 6", Duration: "4h", Timezone: "Mars/Olympus"}).IsOpen(time.Now()))
+	require.False(t, (&MaintenanceWindow{Schedule: "* * * * *"}).IsOpen(time.Now()))
+}
+
+func TestChiReconcileIsInMaintenanceWindow(t *testing.T) {
+	now := time.Date(2024, 1, 13, 3, 0, 0, 0, time.UTC)
+
+	var reconcile *ChiReconcile
+	require.True(t, reconcile.IsInMaintenanceWindow(now))
+
+	reconcile = &ChiReconcile{
+		MaintenanceWindows: []*MaintenanceWindow{
+			{Schedule: "0 22 * * *", Duration: "2h"},
+			{Schedule: "30 2 * * *", Duration: "1h"},
+		},
+	}
+	require.True(t, reconcile.IsInMaintenanceWindow(now))
+	require.False(t, reconcile.IsInMaintenanceWindow(now.Add(time.Hour)))
+	require.Equal(t, time.Date(2024, 1, 13, 22, 0, 0, 0, time.UTC), reconcile.GetNextMaintenanceWindow(now.Add(time.Hour)))
+}
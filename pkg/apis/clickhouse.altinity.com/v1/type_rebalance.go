@@ -0,0 +1,231 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+// Possible rebalance strategies
+const (
+	// RebalanceStrategyBytes balances shards by bytes on disk
+	RebalanceStrategyBytes = "bytes"
+	// RebalanceStrategyRows balances shards by number of rows
+	RebalanceStrategyRows = "rows"
+)
+
+// Possible rebalance methods
+const (
+	// RebalanceMethodInsertSelect copies partition with INSERT SELECT over cluster() table function
+	// and drops it on the source shard afterwards
+	RebalanceMethodInsertSelect = "insertSelect"
+	// RebalanceMethodMovePartition moves partition with ALTER TABLE MOVE PARTITION TO SHARD.
+	// Requires Replicated tables with part_moves_between_shards_enable setting
+	RebalanceMethodMovePartition = "movePartition"
+)
+
+const (
+	defaultRebalanceMinPartitionIdleTime = 3600
+)
+
+// ClusterRebalance defines how data is rebalanced over shards when shards are added to the cluster
+type ClusterRebalance struct {
+	// Enabled turns on moving partitions from existing shards to the added ones
+	Enabled *types.StringBool `json:"enabled,omitempty"              yaml:"enabled,omitempty"`
+	// Strategy specifies what is balanced over shards
+	Strategy string `json:"strategy,omitempty"             yaml:"strategy,omitempty"`
+	// Method specifies how partitions are moved between shards
+	Method string `json:"method,omitempty"               yaml:"method,omitempty"`
+	// MinPartitionIdleTime specifies number of seconds partition has to stay unmodified in order to be moved.
+	// Partitions receiving inserts are not moved, so no data is lost when source partition is dropped
+	MinPartitionIdleTime *types.Int32 `json:"minPartitionIdleTime,omitempty" yaml:"minPartitionIdleTime,omitempty"`
+	// Tables limits rebalancing to the listed tables in 'database.table' form
+	Tables []string `json:"tables,omitempty"               yaml:"tables,omitempty"`
+}
+
+// IsEnabled checks whether rebalancing is enabled
+func (r *ClusterRebalance) IsEnabled() bool {
+	if r == nil {
+		return false
+	}
+	return r.Enabled.Value()
+}
+
+// GetStrategy gets strategy
+func (r *ClusterRebalance) GetStrategy() string {
+	if (r == nil) || (r.Strategy == "") {
+		return RebalanceStrategyBytes
+	}
+	return r.Strategy
+}
+
+// GetMethod gets method
+func (r *ClusterRebalance) GetMethod() string {
+	if (r == nil) || (r.Method == "") {
+		return RebalanceMethodInsertSelect
+	}
+	return r.Method
+}
+
+// GetMinPartitionIdleTime gets min partition idle time in seconds
+func (r *ClusterRebalance) GetMinPartitionIdleTime() int {
+	if (r == nil) || !r.MinPartitionIdleTime.HasValue() {
+		return defaultRebalanceMinPartitionIdleTime
+	}
+	return r.MinPartitionIdleTime.IntValue()
+}
+
+// GetTables gets tables
+func (r *ClusterRebalance) GetTables() []string {
+	if r == nil {
+		return nil
+	}
+	return r.Tables
+}
+
+// ClusterDrain defines how data is drained from shards which are removed from the cluster.
+// Removed shards are never deleted unless their data is moved to the remaining shards and verified
+type ClusterDrain struct {
+	// Enabled specifies whether data of the removed shards is moved to the remaining shards.
+	// Enabled by default, when disabled the removed shards are kept along with their data
+	Enabled *types.StringBool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+}
+
+// IsEnabled checks whether drain is enabled
+func (d *ClusterDrain) IsEnabled() bool {
+	if (d == nil) || (d.Enabled == nil) {
+		return true
+	}
+	return d.Enabled.Value()
+}
+
+// Possible rebalance statuses
+const (
+	RebalanceStatusInProgress = "InProgress"
+	RebalanceStatusCompleted  = "Completed"
+	RebalanceStatusPaused     = "Paused"
+)
+
+// Possible rebalance table statuses
+const (
+	RebalanceTableStatusInProgress = "InProgress"
+	RebalanceTableStatusCompleted  = "Completed"
+	RebalanceTableStatusSkipped    = "Skipped"
+	RebalanceTableStatusFailed     = "Failed"
+)
+
+// Possible phases of a partition move
+const (
+	// RebalanceMovePhaseCopying specifies partition is being copied to the destination shard
+	RebalanceMovePhaseCopying = "Copying"
+	// RebalanceMovePhaseCopied specifies partition is copied and verified, but is not dropped on the source shard yet
+	RebalanceMovePhaseCopied = "Copied"
+)
+
+// RebalanceStatus defines progress of data rebalancing over shards of a cluster
+type RebalanceStatus struct {
+	Cluster string `json:"cluster,omitempty"     yaml:"cluster,omitempty"`
+	// DrainShards lists shards data is drained from. Empty in case data is rebalanced over all shards
+	DrainShards []string                `json:"drainShards,omitempty" yaml:"drainShards,omitempty"`
+	Status      string                  `json:"status,omitempty"      yaml:"status,omitempty"`
+	Error       string                  `json:"error,omitempty"       yaml:"error,omitempty"`
+	Tables      []*RebalanceTableStatus `json:"tables,omitempty"      yaml:"tables,omitempty"`
+}
+
+// RebalanceTableStatus defines progress of a table rebalancing
+type RebalanceTableStatus struct {
+	Database         string         `json:"database,omitempty"         yaml:"database,omitempty"`
+	Table            string         `json:"table,omitempty"            yaml:"table,omitempty"`
+	Status           string         `json:"status,omitempty"           yaml:"status,omitempty"`
+	Error            string         `json:"error,omitempty"            yaml:"error,omitempty"`
+	PartitionsToMove int            `json:"partitionsToMove,omitempty" yaml:"partitionsToMove,omitempty"`
+	PartitionsMoved  int            `json:"partitionsMoved,omitempty"  yaml:"partitionsMoved,omitempty"`
+	Move             *RebalanceMove `json:"move,omitempty"             yaml:"move,omitempty"`
+}
+
+// RebalanceMove defines partition move in progress.
+// It is kept in status in order to resume the move safely in case reconcile is interrupted
+type RebalanceMove struct {
+	Partition      string `json:"partition,omitempty"      yaml:"partition,omitempty"`
+	PartitionID    string `json:"partitionID,omitempty"    yaml:"partitionID,omitempty"`
+	FromShard      string `json:"fromShard,omitempty"      yaml:"fromShard,omitempty"`
+	ToShard        string `json:"toShard,omitempty"        yaml:"toShard,omitempty"`
+	Phase          string `json:"phase,omitempty"          yaml:"phase,omitempty"`
+	Rows           int64  `json:"rows,omitempty"           yaml:"rows,omitempty"`
+	DestRowsBefore int64  `json:"destRowsBefore,omitempty" yaml:"destRowsBefore,omitempty"`
+	// Started is a time the move is started at, RFC3339
+	Started string `json:"started,omitempty"         yaml:"started,omitempty"`
+	// PartsInProgress is a number of parts of the partition which are still being moved by MOVE PARTITION TO SHARD
+	PartsInProgress int `json:"partsInProgress,omitempty" yaml:"partsInProgress,omitempty"`
+}
+
+// IsFinished checks whether rebalancing is over
+func (s *RebalanceStatus) IsFinished() bool {
+	if s == nil {
+		return true
+	}
+	return s.Status == RebalanceStatusCompleted
+}
+
+// IsInProgress checks whether rebalancing is being run
+func (s *RebalanceStatus) IsInProgress() bool {
+	if s == nil {
+		return false
+	}
+	return s.Status == RebalanceStatusInProgress
+}
+
+// IsDrain checks whether status describes drain of the shards
+func (s *RebalanceStatus) IsDrain() bool {
+	if s == nil {
+		return false
+	}
+	return len(s.DrainShards) > 0
+}
+
+// FindTable finds table status
+func (s *RebalanceStatus) FindTable(database, table string) *RebalanceTableStatus {
+	if s == nil {
+		return nil
+	}
+	for _, t := range s.Tables {
+		if (t.Database == database) && (t.Table == table) {
+			return t
+		}
+	}
+	return nil
+}
+
+// EnsureTable finds table status or creates new one
+func (s *RebalanceStatus) EnsureTable(database, table string) *RebalanceTableStatus {
+	if t := s.FindTable(database, table); t != nil {
+		return t
+	}
+	t := &RebalanceTableStatus{
+		Database: database,
+		Table:    table,
+	}
+	s.Tables = append(s.Tables, t)
+	return t
+}
+
+// Pause marks rebalancing as paused due to specified error
+func (s *RebalanceStatus) Pause(err string) {
+	if s == nil {
+		return
+	}
+	s.Status = RebalanceStatusPaused
+	s.Error = err
+}
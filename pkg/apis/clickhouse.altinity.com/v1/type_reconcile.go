@@ -37,6 +37,9 @@ type ChiReconcile struct {
 	StatefulSet ReconcileStatefulSet `json:"statefulSet,omitempty" yaml:"statefulSet,omitempty"`
 	// Host specifies host-lever reconcile settings
 	Host ReconcileHost `json:"host" yaml:"host"`
+	// MaintenanceWindows specify time windows when disruptive actions, such as host restarts and
+	// StatefulSet updates, are allowed. Disruptive actions are not limited in case no windows specified
+	MaintenanceWindows []*MaintenanceWindow `json:"maintenanceWindows,omitempty" yaml:"maintenanceWindows,omitempty"`
 }
 
 type ClusterReconcile struct {
@@ -46,6 +49,12 @@ type ClusterReconcile struct {
 	StatefulSet ReconcileStatefulSet `json:"statefulSet,omitempty" yaml:"statefulSet,omitempty"`
 	// Host specifies host-lever reconcile settings
 	Host ReconcileHost `json:"host" yaml:"host"`
+	// Rebalance specifies how data is rebalanced over shards when shards are added
+	Rebalance *ClusterRebalance `json:"rebalance,omitempty" yaml:"rebalance,omitempty"`
+	// Drain specifies how data is drained from shards which are removed from the cluster
+	Drain *ClusterDrain `json:"drain,omitempty" yaml:"drain,omitempty"`
+	// Upgrade specifies how ClickHouse version is upgraded when image of the hosts is changed
+	Upgrade *ClusterUpgrade `json:"upgrade,omitempty" yaml:"upgrade,omitempty"`
 }
 
 // ReconcileStatefulSet defines StatefulSet reconcile settings
@@ -126,6 +135,9 @@ func (r *ChiReconcile) MergeFrom(from *ChiReconcile, _type MergeType) *ChiReconc
 		if r.ConfigMapPropagationTimeout == 0 {
 			r.ConfigMapPropagationTimeout = from.ConfigMapPropagationTimeout
 		}
+		if len(r.MaintenanceWindows) == 0 {
+			r.MaintenanceWindows = from.MaintenanceWindows
+		}
 	case MergeTypeOverrideByNonEmptyValues:
 		if from.Policy != "" {
 			// Override by non-empty values only
@@ -135,6 +147,10 @@ func (r *ChiReconcile) MergeFrom(from *ChiReconcile, _type MergeType) *ChiReconc
 			// Override by non-empty values only
 			r.ConfigMapPropagationTimeout = from.ConfigMapPropagationTimeout
 		}
+		if len(from.MaintenanceWindows) > 0 {
+			// Override by non-empty values only
+			r.MaintenanceWindows = from.MaintenanceWindows
+		}
 	}
 
 	r.Cleanup = r.Cleanup.MergeFrom(from.Cleanup, _type)
@@ -218,6 +234,42 @@ func (r *ChiReconcile) IsReconcilingPolicyNoWait() bool {
 	return strings.ToLower(r.GetPolicy()) == ReconcilingPolicyNoWait
 }
 
+// HasMaintenanceWindows checks whether disruptive actions are limited by maintenance windows
+func (r *ChiReconcile) HasMaintenanceWindows() bool {
+	if r == nil {
+		return false
+	}
+	return len(r.MaintenanceWindows) > 0
+}
+
+// IsInMaintenanceWindow checks whether disruptive actions are allowed at the specified time
+func (r *ChiReconcile) IsInMaintenanceWindow(now time.Time) bool {
+	if !r.HasMaintenanceWindows() {
+		// Not limited
+		return true
+	}
+	for _, window := range r.MaintenanceWindows {
+		if window.IsOpen(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNextMaintenanceWindow gets the time the nearest maintenance window opens after the specified time
+func (r *ChiReconcile) GetNextMaintenanceWindow(now time.Time) time.Time {
+	var next time.Time
+	if r == nil {
+		return next
+	}
+	for _, window := range r.MaintenanceWindows {
+		if open := window.NextOpen(now); !open.IsZero() && (next.IsZero() || open.Before(next)) {
+			next = open
+		}
+	}
+	return next
+}
+
 // GetCleanup gets cleanup
 func (r *ChiReconcile) GetCleanup() *Cleanup {
 	if r == nil {
@@ -22,6 +22,8 @@ type ChiReplica struct {
 	Files       *Settings      `json:"files,omitempty"       yaml:"files,omitempty"`
 	Templates   *TemplatesList `json:"templates,omitempty"   yaml:"templates,omitempty"`
 	ShardsCount int            `json:"shardsCount,omitempty" yaml:"shardsCount,omitempty"`
+	// KubeContext names remote kube context hosts of the replica live in
+	KubeContext string `json:"kubeContext,omitempty" yaml:"kubeContext,omitempty"`
 	// TODO refactor into map[string]Host
 	Hosts []*Host `json:"shards,omitempty" yaml:"shards,omitempty"`
 
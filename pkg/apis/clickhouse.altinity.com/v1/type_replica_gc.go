@@ -0,0 +1,129 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"time"
+
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+// Possible modes of the replica garbage collector
+const (
+	// ReplicaGCModeReport reports orphaned replicas in status only, which is a dry run
+	ReplicaGCModeReport = "report"
+	// ReplicaGCModeDrop reports orphaned replicas and drops the ones reported by the previous run already
+	ReplicaGCModeDrop = "drop"
+)
+
+const defaultReplicaGCInterval = time.Hour
+
+// ClusterReplicaGC defines how replicas registered in ZooKeeper, which belong to none of the hosts of the CHI, are collected.
+// Such orphaned replicas remain when hosts are removed abnormally, for example with lost volumes.
+type ClusterReplicaGC struct {
+	// Enabled turns on periodic search of orphaned replicas
+	Enabled *types.StringBool `json:"enabled,omitempty"  yaml:"enabled,omitempty"`
+	// Mode specifies whether orphaned replicas are reported only or dropped as well
+	Mode string `json:"mode,omitempty"     yaml:"mode,omitempty"`
+	// Interval specifies how often orphaned replicas are searched for, such as 1h
+	Interval string `json:"interval,omitempty" yaml:"interval,omitempty"`
+	// Run requests an on-demand run. Any new value starts the run regardless of the interval
+	Run string `json:"run,omitempty"      yaml:"run,omitempty"`
+}
+
+// IsEnabled checks whether replica garbage collector is enabled
+func (gc *ClusterReplicaGC) IsEnabled() bool {
+	if gc == nil {
+		return false
+	}
+	return gc.Enabled.Value()
+}
+
+// IsDrop checks whether orphaned replicas have to be dropped
+func (gc *ClusterReplicaGC) IsDrop() bool {
+	return gc.IsEnabled() && (gc.Mode == ReplicaGCModeDrop)
+}
+
+// GetInterval gets interval between the runs
+func (gc *ClusterReplicaGC) GetInterval() time.Duration {
+	if gc == nil {
+		return defaultReplicaGCInterval
+	}
+	interval, err := time.ParseDuration(gc.Interval)
+	if err != nil || interval <= 0 {
+		return defaultReplicaGCInterval
+	}
+	return interval
+}
+
+// IsDue checks whether the next run is due at the specified time, considering report of the previous run
+func (gc *ClusterReplicaGC) IsDue(prev *ReplicaGCReport, now time.Time) bool {
+	if !gc.IsEnabled() {
+		return false
+	}
+	if prev == nil {
+		return true
+	}
+	if gc.Run != prev.Run {
+		// On-demand run is requested
+		return true
+	}
+	last, err := time.Parse(time.RFC3339, prev.Time)
+	if err != nil {
+		return true
+	}
+	return !now.Before(last.Add(gc.GetInterval()))
+}
+
+// ReplicaGCReport describes orphaned replicas of the cluster found by the last run of the replica garbage collector
+type ReplicaGCReport struct {
+	// Cluster the report is made for
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	// Time of the run in RFC3339 format
+	Time string `json:"time,omitempty"    yaml:"time,omitempty"`
+	// Run specifies on-demand run request the report is made for
+	Run string `json:"run,omitempty"     yaml:"run,omitempty"`
+	// Orphans lists orphaned replicas
+	Orphans []*OrphanReplica `json:"orphans,omitempty" yaml:"orphans,omitempty"`
+	// Error specifies why the run has failed
+	Error string `json:"error,omitempty"   yaml:"error,omitempty"`
+}
+
+// HasOrphan checks whether the report lists the orphaned replica
+func (r *ReplicaGCReport) HasOrphan(zookeeperPath, replica string) bool {
+	if r == nil {
+		return false
+	}
+	for _, orphan := range r.Orphans {
+		if (orphan.ZookeeperPath == zookeeperPath) && (orphan.Replica == replica) {
+			return true
+		}
+	}
+	return false
+}
+
+// OrphanReplica describes replica registered in ZooKeeper for a replicated table, which belongs to none of the hosts of the CHI
+type OrphanReplica struct {
+	// Table specifies replicated table as <database>.<table>
+	Table string `json:"table,omitempty"         yaml:"table,omitempty"`
+	// ZookeeperPath specifies ZooKeeper path of the table
+	ZookeeperPath string `json:"zookeeperPath,omitempty" yaml:"zookeeperPath,omitempty"`
+	// Replica specifies name of the orphaned replica
+	Replica string `json:"replica,omitempty"       yaml:"replica,omitempty"`
+	// Dropped specifies time the replica was dropped in RFC3339 format. Empty in case replica is reported only
+	Dropped string `json:"dropped,omitempty"       yaml:"dropped,omitempty"`
+	// Error specifies why the replica could not be dropped
+	Error string `json:"error,omitempty"         yaml:"error,omitempty"`
+}
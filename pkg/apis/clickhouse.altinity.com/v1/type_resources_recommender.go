@@ -0,0 +1,143 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"time"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+// Possible modes of the resources recommender
+const (
+	// ResourcesRecommenderModeRecommend publishes recommendations only
+	ResourcesRecommenderModeRecommend = "recommend"
+	// ResourcesRecommenderModeApply publishes recommendations and applies them to the hosts via rolling update
+	ResourcesRecommenderModeApply = "apply"
+)
+
+const (
+	defaultResourcesRecommenderHeadroom  = 20
+	defaultResourcesRecommenderMinChange = 10
+	defaultResourcesRecommenderWindow    = 24 * time.Hour
+)
+
+// ClusterResourcesRecommender defines how CPU and memory of the cluster's hosts are recommended
+type ClusterResourcesRecommender struct {
+	// Enabled turns on sampling of the hosts resources usage
+	Enabled *types.StringBool `json:"enabled,omitempty"   yaml:"enabled,omitempty"`
+	// Mode specifies whether recommendations are published only or applied as well
+	Mode string `json:"mode,omitempty"      yaml:"mode,omitempty"`
+	// Window specifies how long usage history recommendations are based on is, such as 24h
+	Window string `json:"window,omitempty"    yaml:"window,omitempty"`
+	// Headroom specifies percent added on top of the observed usage
+	Headroom *types.Int32 `json:"headroom,omitempty"  yaml:"headroom,omitempty"`
+	// MinChange specifies min change in percent of any value which makes recommendation to be applied
+	MinChange *types.Int32 `json:"minChange,omitempty" yaml:"minChange,omitempty"`
+}
+
+// IsEnabled checks whether resources recommender is enabled
+func (r *ClusterResourcesRecommender) IsEnabled() bool {
+	if r == nil {
+		return false
+	}
+	return r.Enabled.Value()
+}
+
+// IsApply checks whether recommendations have to be applied to the hosts
+func (r *ClusterResourcesRecommender) IsApply() bool {
+	return r.IsEnabled() && (r.Mode == ResourcesRecommenderModeApply)
+}
+
+// GetWindow gets duration of usage history
+func (r *ClusterResourcesRecommender) GetWindow() time.Duration {
+	if r == nil {
+		return defaultResourcesRecommenderWindow
+	}
+	window, err := time.ParseDuration(r.Window)
+	if err != nil || window <= 0 {
+		return defaultResourcesRecommenderWindow
+	}
+	return window
+}
+
+// GetHeadroom gets headroom as a ratio
+func (r *ClusterResourcesRecommender) GetHeadroom() float64 {
+	if (r == nil) || !r.Headroom.HasValue() || (r.Headroom.IntValue() < 0) {
+		return float64(defaultResourcesRecommenderHeadroom) / 100
+	}
+	return float64(r.Headroom.IntValue()) / 100
+}
+
+// GetMinChange gets min change as a ratio
+func (r *ClusterResourcesRecommender) GetMinChange() float64 {
+	if (r == nil) || !r.MinChange.HasValue() || (r.MinChange.IntValue() < 0) {
+		return float64(defaultResourcesRecommenderMinChange) / 100
+	}
+	return float64(r.MinChange.IntValue()) / 100
+}
+
+// ResourcesRecommendation describes CPU and memory recommended for each host of the cluster
+type ResourcesRecommendation struct {
+	// Cluster the recommendation is made for
+	Cluster string `json:"cluster,omitempty"       yaml:"cluster,omitempty"`
+	// Time of the recommendation in RFC3339 format
+	Time string `json:"time,omitempty"          yaml:"time,omitempty"`
+	// Samples specifies number of usage samples the recommendation is based on
+	Samples int `json:"samples,omitempty"       yaml:"samples,omitempty"`
+	// CPURequest is recommended CPU request, such as 1500m
+	CPURequest string `json:"cpuRequest,omitempty"    yaml:"cpuRequest,omitempty"`
+	// CPULimit is recommended CPU limit
+	CPULimit string `json:"cpuLimit,omitempty"      yaml:"cpuLimit,omitempty"`
+	// MemoryRequest is recommended memory request, such as 4Gi
+	MemoryRequest string `json:"memoryRequest,omitempty" yaml:"memoryRequest,omitempty"`
+	// MemoryLimit is recommended memory limit
+	MemoryLimit string `json:"memoryLimit,omitempty"   yaml:"memoryLimit,omitempty"`
+	// Applied specifies time the recommendation was applied to the hosts in RFC3339 format.
+	// Empty in case recommendation is not applied yet
+	Applied string `json:"applied,omitempty"       yaml:"applied,omitempty"`
+}
+
+// IsApplied checks whether recommendation is applied already
+func (r *ResourcesRecommendation) IsApplied() bool {
+	if r == nil {
+		return false
+	}
+	return r.Applied != ""
+}
+
+// GetResourceRequirements gets recommendation as container resources. Malformed values are skipped
+func (r *ResourcesRecommendation) GetResourceRequirements() *core.ResourceRequirements {
+	if r == nil {
+		return nil
+	}
+	resources := &core.ResourceRequirements{
+		Requests: core.ResourceList{},
+		Limits:   core.ResourceList{},
+	}
+	set := func(list core.ResourceList, name core.ResourceName, value string) {
+		if quantity, err := resource.ParseQuantity(value); err == nil {
+			list[name] = quantity
+		}
+	}
+	set(resources.Requests, core.ResourceCPU, r.CPURequest)
+	set(resources.Requests, core.ResourceMemory, r.MemoryRequest)
+	set(resources.Limits, core.ResourceCPU, r.CPULimit)
+	set(resources.Limits, core.ResourceMemory, r.MemoryLimit)
+	return resources
+}
@@ -0,0 +1,186 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "slices"
+
+// Schema defines databases, tables, materialized views and dictionaries, which are created on every cluster
+// of the CHI and kept in sync with their definitions.
+// Every object can be specified either as SQL or as a structured definition
+type Schema struct {
+	Databases         []*SchemaDatabase         `json:"databases,omitempty"         yaml:"databases,omitempty"`
+	Tables            []*SchemaTable            `json:"tables,omitempty"            yaml:"tables,omitempty"`
+	MaterializedViews []*SchemaMaterializedView `json:"materializedViews,omitempty" yaml:"materializedViews,omitempty"`
+	Dictionaries      []*SchemaDictionary       `json:"dictionaries,omitempty"      yaml:"dictionaries,omitempty"`
+}
+
+// SchemaDatabase defines database
+type SchemaDatabase struct {
+	Name string `json:"name,omitempty"   yaml:"name,omitempty"`
+	// Engine specifies database engine, e.g. Atomic or Replicated('/clickhouse/databases/db', '{shard}', '{replica}')
+	Engine string `json:"engine,omitempty" yaml:"engine,omitempty"`
+	// SQL specifies complete CREATE DATABASE statement. Structured fields are ignored when SQL is specified
+	SQL string `json:"sql,omitempty"    yaml:"sql,omitempty"`
+}
+
+// SchemaColumn defines column of a table or a dictionary
+type SchemaColumn struct {
+	Name string `json:"name,omitempty"    yaml:"name,omitempty"`
+	Type string `json:"type,omitempty"    yaml:"type,omitempty"`
+	// Default specifies default expression, e.g. DEFAULT now() or MATERIALIZED toDate(ts)
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+	Codec   string `json:"codec,omitempty"   yaml:"codec,omitempty"`
+	Comment string `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// SchemaTable defines table
+type SchemaTable struct {
+	Database string `json:"database,omitempty"    yaml:"database,omitempty"`
+	Name     string `json:"name,omitempty"        yaml:"name,omitempty"`
+	// SQL specifies complete CREATE TABLE statement. Structured fields are ignored when SQL is specified
+	SQL         string            `json:"sql,omitempty"         yaml:"sql,omitempty"`
+	Columns     []*SchemaColumn   `json:"columns,omitempty"     yaml:"columns,omitempty"`
+	Engine      string            `json:"engine,omitempty"      yaml:"engine,omitempty"`
+	PartitionBy string            `json:"partitionBy,omitempty" yaml:"partitionBy,omitempty"`
+	OrderBy     string            `json:"orderBy,omitempty"     yaml:"orderBy,omitempty"`
+	PrimaryKey  string            `json:"primaryKey,omitempty"  yaml:"primaryKey,omitempty"`
+	TTL         string            `json:"ttl,omitempty"         yaml:"ttl,omitempty"`
+	Settings    map[string]string `json:"settings,omitempty"    yaml:"settings,omitempty"`
+}
+
+// SchemaMaterializedView defines materialized view
+type SchemaMaterializedView struct {
+	Database string `json:"database,omitempty" yaml:"database,omitempty"`
+	Name     string `json:"name,omitempty"     yaml:"name,omitempty"`
+	// SQL specifies complete CREATE MATERIALIZED VIEW statement. Structured fields are ignored when SQL is specified
+	SQL string `json:"sql,omitempty"      yaml:"sql,omitempty"`
+	// To specifies table in 'database.table' form the view writes to
+	To string `json:"to,omitempty"       yaml:"to,omitempty"`
+	// Query specifies SELECT query of the view
+	Query string `json:"query,omitempty"    yaml:"query,omitempty"`
+}
+
+// SchemaDictionary defines dictionary
+type SchemaDictionary struct {
+	Database string `json:"database,omitempty"   yaml:"database,omitempty"`
+	Name     string `json:"name,omitempty"       yaml:"name,omitempty"`
+	// SQL specifies complete CREATE DICTIONARY statement. Structured fields are ignored when SQL is specified
+	SQL        string          `json:"sql,omitempty"        yaml:"sql,omitempty"`
+	Columns    []*SchemaColumn `json:"columns,omitempty"    yaml:"columns,omitempty"`
+	PrimaryKey string          `json:"primaryKey,omitempty" yaml:"primaryKey,omitempty"`
+	// Source specifies dictionary source, e.g. CLICKHOUSE(TABLE 'table')
+	Source string `json:"source,omitempty"     yaml:"source,omitempty"`
+	// Layout specifies dictionary layout, e.g. HASHED()
+	Layout string `json:"layout,omitempty"     yaml:"layout,omitempty"`
+	// Lifetime specifies dictionary lifetime, e.g. MIN 0 MAX 300
+	Lifetime string `json:"lifetime,omitempty"   yaml:"lifetime,omitempty"`
+}
+
+// IsEmpty checks whether schema has no objects specified
+func (s *Schema) IsEmpty() bool {
+	if s == nil {
+		return true
+	}
+	return len(s.Databases)+len(s.Tables)+len(s.MaterializedViews)+len(s.Dictionaries) == 0
+}
+
+// MergeFrom merges from specified source. Objects of the source replace objects with the same name
+func (s *Schema) MergeFrom(from *Schema) *Schema {
+	if from == nil {
+		return s
+	}
+	if s == nil {
+		return from.DeepCopy()
+	}
+
+	for _, database := range from.Databases {
+		s.Databases = mergeSchemaObject(s.Databases, database.DeepCopy(), func(a, b *SchemaDatabase) bool {
+			return a.Name == b.Name
+		})
+	}
+	for _, table := range from.Tables {
+		s.Tables = mergeSchemaObject(s.Tables, table.DeepCopy(), func(a, b *SchemaTable) bool {
+			return (a.Database == b.Database) && (a.Name == b.Name)
+		})
+	}
+	for _, view := range from.MaterializedViews {
+		s.MaterializedViews = mergeSchemaObject(s.MaterializedViews, view.DeepCopy(), func(a, b *SchemaMaterializedView) bool {
+			return (a.Database == b.Database) && (a.Name == b.Name)
+		})
+	}
+	for _, dictionary := range from.Dictionaries {
+		s.Dictionaries = mergeSchemaObject(s.Dictionaries, dictionary.DeepCopy(), func(a, b *SchemaDictionary) bool {
+			return (a.Database == b.Database) && (a.Name == b.Name)
+		})
+	}
+	return s
+}
+
+// mergeSchemaObject replaces equal object in the list or appends the object to the list
+func mergeSchemaObject[T any](list []*T, object *T, equal func(a, b *T) bool) []*T {
+	for i := range list {
+		if equal(list[i], object) {
+			list[i] = object
+			return list
+		}
+	}
+	return append(list, object)
+}
+
+// Possible schema statuses
+const (
+	SchemaStatusInSync  = "InSync"
+	SchemaStatusDrifted = "Drifted"
+	SchemaStatusFailed  = "Failed"
+)
+
+// Possible schema object kinds
+const (
+	SchemaObjectDatabase         = "database"
+	SchemaObjectTable            = "table"
+	SchemaObjectMaterializedView = "materializedView"
+	SchemaObjectDictionary       = "dictionary"
+)
+
+// SchemaStatus describes state of the declared schema on the cluster
+type SchemaStatus struct {
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	Status  string `json:"status,omitempty"  yaml:"status,omitempty"`
+	// Drift lists objects which differ from their definitions
+	Drift  []*SchemaDrift `json:"drift,omitempty"   yaml:"drift,omitempty"`
+	Errors []string       `json:"errors,omitempty"  yaml:"errors,omitempty"`
+}
+
+// SchemaDrift describes differences of an object from its definition
+type SchemaDrift struct {
+	Kind   string `json:"kind,omitempty"        yaml:"kind,omitempty"`
+	Object string `json:"object,omitempty"      yaml:"object,omitempty"`
+	// Hosts lists hosts the object differs on the same way
+	Hosts       []string `json:"hosts,omitempty"       yaml:"hosts,omitempty"`
+	Differences []string `json:"differences,omitempty" yaml:"differences,omitempty"`
+}
+
+// AddDrift adds drift of the object on the host. Hosts the object differs on the same way on are reported together
+func (s *SchemaStatus) AddDrift(host string, drift *SchemaDrift) {
+	for _, d := range s.Drift {
+		if (d.Kind == drift.Kind) && (d.Object == drift.Object) && slices.Equal(d.Differences, drift.Differences) {
+			d.Hosts = append(d.Hosts, host)
+			return
+		}
+	}
+	drift = drift.DeepCopy()
+	drift.Hosts = []string{host}
+	s.Drift = append(s.Drift, drift)
+}
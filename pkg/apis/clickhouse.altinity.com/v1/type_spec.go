@@ -33,6 +33,9 @@ type ChiSpec struct {
 	Configuration          *Configuration    `json:"configuration,omitempty"          yaml:"configuration,omitempty"`
 	Templates              *Templates        `json:"templates,omitempty"              yaml:"templates,omitempty"`
 	UseTemplates           []*TemplateRef    `json:"useTemplates,omitempty"           yaml:"useTemplates,omitempty"`
+	Clone                  *Clone            `json:"clone,omitempty"                  yaml:"clone,omitempty"`
+	DisasterRecovery       *DisasterRecovery `json:"disasterRecovery,omitempty"       yaml:"disasterRecovery,omitempty"`
+	KubeContexts           []*KubeContext    `json:"kubeContexts,omitempty"           yaml:"kubeContexts,omitempty"`
 }
 
 // HasTaskID checks whether task id is specified
@@ -72,6 +75,40 @@ func (spec *ChiSpec) GetTroubleshoot() *types.StringBool {
 	return spec.Troubleshoot
 }
 
+// GetClone gets clone section
+func (spec *ChiSpec) GetClone() *Clone {
+	if spec == nil {
+		return nil
+	}
+	return spec.Clone
+}
+
+// GetDisasterRecovery gets disaster recovery section
+func (spec *ChiSpec) GetDisasterRecovery() *DisasterRecovery {
+	if spec == nil {
+		return nil
+	}
+	return spec.DisasterRecovery
+}
+
+// GetKubeContexts gets remote kube contexts
+func (spec *ChiSpec) GetKubeContexts() []*KubeContext {
+	if spec == nil {
+		return nil
+	}
+	return spec.KubeContexts
+}
+
+// GetKubeContext gets remote kube context by name
+func (spec *ChiSpec) GetKubeContext(name string) *KubeContext {
+	for _, kubeContext := range spec.GetKubeContexts() {
+		if kubeContext.GetName() == name {
+			return kubeContext
+		}
+	}
+	return nil
+}
+
 func (spec *ChiSpec) GetNamespaceDomainPattern() *types.String {
 	if spec == nil {
 		return (*types.String)(nil)
@@ -137,6 +174,12 @@ func (spec *ChiSpec) MergeFrom(from *ChiSpec, _type MergeType) {
 		if !spec.Suspend.HasValue() {
 			spec.Suspend = spec.Suspend.MergeFrom(from.Suspend)
 		}
+		if spec.Clone == nil {
+			spec.Clone = from.Clone.DeepCopy()
+		}
+		if spec.DisasterRecovery == nil {
+			spec.DisasterRecovery = from.DisasterRecovery.DeepCopy()
+		}
 	case MergeTypeOverrideByNonEmptyValues:
 		if from.HasTaskID() {
 			spec.TaskID = spec.TaskID.MergeFrom(from.TaskID)
@@ -159,6 +202,12 @@ func (spec *ChiSpec) MergeFrom(from *ChiSpec, _type MergeType) {
 		if from.Suspend.HasValue() {
 			spec.Suspend = spec.Suspend.MergeFrom(from.Suspend)
 		}
+		if from.Clone != nil {
+			spec.Clone = from.Clone.DeepCopy()
+		}
+		if from.DisasterRecovery != nil {
+			spec.DisasterRecovery = from.DisasterRecovery.DeepCopy()
+		}
 	}
 
 	spec.Templating = spec.Templating.MergeFrom(from.Templating, _type)
@@ -166,6 +215,7 @@ func (spec *ChiSpec) MergeFrom(from *ChiSpec, _type MergeType) {
 	spec.Defaults = spec.Defaults.MergeFrom(from.Defaults, _type)
 	spec.Configuration = spec.Configuration.MergeFrom(from.Configuration, _type)
 	spec.Templates = spec.Templates.MergeFrom(from.Templates, _type)
+	spec.KubeContexts = mergeKubeContexts(spec.KubeContexts, from.KubeContexts, _type)
 	// TODO may be it would be wiser to make more intelligent merge
 	spec.UseTemplates = append(spec.UseTemplates, from.UseTemplates...)
 }
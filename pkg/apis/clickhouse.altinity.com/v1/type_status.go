@@ -18,6 +18,9 @@ import (
 	"sort"
 	"sync"
 
+	apiMeta "k8s.io/apimachinery/pkg/api/meta"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
 	"github.com/altinity/clickhouse-operator/pkg/util"
 	"github.com/altinity/clickhouse-operator/pkg/version"
@@ -44,40 +47,50 @@ const (
 // that application logic sticks to the synchronized getter/setters by auditing whether all explicit Go field-level
 // accesses are strictly within _this_ source file OR the generated deep copy source file.
 type Status struct {
-	CHOpVersion              string                  `json:"chop-version,omitempty"             yaml:"chop-version,omitempty"`
-	CHOpCommit               string                  `json:"chop-commit,omitempty"              yaml:"chop-commit,omitempty"`
-	CHOpDate                 string                  `json:"chop-date,omitempty"                yaml:"chop-date,omitempty"`
-	CHOpIP                   string                  `json:"chop-ip,omitempty"                  yaml:"chop-ip,omitempty"`
-	ClustersCount            int                     `json:"clusters,omitempty"                 yaml:"clusters,omitempty"`
-	ShardsCount              int                     `json:"shards,omitempty"                   yaml:"shards,omitempty"`
-	ReplicasCount            int                     `json:"replicas,omitempty"                 yaml:"replicas,omitempty"`
-	HostsCount               int                     `json:"hosts,omitempty"                    yaml:"hosts,omitempty"`
-	Status                   string                  `json:"status,omitempty"                   yaml:"status,omitempty"`
-	TaskID                   string                  `json:"taskID,omitempty"                   yaml:"taskID,omitempty"`
-	TaskIDsStarted           []string                `json:"taskIDsStarted,omitempty"           yaml:"taskIDsStarted,omitempty"`
-	TaskIDsCompleted         []string                `json:"taskIDsCompleted,omitempty"         yaml:"taskIDsCompleted,omitempty"`
-	Action                   string                  `json:"action,omitempty"                   yaml:"action,omitempty"`
-	Actions                  []string                `json:"actions,omitempty"                  yaml:"actions,omitempty"`
-	Error                    string                  `json:"error,omitempty"                    yaml:"error,omitempty"`
-	Errors                   []string                `json:"errors,omitempty"                   yaml:"errors,omitempty"`
-	HostsUpdatedCount        int                     `json:"hostsUpdated,omitempty"             yaml:"hostsUpdated,omitempty"`
-	HostsAddedCount          int                     `json:"hostsAdded,omitempty"               yaml:"hostsAdded,omitempty"`
-	HostsUnchangedCount      int                     `json:"hostsUnchanged,omitempty"           yaml:"hostsUnchanged,omitempty"`
-	HostsFailedCount         int                     `json:"hostsFailed,omitempty"              yaml:"hostsFailed,omitempty"`
-	HostsCompletedCount      int                     `json:"hostsCompleted,omitempty"           yaml:"hostsCompleted,omitempty"`
-	HostsDeletedCount        int                     `json:"hostsDeleted,omitempty"             yaml:"hostsDeleted,omitempty"`
-	HostsDeleteCount         int                     `json:"hostsDelete,omitempty"              yaml:"hostsDelete,omitempty"`
-	Pods                     []string                `json:"pods,omitempty"                     yaml:"pods,omitempty"`
-	PodIPs                   []string                `json:"pod-ips,omitempty"                  yaml:"pod-ips,omitempty"`
-	FQDNs                    []string                `json:"fqdns,omitempty"                    yaml:"fqdns,omitempty"`
-	Endpoint                 string                  `json:"endpoint,omitempty"                 yaml:"endpoint,omitempty"`
-	Endpoints                []string                `json:"endpoints,omitempty"                yaml:"endpoints,omitempty"`
-	NormalizedCR             *ClickHouseInstallation `json:"normalized,omitempty"               yaml:"normalized,omitempty"`
-	NormalizedCRCompleted    *ClickHouseInstallation `json:"normalizedCompleted,omitempty"      yaml:"normalizedCompleted,omitempty"`
-	ActionPlan               *ActionPlan             `json:"actionPlan,omitempty"               yaml:"actionPlan,omitempty"`
-	HostsWithTablesCreated   []string                `json:"hostsWithTablesCreated,omitempty"   yaml:"hostsWithTablesCreated,omitempty"`
-	HostsWithReplicaCaughtUp []string                `json:"hostsWithReplicaCaughtUp,omitempty" yaml:"hostsWithReplicaCaughtUp,omitempty"`
-	UsedTemplates            []*TemplateRef          `json:"usedTemplates,omitempty"            yaml:"usedTemplates,omitempty"`
+	CHOpVersion              string                     `json:"chop-version,omitempty"             yaml:"chop-version,omitempty"`
+	CHOpCommit               string                     `json:"chop-commit,omitempty"              yaml:"chop-commit,omitempty"`
+	CHOpDate                 string                     `json:"chop-date,omitempty"                yaml:"chop-date,omitempty"`
+	CHOpIP                   string                     `json:"chop-ip,omitempty"                  yaml:"chop-ip,omitempty"`
+	ClustersCount            int                        `json:"clusters,omitempty"                 yaml:"clusters,omitempty"`
+	ShardsCount              int                        `json:"shards,omitempty"                   yaml:"shards,omitempty"`
+	ReplicasCount            int                        `json:"replicas,omitempty"                 yaml:"replicas,omitempty"`
+	HostsCount               int                        `json:"hosts,omitempty"                    yaml:"hosts,omitempty"`
+	Status                   string                     `json:"status,omitempty"                   yaml:"status,omitempty"`
+	TaskID                   string                     `json:"taskID,omitempty"                   yaml:"taskID,omitempty"`
+	TaskIDsStarted           []string                   `json:"taskIDsStarted,omitempty"           yaml:"taskIDsStarted,omitempty"`
+	TaskIDsCompleted         []string                   `json:"taskIDsCompleted,omitempty"         yaml:"taskIDsCompleted,omitempty"`
+	Action                   string                     `json:"action,omitempty"                   yaml:"action,omitempty"`
+	Actions                  []string                   `json:"actions,omitempty"                  yaml:"actions,omitempty"`
+	Error                    string                     `json:"error,omitempty"                    yaml:"error,omitempty"`
+	Errors                   []string                   `json:"errors,omitempty"                   yaml:"errors,omitempty"`
+	HostsUpdatedCount        int                        `json:"hostsUpdated,omitempty"             yaml:"hostsUpdated,omitempty"`
+	HostsAddedCount          int                        `json:"hostsAdded,omitempty"               yaml:"hostsAdded,omitempty"`
+	HostsUnchangedCount      int                        `json:"hostsUnchanged,omitempty"           yaml:"hostsUnchanged,omitempty"`
+	HostsFailedCount         int                        `json:"hostsFailed,omitempty"              yaml:"hostsFailed,omitempty"`
+	HostsCompletedCount      int                        `json:"hostsCompleted,omitempty"           yaml:"hostsCompleted,omitempty"`
+	HostsDeletedCount        int                        `json:"hostsDeleted,omitempty"             yaml:"hostsDeleted,omitempty"`
+	HostsDeleteCount         int                        `json:"hostsDelete,omitempty"              yaml:"hostsDelete,omitempty"`
+	Pods                     []string                   `json:"pods,omitempty"                     yaml:"pods,omitempty"`
+	PodIPs                   []string                   `json:"pod-ips,omitempty"                  yaml:"pod-ips,omitempty"`
+	FQDNs                    []string                   `json:"fqdns,omitempty"                    yaml:"fqdns,omitempty"`
+	Endpoint                 string                     `json:"endpoint,omitempty"                 yaml:"endpoint,omitempty"`
+	Endpoints                []string                   `json:"endpoints,omitempty"                yaml:"endpoints,omitempty"`
+	NormalizedCR             *ClickHouseInstallation    `json:"normalized,omitempty"               yaml:"normalized,omitempty"`
+	NormalizedCRCompleted    *ClickHouseInstallation    `json:"normalizedCompleted,omitempty"      yaml:"normalizedCompleted,omitempty"`
+	ActionPlan               *ActionPlan                `json:"actionPlan,omitempty"               yaml:"actionPlan,omitempty"`
+	HostsWithTablesCreated   []string                   `json:"hostsWithTablesCreated,omitempty"   yaml:"hostsWithTablesCreated,omitempty"`
+	HostsWithReplicaCaughtUp []string                   `json:"hostsWithReplicaCaughtUp,omitempty" yaml:"hostsWithReplicaCaughtUp,omitempty"`
+	UsedTemplates            []*TemplateRef             `json:"usedTemplates,omitempty"            yaml:"usedTemplates,omitempty"`
+	Rebalance                []*RebalanceStatus         `json:"rebalance,omitempty"                yaml:"rebalance,omitempty"`
+	Schema                   []*SchemaStatus            `json:"schema,omitempty"                   yaml:"schema,omitempty"`
+	Autoscaling              []*AutoscalingEvent        `json:"autoscaling,omitempty"              yaml:"autoscaling,omitempty"`
+	Recommendations          []*ResourcesRecommendation `json:"recommendations,omitempty"          yaml:"recommendations,omitempty"`
+	ReplicaGC                []*ReplicaGCReport         `json:"replicaGC,omitempty"                yaml:"replicaGC,omitempty"`
+	Clone                    []*CloneStatus             `json:"clone,omitempty"                    yaml:"clone,omitempty"`
+	DisasterRecovery         *DisasterRecoveryStatus    `json:"disasterRecovery,omitempty"         yaml:"disasterRecovery,omitempty"`
+	Maintenance              []*HostMaintenance         `json:"maintenance,omitempty"              yaml:"maintenance,omitempty"`
+	DeferredActions          []*DeferredAction          `json:"deferredActions,omitempty"          yaml:"deferredActions,omitempty"`
+	Conditions               []meta.Condition           `json:"conditions,omitempty"               yaml:"conditions,omitempty"`
 
 	mu sync.RWMutex `json:"-" yaml:"-"`
 }
@@ -199,6 +212,157 @@ func (s *Status) PushUsedTemplate(templateRefs ...*TemplateRef) {
 	}
 }
 
+// SetRebalance sets rebalance status of the cluster
+func (s *Status) SetRebalance(rebalance *RebalanceStatus) {
+	doWithWriteLock(s, func(s *Status) {
+		for i := range s.Rebalance {
+			if s.Rebalance[i].Cluster == rebalance.Cluster {
+				s.Rebalance[i] = rebalance.DeepCopy()
+				return
+			}
+		}
+		s.Rebalance = append(s.Rebalance, rebalance.DeepCopy())
+	})
+}
+
+// DeleteRebalance deletes rebalance status of the cluster
+func (s *Status) DeleteRebalance(cluster string) {
+	doWithWriteLock(s, func(s *Status) {
+		var rebalance []*RebalanceStatus
+		for _, r := range s.Rebalance {
+			if r.Cluster != cluster {
+				rebalance = append(rebalance, r)
+			}
+		}
+		s.Rebalance = rebalance
+	})
+}
+
+// PushAutoscalingEvent pushes scaling decision to the history, keeping the history limited
+func (s *Status) PushAutoscalingEvent(event *AutoscalingEvent) {
+	doWithWriteLock(s, func(s *Status) {
+		s.Autoscaling = append(s.Autoscaling, event.DeepCopy())
+		if len(s.Autoscaling) > autoscalingHistoryLength {
+			s.Autoscaling = s.Autoscaling[len(s.Autoscaling)-autoscalingHistoryLength:]
+		}
+	})
+}
+
+// SetResourcesRecommendation sets resources recommendation of the cluster
+func (s *Status) SetResourcesRecommendation(recommendation *ResourcesRecommendation) {
+	doWithWriteLock(s, func(s *Status) {
+		for i := range s.Recommendations {
+			if s.Recommendations[i].Cluster == recommendation.Cluster {
+				s.Recommendations[i] = recommendation.DeepCopy()
+				return
+			}
+		}
+		s.Recommendations = append(s.Recommendations, recommendation.DeepCopy())
+	})
+}
+
+// SetReplicaGCReport sets report of the replica garbage collector of the cluster
+func (s *Status) SetReplicaGCReport(report *ReplicaGCReport) {
+	doWithWriteLock(s, func(s *Status) {
+		for i := range s.ReplicaGC {
+			if s.ReplicaGC[i].Cluster == report.Cluster {
+				s.ReplicaGC[i] = report.DeepCopy()
+				return
+			}
+		}
+		s.ReplicaGC = append(s.ReplicaGC, report.DeepCopy())
+	})
+}
+
+// SetClone sets clone status of the cluster
+func (s *Status) SetClone(clone *CloneStatus) {
+	doWithWriteLock(s, func(s *Status) {
+		for i := range s.Clone {
+			if s.Clone[i].Cluster == clone.Cluster {
+				s.Clone[i] = clone.DeepCopy()
+				return
+			}
+		}
+		s.Clone = append(s.Clone, clone.DeepCopy())
+	})
+}
+
+// SetDisasterRecovery sets disaster recovery status
+func (s *Status) SetDisasterRecovery(dr *DisasterRecoveryStatus) {
+	doWithWriteLock(s, func(s *Status) {
+		s.DisasterRecovery = dr.DeepCopy()
+	})
+}
+
+// SetMaintenance sets list of hosts in maintenance
+func (s *Status) SetMaintenance(maintenance []*HostMaintenance) {
+	doWithWriteLock(s, func(s *Status) {
+		s.Maintenance = nil
+		for _, m := range maintenance {
+			s.Maintenance = append(s.Maintenance, m.DeepCopy())
+		}
+	})
+}
+
+// SetDeferredAction sets actions deferred on the host
+func (s *Status) SetDeferredAction(action *DeferredAction) {
+	doWithWriteLock(s, func(s *Status) {
+		for i := range s.DeferredActions {
+			if s.DeferredActions[i].Host == action.Host {
+				s.DeferredActions[i] = action.DeepCopy()
+				return
+			}
+		}
+		s.DeferredActions = append(s.DeferredActions, action.DeepCopy())
+	})
+}
+
+// DeleteDeferredAction deletes actions deferred on the host
+func (s *Status) DeleteDeferredAction(host string) {
+	doWithWriteLock(s, func(s *Status) {
+		var actions []*DeferredAction
+		for _, action := range s.DeferredActions {
+			if action.Host != host {
+				actions = append(actions, action)
+			}
+		}
+		s.DeferredActions = actions
+	})
+}
+
+// SetCondition sets condition of the specified type. Transition time is updated only in case condition status changes
+func (s *Status) SetCondition(condition meta.Condition) {
+	doWithWriteLock(s, func(s *Status) {
+		apiMeta.SetStatusCondition(&s.Conditions, condition)
+	})
+}
+
+// SetSchema sets schema status of the cluster
+func (s *Status) SetSchema(schema *SchemaStatus) {
+	doWithWriteLock(s, func(s *Status) {
+		for i := range s.Schema {
+			if s.Schema[i].Cluster == schema.Cluster {
+				s.Schema[i] = schema.DeepCopy()
+				return
+			}
+		}
+		s.Schema = append(s.Schema, schema.DeepCopy())
+	})
+}
+
+// DeleteSchema deletes schema status of the cluster
+func (s *Status) DeleteSchema(cluster string) {
+	doWithWriteLock(s, func(s *Status) {
+		var schema []*SchemaStatus
+		for _, status := range s.Schema {
+			if status.Cluster != cluster {
+				schema = append(schema, status)
+			}
+		}
+		s.Schema = schema
+	})
+}
+
 // GetUsedTemplatesCount gets used templates count
 func (s *Status) GetUsedTemplatesCount() int {
 	return getIntWithReadLock(s, func(s *Status) int {
@@ -354,6 +518,16 @@ func prepareOptions(opts types.CopyStatusOptions) types.CopyStatusOptions {
 		opts.Copy.Errors = true
 		opts.Copy.HostsWithTablesCreated = true
 		opts.Copy.UsedTemplates = true
+		opts.Copy.Rebalance = true
+		opts.Copy.Autoscaling = true
+		opts.Copy.Recommendations = true
+		opts.Copy.ReplicaGC = true
+		opts.Copy.Clone = true
+		opts.Copy.DisasterRecovery = true
+		opts.Copy.Maintenance = true
+		opts.Copy.DeferredActions = true
+		opts.Copy.Conditions = true
+		opts.Copy.Schema = true
 	}
 
 	if opts.FieldGroupActions {
@@ -399,6 +573,16 @@ func prepareOptions(opts types.CopyStatusOptions) types.CopyStatusOptions {
 		opts.Copy.NormalizedCR = true
 		opts.Copy.ActionPlan = true
 		opts.Copy.UsedTemplates = true
+		opts.Copy.Rebalance = true
+		opts.Copy.Autoscaling = true
+		opts.Copy.Recommendations = true
+		opts.Copy.ReplicaGC = true
+		opts.Copy.Clone = true
+		opts.Copy.DisasterRecovery = true
+		opts.Copy.Maintenance = true
+		opts.Copy.DeferredActions = true
+		opts.Copy.Conditions = true
+		opts.Copy.Schema = true
 	}
 
 	if opts.FieldGroupNormalized {
@@ -438,6 +622,16 @@ func prepareOptions(opts types.CopyStatusOptions) types.CopyStatusOptions {
 		opts.Copy.NormalizedCRCompleted = true
 		opts.Copy.ActionPlan = true
 		opts.Copy.UsedTemplates = true
+		opts.Copy.Rebalance = true
+		opts.Copy.Autoscaling = true
+		opts.Copy.Recommendations = true
+		opts.Copy.ReplicaGC = true
+		opts.Copy.Clone = true
+		opts.Copy.DisasterRecovery = true
+		opts.Copy.Maintenance = true
+		opts.Copy.DeferredActions = true
+		opts.Copy.Conditions = true
+		opts.Copy.Schema = true
 	}
 
 	return opts
@@ -562,6 +756,63 @@ func (s *Status) CopyFrom(f *Status, opts types.CopyStatusOptions) {
 					s.UsedTemplates = append(s.UsedTemplates, from.UsedTemplates...)
 				}
 			}
+			if opts.Copy.Rebalance {
+				s.Rebalance = nil
+				for _, rebalance := range from.Rebalance {
+					s.Rebalance = append(s.Rebalance, rebalance.DeepCopy())
+				}
+			}
+			if opts.Copy.Schema {
+				s.Schema = nil
+				for _, schema := range from.Schema {
+					s.Schema = append(s.Schema, schema.DeepCopy())
+				}
+			}
+			if opts.Copy.Autoscaling {
+				s.Autoscaling = nil
+				for _, event := range from.Autoscaling {
+					s.Autoscaling = append(s.Autoscaling, event.DeepCopy())
+				}
+			}
+			if opts.Copy.Recommendations {
+				s.Recommendations = nil
+				for _, recommendation := range from.Recommendations {
+					s.Recommendations = append(s.Recommendations, recommendation.DeepCopy())
+				}
+			}
+			if opts.Copy.ReplicaGC {
+				s.ReplicaGC = nil
+				for _, report := range from.ReplicaGC {
+					s.ReplicaGC = append(s.ReplicaGC, report.DeepCopy())
+				}
+			}
+			if opts.Copy.Clone {
+				s.Clone = nil
+				for _, clone := range from.Clone {
+					s.Clone = append(s.Clone, clone.DeepCopy())
+				}
+			}
+			if opts.Copy.DisasterRecovery {
+				s.DisasterRecovery = from.DisasterRecovery.DeepCopy()
+			}
+			if opts.Copy.Maintenance {
+				s.Maintenance = nil
+				for _, maintenance := range from.Maintenance {
+					s.Maintenance = append(s.Maintenance, maintenance.DeepCopy())
+				}
+			}
+			if opts.Copy.DeferredActions {
+				s.DeferredActions = nil
+				for _, action := range from.DeferredActions {
+					s.DeferredActions = append(s.DeferredActions, action.DeepCopy())
+				}
+			}
+			if opts.Copy.Conditions {
+				// Merge conditions in order to keep transition time of the conditions not changed
+				for _, condition := range from.Conditions {
+					apiMeta.SetStatusCondition(&s.Conditions, *condition.DeepCopy())
+				}
+			}
 		})
 	})
 }
@@ -798,6 +1049,131 @@ func (s *Status) GetHostsWithReplicaCaughtUp() []string {
 	})
 }
 
+// GetRebalance gets copy of the rebalance status of the cluster
+func (s *Status) GetRebalance(cluster string) *RebalanceStatus {
+	var rebalance *RebalanceStatus
+	doWithReadLock(s, func(s *Status) {
+		for _, r := range s.Rebalance {
+			if r.Cluster == cluster {
+				rebalance = r.DeepCopy()
+				return
+			}
+		}
+	})
+	return rebalance
+}
+
+// GetLastAutoscalingEvent gets copy of the latest scaling decision made for the cluster
+func (s *Status) GetLastAutoscalingEvent(cluster string) *AutoscalingEvent {
+	var event *AutoscalingEvent
+	doWithReadLock(s, func(s *Status) {
+		for i := len(s.Autoscaling) - 1; i >= 0; i-- {
+			if s.Autoscaling[i].Cluster == cluster {
+				event = s.Autoscaling[i].DeepCopy()
+				return
+			}
+		}
+	})
+	return event
+}
+
+// GetResourcesRecommendation gets copy of the resources recommendation of the cluster
+func (s *Status) GetResourcesRecommendation(cluster string) *ResourcesRecommendation {
+	var recommendation *ResourcesRecommendation
+	doWithReadLock(s, func(s *Status) {
+		for _, r := range s.Recommendations {
+			if r.Cluster == cluster {
+				recommendation = r.DeepCopy()
+				return
+			}
+		}
+	})
+	return recommendation
+}
+
+// GetReplicaGCReport gets copy of the report of the replica garbage collector of the cluster
+func (s *Status) GetReplicaGCReport(cluster string) *ReplicaGCReport {
+	var report *ReplicaGCReport
+	doWithReadLock(s, func(s *Status) {
+		for _, r := range s.ReplicaGC {
+			if r.Cluster == cluster {
+				report = r.DeepCopy()
+				return
+			}
+		}
+	})
+	return report
+}
+
+// GetClone gets copy of the clone status of the cluster
+func (s *Status) GetClone(cluster string) *CloneStatus {
+	var clone *CloneStatus
+	doWithReadLock(s, func(s *Status) {
+		for _, c := range s.Clone {
+			if c.Cluster == cluster {
+				clone = c.DeepCopy()
+				return
+			}
+		}
+	})
+	return clone
+}
+
+// GetDisasterRecovery gets copy of the disaster recovery status
+func (s *Status) GetDisasterRecovery() *DisasterRecoveryStatus {
+	var dr *DisasterRecoveryStatus
+	doWithReadLock(s, func(s *Status) {
+		dr = s.DisasterRecovery.DeepCopy()
+	})
+	return dr
+}
+
+// GetMaintenance gets copy of the maintenance record of the host
+func (s *Status) GetMaintenance(host string) *HostMaintenance {
+	var maintenance *HostMaintenance
+	doWithReadLock(s, func(s *Status) {
+		for _, m := range s.Maintenance {
+			if m.Host == host {
+				maintenance = m.DeepCopy()
+				return
+			}
+		}
+	})
+	return maintenance
+}
+
+// GetDeferredAction gets copy of actions deferred on the host
+func (s *Status) GetDeferredAction(host string) *DeferredAction {
+	var action *DeferredAction
+	doWithReadLock(s, func(s *Status) {
+		for _, a := range s.DeferredActions {
+			if a.Host == host {
+				action = a.DeepCopy()
+				return
+			}
+		}
+	})
+	return action
+}
+
+// HasDeferredActions checks whether there are actions deferred on any host
+func (s *Status) HasDeferredActions() bool {
+	return getIntWithReadLock(s, func(s *Status) int {
+		return len(s.DeferredActions)
+	}) > 0
+}
+
+// GetCondition gets copy of the condition of the specified type
+func (s *Status) GetCondition(conditionType string) *meta.Condition {
+	var condition *meta.Condition
+	doWithReadLock(s, func(s *Status) {
+		if c := apiMeta.FindStatusCondition(s.Conditions, conditionType); c != nil {
+			condition = c.DeepCopy()
+		}
+	})
+	return condition
+}
+
 // Begin helpers
 
 func doWithWriteLock(s *Status, f func(*Status)) {
@@ -894,3 +1270,17 @@ func pushTaskIDCompletedNoSync(s *Status) {
 		s.TaskIDsCompleted = s.TaskIDsCompleted[:maxTaskIDs]
 	}
 }
+
+// GetSchema gets copy of the schema status of the cluster
+func (s *Status) GetSchema(cluster string) *SchemaStatus {
+	var schema *SchemaStatus
+	doWithReadLock(s, func(s *Status) {
+		for _, status := range s.Schema {
+			if status.Cluster == cluster {
+				schema = status.DeepCopy()
+				return
+			}
+		}
+	})
+	return schema
+}
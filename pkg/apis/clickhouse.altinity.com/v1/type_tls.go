@@ -0,0 +1,119 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"time"
+
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+// TLS verification modes, as understood by the openSSL section of the config
+const (
+	TLSVerificationModeNone    = "none"
+	TLSVerificationModeRelaxed = "relaxed"
+	TLSVerificationModeStrict  = "strict"
+)
+
+// Defaults of the certificates provisioned by the operator
+const (
+	TLSDefaultValidity    = 365 * 24 * time.Hour
+	TLSDefaultRenewBefore = 30 * 24 * time.Hour
+)
+
+// TLS defines certificates provisioning, which is performed by the operator
+type TLS struct {
+	// Enabled specifies whether the operator has to provision CA and per-host certificates
+	Enabled *types.StringBool `json:"enabled,omitempty"          yaml:"enabled,omitempty"`
+	// Validity specifies duration certificates are issued for
+	Validity *types.String `json:"validity,omitempty"         yaml:"validity,omitempty"`
+	// RenewBefore specifies how long before expiry certificates are re-issued
+	RenewBefore *types.String `json:"renewBefore,omitempty"      yaml:"renewBefore,omitempty"`
+	// VerificationMode specifies how peers certificates are verified
+	VerificationMode *types.String `json:"verificationMode,omitempty" yaml:"verificationMode,omitempty"`
+}
+
+// IsEnabled checks whether certificates provisioning is enabled
+func (t *TLS) IsEnabled() bool {
+	if t == nil {
+		return false
+	}
+	return t.Enabled.IsTrue()
+}
+
+// GetValidity gets validity of the certificates
+func (t *TLS) GetValidity() time.Duration {
+	if t == nil {
+		return TLSDefaultValidity
+	}
+	return parseTLSDuration(t.Validity, TLSDefaultValidity)
+}
+
+// GetRenewBefore gets how long before expiry certificates are renewed
+func (t *TLS) GetRenewBefore() time.Duration {
+	if t == nil {
+		return TLSDefaultRenewBefore
+	}
+	return parseTLSDuration(t.RenewBefore, TLSDefaultRenewBefore)
+}
+
+// GetVerificationMode gets verification mode
+func (t *TLS) GetVerificationMode() string {
+	if t == nil || !t.VerificationMode.HasValue() {
+		return TLSVerificationModeRelaxed
+	}
+	return t.VerificationMode.Value()
+}
+
+// Normalize normalizes TLS with fallback to defaults in case values are unrecognized
+func (t *TLS) Normalize() *TLS {
+	if t == nil {
+		return nil
+	}
+	t.Enabled = t.Enabled.Normalize(false)
+	switch t.VerificationMode.Value() {
+	case TLSVerificationModeNone, TLSVerificationModeRelaxed, TLSVerificationModeStrict:
+	default:
+		t.VerificationMode = types.NewString(TLSVerificationModeRelaxed)
+	}
+	return t
+}
+
+// MergeFrom merges from specified source
+func (t *TLS) MergeFrom(from *TLS) *TLS {
+	if from == nil {
+		return t
+	}
+	if t == nil {
+		t = &TLS{}
+	}
+	t.Enabled = t.Enabled.MergeFrom(from.Enabled)
+	t.Validity = t.Validity.MergeFrom(from.Validity)
+	t.RenewBefore = t.RenewBefore.MergeFrom(from.RenewBefore)
+	t.VerificationMode = t.VerificationMode.MergeFrom(from.VerificationMode)
+	return t
+}
+
+// parseTLSDuration parses duration, falling back to the default value on empty or malformed value
+func parseTLSDuration(value *types.String, defaultValue time.Duration) time.Duration {
+	if !value.HasValue() {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value.Value())
+	if err != nil || duration <= 0 {
+		return defaultValue
+	}
+	return duration
+}
@@ -0,0 +1,177 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"math"
+	"strings"
+
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+// Possible upgrade canaries
+const (
+	// UpgradeCanaryHost upgrades the first host of the first shard before the rest of the cluster
+	UpgradeCanaryHost = "host"
+	// UpgradeCanaryShard upgrades the first shard before the rest of the cluster
+	UpgradeCanaryShard = "shard"
+)
+
+const (
+	defaultUpgradeWavePercent                 = 25
+	defaultUpgradeHealthGateTimeout           = 600
+	defaultUpgradeHealthGateMaxErrors         = 10
+	defaultUpgradeHealthGateMaxReplicaDelay   = 300
+	defaultUpgradeHealthGateVersionValidation = true
+)
+
+// defaultUpgradeHealthGateIgnoreErrors lists errors caused by clients rather than by the host itself,
+// they are not counted by the health gate unless ignored errors are specified explicitly
+var defaultUpgradeHealthGateIgnoreErrors = []string{
+	"UNKNOWN_TABLE",
+	"UNKNOWN_DATABASE",
+	"UNKNOWN_IDENTIFIER",
+	"UNKNOWN_FUNCTION",
+	"SYNTAX_ERROR",
+	"ILLEGAL_TYPE_OF_ARGUMENT",
+	"TYPE_MISMATCH",
+	"ACCESS_DENIED",
+	"AUTHENTICATION_FAILED",
+	"REQUIRED_PASSWORD",
+	"QUERY_WAS_CANCELLED",
+	"TIMEOUT_EXCEEDED",
+	"TOO_MANY_SIMULTANEOUS_QUERIES",
+	"QUOTA_EXCEEDED",
+}
+
+// ClusterUpgrade defines how ClickHouse version is upgraded in the cluster when image of the hosts is changed.
+// Canary is upgraded first, the rest of the shards are upgraded in waves afterwards.
+// Each stage has to pass health gate, otherwise hosts of the stage are rolled back and reconcile is stopped
+type ClusterUpgrade struct {
+	// Enabled turns on staged upgrade
+	Enabled *types.StringBool `json:"enabled,omitempty"     yaml:"enabled,omitempty"`
+	// Canary specifies what is upgraded first, either host or shard
+	Canary string `json:"canary,omitempty"      yaml:"canary,omitempty"`
+	// WavePercent specifies percent of shards upgraded in each wave after the canary
+	WavePercent *types.Int32 `json:"wavePercent,omitempty" yaml:"wavePercent,omitempty"`
+	// HealthGate specifies checks each stage has to pass in order to proceed with the next stage
+	HealthGate *UpgradeHealthGate `json:"healthGate,omitempty"  yaml:"healthGate,omitempty"`
+}
+
+// UpgradeHealthGate defines checks upgraded hosts have to pass
+type UpgradeHealthGate struct {
+	// Timeout specifies number of seconds hosts have to pass checks within
+	Timeout *types.Int32 `json:"timeout,omitempty"             yaml:"timeout,omitempty"`
+	// ValidateVersion specifies whether ClickHouse version reported by the hosts has to match the image tag
+	ValidateVersion *types.StringBool `json:"validateVersion,omitempty"     yaml:"validateVersion,omitempty"`
+	// MaxErrors specifies max number of errors reported by the host in system.errors since the stage is started
+	MaxErrors *types.Int32 `json:"maxErrors,omitempty"           yaml:"maxErrors,omitempty"`
+	// IgnoreErrors lists names of the errors in system.errors which are not counted
+	IgnoreErrors []string `json:"ignoreErrors,omitempty"        yaml:"ignoreErrors,omitempty"`
+	// MaxReplicationDelay specifies max replication delay of the host, in seconds
+	MaxReplicationDelay *types.Int32 `json:"maxReplicationDelay,omitempty" yaml:"maxReplicationDelay,omitempty"`
+}
+
+// IsEnabled checks whether staged upgrade is enabled
+func (u *ClusterUpgrade) IsEnabled() bool {
+	if u == nil {
+		return false
+	}
+	return u.Enabled.Value()
+}
+
+// GetCanary gets canary
+func (u *ClusterUpgrade) GetCanary() string {
+	if (u == nil) || (u.Canary == "") {
+		return UpgradeCanaryHost
+	}
+	return u.Canary
+}
+
+// GetWavePercent gets wave percent
+func (u *ClusterUpgrade) GetWavePercent() int {
+	if (u == nil) || !u.WavePercent.HasValue() {
+		return defaultUpgradeWavePercent
+	}
+	return u.WavePercent.IntValue()
+}
+
+// GetWaveSize gets number of shards upgraded in each wave out of the specified number of shards.
+// At least one shard is upgraded in each wave
+func (u *ClusterUpgrade) GetWaveSize(shards int) int {
+	size := int(math.Ceil(float64(shards) * float64(u.GetWavePercent()) / 100))
+	if size < 1 {
+		return 1
+	}
+	return size
+}
+
+// GetHealthGate gets health gate
+func (u *ClusterUpgrade) GetHealthGate() *UpgradeHealthGate {
+	if u == nil {
+		return nil
+	}
+	return u.HealthGate
+}
+
+// GetTimeout gets timeout in seconds
+func (g *UpgradeHealthGate) GetTimeout() int {
+	if (g == nil) || !g.Timeout.HasValue() {
+		return defaultUpgradeHealthGateTimeout
+	}
+	return g.Timeout.IntValue()
+}
+
+// ShouldValidateVersion checks whether ClickHouse version has to be validated
+func (g *UpgradeHealthGate) ShouldValidateVersion() bool {
+	if (g == nil) || (g.ValidateVersion == nil) {
+		return defaultUpgradeHealthGateVersionValidation
+	}
+	return g.ValidateVersion.Value()
+}
+
+// GetMaxErrors gets max number of errors
+func (g *UpgradeHealthGate) GetMaxErrors() int {
+	if (g == nil) || !g.MaxErrors.HasValue() {
+		return defaultUpgradeHealthGateMaxErrors
+	}
+	return g.MaxErrors.IntValue()
+}
+
+// GetIgnoreErrors gets names of the errors which are not counted
+func (g *UpgradeHealthGate) GetIgnoreErrors() []string {
+	if (g == nil) || (len(g.IgnoreErrors) == 0) {
+		return defaultUpgradeHealthGateIgnoreErrors
+	}
+	return g.IgnoreErrors
+}
+
+// IsErrorIgnored checks whether the error with the specified name is not counted
+func (g *UpgradeHealthGate) IsErrorIgnored(name string) bool {
+	for _, ignored := range g.GetIgnoreErrors() {
+		if strings.EqualFold(ignored, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMaxReplicationDelay gets max replication delay in seconds
+func (g *UpgradeHealthGate) GetMaxReplicationDelay() int {
+	if (g == nil) || !g.MaxReplicationDelay.HasValue() {
+		return defaultUpgradeHealthGateMaxReplicaDelay
+	}
+	return g.MaxReplicationDelay.IntValue()
+}
@@ -0,0 +1,25 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+func TestClusterUpgradeGetWaveSize(t *testing.T) {
+	var upgrade *ClusterUpgrade
+	require.Equal(t, 1, upgrade.GetWaveSize(3))
+	require.Equal(t, 3, upgrade.GetWaveSize(9))
+	require.Equal(t, 1, upgrade.GetWaveSize(0))
+
+	upgrade = &ClusterUpgrade{
+		WavePercent: types.NewInt32(50),
+	}
+	require.Equal(t, 2, upgrade.GetWaveSize(3))
+	require.Equal(t, 5, upgrade.GetWaveSize(10))
+
+	upgrade.WavePercent = types.NewInt32(100)
+	require.Equal(t, 7, upgrade.GetWaveSize(7))
+}
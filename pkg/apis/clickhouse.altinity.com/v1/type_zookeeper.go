@@ -32,6 +32,21 @@ type ZookeeperConfig struct {
 	Root               string            `json:"root,omitempty"                 yaml:"root,omitempty"`
 	Identity           string            `json:"identity,omitempty"             yaml:"identity,omitempty"`
 	UseCompression     *types.StringBool `json:"use_compression,omitempty"      yaml:"use_compression,omitempty"`
+	Keeper             *KeeperRef        `json:"keeper,omitempty"               yaml:"keeper,omitempty"`
+}
+
+// KeeperRef refers to a ClickHouseKeeperInstallation, which hosts are used as zookeeper nodes
+type KeeperRef struct {
+	Name      string `json:"name,omitempty"      yaml:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+// HasKeeperRef checks whether config refers to a ClickHouseKeeperInstallation
+func (zkc *ZookeeperConfig) HasKeeperRef() bool {
+	if zkc == nil {
+		return false
+	}
+	return (zkc.Keeper != nil) && (zkc.Keeper.Name != "")
 }
 
 type ZookeeperNodes []ZookeeperNode
@@ -119,6 +134,9 @@ func (zkc *ZookeeperConfig) MergeFrom(from *ZookeeperConfig, _type MergeType) *Z
 		zkc.Identity = from.Identity
 	}
 	zkc.UseCompression = zkc.UseCompression.MergeFrom(from.UseCompression)
+	if from.HasKeeperRef() && ((_type == MergeTypeOverrideByNonEmptyValues) || !zkc.HasKeeperRef()) {
+		zkc.Keeper = from.Keeper.DeepCopy()
+	}
 
 	return zkc
 }
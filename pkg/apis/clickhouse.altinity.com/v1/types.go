@@ -53,6 +53,10 @@ type ClickHouseInstallationRuntime struct {
 	MinVersion        *swversion.SoftWareVersion `json:"-" yaml:"-"`
 	MaxVersion        *swversion.SoftWareVersion `json:"-" yaml:"-"`
 	ActionPlan        IActionPlan                `json:"-" yaml:"-"`
+	// TLSRootCA is a PEM-encoded CA certificate provisioned by the operator
+	TLSRootCA string `json:"-" yaml:"-"`
+	// DisasterRecovery keeps installations related by disaster recovery, provisioned by the operator
+	DisasterRecovery *DisasterRecoveryRuntime `json:"-" yaml:"-"`
 }
 
 func newClickHouseInstallationRuntime() *ClickHouseInstallationRuntime {
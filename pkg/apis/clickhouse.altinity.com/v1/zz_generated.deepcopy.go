@@ -27,6 +27,7 @@ import (
 	messagediffv1 "gopkg.in/d4l3k/messagediff.v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -154,6 +155,38 @@ func (in *AddonSpec) DeepCopy() *AddonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingEvent) DeepCopyInto(out *AutoscalingEvent) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingEvent.
+func (in *AutoscalingEvent) DeepCopy() *AutoscalingEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingMetric) DeepCopyInto(out *AutoscalingMetric) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingMetric.
+func (in *AutoscalingMetric) DeepCopy() *AutoscalingMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiClusterAddress) DeepCopyInto(out *ChiClusterAddress) {
 	*out = *in
@@ -222,6 +255,11 @@ func (in *ChiClusterRuntime) DeepCopyInto(out *ChiClusterRuntime) {
 		*out = new(ClickHouseInstallation)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.KeptShards != nil {
+		in, out := &in.KeptShards, &out.KeptShards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -245,7 +283,19 @@ func (in *ChiReconcile) DeepCopyInto(out *ChiReconcile) {
 	}
 	in.Macros.DeepCopyInto(&out.Macros)
 	out.Runtime = in.Runtime
+	out.StatefulSet = in.StatefulSet
 	in.Host.DeepCopyInto(&out.Host)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]*MaintenanceWindow, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(MaintenanceWindow)
+				**out = **in
+			}
+		}
+	}
 	return
 }
 
@@ -505,6 +555,27 @@ func (in *ChiSpec) DeepCopyInto(out *ChiSpec) {
 			}
 		}
 	}
+	if in.Clone != nil {
+		in, out := &in.Clone, &out.Clone
+		*out = new(Clone)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisasterRecovery != nil {
+		in, out := &in.DisasterRecovery, &out.DisasterRecovery
+		*out = new(DisasterRecovery)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeContexts != nil {
+		in, out := &in.KubeContexts, &out.KubeContexts
+		*out = make([]*KubeContext, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(KubeContext)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	return
 }
 
@@ -656,6 +727,11 @@ func (in *ClickHouseInstallationRuntime) DeepCopyInto(out *ClickHouseInstallatio
 		in, out := &in.MaxVersion, &out.MaxVersion
 		*out = (*in).DeepCopy()
 	}
+	if in.DisasterRecovery != nil {
+		in, out := &in.DisasterRecovery, &out.DisasterRecovery
+		*out = new(DisasterRecoveryRuntime)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -801,6 +877,68 @@ func (in *ClickHouseOperatorConfigurationList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Clone) DeepCopyInto(out *Clone) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(CloneSource)
+		**out = **in
+	}
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Clone.
+func (in *Clone) DeepCopy() *Clone {
+	if in == nil {
+		return nil
+	}
+	out := new(Clone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSource) DeepCopyInto(out *CloneSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSource.
+func (in *CloneSource) DeepCopy() *CloneSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneStatus) DeepCopyInto(out *CloneStatus) {
+	*out = *in
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneStatus.
+func (in *CloneStatus) DeepCopy() *CloneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -864,6 +1002,21 @@ func (in *Cluster) DeepCopyInto(out *Cluster) {
 		*out = new(ChiClusterLayout)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(ClusterAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourcesRecommender != nil {
+		in, out := &in.ResourcesRecommender, &out.ResourcesRecommender
+		*out = new(ClusterResourcesRecommender)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaGC != nil {
+		in, out := &in.ReplicaGC, &out.ReplicaGC
+		*out = new(ClusterReplicaGC)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Runtime.DeepCopyInto(&out.Runtime)
 	return
 }
@@ -878,11 +1031,115 @@ func (in *Cluster) DeepCopy() *Cluster {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAutoscaling) DeepCopyInto(out *ClusterAutoscaling) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(types.StringBool)
+		**out = **in
+	}
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(types.Int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(types.Int32)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]AutoscalingMetric, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAutoscaling.
+func (in *ClusterAutoscaling) DeepCopy() *ClusterAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDrain) DeepCopyInto(out *ClusterDrain) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(types.StringBool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDrain.
+func (in *ClusterDrain) DeepCopy() *ClusterDrain {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDrain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRebalance) DeepCopyInto(out *ClusterRebalance) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(types.StringBool)
+		**out = **in
+	}
+	if in.MinPartitionIdleTime != nil {
+		in, out := &in.MinPartitionIdleTime, &out.MinPartitionIdleTime
+		*out = new(types.Int32)
+		**out = **in
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRebalance.
+func (in *ClusterRebalance) DeepCopy() *ClusterRebalance {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRebalance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterReconcile) DeepCopyInto(out *ClusterReconcile) {
 	*out = *in
 	out.Runtime = in.Runtime
+	out.StatefulSet = in.StatefulSet
 	in.Host.DeepCopyInto(&out.Host)
+	if in.Rebalance != nil {
+		in, out := &in.Rebalance, &out.Rebalance
+		*out = new(ClusterRebalance)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(ClusterDrain)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(ClusterUpgrade)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -896,6 +1153,58 @@ func (in *ClusterReconcile) DeepCopy() *ClusterReconcile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterReplicaGC) DeepCopyInto(out *ClusterReplicaGC) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(types.StringBool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterReplicaGC.
+func (in *ClusterReplicaGC) DeepCopy() *ClusterReplicaGC {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterReplicaGC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourcesRecommender) DeepCopyInto(out *ClusterResourcesRecommender) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(types.StringBool)
+		**out = **in
+	}
+	if in.Headroom != nil {
+		in, out := &in.Headroom, &out.Headroom
+		*out = new(types.Int32)
+		**out = **in
+	}
+	if in.MinChange != nil {
+		in, out := &in.MinChange, &out.MinChange
+		*out = new(types.Int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourcesRecommender.
+func (in *ClusterResourcesRecommender) DeepCopy() *ClusterResourcesRecommender {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourcesRecommender)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSecret) DeepCopyInto(out *ClusterSecret) {
 	*out = *in
@@ -921,6 +1230,37 @@ func (in *ClusterSecret) DeepCopy() *ClusterSecret {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgrade) DeepCopyInto(out *ClusterUpgrade) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(types.StringBool)
+		**out = **in
+	}
+	if in.WavePercent != nil {
+		in, out := &in.WavePercent, &out.WavePercent
+		*out = new(types.Int32)
+		**out = **in
+	}
+	if in.HealthGate != nil {
+		in, out := &in.HealthGate, &out.HealthGate
+		*out = new(UpgradeHealthGate)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgrade.
+func (in *ClusterUpgrade) DeepCopy() *ClusterUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComparableAttributes) DeepCopyInto(out *ComparableAttributes) {
 	*out = *in
@@ -1018,6 +1358,16 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 			}
 		}
 	}
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(Schema)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLS)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1068,33 +1418,149 @@ func (in *Defaults) DeepCopy() *Defaults {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DistributedDDL) DeepCopyInto(out *DistributedDDL) {
+func (in *DeferredAction) DeepCopyInto(out *DeferredAction) {
 	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DistributedDDL.
-func (in *DistributedDDL) DeepCopy() *DistributedDDL {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeferredAction.
+func (in *DeferredAction) DeepCopy() *DeferredAction {
 	if in == nil {
 		return nil
 	}
-	out := new(DistributedDDL)
+	out := new(DeferredAction)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FillStatusParams) DeepCopyInto(out *FillStatusParams) {
+func (in *DisasterRecovery) DeepCopyInto(out *DisasterRecovery) {
 	*out = *in
-	if in.Pods != nil {
-		in, out := &in.Pods, &out.Pods
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Primary != nil {
+		in, out := &in.Primary, &out.Primary
+		*out = new(DisasterRecoveryPrimary)
+		**out = **in
 	}
-	if in.FQDNs != nil {
-		in, out := &in.FQDNs, &out.FQDNs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Promote != nil {
+		in, out := &in.Promote, &out.Promote
+		*out = new(types.StringBool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecovery.
+func (in *DisasterRecovery) DeepCopy() *DisasterRecovery {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryPrimary) DeepCopyInto(out *DisasterRecoveryPrimary) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryPrimary.
+func (in *DisasterRecoveryPrimary) DeepCopy() *DisasterRecoveryPrimary {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryPrimary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryRuntime) DeepCopyInto(out *DisasterRecoveryRuntime) {
+	*out = *in
+	if in.Primary != nil {
+		in, out := &in.Primary, &out.Primary
+		*out = new(ClickHouseInstallation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]*ClickHouseInstallation, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ClickHouseInstallation)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryRuntime.
+func (in *DisasterRecoveryRuntime) DeepCopy() *DisasterRecoveryRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryStatus) DeepCopyInto(out *DisasterRecoveryStatus) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryStatus.
+func (in *DisasterRecoveryStatus) DeepCopy() *DisasterRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DistributedDDL) DeepCopyInto(out *DistributedDDL) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DistributedDDL.
+func (in *DistributedDDL) DeepCopy() *DistributedDDL {
+	if in == nil {
+		return nil
+	}
+	out := new(DistributedDDL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FillStatusParams) DeepCopyInto(out *FillStatusParams) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FQDNs != nil {
+		in, out := &in.FQDNs, &out.FQDNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	if in.Endpoints != nil {
 		in, out := &in.Endpoints, &out.Endpoints
@@ -1130,6 +1596,11 @@ func (in *Host) DeepCopyInto(out *Host) {
 		*out = new(TemplatesList)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(types.StringBool)
+		**out = **in
+	}
 	in.Runtime.DeepCopyInto(&out.Runtime)
 	return
 }
@@ -1160,6 +1631,22 @@ func (in *HostAddress) DeepCopy() *HostAddress {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostMaintenance) DeepCopyInto(out *HostMaintenance) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostMaintenance.
+func (in *HostMaintenance) DeepCopy() *HostMaintenance {
+	if in == nil {
+		return nil
+	}
+	out := new(HostMaintenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostPorts) DeepCopyInto(out *HostPorts) {
 	*out = *in
@@ -1233,6 +1720,11 @@ func (in *HostRuntime) DeepCopyInto(out *HostRuntime) {
 		*out = new(types.Int32)
 		**out = **in
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CurStatefulSet != nil {
 		in, out := &in.CurStatefulSet, &out.CurStatefulSet
 		*out = new(appsv1.StatefulSet)
@@ -1394,6 +1886,47 @@ func (in *HostsField) DeepCopy() *HostsField {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeeperRef) DeepCopyInto(out *KeeperRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeeperRef.
+func (in *KeeperRef) DeepCopy() *KeeperRef {
+	if in == nil {
+		return nil
+	}
+	out := new(KeeperRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeContext) DeepCopyInto(out *KubeContext) {
+	*out = *in
+	if in.Kubeconfig != nil {
+		in, out := &in.Kubeconfig, &out.Kubeconfig
+		*out = (*in).DeepCopy()
+	}
+	if in.NamespaceDomainPattern != nil {
+		in, out := &in.NamespaceDomainPattern, &out.NamespaceDomainPattern
+		*out = new(types.String)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeContext.
+func (in *KubeContext) DeepCopy() *KubeContext {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeContext)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MacrosSection) DeepCopyInto(out *MacrosSection) {
 	*out = *in
@@ -1436,6 +1969,22 @@ func (in *MacrosSections) DeepCopy() *MacrosSections {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectsCleanup) DeepCopyInto(out *ObjectsCleanup) {
 	*out = *in
@@ -1641,6 +2190,18 @@ func (in *OperatorConfigClickHouse) DeepCopyInto(out *OperatorConfigClickHouse)
 	out.Access = in.Access
 	in.Addons.DeepCopyInto(&out.Addons)
 	out.Metrics = in.Metrics
+	if in.Metrics.Queries != nil {
+		in, out := &in.Metrics.Queries, &out.Metrics.Queries
+		*out = make([]*OperatorConfigMetricsQuery, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(OperatorConfigMetricsQuery)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	in.Metrics.QueryLog.DeepCopyInto(&out.Metrics.QueryLog)
 	return
 }
 
@@ -1813,6 +2374,7 @@ func (in *OperatorConfigLabelRuntime) DeepCopy() *OperatorConfigLabelRuntime {
 func (in *OperatorConfigMetrics) DeepCopyInto(out *OperatorConfigMetrics) {
 	*out = *in
 	in.Labels.DeepCopyInto(&out.Labels)
+	in.API.DeepCopyInto(&out.API)
 	return
 }
 
@@ -1826,6 +2388,49 @@ func (in *OperatorConfigMetrics) DeepCopy() *OperatorConfigMetrics {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigMetricsAPI) DeepCopyInto(out *OperatorConfigMetricsAPI) {
+	*out = *in
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.TLS = in.TLS
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(types.StringBool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigMetricsAPI.
+func (in *OperatorConfigMetricsAPI) DeepCopy() *OperatorConfigMetricsAPI {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigMetricsAPI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigMetricsAPITLS) DeepCopyInto(out *OperatorConfigMetricsAPITLS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigMetricsAPITLS.
+func (in *OperatorConfigMetricsAPITLS) DeepCopy() *OperatorConfigMetricsAPITLS {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigMetricsAPITLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorConfigMetricsLabels) DeepCopyInto(out *OperatorConfigMetricsLabels) {
 	*out = *in
@@ -1847,6 +2452,65 @@ func (in *OperatorConfigMetricsLabels) DeepCopy() *OperatorConfigMetricsLabels {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigMetricsQueryLog) DeepCopyInto(out *OperatorConfigMetricsQueryLog) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(types.StringBool)
+		**out = **in
+	}
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]float64, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigMetricsQueryLog.
+func (in *OperatorConfigMetricsQueryLog) DeepCopy() *OperatorConfigMetricsQueryLog {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigMetricsQueryLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigMetricsQuery) DeepCopyInto(out *OperatorConfigMetricsQuery) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CHISelector != nil {
+		in, out := &in.CHISelector, &out.CHISelector
+		*out = make(TargetSelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigMetricsQuery.
+func (in *OperatorConfigMetricsQuery) DeepCopy() *OperatorConfigMetricsQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigMetricsQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorConfigReconcile) DeepCopyInto(out *OperatorConfigReconcile) {
 	*out = *in
@@ -2115,6 +2779,22 @@ func (in *OperatorConfigWatchNamespaces) DeepCopy() *OperatorConfigWatchNamespac
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanReplica) DeepCopyInto(out *OrphanReplica) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanReplica.
+func (in *OrphanReplica) DeepCopy() *OrphanReplica {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanReplica)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodDistribution) DeepCopyInto(out *PodDistribution) {
 	*out = *in
@@ -2224,25 +2904,94 @@ func (in *PortDistribution) DeepCopy() *PortDistribution {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReconcileHost) DeepCopyInto(out *ReconcileHost) {
+func (in *RebalanceMove) DeepCopyInto(out *RebalanceMove) {
 	*out = *in
-	in.Wait.DeepCopyInto(&out.Wait)
-	in.Drop.DeepCopyInto(&out.Drop)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileHost.
-func (in *ReconcileHost) DeepCopy() *ReconcileHost {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RebalanceMove.
+func (in *RebalanceMove) DeepCopy() *RebalanceMove {
 	if in == nil {
 		return nil
 	}
-	out := new(ReconcileHost)
+	out := new(RebalanceMove)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReconcileHostDrop) DeepCopyInto(out *ReconcileHostDrop) {
+func (in *RebalanceStatus) DeepCopyInto(out *RebalanceStatus) {
+	*out = *in
+	if in.DrainShards != nil {
+		in, out := &in.DrainShards, &out.DrainShards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]*RebalanceTableStatus, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(RebalanceTableStatus)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RebalanceStatus.
+func (in *RebalanceStatus) DeepCopy() *RebalanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RebalanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RebalanceTableStatus) DeepCopyInto(out *RebalanceTableStatus) {
+	*out = *in
+	if in.Move != nil {
+		in, out := &in.Move, &out.Move
+		*out = new(RebalanceMove)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RebalanceTableStatus.
+func (in *RebalanceTableStatus) DeepCopy() *RebalanceTableStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RebalanceTableStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileHost) DeepCopyInto(out *ReconcileHost) {
+	*out = *in
+	in.Wait.DeepCopyInto(&out.Wait)
+	in.Drop.DeepCopyInto(&out.Drop)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileHost.
+func (in *ReconcileHost) DeepCopy() *ReconcileHost {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileHost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileHostDrop) DeepCopyInto(out *ReconcileHostDrop) {
 	*out = *in
 	if in.Replicas != nil {
 		in, out := &in.Replicas, &out.Replicas
@@ -2424,6 +3173,210 @@ func (in *ReconcileRuntime) DeepCopy() *ReconcileRuntime {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaGCReport) DeepCopyInto(out *ReplicaGCReport) {
+	*out = *in
+	if in.Orphans != nil {
+		in, out := &in.Orphans, &out.Orphans
+		*out = make([]*OrphanReplica, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(OrphanReplica)
+				**out = **in
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaGCReport.
+func (in *ReplicaGCReport) DeepCopy() *ReplicaGCReport {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaGCReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcesRecommendation) DeepCopyInto(out *ResourcesRecommendation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcesRecommendation.
+func (in *ResourcesRecommendation) DeepCopy() *ResourcesRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcesRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Schema) DeepCopyInto(out *Schema) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]*SchemaDatabase, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(SchemaDatabase)
+				**out = **in
+			}
+		}
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]*SchemaTable, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(SchemaTable)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.MaterializedViews != nil {
+		in, out := &in.MaterializedViews, &out.MaterializedViews
+		*out = make([]*SchemaMaterializedView, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(SchemaMaterializedView)
+				**out = **in
+			}
+		}
+	}
+	if in.Dictionaries != nil {
+		in, out := &in.Dictionaries, &out.Dictionaries
+		*out = make([]*SchemaDictionary, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(SchemaDictionary)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Schema.
+func (in *Schema) DeepCopy() *Schema {
+	if in == nil {
+		return nil
+	}
+	out := new(Schema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaColumn) DeepCopyInto(out *SchemaColumn) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaColumn.
+func (in *SchemaColumn) DeepCopy() *SchemaColumn {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaColumn)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaDatabase) DeepCopyInto(out *SchemaDatabase) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaDatabase.
+func (in *SchemaDatabase) DeepCopy() *SchemaDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaDictionary) DeepCopyInto(out *SchemaDictionary) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]*SchemaColumn, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(SchemaColumn)
+				**out = **in
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaDictionary.
+func (in *SchemaDictionary) DeepCopy() *SchemaDictionary {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaDictionary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaDrift) DeepCopyInto(out *SchemaDrift) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Differences != nil {
+		in, out := &in.Differences, &out.Differences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaDrift.
+func (in *SchemaDrift) DeepCopy() *SchemaDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaMaterializedView) DeepCopyInto(out *SchemaMaterializedView) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaMaterializedView.
+func (in *SchemaMaterializedView) DeepCopy() *SchemaMaterializedView {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaMaterializedView)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SchemaPolicy) DeepCopyInto(out *SchemaPolicy) {
 	*out = *in
@@ -2440,6 +3393,72 @@ func (in *SchemaPolicy) DeepCopy() *SchemaPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaStatus) DeepCopyInto(out *SchemaStatus) {
+	*out = *in
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]*SchemaDrift, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(SchemaDrift)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaStatus.
+func (in *SchemaStatus) DeepCopy() *SchemaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaTable) DeepCopyInto(out *SchemaTable) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]*SchemaColumn, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(SchemaColumn)
+				**out = **in
+			}
+		}
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaTable.
+func (in *SchemaTable) DeepCopy() *SchemaTable {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaTable)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceTemplate) DeepCopyInto(out *ServiceTemplate) {
 	*out = *in
@@ -2731,6 +3750,106 @@ func (in *Status) DeepCopyInto(out *Status) {
 			}
 		}
 	}
+	if in.Rebalance != nil {
+		in, out := &in.Rebalance, &out.Rebalance
+		*out = make([]*RebalanceStatus, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(RebalanceStatus)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = make([]*SchemaStatus, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(SchemaStatus)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = make([]*AutoscalingEvent, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(AutoscalingEvent)
+				**out = **in
+			}
+		}
+	}
+	if in.Recommendations != nil {
+		in, out := &in.Recommendations, &out.Recommendations
+		*out = make([]*ResourcesRecommendation, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ResourcesRecommendation)
+				**out = **in
+			}
+		}
+	}
+	if in.ReplicaGC != nil {
+		in, out := &in.ReplicaGC, &out.ReplicaGC
+		*out = make([]*ReplicaGCReport, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ReplicaGCReport)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Clone != nil {
+		in, out := &in.Clone, &out.Clone
+		*out = make([]*CloneStatus, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(CloneStatus)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.DisasterRecovery != nil {
+		in, out := &in.DisasterRecovery, &out.DisasterRecovery
+		*out = new(DisasterRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = make([]*HostMaintenance, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(HostMaintenance)
+				**out = **in
+			}
+		}
+	}
+	if in.DeferredActions != nil {
+		in, out := &in.DeferredActions, &out.DeferredActions
+		*out = make([]*DeferredAction, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(DeferredAction)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.mu = in.mu
 	return
 }
@@ -2761,6 +3880,42 @@ func (in *StorageManagement) DeepCopy() *StorageManagement {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLS) DeepCopyInto(out *TLS) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(types.StringBool)
+		**out = **in
+	}
+	if in.Validity != nil {
+		in, out := &in.Validity, &out.Validity
+		*out = new(types.String)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(types.String)
+		**out = **in
+	}
+	if in.VerificationMode != nil {
+		in, out := &in.VerificationMode, &out.VerificationMode
+		*out = new(types.String)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLS.
+func (in *TLS) DeepCopy() *TLS {
+	if in == nil {
+		return nil
+	}
+	out := new(TLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in TargetSelector) DeepCopyInto(out *TargetSelector) {
 	{
@@ -2884,6 +4039,47 @@ func (in *TemplatesList) DeepCopy() *TemplatesList {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeHealthGate) DeepCopyInto(out *UpgradeHealthGate) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(types.Int32)
+		**out = **in
+	}
+	if in.ValidateVersion != nil {
+		in, out := &in.ValidateVersion, &out.ValidateVersion
+		*out = new(types.StringBool)
+		**out = **in
+	}
+	if in.MaxErrors != nil {
+		in, out := &in.MaxErrors, &out.MaxErrors
+		*out = new(types.Int32)
+		**out = **in
+	}
+	if in.IgnoreErrors != nil {
+		in, out := &in.IgnoreErrors, &out.IgnoreErrors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxReplicationDelay != nil {
+		in, out := &in.MaxReplicationDelay, &out.MaxReplicationDelay
+		*out = new(types.Int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeHealthGate.
+func (in *UpgradeHealthGate) DeepCopy() *UpgradeHealthGate {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeHealthGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeClaimTemplate) DeepCopyInto(out *VolumeClaimTemplate) {
 	*out = *in
@@ -2949,6 +4145,11 @@ func (in *ZookeeperConfig) DeepCopyInto(out *ZookeeperConfig) {
 		*out = new(types.StringBool)
 		**out = **in
 	}
+	if in.Keeper != nil {
+		in, out := &in.Keeper, &out.Keeper
+		*out = new(KeeperRef)
+		**out = **in
+	}
 	return
 }
 
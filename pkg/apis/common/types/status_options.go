@@ -73,4 +73,16 @@ type Status struct {
 	ActionPlan             bool
 	HostsWithTablesCreated bool
 	UsedTemplates          bool
+	Rebalance              bool
+	Schema                 bool
+	Autoscaling            bool
+	Recommendations        bool
+	ReplicaGC              bool
+	Clone                  bool
+	DisasterRecovery       bool
+	Maintenance            bool
+	DeferredActions        bool
+	Conditions             bool
+	Keepers                bool
+	Snapshots              bool
 }
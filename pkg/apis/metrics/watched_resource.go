@@ -17,11 +17,14 @@ package metrics
 import (
 	"encoding/json"
 
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 )
 
-// WatchedCR specifies watched ClickHouseInstallation
+// WatchedCR specifies watched ClickHouseInstallation or ClickHouseKeeperInstallation
 type WatchedCR struct {
+	// Kind of the CR. Empty kind stands for ClickHouseInstallation
+	Kind        string            `json:"kind,omitempty"`
 	Namespace   string            `json:"namespace"`
 	Name        string            `json:"name"`
 	Labels      map[string]string `json:"labels"`
@@ -43,6 +46,8 @@ type WatchedHost struct {
 	TLSPort   int32  `json:"tlsPort,omitempty"   yaml:"tlsPort,omitempty"`
 	HTTPPort  int32  `json:"httpPort,omitempty"  yaml:"httpPort,omitempty"`
 	HTTPSPort int32  `json:"httpsPort,omitempty" yaml:"httpsPort,omitempty"`
+	// ZKPort is the client port of the ClickHouse Keeper host
+	ZKPort int32 `json:"zkPort,omitempty"    yaml:"zkPort,omitempty"`
 }
 
 // NewWatchedCR creates new watched CR
@@ -56,6 +61,9 @@ func (cr *WatchedCR) readFrom(src api.ICustomResource) {
 	if cr == nil {
 		return
 	}
+	if _, ok := src.(*apiChk.ClickHouseKeeperInstallation); ok {
+		cr.Kind = apiChk.ClickHouseKeeperInstallationCRDResourceKind
+	}
 	cr.Namespace = src.GetNamespace()
 	cr.Name = src.GetName()
 	cr.Labels = src.GetLabels()
@@ -78,9 +86,21 @@ func (cr *WatchedCR) empty() bool {
 }
 
 func (cr *WatchedCR) IndexKey() string {
+	if cr.IsKeeper() {
+		// Keep CHK apart from CHI of the same name
+		return "chk:" + cr.Namespace + ":" + cr.Name
+	}
 	return cr.Namespace + ":" + cr.Name
 }
 
+// IsKeeper checks whether the CR is a ClickHouseKeeperInstallation
+func (cr *WatchedCR) IsKeeper() bool {
+	if cr == nil {
+		return false
+	}
+	return cr.Kind == apiChk.ClickHouseKeeperInstallationCRDResourceKind
+}
+
 func (cr *WatchedCR) WalkHosts(f func(*WatchedCR, *WatchedCluster, *WatchedHost)) {
 	if cr == nil {
 		return
@@ -92,6 +112,17 @@ func (cr *WatchedCR) WalkHosts(f func(*WatchedCR, *WatchedCluster, *WatchedHost)
 	}
 }
 
+// GetKind gets kind of the CR
+func (cr *WatchedCR) GetKind() string {
+	if cr == nil {
+		return ""
+	}
+	if cr.IsKeeper() {
+		return cr.Kind
+	}
+	return api.ClickHouseInstallationCRDResourceKind
+}
+
 func (cr *WatchedCR) GetName() string {
 	if cr == nil {
 		return ""
@@ -153,4 +184,5 @@ func (host *WatchedHost) readFrom(h *api.Host) {
 	host.TLSPort = h.TLSPort.Value()
 	host.HTTPPort = h.HTTPPort.Value()
 	host.HTTPSPort = h.HTTPSPort.Value()
+	host.ZKPort = h.ZKPort.Value()
 }
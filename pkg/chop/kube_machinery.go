@@ -22,11 +22,14 @@ import (
 	"strconv"
 
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiMachineryRuntime "k8s.io/apimachinery/pkg/runtime"
 	kube "k8s.io/client-go/kubernetes"
 	kuberest "k8s.io/client-go/rest"
 	kubeclientcmd "k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/apis/deployment"
 	chopclientset "github.com/altinity/clickhouse-operator/pkg/client/clientset/versioned"
 )
@@ -116,3 +119,24 @@ func GetClientset(kubeConfigFile, masterURL string) (
 
 	return kubeClientset, apiextensionsClientset, chopClientset
 }
+
+// GetKeeperClient gets k8s API client of ClickHouseKeeperInstallation objects
+func GetKeeperClient(kubeConfigFile, masterURL string) client.WithWatch {
+	kubeConfig, err := getKubeConfig(kubeConfigFile, masterURL)
+	if err != nil {
+		log.F().Fatal("Unable to build kubeconf: %s", err.Error())
+		os.Exit(1)
+	}
+
+	scheme := apiMachineryRuntime.NewScheme()
+	if err := apiChk.AddToScheme(scheme); err != nil {
+		log.F().Fatal("Unable to build ClickHouseKeeperInstallation API scheme: %s", err.Error())
+	}
+
+	keeperClient, err := client.NewWithWatch(kubeConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		log.F().Fatal("Unable to initialize ClickHouseKeeperInstallation API client: %s", err.Error())
+	}
+
+	return keeperClient
+}
@@ -0,0 +1,191 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	apiMachinery "k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	apiBackup "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-backup.altinity.com/v1"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// pollInterval specifies how often progress of in-progress operations is polled
+const pollInterval = 10 * time.Second
+
+// BackupController reconciles a ClickHouseBackup object
+type BackupController struct {
+	client.Client
+	Scheme *apiMachinery.Scheme
+
+	// Executor runs SQL on ClickHouse hosts. Default executor is used when not specified
+	Executor IExecutor
+}
+
+func (c *BackupController) newWorker() *worker {
+	if c.Executor == nil {
+		c.Executor = NewExecutor()
+	}
+	return newWorker(c.Client, c.Executor)
+}
+
+func (c *BackupController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return ctrl.Result{}, nil
+	}
+
+	// Fetch the ClickHouseBackup instance
+	backup := &apiBackup.ClickHouseBackup{}
+	if err := c.Client.Get(ctx, req.NamespacedName, backup); err != nil {
+		if apiErrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return ctrl.Result{}, nil
+		}
+		// Return and requeue
+		return ctrl.Result{}, err
+	}
+
+	w := c.newWorker()
+	status := backup.EnsureStatus()
+
+	if status.IsFinished() {
+		// Nothing to do with finished backup except of history limit
+		return ctrl.Result{}, w.enforceHistory(ctx, backup)
+	}
+
+	chi, err := w.getBackupCHI(ctx, backup)
+	switch {
+	case err == nil:
+		switch {
+		case !status.IsStarted():
+			log.V(1).M(req.Namespace, req.Name).F().Info("start backup of CHI %s", backup.Spec.GetCHI().Value())
+			w.startBackup(ctx, chi, backup)
+		case status.HasPendingHosts():
+			w.launchBackup(ctx, chi, backup)
+		default:
+			w.poll(ctx, chi, status, apiBackup.HostStatusBackupFailed)
+		}
+	case apiErrors.IsNotFound(err) || !status.IsStarted():
+		status.Fail(fmt.Sprintf("unable to get CHI: %v", err))
+	default:
+		// Transient error, try again later
+		return ctrl.Result{}, err
+	}
+
+	if err := c.Client.Status().Update(ctx, backup); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	switch {
+	case !status.IsFinished() && status.HasPendingHosts():
+		// Operation ids are persisted, operations can be issued
+		return ctrl.Result{Requeue: true}, nil
+	case !status.IsFinished():
+		return ctrl.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	log.V(1).M(req.Namespace, req.Name).F().Info("backup finished with status %s", status.Status)
+	return ctrl.Result{}, w.enforceHistory(ctx, backup)
+}
+
+// getBackupCHI gets CHI to be backed up
+func (w *worker) getBackupCHI(ctx context.Context, backup *apiBackup.ClickHouseBackup) (*api.ClickHouseInstallation, error) {
+	name := backup.Spec.GetCHI().Value()
+	if name == "" {
+		return nil, fmt.Errorf("chi is not specified")
+	}
+	return w.getCHI(ctx, backup.GetNamespace(), name)
+}
+
+// startBackup plans backup of every shard of the CHI on the first replica of the shard.
+// Backup is not issued on the hosts until ids of the operations are persisted in status
+func (w *worker) startBackup(ctx context.Context, chi *api.ClickHouseInstallation, backup *apiBackup.ClickHouseBackup) {
+	status := backup.EnsureStatus()
+	status.Start()
+
+	// Check destination is valid before anything is planned
+	if _, err := w.newDestinationBuilder(ctx, backup.GetNamespace(), backup.Spec.GetDestination()); err != nil {
+		status.Fail(err.Error())
+		return
+	}
+	// Check history limit can be enforced, as old backups are deleted along with their data
+	if hasHistoryLimit(backup) {
+		if _, err := w.newDataRemover(ctx, backup.GetNamespace(), backup.Spec.GetDestination()); err != nil {
+			status.Fail(fmt.Sprintf("history limit can not be enforced: %v", err))
+			return
+		}
+	}
+
+	cluster := backup.Spec.GetCluster().Value()
+	chi.WalkHosts(func(host *api.Host) error {
+		if (cluster != "") && (host.Runtime.Address.ClusterName != cluster) {
+			return nil
+		}
+		if host.Runtime.Address.ReplicaIndex != 0 {
+			// Shard data is backed up from one replica only
+			return nil
+		}
+
+		status.PushHost(&apiBackup.HostStatus{
+			Host:     host.Runtime.Address.FQDN,
+			Cluster:  host.Runtime.Address.ClusterName,
+			Shard:    host.Runtime.Address.ShardName,
+			ID:       operationID(backup, host),
+			Location: location(backup, host),
+			Status:   apiBackup.HostStatusPending,
+		})
+		return nil
+	})
+
+	if len(status.Hosts) == 0 {
+		status.Fail(fmt.Sprintf("no hosts found to backup in cluster '%s'", cluster))
+		return
+	}
+
+	status.Aggregate()
+}
+
+// launchBackup issues backup on the pending hosts
+func (w *worker) launchBackup(ctx context.Context, chi *api.ClickHouseInstallation, backup *apiBackup.ClickHouseBackup) {
+	status := backup.EnsureStatus()
+
+	destination, err := w.newDestinationBuilder(ctx, backup.GetNamespace(), backup.Spec.GetDestination())
+	if err != nil {
+		status.Fail(err.Error())
+		return
+	}
+
+	w.launch(ctx, chi, status, apiBackup.HostStatusCreatingBackup, apiBackup.HostStatusBackupFailed,
+		func(host *api.Host, hostStatus *apiBackup.HostStatus) string {
+			return sqlBackup(
+				backup.Spec.Databases,
+				backup.Spec.Tables,
+				destination(hostStatus.Location),
+				hostStatus.ID,
+				backup.Spec.Settings,
+			)
+		},
+	)
+}
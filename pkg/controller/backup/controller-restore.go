@@ -0,0 +1,237 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	apiMachinery "k8s.io/apimachinery/pkg/runtime"
+	apiMachineryTypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	apiBackup "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-backup.altinity.com/v1"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// RestoreController reconciles a ClickHouseRestore object
+type RestoreController struct {
+	client.Client
+	Scheme *apiMachinery.Scheme
+
+	// Executor runs SQL on ClickHouse hosts. Default executor is used when not specified
+	Executor IExecutor
+}
+
+func (c *RestoreController) newWorker() *worker {
+	if c.Executor == nil {
+		c.Executor = NewExecutor()
+	}
+	return newWorker(c.Client, c.Executor)
+}
+
+func (c *RestoreController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return ctrl.Result{}, nil
+	}
+
+	// Fetch the ClickHouseRestore instance
+	restore := &apiBackup.ClickHouseRestore{}
+	if err := c.Client.Get(ctx, req.NamespacedName, restore); err != nil {
+		if apiErrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return ctrl.Result{}, nil
+		}
+		// Return and requeue
+		return ctrl.Result{}, err
+	}
+
+	status := restore.EnsureStatus()
+	if status.IsFinished() {
+		// Nothing to do with finished restore
+		return ctrl.Result{}, nil
+	}
+
+	w := c.newWorker()
+
+	backup, err := w.getRestoreBackup(ctx, restore)
+	switch {
+	case err != nil:
+		if !apiErrors.IsNotFound(err) && status.IsStarted() {
+			// Transient error, try again later
+			return ctrl.Result{}, err
+		}
+		status.Fail(fmt.Sprintf("unable to get backup: %v", err))
+	case backup.GetStatusT().IsFinished() && !backup.GetStatusT().IsCompleted():
+		status.Fail(fmt.Sprintf("backup %s is not completed", backup.GetName()))
+	case !backup.GetStatusT().IsCompleted():
+		// Backup is still in progress, wait for it
+		log.V(1).M(req.Namespace, req.Name).F().Info("backup %s is not completed yet, wait", backup.GetName())
+		return ctrl.Result{RequeueAfter: pollInterval}, nil
+	default:
+		if res, err := w.runRestore(ctx, restore, backup); err != nil {
+			return res, err
+		}
+	}
+
+	if err := c.Client.Status().Update(ctx, restore); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	switch {
+	case !status.IsFinished() && status.HasPendingHosts():
+		// Operation ids are persisted, operations can be issued
+		return ctrl.Result{Requeue: true}, nil
+	case !status.IsFinished():
+		return ctrl.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	log.V(1).M(req.Namespace, req.Name).F().Info("restore finished with status %s", status.Status)
+	return ctrl.Result{}, nil
+}
+
+// runRestore either starts restore or polls its progress
+func (w *worker) runRestore(ctx context.Context, restore *apiBackup.ClickHouseRestore, backup *apiBackup.ClickHouseBackup) (ctrl.Result, error) {
+	status := restore.EnsureStatus()
+
+	chiName := restore.Spec.GetCHI().Value()
+	if chiName == "" {
+		chiName = backup.Spec.GetCHI().Value()
+	}
+
+	chi, err := w.getCHI(ctx, restore.GetNamespace(), chiName)
+	switch {
+	case err == nil:
+		switch {
+		case !status.IsStarted():
+			log.V(1).M(restore.GetNamespace(), restore.GetName()).F().Info("start restore of CHI %s from backup %s", chiName, backup.GetName())
+			w.startRestore(ctx, chi, restore, backup)
+		case status.HasPendingHosts():
+			w.launchRestore(ctx, chi, restore, backup)
+		default:
+			w.poll(ctx, chi, status, apiBackup.HostStatusRestoreFailed)
+		}
+	case apiErrors.IsNotFound(err) || !status.IsStarted():
+		status.Fail(fmt.Sprintf("unable to get CHI: %v", err))
+	default:
+		// Transient error, try again later
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// getRestoreBackup gets backup to restore from
+func (w *worker) getRestoreBackup(ctx context.Context, restore *apiBackup.ClickHouseRestore) (*apiBackup.ClickHouseBackup, error) {
+	name := restore.Spec.GetBackup().Value()
+	if name == "" {
+		return nil, fmt.Errorf("backup is not specified")
+	}
+	backup := &apiBackup.ClickHouseBackup{}
+	if err := w.Get(ctx, apiMachineryTypes.NamespacedName{Namespace: restore.GetNamespace(), Name: name}, backup); err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
+
+// startRestore plans restore of every shard of the CHI found in the backup.
+// Restore is not issued on the hosts until ids of the operations are persisted in status
+func (w *worker) startRestore(
+	ctx context.Context,
+	chi *api.ClickHouseInstallation,
+	restore *apiBackup.ClickHouseRestore,
+	backup *apiBackup.ClickHouseBackup,
+) {
+	status := restore.EnsureStatus()
+	status.Start()
+
+	// Check destination is valid before anything is planned
+	if _, err := w.newDestinationBuilder(ctx, backup.GetNamespace(), backup.Spec.GetDestination()); err != nil {
+		status.Fail(err.Error())
+		return
+	}
+
+	cluster := restore.Spec.GetCluster().Value()
+	chi.WalkHosts(func(host *api.Host) error {
+		if (cluster != "") && (host.Runtime.Address.ClusterName != cluster) {
+			return nil
+		}
+		backupStatus := backup.GetStatusT().FindHost(host.Runtime.Address.ClusterName, host.Runtime.Address.ShardName)
+		if backupStatus == nil {
+			// Shard is not in the backup
+			return nil
+		}
+
+		status.PushHost(&apiBackup.HostStatus{
+			Host:     host.Runtime.Address.FQDN,
+			Cluster:  host.Runtime.Address.ClusterName,
+			Shard:    host.Runtime.Address.ShardName,
+			ID:       operationID(restore, host),
+			Location: backupStatus.Location,
+			Status:   apiBackup.HostStatusPending,
+		})
+		return nil
+	})
+
+	if len(status.Hosts) == 0 {
+		status.Fail(fmt.Sprintf("no shards of the backup %s found in CHI %s", backup.GetName(), chi.GetName()))
+		return
+	}
+
+	status.Aggregate()
+}
+
+// launchRestore issues restore on the pending hosts.
+// Data is restored on the first replica of the shard, other replicas restore structure only
+// and fetch data by means of replication.
+func (w *worker) launchRestore(
+	ctx context.Context,
+	chi *api.ClickHouseInstallation,
+	restore *apiBackup.ClickHouseRestore,
+	backup *apiBackup.ClickHouseBackup,
+) {
+	status := restore.EnsureStatus()
+
+	destination, err := w.newDestinationBuilder(ctx, backup.GetNamespace(), backup.Spec.GetDestination())
+	if err != nil {
+		status.Fail(err.Error())
+		return
+	}
+
+	w.launch(ctx, chi, status, apiBackup.HostStatusRestoring, apiBackup.HostStatusRestoreFailed,
+		func(host *api.Host, hostStatus *apiBackup.HostStatus) string {
+			settings := make(map[string]string)
+			for name, value := range restore.Spec.Settings {
+				settings[name] = value
+			}
+			if host.Runtime.Address.ReplicaIndex != 0 {
+				settings["structure_only"] = "1"
+			}
+			return sqlRestore(
+				restore.Spec.Databases,
+				restore.Spec.Tables,
+				destination(hostStatus.Location),
+				hostStatus.ID,
+				settings,
+			)
+		},
+	)
+}
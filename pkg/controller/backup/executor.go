@@ -0,0 +1,123 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/schemer"
+	"github.com/altinity/clickhouse-operator/pkg/model/clickhouse"
+)
+
+// Operation describes backup or restore operation as listed in system.backups
+type Operation struct {
+	Status    string
+	Error     string
+	NumFiles  int64
+	TotalSize int64
+	StartTime string
+	EndTime   string
+}
+
+// IExecutor runs backup-related SQL on ClickHouse hosts
+type IExecutor interface {
+	// Exec runs SQL statement on the host
+	Exec(ctx context.Context, host *api.Host, sql string) error
+	// QueryOperation fetches operation with specified id from system.backups of the host.
+	// Nil is returned in case the host does not know the operation
+	QueryOperation(ctx context.Context, host *api.Host, id string) (*Operation, error)
+}
+
+// Executor runs backup-related SQL on ClickHouse hosts via schemer cluster
+type Executor struct {
+	// hostname resolves name the host is reachable by
+	hostname func(host *api.Host) string
+}
+
+// NewExecutor creates new executor
+func NewExecutor() *Executor {
+	return &Executor{
+		hostname: func(host *api.Host) string {
+			return host.Runtime.Address.FQDN
+		},
+	}
+}
+
+// Exec runs SQL statement on the host
+func (e *Executor) Exec(ctx context.Context, host *api.Host, sql string) error {
+	// Statement may carry credentials, do not log it
+	opts := clickhouse.NewQueryOptions().SetRetry(false).SetLogQueries(false)
+	return e.cluster(host).ExecAll(ctx, []string{sql}, opts)
+}
+
+// QueryOperation fetches operation with specified id from system.backups of the host
+func (e *Executor) QueryOperation(ctx context.Context, host *api.Host, id string) (*Operation, error) {
+	query, err := e.cluster(host).QueryAny(ctx, sqlOperation(id))
+	defer query.Close()
+	if err != nil {
+		return nil, err
+	}
+	if query == nil {
+		return nil, fmt.Errorf("no result")
+	}
+
+	var statuses, errors, numFiles, totalSizes, startTimes, endTimes []string
+	if err := query.UnzipColumnsAsStrings(&statuses, &errors, &numFiles, &totalSizes, &startTimes, &endTimes); err != nil {
+		return nil, err
+	}
+	if len(statuses) == 0 {
+		// Operation is unknown to the host
+		return nil, nil
+	}
+
+	op := &Operation{
+		Status:    statuses[0],
+		Error:     errors[0],
+		StartTime: startTimes[0],
+		EndTime:   endTimes[0],
+	}
+	op.NumFiles, _ = strconv.ParseInt(numFiles[0], 10, 64)
+	op.TotalSize, _ = strconv.ParseInt(totalSizes[0], 10, 64)
+	return op, nil
+}
+
+// cluster creates schemer cluster able to connect to the host
+func (e *Executor) cluster(host *api.Host) *schemer.Cluster {
+	// Make base cluster connection params
+	params := clickhouse.NewClusterConnectionParamsFromCHOpConfig(chop.Config())
+	// Adjust base cluster connection params with per-host props
+	switch params.Scheme {
+	case api.ChSchemeAuto:
+		switch {
+		case host.HTTPPort.HasValue():
+			params.Scheme = "http"
+			params.Port = host.HTTPPort.IntValue()
+		case host.HTTPSPort.HasValue():
+			params.Scheme = "https"
+			params.Port = host.HTTPSPort.IntValue()
+		}
+	case api.ChSchemeHTTP:
+		params.Port = host.HTTPPort.IntValue()
+	case api.ChSchemeHTTPS:
+		params.Port = host.HTTPSPort.IntValue()
+	}
+	cluster := schemer.NewCluster().SetClusterConnectionParams(params)
+	cluster.SetHosts([]string{e.hostname(host)})
+	return cluster
+}
@@ -0,0 +1,249 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// s3DefaultRegion is used to sign requests when region can not be derived from the endpoint
+	s3DefaultRegion = "us-east-1"
+	// s3EmptyPayloadHash is SHA256 of the empty payload, requests made by the client have no payload
+	s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+var (
+	// s3VirtualHostedRegexp matches host of the virtual-hosted style endpoint, where bucket is a part of the host,
+	// in the same manner as ClickHouse does
+	s3VirtualHostedRegexp = regexp.MustCompile(`^(.+)\.(s3|cos|obs|oss|eos)([.\-][a-z0-9\-.:]+)$`)
+	// s3RegionRegexp matches region of AWS endpoint, e.g. s3.eu-west-1.amazonaws.com
+	s3RegionRegexp = regexp.MustCompile(`(^|\.)s3[.\-]([a-z0-9\-]+)\.amazonaws\.com$`)
+)
+
+// s3Client deletes objects of an S3-compatible object storage.
+// Requests are signed with AWS Signature Version 4.
+type s3Client struct {
+	// bucket is URL of the bucket, object keys are appended to its path
+	bucket *url.URL
+	// prefix is key prefix of the endpoint within the bucket
+	prefix          string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	client          *http.Client
+}
+
+// newS3Client creates S3 client for the endpoint specified as URL of the bucket and path prefix
+func newS3Client(endpoint, accessKeyID, secretAccessKey string) (*s3Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint %s has no host", endpoint)
+	}
+
+	c := &s3Client{
+		region:          s3DefaultRegion,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		client:          http.DefaultClient,
+	}
+
+	key := strings.Trim(u.Path, "/")
+	if s3VirtualHostedRegexp.MatchString(u.Hostname()) {
+		c.bucket = &url.URL{Scheme: u.Scheme, Host: u.Host}
+		c.prefix = key
+	} else {
+		// Path-style endpoint, bucket is the first segment of the path
+		bucket, prefix, _ := strings.Cut(key, "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("s3 endpoint %s has no bucket", endpoint)
+		}
+		c.bucket = &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/" + bucket}
+		c.prefix = prefix
+	}
+
+	if m := s3RegionRegexp.FindStringSubmatch(u.Hostname()); m != nil {
+		c.region = m[2]
+	}
+
+	return c, nil
+}
+
+// s3ListResult is the response of ListObjectsV2
+type s3ListResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// DeleteDir deletes all objects under the directory specified relative to the endpoint
+func (c *s3Client) DeleteDir(ctx context.Context, dir string) error {
+	prefix := strings.TrimPrefix(path.Join(c.prefix, dir)+"/", "/")
+	token := ""
+	for {
+		query := map[string]string{
+			"list-type": "2",
+			"prefix":    prefix,
+		}
+		if token != "" {
+			query["continuation-token"] = token
+		}
+		body, err := c.do(ctx, http.MethodGet, "", query)
+		if err != nil {
+			return err
+		}
+		result := &s3ListResult{}
+		if err := xml.Unmarshal(body, result); err != nil {
+			return fmt.Errorf("unable to parse list of objects: %v", err)
+		}
+
+		for _, object := range result.Contents {
+			if _, err := c.do(ctx, http.MethodDelete, object.Key, nil); err != nil {
+				return err
+			}
+		}
+
+		if !result.IsTruncated || (result.NextContinuationToken == "") {
+			return nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do makes signed request to the object of the bucket and returns response body
+func (c *s3Client) do(ctx context.Context, method, key string, query map[string]string) ([]byte, error) {
+	uri := s3Escape(c.bucket.Path+"/"+key, false)
+	rawQuery := s3CanonicalQuery(query)
+	u := c.bucket.Scheme + "://" + c.bucket.Host + uri
+	if rawQuery != "" {
+		u += "?" + rawQuery
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.sign(req, uri, rawQuery, time.Now())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if (resp.StatusCode < 200) || (resp.StatusCode >= 300) {
+		return nil, fmt.Errorf("%s %s failed with status %s: %s", method, uri, resp.Status, body)
+	}
+	return body, nil
+}
+
+// sign signs request with AWS Signature Version 4
+func (c *s3Client) sign(req *http.Request, uri, rawQuery string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", s3EmptyPayloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uri,
+		rawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + s3EmptyPayloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		s3EmptyPayloadHash,
+	}, "\n")
+	scope := date + "/" + c.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.secretAccessKey), date)
+	key = hmacSHA256(key, c.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// s3CanonicalQuery builds query string sorted by parameter name, as signature requires
+func s3CanonicalQuery(query map[string]string) string {
+	var params []string
+	for name, value := range query {
+		params = append(params, s3Escape(name, true)+"="+s3Escape(value, true))
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// s3Escape escapes string as signature requires. Slash is escaped in query parameters only
+func s3Escape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ('A' <= ch && ch <= 'Z') || ('a' <= ch && ch <= 'z') || ('0' <= ch && ch <= '9'),
+			ch == '-', ch == '_', ch == '.', ch == '~',
+			(ch == '/') && !escapeSlash:
+			b.WriteByte(ch)
+		default:
+			_, _ = fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,156 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+)
+
+// Databases which are never backed up
+const ignoredDBs = `system, information_schema, INFORMATION_SCHEMA`
+
+// sqlBackup builds 'BACKUP ... TO ...' statement
+func sqlBackup(databases, tables []string, destination string, id string, settings map[string]string) string {
+	return fmt.Sprintf(
+		"BACKUP %s TO %s SETTINGS %s ASYNC",
+		sqlObjects(databases, tables),
+		destination,
+		sqlSettings(id, settings),
+	)
+}
+
+// sqlRestore builds 'RESTORE ... FROM ...' statement
+func sqlRestore(databases, tables []string, destination string, id string, settings map[string]string) string {
+	return fmt.Sprintf(
+		"RESTORE %s FROM %s SETTINGS %s ASYNC",
+		sqlObjects(databases, tables),
+		destination,
+		sqlSettings(id, settings),
+	)
+}
+
+// sqlOperation builds query fetching operation status from system.backups
+func sqlOperation(id string) string {
+	return heredoc.Docf(`
+		SELECT
+			toString(status),
+			error,
+			num_files,
+			total_size,
+			toString(start_time),
+			toString(end_time)
+		FROM
+			system.backups
+		WHERE
+			id = %s
+		`,
+		quote(id),
+	)
+}
+
+// sqlObjects builds list of objects to be backed up or restored
+func sqlObjects(databases, tables []string) string {
+	if (len(databases) == 0) && (len(tables) == 0) {
+		return "ALL EXCEPT DATABASES " + ignoredDBs
+	}
+	var objects []string
+	for _, database := range databases {
+		objects = append(objects, "DATABASE "+identifier(database))
+	}
+	for _, table := range tables {
+		objects = append(objects, "TABLE "+tableIdentifier(table))
+	}
+	return strings.Join(objects, ", ")
+}
+
+// Settings names and values passed to SQL as is
+var (
+	settingNameRegexp  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	settingValueRegexp = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+)
+
+// sqlSettings builds SETTINGS clause content. Settings are sorted in order to have stable statement
+func sqlSettings(id string, settings map[string]string) string {
+	list := []string{"id = " + quote(id)}
+	var names []string
+	for name := range settings {
+		if name == "id" {
+			// id is managed by the operator
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		list = append(list, settingName(name)+" = "+settingValue(settings[name]))
+	}
+	return strings.Join(list, ", ")
+}
+
+// settingName quotes setting name unless it is a plain identifier
+func settingName(name string) string {
+	if settingNameRegexp.MatchString(name) {
+		return name
+	}
+	return identifier(name)
+}
+
+// settingValue quotes setting value unless it is a number
+func settingValue(value string) string {
+	if settingValueRegexp.MatchString(value) {
+		return value
+	}
+	return quote(value)
+}
+
+// sqlDiskDestination builds Disk() backup destination
+func sqlDiskDestination(disk, path string) string {
+	return fmt.Sprintf("Disk(%s, %s)", quote(disk), quote(path))
+}
+
+// sqlS3Destination builds S3() backup destination
+func sqlS3Destination(url, accessKeyID, secretAccessKey string) string {
+	if (accessKeyID == "") && (secretAccessKey == "") {
+		return fmt.Sprintf("S3(%s)", quote(url))
+	}
+	return fmt.Sprintf("S3(%s, %s, %s)", quote(url), quote(accessKeyID), quote(secretAccessKey))
+}
+
+// quote quotes string literal
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
+
+// identifier quotes identifier
+func identifier(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "`", "\\`")
+	return "`" + s + "`"
+}
+
+// tableIdentifier quotes table name specified in 'database.table' or 'table' form
+func tableIdentifier(s string) string {
+	if database, table, found := strings.Cut(s, "."); found {
+		return identifier(database) + "." + identifier(table)
+	}
+	return identifier(s)
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	apiBackup "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-backup.altinity.com/v1"
+)
+
+// hasHistoryLimit checks whether history limit is specified for the backup
+func hasHistoryLimit(backup *apiBackup.ClickHouseBackup) bool {
+	keepLast := backup.Spec.GetHistory().GetKeepLast()
+	return keepLast.HasValue() && (keepLast.Value() > 0)
+}
+
+// enforceHistory deletes completed backups of the same CHI exceeding history limit of the backup.
+// Backup data is deleted from the destination before the backup resource, so no data is left behind
+// without a resource pointing to it. Backups which data can not be deleted are kept.
+func (w *worker) enforceHistory(ctx context.Context, backup *apiBackup.ClickHouseBackup) error {
+	if !hasHistoryLimit(backup) {
+		return nil
+	}
+	keepLast := backup.Spec.GetHistory().GetKeepLast()
+
+	list := &apiBackup.ClickHouseBackupList{}
+	if err := w.List(ctx, list, client.InNamespace(backup.GetNamespace())); err != nil {
+		return err
+	}
+
+	var completed []*apiBackup.ClickHouseBackup
+	for i := range list.Items {
+		item := &list.Items[i]
+		if (item.Spec.GetCHI().Value() == backup.Spec.GetCHI().Value()) && item.GetStatusT().IsCompleted() {
+			completed = append(completed, item)
+		}
+	}
+
+	// Latest backups go first
+	sort.SliceStable(completed, func(i, j int) bool {
+		return completionTime(completed[j]).Before(completionTime(completed[i]))
+	})
+
+	for i := int(keepLast.Value()); i < len(completed); i++ {
+		if err := w.deleteBackup(ctx, completed[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteBackup deletes data of the backup from the destination and then the backup resource
+func (w *worker) deleteBackup(ctx context.Context, backup *apiBackup.ClickHouseBackup) error {
+	remove, err := w.newDataRemover(ctx, backup.GetNamespace(), backup.Spec.GetDestination())
+	if err != nil {
+		log.V(1).M(backup.GetNamespace(), backup.GetName()).F().Warning("history limit keeps backup, its data can not be deleted: %v", err)
+		return nil
+	}
+
+	for _, hostStatus := range backup.GetStatusT().Hosts {
+		if hostStatus.Location == "" {
+			continue
+		}
+		if err := remove(ctx, hostStatus.Location); err != nil {
+			return fmt.Errorf("unable to delete data of backup %s/%s: %v", backup.GetNamespace(), backup.GetName(), err)
+		}
+	}
+
+	log.V(1).M(backup.GetNamespace(), backup.GetName()).F().Info("history limit removes backup, data is deleted")
+	return client.IgnoreNotFound(w.Delete(ctx, backup))
+}
+
+// completionTime gets completion time of the backup
+func completionTime(backup *apiBackup.ClickHouseBackup) time.Time {
+	if t := backup.GetStatusT().CompletionTime; t != nil {
+		return t.Time
+	}
+	return backup.GetCreationTimestamp().Time
+}
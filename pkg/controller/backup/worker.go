@@ -0,0 +1,253 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	apiMachineryTypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	apiBackup "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-backup.altinity.com/v1"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	commonNormalizer "github.com/altinity/clickhouse-operator/pkg/model/common/normalizer"
+)
+
+// worker runs backup and restore operations over hosts of a CHI
+type worker struct {
+	client.Client
+	executor IExecutor
+}
+
+// newWorker creates new worker
+func newWorker(c client.Client, executor IExecutor) *worker {
+	return &worker{
+		Client:   c,
+		executor: executor,
+	}
+}
+
+// getCHI fetches CHI and normalizes it, so all hosts are built and ready to be walked over
+func (w *worker) getCHI(ctx context.Context, namespace, name string) (*api.ClickHouseInstallation, error) {
+	chi := &api.ClickHouseInstallation{}
+	if err := w.Get(ctx, apiMachineryTypes.NamespacedName{Namespace: namespace, Name: name}, chi); err != nil {
+		return nil, err
+	}
+	n := normalizer.New(func(namespace, name string) (*core.Secret, error) {
+		secret := &core.Secret{}
+		err := w.Get(ctx, apiMachineryTypes.NamespacedName{Namespace: namespace, Name: name}, secret)
+		return secret, err
+	}, nil)
+	return n.CreateTemplated(chi, commonNormalizer.NewOptions[api.ClickHouseInstallation]())
+}
+
+// getSecretValue fetches value referenced by the data source
+func (w *worker) getSecretValue(ctx context.Context, namespace string, source *types.DataSource) (string, error) {
+	if (source == nil) || (source.SecretKeyRef == nil) {
+		return "", nil
+	}
+	secret := &core.Secret{}
+	if err := w.Get(ctx, apiMachineryTypes.NamespacedName{Namespace: namespace, Name: source.SecretKeyRef.Name}, secret); err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[source.SecretKeyRef.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in secret %s/%s", source.SecretKeyRef.Key, namespace, source.SecretKeyRef.Name)
+	}
+	return string(value), nil
+}
+
+// destinationBuilder builds SQL destination for the specified location
+type destinationBuilder func(location string) string
+
+// newDestinationBuilder creates destination builder for backup destination
+func (w *worker) newDestinationBuilder(ctx context.Context, namespace string, destination *apiBackup.Destination) (destinationBuilder, error) {
+	switch {
+	case destination.HasDisk():
+		disk := destination.Disk.Name.Value()
+		if disk == "" {
+			return nil, fmt.Errorf("disk name is not specified")
+		}
+		prefix := destination.Disk.Path.Value()
+		return func(location string) string {
+			return sqlDiskDestination(disk, path.Join(prefix, location))
+		}, nil
+
+	case destination.HasS3():
+		endpoint := strings.TrimSuffix(destination.S3.Endpoint.Value(), "/")
+		if endpoint == "" {
+			return nil, fmt.Errorf("s3 endpoint is not specified")
+		}
+		accessKeyID, err := w.getSecretValue(ctx, namespace, destination.S3.AccessKeyID)
+		if err != nil {
+			return nil, err
+		}
+		secretAccessKey, err := w.getSecretValue(ctx, namespace, destination.S3.SecretAccessKey)
+		if err != nil {
+			return nil, err
+		}
+		return func(location string) string {
+			return sqlS3Destination(endpoint+"/"+location, accessKeyID, secretAccessKey)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("destination is not specified")
+}
+
+// dataRemover deletes backup data stored at the specified location
+type dataRemover func(ctx context.Context, location string) error
+
+// newDataRemover creates data remover for backup destination.
+// ClickHouse is not able to delete backups, so data is deleted by the operator itself,
+// which is possible for S3 destination with credentials only
+func (w *worker) newDataRemover(ctx context.Context, namespace string, destination *apiBackup.Destination) (dataRemover, error) {
+	if !destination.HasS3() || !destination.S3.HasCredentials() {
+		return nil, fmt.Errorf("backup data can be deleted from s3 destination with credentials only")
+	}
+	accessKeyID, err := w.getSecretValue(ctx, namespace, destination.S3.AccessKeyID)
+	if err != nil {
+		return nil, err
+	}
+	secretAccessKey, err := w.getSecretValue(ctx, namespace, destination.S3.SecretAccessKey)
+	if err != nil {
+		return nil, err
+	}
+	s3, err := newS3Client(destination.S3.Endpoint.Value(), accessKeyID, secretAccessKey)
+	if err != nil {
+		return nil, err
+	}
+	return s3.DeleteDir, nil
+}
+
+// location builds path of the shard's backup relative to the destination
+func location(backup *apiBackup.ClickHouseBackup, host *api.Host) string {
+	return path.Join(
+		backup.GetNamespace(),
+		backup.Spec.GetCHI().Value(),
+		backup.GetName(),
+		host.Runtime.Address.ClusterName,
+		host.Runtime.Address.ShardName,
+	)
+}
+
+// operationID builds id of the operation in system.backups, unique across CRs and their re-creations
+func operationID(obj client.Object, host *api.Host) string {
+	return fmt.Sprintf("%s-%s", obj.GetUID(), host.Runtime.Address.HostName)
+}
+
+// findHost finds host of the CHI by FQDN
+func findHost(chi *api.ClickHouseInstallation, fqdn string) *api.Host {
+	var found *api.Host
+	chi.WalkHosts(func(host *api.Host) error {
+		if host.Runtime.Address.FQDN == fqdn {
+			found = host
+		}
+		return nil
+	})
+	return found
+}
+
+// poll refreshes status of the in-progress hosts from system.backups
+func (w *worker) poll(ctx context.Context, chi *api.ClickHouseInstallation, status *apiBackup.Status, failedStatus string) {
+	for _, hostStatus := range status.Hosts {
+		if hostStatus.IsFinished() {
+			continue
+		}
+
+		host := findHost(chi, hostStatus.Host)
+		if host == nil {
+			hostStatus.Status = failedStatus
+			hostStatus.Error = "host is not found in CHI"
+			continue
+		}
+
+		op, err := w.executor.QueryOperation(ctx, host, hostStatus.ID)
+		switch {
+		case err != nil:
+			// Host may be temporarily unavailable, try again later
+			log.V(1).M(chi).F().Warning("unable to query operation %s on host %s err: %v", hostStatus.ID, hostStatus.Host, err)
+			continue
+		case op == nil:
+			// system.backups is in-memory, so the operation is lost in case host was restarted
+			hostStatus.Status = failedStatus
+			hostStatus.Error = "operation is not found in system.backups, host may have been restarted"
+			continue
+		}
+
+		setOperation(hostStatus, op)
+	}
+	status.Aggregate()
+}
+
+// launch issues operation on the pending hosts.
+// Operation id is persisted in status before the operation is issued, so in case status update failed
+// after the operation had been issued, the operation is found in system.backups instead of being issued again
+func (w *worker) launch(
+	ctx context.Context,
+	chi *api.ClickHouseInstallation,
+	status *apiBackup.Status,
+	launchedStatus string,
+	failedStatus string,
+	statement func(host *api.Host, hostStatus *apiBackup.HostStatus) string,
+) {
+	for _, hostStatus := range status.Hosts {
+		if !hostStatus.IsPending() {
+			continue
+		}
+
+		host := findHost(chi, hostStatus.Host)
+		if host == nil {
+			hostStatus.Status = failedStatus
+			hostStatus.Error = "host is not found in CHI"
+			continue
+		}
+
+		op, err := w.executor.QueryOperation(ctx, host, hostStatus.ID)
+		switch {
+		case err != nil:
+			// Host may be temporarily unavailable, try again later
+			log.V(1).M(chi).F().Warning("unable to query operation %s on host %s err: %v", hostStatus.ID, hostStatus.Host, err)
+			continue
+		case op != nil:
+			// Operation has been issued already
+			setOperation(hostStatus, op)
+			continue
+		}
+
+		hostStatus.Status = launchedStatus
+		if err := w.executor.Exec(ctx, host, statement(host, hostStatus)); err != nil {
+			hostStatus.Status = failedStatus
+			hostStatus.Error = err.Error()
+		}
+	}
+	status.Aggregate()
+}
+
+// setOperation copies operation progress into host status
+func setOperation(hostStatus *apiBackup.HostStatus, op *Operation) {
+	hostStatus.Status = op.Status
+	hostStatus.Error = op.Error
+	hostStatus.NumFiles = op.NumFiles
+	hostStatus.TotalSize = op.TotalSize
+	hostStatus.StartTime = op.StartTime
+	hostStatus.EndTime = op.EndTime
+}
@@ -45,6 +45,7 @@ const (
 	priorityReconcileChopConfig    int = 3
 	priorityReconcileEndpoints     int = 15
 	priorityReconcileEndpointSlice int = 15
+	priorityRebalanceCHI           int = 20
 )
 
 // ReconcileCHI specifies reconcile request queue item
@@ -259,3 +260,33 @@ func NewReconcilePod(cmd string, old, new *core.Pod) *ReconcilePod {
 		New: new,
 	}
 }
+
+// RebalanceCHI specifies request to take a step of the rebalances in progress of the CHI
+type RebalanceCHI struct {
+	PriorityQueueItem
+	Namespace string
+	Name      string
+}
+
+var _ queue.PriorityQueueItem = &RebalanceCHI{}
+
+// Handle returns handle of the queue item
+func (r RebalanceCHI) Handle() queue.T {
+	return "RebalanceCHI" + ":" + r.Namespace + "/" + r.Name
+}
+
+// ReconcileHandle returns handle of the reconcile of the CHI
+func (r RebalanceCHI) ReconcileHandle() queue.T {
+	return "ReconcileCHI" + ":" + r.Namespace + "/" + r.Name
+}
+
+// NewRebalanceCHI creates new rebalance queue item
+func NewRebalanceCHI(namespace, name string) *RebalanceCHI {
+	return &RebalanceCHI{
+		PriorityQueueItem: PriorityQueueItem{
+			priority: priorityRebalanceCHI,
+		},
+		Namespace: namespace,
+		Name:      name,
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	kubeTypes "k8s.io/apimachinery/pkg/types"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/controller"
+	"github.com/altinity/clickhouse-operator/pkg/interfaces"
+	metricsClickHouse "github.com/altinity/clickhouse-operator/pkg/metrics/clickhouse"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/autoscaler"
+	chiNormalizer "github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/clickhouse"
+	commonNormalizer "github.com/altinity/clickhouse-operator/pkg/model/common/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// autoscalePeriod specifies how often autoscaling policies are evaluated
+const autoscalePeriod = 30 * time.Second
+
+// hasAutoscaling checks whether any cluster of the CHI has autoscaling enabled.
+// Layout is not touched while reconcile is running, scaling decision is made once it is completed
+func hasAutoscaling(chi *api.ClickHouseInstallation) bool {
+	if chi.IsStopped() || chi.Spec.Suspend.Value() || (chi.EnsureStatus().GetStatus() == api.StatusInProgress) {
+		return false
+	}
+	if chi.GetSpecT().Configuration == nil {
+		return false
+	}
+	for _, cluster := range chi.GetSpecT().Configuration.Clusters {
+		if cluster.GetAutoscaling().IsEnabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// autoscaleCR evaluates autoscaling policies of all clusters of the CHI.
+// Clusters are scaled by changing replicasCount in the CHI spec, so the regular reconcile applies the new layout.
+func (c *Controller) autoscaleCR(ctx context.Context, chi, normalized *api.ClickHouseInstallation) {
+	for index, cluster := range chi.GetSpecT().Configuration.Clusters {
+		policy := cluster.GetAutoscaling()
+		if !policy.IsEnabled() {
+			continue
+		}
+		if (cluster.Layout != nil) && ((len(cluster.Layout.Shards) > 0) || (len(cluster.Layout.Replicas) > 0)) {
+			log.V(1).M(chi).F().Warning("Cluster %s has explicitly specified shards or replicas, unable to autoscale", cluster.GetName())
+			continue
+		}
+		if last := chi.EnsureStatus().GetLastAutoscalingEvent(cluster.GetName()); last != nil {
+			if time.Since(last.GetTime()) < policy.GetCooldown() {
+				continue
+			}
+		}
+		normalizedCluster, ok := normalized.FindCluster(cluster.GetName()).(*api.Cluster)
+		if !ok || (normalizedCluster == nil) {
+			continue
+		}
+
+		current := normalizedCluster.GetLayout().ReplicasCount
+		desired, reason := autoscaler.Recommend(policy, current, c.collectAutoscalingMetrics(ctx, normalizedCluster, policy))
+		if desired == current {
+			log.V(2).M(chi).F().Info("Cluster %s keeps %d replicas: %s", cluster.GetName(), current, reason)
+			continue
+		}
+
+		if err := c.scaleCluster(ctx, chi, index, cluster, current, desired, reason); err != nil {
+			log.V(1).M(chi).F().Error("unable to scale cluster %s from %d to %d replicas. err: %v", cluster.GetName(), current, desired, err)
+		}
+	}
+}
+
+// collectAutoscalingMetrics collects values of the metrics the cluster is scaled by from all hosts of the cluster.
+// Hosts which are not reachable are skipped.
+func (c *Controller) collectAutoscalingMetrics(
+	ctx context.Context,
+	cluster *api.Cluster,
+	policy *api.ClusterAutoscaling,
+) map[string][]float64 {
+	var names []string
+	for _, metric := range policy.GetMetrics() {
+		names = append(names, metric.GetName())
+	}
+
+	values := make(map[string][]float64)
+	cluster.WalkHosts(func(host *api.Host) error {
+		hostValues, err := c.newHostMetricsFetcher(host).GetMetricValues(ctx, names...)
+		if err != nil {
+			log.V(1).M(host).F().Warning("unable to fetch metrics of the host %s. err: %v", host.GetName(), err)
+			return nil
+		}
+		for name, value := range hostValues {
+			values[name] = append(values[name], value)
+		}
+		return nil
+	})
+	return values
+}
+
+// normalizeCR normalizes copy of the CHI, so layout of the clusters is available
+func (c *Controller) normalizeCR(ctx context.Context, chi *api.ClickHouseInstallation) (*api.ClickHouseInstallation, error) {
+	return chiNormalizer.New(func(namespace, name string) (*core.Secret, error) {
+		return c.kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, controller.NewGetOptions())
+	}, func(namespace, name string) (api.ZookeeperNodes, error) {
+		return c.getKeeperNodes(namespace, name)
+	}).CreateTemplated(chi.DeepCopy(), commonNormalizer.NewOptions[api.ClickHouseInstallation]())
+}
+
+// newHostMetricsFetcher creates metrics fetcher for the host
+func (c *Controller) newHostMetricsFetcher(host *api.Host) *metricsClickHouse.MetricsFetcher {
+	params := clickhouse.NewClusterConnectionParamsFromCHOpConfig(chop.Config())
+	switch params.Scheme {
+	case api.ChSchemeAuto:
+		switch {
+		case host.HTTPPort.HasValue():
+			params.Scheme = "http"
+			params.Port = host.HTTPPort.IntValue()
+		case host.HTTPSPort.HasValue():
+			params.Scheme = "https"
+			params.Port = host.HTTPSPort.IntValue()
+		}
+	case api.ChSchemeHTTP:
+		params.Port = host.HTTPPort.IntValue()
+	case api.ChSchemeHTTPS:
+		params.Port = host.HTTPSPort.IntValue()
+	}
+	return metricsClickHouse.NewMetricsFetcher(
+		params.NewEndpointConnectionParams(c.namer.Name(interfaces.NameFQDN, host)),
+		chop.Config().ClickHouse.Metrics.TablesRegexp,
+	)
+}
+
+// scaleCluster records scaling decision in the status and sets new replicasCount of the cluster in the CHI spec
+func (c *Controller) scaleCluster(
+	ctx context.Context,
+	chi *api.ClickHouseInstallation,
+	index int,
+	cluster *api.Cluster,
+	from, to int,
+	reason string,
+) error {
+	log.V(1).M(chi).F().Info("Scale cluster %s from %d to %d replicas: %s", cluster.GetName(), from, to, reason)
+
+	// Decision is recorded first, so cooldown is respected even in case spec update fails
+	chi.EnsureStatus().PushAutoscalingEvent(&api.AutoscalingEvent{
+		Time:    time.Now().UTC().Format(time.RFC3339),
+		Cluster: cluster.GetName(),
+		From:    from,
+		To:      to,
+		Reason:  reason,
+	})
+	err := c.updateCRObjectStatus(ctx, chi, types.UpdateStatusOptions{
+		CopyStatusOptions: types.CopyStatusOptions{
+			CopyStatusField: types.CopyStatusField{
+				Copy: types.Status{
+					Autoscaling: true,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.patchClusterReplicasCount(ctx, chi, index, cluster, to)
+}
+
+type patchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// patchClusterReplicasCount patches replicasCount of the cluster in the CHI spec.
+// Patch fails in case clusters were reordered meanwhile.
+func (c *Controller) patchClusterReplicasCount(
+	ctx context.Context,
+	chi *api.ClickHouseInstallation,
+	index int,
+	cluster *api.Cluster,
+	replicas int,
+) error {
+	if util.IsContextDone(ctx) {
+		log.V(1).Info("Autoscale is aborted. CR: %s ", chi.GetName())
+		return nil
+	}
+
+	path := fmt.Sprintf("/spec/configuration/clusters/%d", index)
+	ops := []patchOperation{
+		{
+			Op:    "test",
+			Path:  path + "/name",
+			Value: cluster.GetName(),
+		},
+	}
+	if cluster.Layout == nil {
+		ops = append(ops, patchOperation{
+			Op:    "add",
+			Path:  path + "/layout",
+			Value: map[string]int{"replicasCount": replicas},
+		})
+	} else {
+		ops = append(ops, patchOperation{
+			Op:    "add",
+			Path:  path + "/layout/replicasCount",
+			Value: replicas,
+		})
+	}
+	payload, _ := json.Marshal(ops)
+
+	_, err := c.chopClient.ClickhouseV1().ClickHouseInstallations(chi.GetNamespace()).Patch(ctx, chi.GetName(), kubeTypes.JSONPatchType, payload, controller.NewPatchOptions())
+	return err
+}
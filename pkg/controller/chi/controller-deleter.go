@@ -23,6 +23,7 @@ import (
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	chiKube "github.com/altinity/clickhouse-operator/pkg/controller/chi/kube"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common/storage"
 	"github.com/altinity/clickhouse-operator/pkg/interfaces"
 )
@@ -31,11 +32,18 @@ import (
 func (c *Controller) deleteHost(ctx context.Context, host *api.Host) error {
 	log.V(1).M(host).S().Info(host.Runtime.Address.ClusterNameString())
 
+	// Objects of the host live in the kube context of the host
+	ctx = chiKube.WithHostKubeContext(ctx, host)
+
 	// Each host consists of:
 	_ = c.deleteStatefulSet(ctx, host)
 	_ = storage.NewStoragePVC(c.kube.Storage()).DeletePVC(ctx, host)
 	_ = c.deleteConfigMap(ctx, host)
 	_ = c.deleteServiceHost(ctx, host)
+	if host.GetKubeContext() != "" {
+		// Remote objects have no owner to be garbage collected with
+		_ = c.deleteSecretIfExists(ctx, host.GetRuntime().GetAddress().GetNamespace(), c.namer.Name(interfaces.NameTLSSecretHost, host))
+	}
 
 	log.V(1).M(host).E().Info(host.Runtime.Address.ClusterNameString())
 
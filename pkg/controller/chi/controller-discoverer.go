@@ -23,6 +23,7 @@ import (
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/controller"
+	chiKube "github.com/altinity/clickhouse-operator/pkg/controller/chi/kube"
 	"github.com/altinity/clickhouse-operator/pkg/interfaces"
 	"github.com/altinity/clickhouse-operator/pkg/model"
 	chiLabeler "github.com/altinity/clickhouse-operator/pkg/model/chi/tags/labeler"
@@ -46,17 +47,38 @@ func (c *Controller) discovery(ctx context.Context, cr api.ICustomResource) *mod
 	l.Info("Discovery\ninclude: %s\nexclude: %s", includeSelector, excludeSelector)
 
 	r := model.NewRegistry()
+	c.discoveryKubeContext(chiKube.WithKubeContext(ctx, cr, ""), r, cr, opts, excludeSelector)
+	for _, name := range getKubeContextNames(cr) {
+		// Objects of hosts living in remote kube contexts are tracked per kube context
+		c.discoveryKubeContext(chiKube.WithKubeContext(ctx, cr, name), r.KubeContext(name), cr, opts, excludeSelector)
+	}
+
+	l.Info("Discovery found %d objects", r.Len())
+	return r
+}
+
+// discoveryKubeContext discovers objects of the CR in the kube context specified by ctx
+func (c *Controller) discoveryKubeContext(ctx context.Context, r *model.Registry, cr api.ICustomResource, opts meta.ListOptions, exclude labels.Selector) {
 	c.discoveryStatefulSets(ctx, r, cr, opts)
-	c.discoveryConfigMaps(ctx, r, cr, opts, excludeSelector)
+	c.discoveryConfigMaps(ctx, r, cr, opts, exclude)
 	c.discoveryServices(ctx, r, cr, opts)
 	c.discoverySecrets(ctx, r, cr, opts)
 	c.discoveryPVCs(ctx, r, cr, opts)
 	// Comment out PV
 	//c.discoveryPVs(ctx, r, chi, opts)
 	c.discoveryPDBs(ctx, r, cr, opts)
+}
 
-	l.Info("Discovery found %d objects", r.Len())
-	return r
+// getKubeContextNames gets names of remote kube contexts declared by the CR
+func getKubeContextNames(cr api.ICustomResource) (names []string) {
+	chi, ok := cr.(*api.ClickHouseInstallation)
+	if !ok {
+		return nil
+	}
+	for _, kubeContext := range chi.GetSpecT().GetKubeContexts() {
+		names = append(names, kubeContext.GetName())
+	}
+	return names
 }
 
 func (c *Controller) discoveryStatefulSets(ctx context.Context, r *model.Registry, cr api.ICustomResource, opts meta.ListOptions) {
@@ -0,0 +1,94 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/conditions"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
+)
+
+const (
+	// healthCheckPeriod specifies how often health of the CHIs is checked
+	healthCheckPeriod = time.Minute
+	// healthCheckMaxReplicaDelay specifies max replication delay, in seconds, replica is considered healthy with
+	healthCheckMaxReplicaDelay = 300
+)
+
+// hasHealthCheck checks whether health of the CHI has to be checked.
+// CHIs being reconciled are skipped, as conditions are maintained by the worker meanwhile.
+func hasHealthCheck(chi *api.ClickHouseInstallation) bool {
+	return chi.EnsureStatus().GetStatus() != api.StatusInProgress
+}
+
+// checkHealthCR checks health of the hosts of the CHI and updates health-related conditions of the CHI
+func (c *Controller) checkHealthCR(ctx context.Context, chi, normalized *api.ClickHouseInstallation) {
+	health := &conditions.Health{
+		Reconciled:    chi.EnsureStatus().GetStatus() == api.StatusCompleted,
+		SchemaManaged: !normalized.GetSpecT().Configuration.GetSchema().IsEmpty(),
+	}
+	if !chi.IsStopped() {
+		c.checkHostsHealth(ctx, normalized, health)
+	}
+	normalized.WalkClusters(func(cluster api.ICluster) error {
+		if schema := chi.EnsureStatus().GetSchema(cluster.GetName()); (schema != nil) && (schema.Status != api.SchemaStatusInSync) {
+			health.SchemaDrifted = append(health.SchemaDrifted, cluster.GetName())
+		}
+		return nil
+	})
+
+	// Report health-related conditions only, so conditions set by the worker meanwhile are not overwritten
+	chi.EnsureStatus().Conditions = nil
+	conditions.Observe(chi, health)
+	if err := c.updateCRObjectStatus(ctx, chi, types.UpdateStatusOptions{
+		CopyStatusOptions: types.CopyStatusOptions{
+			CopyStatusField: types.CopyStatusField{
+				Copy: types.Status{
+					Conditions: true,
+				},
+			},
+		},
+	}); err != nil {
+		log.V(1).M(chi).F().Error("unable to update conditions. err: %v", err)
+	}
+}
+
+// checkHostsHealth checks readiness of the pods and replication health of all hosts of the CHI
+func (c *Controller) checkHostsHealth(ctx context.Context, chi *api.ClickHouseInstallation, health *conditions.Health) {
+	chi.WalkHosts(func(host *api.Host) error {
+		if host.IsStopped() {
+			return nil
+		}
+		health.Hosts++
+		if pod, err := c.kube.Pod().Get(ctx, host); (err != nil) || !k8s.IsPodOK(pod) {
+			health.NotReadyHosts = append(health.NotReadyHosts, host.GetName())
+			return nil
+		}
+		replicas, err := c.newHostMetricsFetcher(host).GetUnhealthyReplicas(ctx, healthCheckMaxReplicaDelay)
+		if err != nil {
+			log.V(1).M(host).F().Warning("unable to check replicas of the host %s. err: %v", host.GetName(), err)
+			return nil
+		}
+		for _, replica := range replicas {
+			health.UnhealthyReplicas = append(health.UnhealthyReplicas, host.GetName()+"/"+replica)
+		}
+		return nil
+	})
+}
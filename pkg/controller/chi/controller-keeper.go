@@ -0,0 +1,203 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/controller/chi/cmd_queue"
+	"github.com/altinity/clickhouse-operator/pkg/interfaces"
+	chkNormalizer "github.com/altinity/clickhouse-operator/pkg/model/chk/normalizer"
+	commonNormalizer "github.com/altinity/clickhouse-operator/pkg/model/common/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/managers"
+)
+
+// newKeeperInformer creates informer of ClickHouseKeeperInstallation objects.
+// chop clientset has no typed client for CHK, so informer is built on top of the controller-runtime client
+func newKeeperInformer(keeperClient client.WithWatch, resync time.Duration) cache.SharedIndexInformer {
+	namespace := chop.Config().GetInformerNamespace()
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options meta.ListOptions) (runtime.Object, error) {
+				list := &apiChk.ClickHouseKeeperInstallationList{}
+				err := keeperClient.List(context.TODO(), list, client.InNamespace(namespace), &client.ListOptions{Raw: &options})
+				return list, err
+			},
+			WatchFunc: func(options meta.ListOptions) (watch.Interface, error) {
+				list := &apiChk.ClickHouseKeeperInstallationList{}
+				return keeperClient.Watch(context.TODO(), list, client.InNamespace(namespace), &client.ListOptions{Raw: &options})
+			},
+		},
+		&apiChk.ClickHouseKeeperInstallation{},
+		resync,
+		cache.Indexers{},
+	)
+}
+
+func (c *Controller) addEventHandlersKeeper() {
+	// Deleted keeper is not handled - CHIs keep zookeeper nodes they were reconciled with
+	c.keeperInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			chk := obj.(*apiChk.ClickHouseKeeperInstallation)
+			log.V(3).M(chk).Info("chkInformer.AddFunc")
+			c.enqueueKeeperReferrers(chk)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldChk := old.(*apiChk.ClickHouseKeeperInstallation)
+			newChk := new.(*apiChk.ClickHouseKeeperInstallation)
+			if oldChk.GetGeneration() == newChk.GetGeneration() {
+				// Status-only update, hosts are the same
+				return
+			}
+			log.V(3).M(newChk).Info("chkInformer.UpdateFunc")
+			c.enqueueKeeperReferrers(newChk)
+		},
+	})
+}
+
+// enqueueKeeperReferrers enqueues reconcile of the CHIs, which refer to the ClickHouseKeeperInstallation
+// and were reconciled with zookeeper nodes other than hosts of the ClickHouseKeeperInstallation now
+func (c *Controller) enqueueKeeperReferrers(chk *apiChk.ClickHouseKeeperInstallation) {
+	chis, err := c.chiLister.List(labels.Everything())
+	if err != nil {
+		log.V(1).M(chk).F().Error("unable to list CHIs err: %v", err)
+		return
+	}
+	for _, chi := range chis {
+		if !ShouldEnqueue(chi) || !refersKeeper(chi, chk.GetNamespace(), chk.GetName()) {
+			continue
+		}
+		if c.isKeeperTopologyChanged(chi) {
+			log.V(1).M(chi).Info("Keeper %s/%s topology changed, reconcile CHI", chk.GetNamespace(), chk.GetName())
+			c.enqueueObject(cmd_queue.NewReconcileCHI(cmd_queue.ReconcileAdd, nil, chi))
+		}
+	}
+}
+
+// keeperRefNamespace gets namespace of the ClickHouseKeeperInstallation referenced by the zookeeper config of the CR.
+// Keeper is looked up in the namespace of the CR in case namespace is not specified explicitly
+func keeperRefNamespace(cr meta.Object, zk *api.ZookeeperConfig) string {
+	if zk.Keeper.Namespace != "" {
+		return zk.Keeper.Namespace
+	}
+	return cr.GetNamespace()
+}
+
+// refersKeeper checks whether any cluster of the CR was reconciled with zookeeper of the ClickHouseKeeperInstallation.
+// Completed normalized CR is checked, so references coming from templates are taken into account as well
+func refersKeeper(cr *api.ClickHouseInstallation, namespace, name string) bool {
+	completed := cr.EnsureStatus().GetNormalizedCRCompleted()
+	if completed == nil {
+		return false
+	}
+	found := false
+	completed.WalkClusters(func(cluster api.ICluster) error {
+		zk := cluster.GetZookeeper()
+		if zk.HasKeeperRef() && (zk.Keeper.Name == name) && (keeperRefNamespace(cr, zk) == namespace) {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
+
+// isKeeperTopologyChanged checks whether hosts of the referenced ClickHouseKeeperInstallations differ
+// from zookeeper nodes the CR was reconciled with last time.
+// Keeper which can not be resolved is not considered as changed - CR keeps zookeeper nodes it was reconciled with
+func (c *Controller) isKeeperTopologyChanged(cr *api.ClickHouseInstallation) bool {
+	completed := cr.EnsureStatus().GetNormalizedCRCompleted()
+	if completed == nil {
+		// CR was not reconciled yet, nothing to compare with
+		return false
+	}
+	changed := false
+	completed.WalkClusters(func(cluster api.ICluster) error {
+		zk := cluster.GetZookeeper()
+		if !zk.HasKeeperRef() {
+			return nil
+		}
+		nodes, err := c.getKeeperNodes(keeperRefNamespace(cr, zk), zk.Keeper.Name)
+		if err == nil && !slices.Equal(nodes.Servers(), zk.Nodes.Servers()) {
+			changed = true
+		}
+		return nil
+	})
+	return changed
+}
+
+// getKeeper gets ClickHouseKeeperInstallation from the informer cache
+func (c *Controller) getKeeper(namespace, name string) (*apiChk.ClickHouseKeeperInstallation, error) {
+	obj, exists, err := c.keeperInformer.GetStore().GetByKey(namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, apiErrors.NewNotFound(apiChk.SchemeGroupVersion.WithResource("clickhousekeeperinstallations").GroupResource(), name)
+	}
+	// Cached object is shared, normalizer has to work on a copy
+	return obj.(*apiChk.ClickHouseKeeperInstallation).DeepCopy(), nil
+}
+
+// getKeeperNodes resolves hosts of the ClickHouseKeeperInstallation into zookeeper nodes
+func (c *Controller) getKeeperNodes(namespace, name string) (api.ZookeeperNodes, error) {
+	chk, err := c.getKeeper(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := chkNormalizer.New().CreateTemplated(chk, commonNormalizer.NewOptions[apiChk.ClickHouseKeeperInstallation]())
+	if err != nil {
+		return nil, err
+	}
+
+	namer := managers.NewNameManager(managers.NameManagerTypeKeeper)
+	var nodes api.ZookeeperNodes
+	normalized.WalkHosts(func(host *api.Host) error {
+		nodes = append(nodes, api.ZookeeperNode{
+			Host: namer.Name(interfaces.NameFQDN, host),
+			Port: types.NewInt32(host.ZKPort.Value()),
+		})
+		return nil
+	})
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("keeper %s/%s has no hosts", namespace, name)
+	}
+	return nodes, nil
+}
+
+// isKeeperReady checks whether the ClickHouseKeeperInstallation is reconciled and ready to serve requests
+func (c *Controller) isKeeperReady(namespace, name string) bool {
+	chk, err := c.getKeeper(namespace, name)
+	if err != nil {
+		return false
+	}
+	ready := chk.EnsureStatus().GetCondition(api.ConditionTypeReady)
+	return (ready != nil) && (ready.Status == meta.ConditionTrue)
+}
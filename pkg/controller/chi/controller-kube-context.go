@@ -0,0 +1,54 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	chiKube "github.com/altinity/clickhouse-operator/pkg/controller/chi/kube"
+)
+
+// registerKubeContexts registers clients of the remote kube contexts declared by the CHI.
+// Kubeconfigs are read from secrets in the namespace of the CHI in the local kube context
+func (c *Controller) registerKubeContexts(ctx context.Context, chi *api.ClickHouseInstallation) (errs []error) {
+	ctx = chiKube.WithKubeContext(ctx, chi, "")
+	for _, kubeContext := range chi.GetSpecT().GetKubeContexts() {
+		if err := c.registerKubeContext(ctx, chi, kubeContext); err != nil {
+			log.V(1).M(chi).F().Warning("Unable to register kube context %s. err: %v", kubeContext.GetName(), err)
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// registerKubeContext registers client of the remote kube context
+func (c *Controller) registerKubeContext(ctx context.Context, chi *api.ClickHouseInstallation, kubeContext *api.KubeContext) error {
+	if !kubeContext.HasKubeconfig() {
+		return fmt.Errorf("kubeconfig of kube context %s is not specified", kubeContext.GetName())
+	}
+	ref := kubeContext.Kubeconfig.SecretKeyRef
+	secret, err := c.kube.Secret().Get(ctx, chi.GetNamespace(), ref.Name)
+	if err != nil {
+		return fmt.Errorf("unable to get kubeconfig secret %s/%s err: %v", chi.GetNamespace(), ref.Name, err)
+	}
+	kubeconfig, ok := secret.Data[ref.Key]
+	if !ok {
+		return fmt.Errorf("kubeconfig secret %s/%s has no key %s", chi.GetNamespace(), ref.Name, ref.Key)
+	}
+	return c.kubeContexts.Register(chi, kubeContext.GetName(), kubeconfig)
+}
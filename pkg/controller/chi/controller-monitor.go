@@ -0,0 +1,171 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// monitorPeriod specifies how often watched CHIs are passed over by the monitors, which are due
+const monitorPeriod = 10 * time.Second
+
+// monitor is a periodic task run over the watched CHIs
+type monitor struct {
+	// name is used in logs
+	name string
+	// period specifies how often the monitor is run
+	period time.Duration
+	// applies checks whether the monitor is run over the CHI
+	applies func(chi *api.ClickHouseInstallation) bool
+	// normalize specifies whether the monitor requires normalized CHI
+	normalize bool
+	// run runs the monitor over the CHI. Normalized CHI is shared by the monitors and must not be modified
+	run func(ctx context.Context, chi, normalized *api.ClickHouseInstallation)
+	// finish is called after the pass over all CHIs, in case it is specified
+	finish func(ctx context.Context)
+	// last is time of the latest run of the monitor
+	last time.Time
+}
+
+// isDue checks whether the monitor has to be run
+func (m *monitor) isDue(now time.Time) bool {
+	return now.Sub(m.last) >= m.period
+}
+
+// newMonitors lists periodic tasks run over the watched CHIs
+func (c *Controller) newMonitors() []*monitor {
+	return []*monitor{
+		{
+			name:      "health",
+			period:    healthCheckPeriod,
+			applies:   hasHealthCheck,
+			normalize: true,
+			run:       c.checkHealthCR,
+		},
+		{
+			name:      "autoscaler",
+			period:    autoscalePeriod,
+			applies:   hasAutoscaling,
+			normalize: true,
+			run:       c.autoscaleCR,
+		},
+		{
+			name:      "resources recommender",
+			period:    recommendPeriod,
+			applies:   hasResourcesRecommender,
+			normalize: true,
+			run:       c.recommendResourcesCR,
+			finish: func(context.Context) {
+				c.usage.Forget(time.Now().Add(-recommendForgetAfter))
+			},
+		},
+		{
+			name:      "replica garbage collector",
+			period:    replicaGCCheckPeriod,
+			applies:   hasReplicaGC,
+			normalize: true,
+			run:       c.collectReplicaGarbageCR,
+		},
+		{
+			name:      "disaster recovery",
+			period:    disasterRecoveryCheckPeriod,
+			applies:   hasDisasterRecoveryLag,
+			normalize: true,
+			run:       c.monitorDisasterRecoveryCR,
+		},
+		{
+			name:    "rebalancer",
+			period:  rebalanceCheckPeriod,
+			applies: hasRebalanceInProgress,
+			run:     c.enqueueRebalance,
+		},
+	}
+}
+
+// runMonitors periodically runs monitors over all watched CHIs.
+// Monitors share one pass over the CHIs, so CHIs are listed from the informer cache and normalized once per pass
+func (c *Controller) runMonitors(ctx context.Context) {
+	monitors := c.newMonitors()
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		c.runMonitorsPass(ctx, monitors, time.Now())
+	}, monitorPeriod)
+}
+
+// runMonitorsPass runs monitors, which are due, over all watched CHIs
+func (c *Controller) runMonitorsPass(ctx context.Context, monitors []*monitor, now time.Time) {
+	var due []*monitor
+	for _, m := range monitors {
+		if m.isDue(now) {
+			m.last = now
+			due = append(due, m)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	chis, err := c.chiLister.List(labels.Everything())
+	if err != nil {
+		log.V(1).F().Error("unable to list CHIs. err: %v", err)
+		return
+	}
+	for _, chi := range chis {
+		if util.IsContextDone(ctx) {
+			return
+		}
+		if chop.Config().IsNamespaceWatched(chi.GetNamespace()) {
+			c.runMonitorsCR(ctx, due, chi)
+		}
+	}
+	for _, m := range due {
+		if m.finish != nil {
+			m.finish(ctx)
+		}
+	}
+}
+
+// runMonitorsCR runs monitors over the CHI. CHI is normalized once, as soon as any monitor requires it
+func (c *Controller) runMonitorsCR(ctx context.Context, monitors []*monitor, chi *api.ClickHouseInstallation) {
+	// Objects of the informer cache must not be modified
+	chi = chi.DeepCopy()
+	var normalized *api.ClickHouseInstallation
+	for _, m := range monitors {
+		if util.IsContextDone(ctx) {
+			return
+		}
+		if !m.applies(chi) {
+			continue
+		}
+		if m.normalize && (normalized == nil) {
+			var err error
+			if normalized, err = c.normalizeCR(ctx, chi); err != nil {
+				log.V(1).M(chi).F().Error("unable to normalize CHI. err: %v", err)
+				return
+			}
+		}
+		log.V(3).M(chi).F().Info("Run %s", m.name)
+		// Monitors update status of the CHI, so every monitor gets a copy of its own
+		m.run(ctx, chi.DeepCopy(), normalized)
+	}
+}
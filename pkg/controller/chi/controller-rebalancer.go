@@ -0,0 +1,44 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/controller/chi/cmd_queue"
+)
+
+// rebalanceCheckPeriod specifies how often CHIs are checked for rebalances in progress
+const rebalanceCheckPeriod = rebalanceMovePollInterval
+
+// enqueueRebalance enqueues step of the rebalances in progress of the CHI.
+// CHI which has the step enqueued already is not enqueued again, as the step in progress would be cancelled
+func (c *Controller) enqueueRebalance(_ context.Context, chi, _ *api.ClickHouseInstallation) {
+	cmd := cmd_queue.NewRebalanceCHI(chi.GetNamespace(), chi.GetName())
+	if _, enqueued := c.rebalances.LoadOrStore(cmd.Handle(), true); !enqueued {
+		c.enqueueObject(cmd)
+	}
+}
+
+// hasRebalanceInProgress checks whether any cluster of the CHI is being rebalanced or drained
+func hasRebalanceInProgress(chi *api.ClickHouseInstallation) bool {
+	for _, status := range chi.EnsureStatus().Rebalance {
+		if status.IsInProgress() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/controller/chi/metrics"
+	"github.com/altinity/clickhouse-operator/pkg/interfaces"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/recommender"
+)
+
+const (
+	// recommendPeriod specifies how often resources usage of the hosts is sampled
+	recommendPeriod = time.Minute
+	// recommendMinSamples specifies min number of samples of the cluster required to make a recommendation
+	recommendMinSamples = 10
+	// recommendForgetAfter specifies how long usage history of the host is kept after the host is gone
+	recommendForgetAfter = 10 * recommendPeriod
+)
+
+// hasResourcesRecommender checks whether any cluster of the CHI has resources recommender enabled
+func hasResourcesRecommender(chi *api.ClickHouseInstallation) bool {
+	if chi.IsStopped() || chi.Spec.Suspend.Value() {
+		return false
+	}
+	if chi.GetSpecT().Configuration == nil {
+		return false
+	}
+	for _, cluster := range chi.GetSpecT().Configuration.Clusters {
+		if cluster.GetResourcesRecommender().IsEnabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// recommendResourcesCR samples resources usage of the hosts and recommends resources of all clusters of the CHI.
+// Recommendation is published in the status only in case it differs significantly from the published one,
+// so neither status nor hosts are updated on minor fluctuations of the load.
+func (c *Controller) recommendResourcesCR(ctx context.Context, chi, normalized *api.ClickHouseInstallation) {
+	for _, cluster := range chi.GetSpecT().Configuration.Clusters {
+		policy := cluster.GetResourcesRecommender()
+		if !policy.IsEnabled() {
+			continue
+		}
+		normalizedCluster, ok := normalized.FindCluster(cluster.GetName()).(*api.Cluster)
+		if !ok || (normalizedCluster == nil) {
+			continue
+		}
+
+		hosts := c.sampleResourcesUsage(ctx, normalizedCluster, policy)
+		samples := c.usage.Samples(hosts...)
+		if len(samples) < recommendMinSamples {
+			log.V(2).M(chi).F().Info("Cluster %s has %d samples only, skip recommendation", cluster.GetName(), len(samples))
+			continue
+		}
+
+		next := recommender.Recommend(cluster.GetName(), samples, policy.GetHeadroom())
+		metrics.ResourcesRecommendation(chi, next)
+
+		prev := chi.EnsureStatus().GetResourcesRecommendation(cluster.GetName())
+		if !recommender.IsSignificantChange(prev, next, policy.GetMinChange()) {
+			continue
+		}
+
+		log.V(1).M(chi).F().Info(
+			"Cluster %s resources recommendation: cpu %s/%s memory %s/%s",
+			cluster.GetName(), next.CPURequest, next.CPULimit, next.MemoryRequest, next.MemoryLimit,
+		)
+		if err := c.setResourcesRecommendation(ctx, chi, next); err != nil {
+			log.V(1).M(chi).F().Error("unable to set resources recommendation of the cluster %s. err: %v", cluster.GetName(), err)
+		}
+	}
+}
+
+// sampleResourcesUsage observes resources usage of all hosts of the cluster.
+// Returns keys of the hosts usage history is kept by. Hosts which are not reachable are skipped.
+func (c *Controller) sampleResourcesUsage(
+	ctx context.Context,
+	cluster *api.Cluster,
+	policy *api.ClusterResourcesRecommender,
+) (hosts []string) {
+	cluster.WalkHosts(func(host *api.Host) error {
+		key := c.namer.Name(interfaces.NameFQDN, host)
+		hosts = append(hosts, key)
+
+		usage, err := c.newHostMetricsFetcher(host).GetResourceUsage(ctx)
+		if err != nil {
+			log.V(1).M(host).F().Warning("unable to fetch resources usage of the host %s. err: %v", host.GetName(), err)
+			return nil
+		}
+		c.usage.Observe(key, recommender.Usage{
+			Time:        time.Now(),
+			CPUTime:     usage.CPUTime,
+			Memory:      usage.Memory,
+			QueryMemory: usage.QueryMemory,
+		}, policy.GetWindow())
+		return nil
+	})
+	return hosts
+}
+
+// setResourcesRecommendation publishes resources recommendation of the cluster in the status.
+// In apply mode recommendation is picked up by the next reconcile, as it is not applied yet.
+func (c *Controller) setResourcesRecommendation(ctx context.Context, chi *api.ClickHouseInstallation, recommendation *api.ResourcesRecommendation) error {
+	chi.EnsureStatus().SetResourcesRecommendation(recommendation)
+	return c.updateCRObjectStatus(ctx, chi, types.UpdateStatusOptions{
+		CopyStatusOptions: types.CopyStatusOptions{
+			CopyStatusField: types.CopyStatusField{
+				Copy: types.Status{
+					Recommendations: true,
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,157 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/schemer"
+)
+
+// replicaGCCheckPeriod specifies how often CHIs are checked for a due run of the replica garbage collector
+const replicaGCCheckPeriod = time.Minute
+
+// hasReplicaGC checks whether any cluster of the CHI has replica garbage collector enabled.
+// CHIs being reconciled are skipped, as hosts may be added or removed meanwhile.
+func hasReplicaGC(chi *api.ClickHouseInstallation) bool {
+	if chi.IsStopped() || chi.Spec.Suspend.Value() || (chi.EnsureStatus().GetStatus() == api.StatusInProgress) {
+		return false
+	}
+	if chi.GetSpecT().Configuration == nil {
+		return false
+	}
+	for _, cluster := range chi.GetSpecT().Configuration.Clusters {
+		if cluster.GetReplicaGC().IsEnabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// collectReplicaGarbageCR searches for orphaned replicas of the clusters of the CHI, which have the run due.
+// Replicas of any host of the CHI and of DR installations are known, so replicas of hosts of other clusters
+// sharing ZooKeeper paths are kept.
+func (c *Controller) collectReplicaGarbageCR(ctx context.Context, chi, normalized *api.ClickHouseInstallation) {
+	crs := []*api.ClickHouseInstallation{normalized}
+	if chi.GetSpecT().GetDisasterRecovery().IsReplica() {
+		primary, err := c.getDisasterRecoveryPrimary(ctx, chi)
+		if err != nil {
+			// Replicas of the primary are not known for sure
+			log.V(1).M(chi).F().Warning("Skip replica garbage collection. err: %v", err)
+			return
+		}
+		crs = append(crs, primary)
+	}
+	for _, replica := range c.listDisasterRecoveryReplicas(chi) {
+		// DR replicas are known even in case they do not replicate the CHI properly
+		if normalizedReplica, err := c.normalizeCR(ctx, replica); err == nil {
+			crs = append(crs, normalizedReplica)
+		}
+	}
+	// Replicas of DR installations share ZooKeeper paths with the CHI
+	known := schemer.KnownReplicas(c.namer, crs...)
+
+	for _, cluster := range chi.GetSpecT().Configuration.Clusters {
+		gc := cluster.GetReplicaGC()
+		prev := chi.EnsureStatus().GetReplicaGCReport(cluster.GetName())
+		if !gc.IsDue(prev, time.Now()) {
+			continue
+		}
+		normalizedCluster, ok := normalized.FindCluster(cluster.GetName()).(*api.Cluster)
+		if !ok || (normalizedCluster == nil) {
+			continue
+		}
+
+		report := c.collectClusterReplicaGarbage(ctx, normalizedCluster, gc, prev, known)
+		log.V(1).M(chi).F().Info("Cluster %s has %d orphaned replicas", cluster.GetName(), len(report.Orphans))
+		chi.EnsureStatus().SetReplicaGCReport(report)
+		err := c.updateCRObjectStatus(ctx, chi, types.UpdateStatusOptions{
+			CopyStatusOptions: types.CopyStatusOptions{
+				CopyStatusField: types.CopyStatusField{
+					Copy: types.Status{
+						ReplicaGC: true,
+					},
+				},
+			},
+		})
+		if err != nil {
+			log.V(1).M(chi).F().Error("unable to report orphaned replicas of the cluster %s. err: %v", cluster.GetName(), err)
+		}
+	}
+}
+
+// collectClusterReplicaGarbage lists replicas registered for the replicated tables of the hosts of the cluster
+// and reports the orphaned ones. In drop mode orphaned replicas reported by the previous run already are dropped,
+// so the drop is always preceded by a dry-run report.
+func (c *Controller) collectClusterReplicaGarbage(
+	ctx context.Context,
+	cluster *api.Cluster,
+	gc *api.ClusterReplicaGC,
+	prev *api.ReplicaGCReport,
+	known map[string]bool,
+) *api.ReplicaGCReport {
+	report := &api.ReplicaGCReport{
+		Cluster: cluster.GetName(),
+		Time:    time.Now().UTC().Format(time.RFC3339),
+		Run:     gc.Run,
+	}
+
+	w := c.newWorker(nil, true)
+	// Host to drop the replica on, which is the first host reporting the ZooKeeper path
+	reporters := make(map[string]*api.Host)
+	var registered []schemer.RegisteredReplica
+	reachable := 0
+	cluster.WalkHosts(func(host *api.Host) error {
+		replicas, err := w.ensureClusterSchemer(host).HostRegisteredReplicas(ctx, host)
+		if err != nil {
+			log.V(1).M(host).F().Warning("unable to list replicas of the host %s. err: %v", host.GetName(), err)
+			return nil
+		}
+		reachable++
+		for _, replica := range replicas {
+			if _, ok := reporters[replica.ZookeeperPath]; !ok {
+				reporters[replica.ZookeeperPath] = host
+			}
+		}
+		registered = append(registered, replicas...)
+		return nil
+	})
+	if reachable == 0 {
+		report.Error = "no host of the cluster is reachable"
+		return report
+	}
+
+	for _, replica := range schemer.OrphanReplicas(registered, func(replica string) bool { return known[replica] }) {
+		orphan := &api.OrphanReplica{
+			Table:         replica.Database + "." + replica.Table,
+			ZookeeperPath: replica.ZookeeperPath,
+			Replica:       replica.Replica,
+		}
+		if gc.IsDrop() && prev.HasOrphan(replica.ZookeeperPath, replica.Replica) {
+			host := reporters[replica.ZookeeperPath]
+			if err := w.ensureClusterSchemer(host).HostDropReplicaByZookeeperPath(ctx, host, replica.ZookeeperPath, replica.Replica); err != nil {
+				orphan.Error = err.Error()
+			} else {
+				orphan.Dropped = time.Now().UTC().Format(time.RFC3339)
+			}
+		}
+		report.Orphans = append(report.Orphans, orphan)
+	}
+	return report
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/sanity-io/litter"
@@ -39,6 +40,7 @@ import (
 	typedCore "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
@@ -47,12 +49,14 @@ import (
 	chopClientSet "github.com/altinity/clickhouse-operator/pkg/client/clientset/versioned"
 	chopClientSetScheme "github.com/altinity/clickhouse-operator/pkg/client/clientset/versioned/scheme"
 	chopInformers "github.com/altinity/clickhouse-operator/pkg/client/informers/externalversions"
+	chopListers "github.com/altinity/clickhouse-operator/pkg/client/listers/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/controller"
 	"github.com/altinity/clickhouse-operator/pkg/controller/chi/cmd_queue"
 	chiKube "github.com/altinity/clickhouse-operator/pkg/controller/chi/kube"
 	ctrlLabeler "github.com/altinity/clickhouse-operator/pkg/controller/chi/labeler"
 	"github.com/altinity/clickhouse-operator/pkg/interfaces"
 	"github.com/altinity/clickhouse-operator/pkg/metrics/clickhouse"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/recommender"
 	chiLabeler "github.com/altinity/clickhouse-operator/pkg/model/chi/tags/labeler"
 	"github.com/altinity/clickhouse-operator/pkg/model/common/volume"
 	"github.com/altinity/clickhouse-operator/pkg/model/managers"
@@ -64,6 +68,8 @@ import (
 type Controller struct {
 	// kube is a generalized kube client
 	kube interfaces.IKube
+	// kubeContexts keeps clients of remote kube contexts hosts may live in
+	kubeContexts *chiKube.Clients
 
 	//
 	// Native clients
@@ -72,6 +78,11 @@ type Controller struct {
 	extClient  apiExtensions.Interface
 	chopClient chopClientSet.Interface
 
+	// chiLister lists ClickHouseInstallation objects from the informer cache
+	chiLister chopListers.ClickHouseInstallationLister
+	// keeperInformer keeps ClickHouseKeeperInstallation objects referenced as zookeeper
+	keeperInformer cache.SharedIndexInformer
+
 	// queues used to organize events queue processed by the operator
 	queues []queue.PriorityQueue
 	// not used explicitly
@@ -80,6 +91,10 @@ type Controller struct {
 	namer       interfaces.INameManager
 	ctrlLabeler *ctrlLabeler.Labeler
 	pvcDeleter  *volume.PVCDeleter
+	// usage keeps resources usage history of the hosts for the resources recommender
+	usage *recommender.History
+	// rebalances keeps CHIs which have rebalance step enqueued, so steps are not enqueued twice
+	rebalances sync.Map
 }
 
 // NewController creates instance of Controller
@@ -87,8 +102,10 @@ func NewController(
 	chopClient chopClientSet.Interface,
 	extClient apiExtensions.Interface,
 	kubeClient kube.Interface,
+	keeperClient client.WithWatch,
 	chopInformerFactory chopInformers.SharedInformerFactory,
 	kubeInformerFactory kubeInformers.SharedInformerFactory,
+	keeperInformerResyncPeriod time.Duration,
 ) *Controller {
 
 	// Initializations
@@ -110,18 +127,23 @@ func NewController(
 	)
 
 	namer := managers.NewNameManager(managers.NameManagerTypeClickHouse)
-	kube := chiKube.NewAdapter(kubeClient, chopClient, namer)
+	kubeContexts := chiKube.NewClients(kubeClient)
+	kube := chiKube.NewAdapter(kubeContexts, chopClient, namer)
 
 	// Create Controller instance
 	controller := &Controller{
-		kubeClient:  kubeClient,
-		extClient:   extClient,
-		chopClient:  chopClient,
-		recorder:    recorder,
-		namer:       namer,
-		kube:        kube,
-		ctrlLabeler: ctrlLabeler.New(kube),
-		pvcDeleter:  volume.NewPVCDeleter(managers.NewNameManager(managers.NameManagerTypeClickHouse)),
+		kubeClient:     kubeClient,
+		extClient:      extClient,
+		chopClient:     chopClient,
+		chiLister:      chopInformerFactory.Clickhouse().V1().ClickHouseInstallations().Lister(),
+		keeperInformer: newKeeperInformer(keeperClient, keeperInformerResyncPeriod),
+		recorder:       recorder,
+		namer:          namer,
+		kube:           kube,
+		kubeContexts:   kubeContexts,
+		ctrlLabeler:    ctrlLabeler.New(kube),
+		pvcDeleter:     volume.NewPVCDeleter(managers.NewNameManager(managers.NameManagerTypeClickHouse)),
+		usage:          recommender.NewHistory(),
 	}
 	controller.initQueues()
 	controller.addEventHandlers(chopInformerFactory, kubeInformerFactory)
@@ -539,6 +561,7 @@ func (c *Controller) addEventHandlers(
 	c.addEventHandlersCHI(chopInformerFactory)
 	c.addEventHandlersCHIT(chopInformerFactory)
 	c.addEventHandlersChopConfig(chopInformerFactory)
+	c.addEventHandlersKeeper()
 	c.addEventHandlersService(kubeInformerFactory)
 	//c.addEventHandlersEndpoints(kubeInformerFactory)
 	c.addEventHandlersEndpointSlice(kubeInformerFactory)
@@ -564,6 +587,12 @@ func (c *Controller) Run(ctx context.Context) {
 
 	log.V(1).Info("Starting ClickHouseInstallation controller")
 
+	// Keeper informer is not a part of informer factories, so it is run by the controller itself
+	go c.keeperInformer.Run(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), c.keeperInformer.HasSynced) {
+		log.V(1).F().Error("Unable to sync ClickHouseKeeperInstallation informer cache")
+	}
+
 	// Label controller runtime objects with proper labels
 	max := 10
 	for cnt := 0; cnt < max; cnt++ {
@@ -595,6 +624,8 @@ func (c *Controller) Run(ctx context.Context) {
 	}
 	defer log.V(1).F().Info("ClickHouseInstallation controller: shutting down workers")
 
+	go c.runMonitors(ctx)
+
 	log.V(1).F().Info("ClickHouseInstallation controller: workers started")
 	<-ctx.Done()
 }
@@ -670,6 +701,11 @@ func (c *Controller) enqueueObject(obj queue.PriorityQueueItem) {
 		case cmd_queue.ReconcileUpdate:
 			enqueue = prepareCHIUpdate(command)
 		}
+	case *cmd_queue.RebalanceCHI:
+		// Rebalance steps share the queue with reconciles of the CHI, so they never run concurrently
+		variants := len(c.queues) - api.DefaultReconcileSystemThreadsNumber
+		index = api.DefaultReconcileSystemThreadsNumber + util.HashIntoIntTopped([]byte(command.ReconcileHandle().(string)), variants)
+		enqueue = true
 	case
 		*cmd_queue.ReconcileCHIT,
 		*cmd_queue.ReconcileChopConfig,
@@ -15,16 +15,14 @@
 package kube
 
 import (
-	kube "k8s.io/client-go/kubernetes"
-
 	chopClientSet "github.com/altinity/clickhouse-operator/pkg/client/clientset/versioned"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common/storage"
 	"github.com/altinity/clickhouse-operator/pkg/interfaces"
 )
 
 type Adapter struct {
-	kubeClient kube.Interface
-	namer      interfaces.INameManager
+	clients *Clients
+	namer   interfaces.INameManager
 
 	// Set of CR k8s components
 
@@ -44,26 +42,34 @@ type Adapter struct {
 	sts        *STS
 }
 
-func NewAdapter(kubeClient kube.Interface, chopClient chopClientSet.Interface, namer interfaces.INameManager) *Adapter {
+// NewAdapter creates new adapter. CRs, events, deployments and replica sets are accessed in the local kube context,
+// other objects are accessed in the kube context specified by the context of a call
+func NewAdapter(clients *Clients, chopClient chopClientSet.Interface, namer interfaces.INameManager) *Adapter {
+	kubeClient := clients.Local()
 	return &Adapter{
-		kubeClient: kubeClient,
-		namer:      namer,
+		clients: clients,
+		namer:   namer,
 
 		cr: NewCR(chopClient, kubeClient),
 
-		configMap:  NewConfigMap(kubeClient),
+		configMap:  NewConfigMap(clients),
 		deployment: NewDeployment(kubeClient),
 		event:      NewEvent(kubeClient),
-		pdb:        NewPDB(kubeClient),
-		pod:        NewPod(kubeClient, namer),
-		pvc:        storage.NewStoragePVC(NewPVC(kubeClient)),
+		pdb:        NewPDB(clients),
+		pod:        NewPod(clients, namer),
+		pvc:        storage.NewStoragePVC(NewPVC(clients)),
 		replicaSet: NewReplicaSet(kubeClient),
-		secret:     NewSecret(kubeClient, namer),
-		service:    NewService(kubeClient, namer),
-		sts:        NewSTS(kubeClient, namer),
+		secret:     NewSecret(clients, namer),
+		service:    NewService(clients, namer),
+		sts:        NewSTS(clients, namer),
 	}
 }
 
+// Clients is a getter
+func (k *Adapter) Clients() *Clients {
+	return k.clients
+}
+
 // CR is a getter
 func (k *Adapter) CR() interfaces.IKubeCR {
 	return k.cr
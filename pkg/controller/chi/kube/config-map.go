@@ -21,7 +21,6 @@ import (
 	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
-	kube "k8s.io/client-go/kubernetes"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	"github.com/altinity/clickhouse-operator/pkg/controller"
@@ -29,33 +28,51 @@ import (
 )
 
 type ConfigMap struct {
-	kubeClient kube.Interface
+	clients *Clients
 }
 
-func NewConfigMap(kubeClient kube.Interface) *ConfigMap {
+func NewConfigMap(clients *Clients) *ConfigMap {
 	return &ConfigMap{
-		kubeClient: kubeClient,
+		clients: clients,
 	}
 }
 
 func (c *ConfigMap) Create(ctx context.Context, cm *core.ConfigMap) (*core.ConfigMap, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dropRemoteOwnerReferences(ctx, cm)
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().ConfigMaps(cm.Namespace).Create(ctx, cm, controller.NewCreateOptions())
+	return kubeClient.CoreV1().ConfigMaps(cm.Namespace).Create(ctx, cm, controller.NewCreateOptions())
 }
 
 func (c *ConfigMap) Get(ctx context.Context, namespace, name string) (*core.ConfigMap, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, controller.NewGetOptions())
+	return kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, controller.NewGetOptions())
 }
 
 func (c *ConfigMap) Update(ctx context.Context, cm *core.ConfigMap) (*core.ConfigMap, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dropRemoteOwnerReferences(ctx, cm)
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().ConfigMaps(cm.Namespace).Update(ctx, cm, controller.NewUpdateOptions())
+	return kubeClient.CoreV1().ConfigMaps(cm.Namespace).Update(ctx, cm, controller.NewUpdateOptions())
 }
 
 func (c *ConfigMap) Remove(ctx context.Context, namespace, name string) error {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().ConfigMaps(namespace).Delete(ctx, name, controller.NewDeleteOptions())
+	return kubeClient.CoreV1().ConfigMaps(namespace).Delete(ctx, name, controller.NewDeleteOptions())
 }
 
 func (c *ConfigMap) Delete(ctx context.Context, namespace, name string) error {
@@ -77,8 +94,12 @@ func (c *ConfigMap) Delete(ctx context.Context, namespace, name string) error {
 }
 
 func (c *ConfigMap) List(ctx context.Context, namespace string, opts meta.ListOptions) ([]core.ConfigMap, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	list, err := c.kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+	list, err := kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +75,7 @@ func (c *CR) getCR(ctx context.Context, namespace, name string) (*api.ClickHouse
 
 func (c *CR) getCM(ctx context.Context, chi api.ICustomResource) (*core.ConfigMap, error) {
 	ctx = k8sCtx(ctx)
-	return NewConfigMap(c.kubeClient).Get(ctx, c.buildCMNamespace(chi), c.buildCMName(chi))
+	return NewConfigMap(NewClients(c.kubeClient)).Get(ctx, c.buildCMNamespace(chi), c.buildCMName(chi))
 }
 
 // buildCR builds CR out of provided components
@@ -282,7 +282,9 @@ func (c *CR) statusUpdateCM(ctx context.Context, cm *core.ConfigMap) error {
 	if cm == nil {
 		return nil
 	}
-	cmm := NewConfigMap(c.kubeClient)
+	// CR is kept in the local kube context
+	ctx = withLocalKubeContext(ctx)
+	cmm := NewConfigMap(NewClients(c.kubeClient))
 	_, err := cmm.Update(ctx, cm)
 	if apiErrors.IsNotFound(err) {
 		_, err = cmm.Create(ctx, cm)
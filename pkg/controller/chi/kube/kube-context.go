@@ -0,0 +1,190 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// kubeContextKey is a key of context value, which names kube context objects are accessed in
+type kubeContextKey struct{}
+
+// WithKubeContext makes objects of the CR be accessed in the named kube context.
+// Empty name stands for the local kube context
+func WithKubeContext(ctx context.Context, cr api.ICustomResource, name string) context.Context {
+	if name == "" {
+		return withLocalKubeContext(ctx)
+	}
+	return context.WithValue(ctx, kubeContextKey{}, buildKubeContextKey(cr, name))
+}
+
+// withLocalKubeContext makes objects be accessed in the local kube context
+func withLocalKubeContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, kubeContextKey{}, "")
+}
+
+// WithHostKubeContext makes objects be accessed in the kube context the host lives in
+func WithHostKubeContext(ctx context.Context, host *api.Host) context.Context {
+	return WithKubeContext(ctx, host.GetCR(), host.GetKubeContext())
+}
+
+// buildKubeContextKey builds key clients of the named kube context of the CR are registered by.
+// Kube contexts are named by CRs, so the same name may refer to different clusters in different CRs
+func buildKubeContextKey(cr api.ICustomResource, name string) string {
+	return util.NamespaceNameString(cr) + "/" + name
+}
+
+// remoteClient is a client of a remote kube context
+type remoteClient struct {
+	client      kube.Interface
+	fingerprint string
+}
+
+// Clients keeps kube clients of the local and of the remote kube contexts
+type Clients struct {
+	local  kube.Interface
+	remote map[string]*remoteClient
+	mu     sync.RWMutex
+}
+
+// NewClients creates new clients
+func NewClients(local kube.Interface) *Clients {
+	return &Clients{
+		local:  local,
+		remote: make(map[string]*remoteClient),
+	}
+}
+
+// Local gets client of the local kube context
+func (c *Clients) Local() kube.Interface {
+	return c.local
+}
+
+// Get gets client of the kube context specified by ctx
+func (c *Clients) Get(ctx context.Context) (kube.Interface, error) {
+	key, _ := ctx.Value(kubeContextKey{}).(string)
+	if key == "" {
+		return c.local, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if remote, ok := c.remote[key]; ok {
+		return remote.client, nil
+	}
+	return nil, fmt.Errorf("kube context %s is not available", key)
+}
+
+// Register registers client of the named kube context of the CR built of the kubeconfig.
+// Client is rebuilt only in case kubeconfig has changed
+func (c *Clients) Register(cr api.ICustomResource, name string, kubeconfig []byte) error {
+	key := buildKubeContextKey(cr, name)
+	fingerprint := util.HashIntoString(kubeconfig)
+
+	c.mu.RLock()
+	remote, ok := c.remote[key]
+	c.mu.RUnlock()
+	if ok && (remote.fingerprint == fingerprint) {
+		return nil
+	}
+
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("unable to parse kubeconfig of kube context %s err: %v", key, err)
+	}
+	if err := validateKubeConfig(config); err != nil {
+		return fmt.Errorf("kubeconfig of kube context %s is not allowed err: %v", key, err)
+	}
+	restConfig, err := clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("unable to build client config of kube context %s err: %v", key, err)
+	}
+	client, err := kube.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("unable to create client of kube context %s err: %v", key, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remote[key] = &remoteClient{
+		client:      client,
+		fingerprint: fingerprint,
+	}
+	return nil
+}
+
+// validateKubeConfig checks kubeconfig provides credentials and certificates as inline data only.
+// Kubeconfig comes from the user-supplied secret, so neither commands to be run by the operator
+// nor files of the operator pod, such as its service account token, may be referenced
+func validateKubeConfig(config *clientcmdapi.Config) error {
+	for name, cluster := range config.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return fmt.Errorf("cluster %s refers to certificate-authority file, certificate-authority-data is expected", name)
+		}
+	}
+	for name, authInfo := range config.AuthInfos {
+		switch {
+		case authInfo.Exec != nil:
+			return fmt.Errorf("user %s specifies exec plugin", name)
+		case authInfo.AuthProvider != nil:
+			return fmt.Errorf("user %s specifies auth-provider", name)
+		case authInfo.TokenFile != "":
+			return fmt.Errorf("user %s refers to tokenFile, token is expected", name)
+		case authInfo.ClientCertificate != "":
+			return fmt.Errorf("user %s refers to client-certificate file, client-certificate-data is expected", name)
+		case authInfo.ClientKey != "":
+			return fmt.Errorf("user %s refers to client-key file, client-key-data is expected", name)
+		}
+	}
+	return nil
+}
+
+// Unregister unregisters clients of all kube contexts of the CR
+func (c *Clients) Unregister(cr api.ICustomResource) {
+	prefix := buildKubeContextKey(cr, "")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.remote {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.remote, key)
+		}
+	}
+}
+
+// isRemoteKubeContext checks whether ctx specifies remote kube context
+func isRemoteKubeContext(ctx context.Context) bool {
+	key, _ := ctx.Value(kubeContextKey{}).(string)
+	return key != ""
+}
+
+// dropRemoteOwnerReferences drops owner references of the object to be written into remote kube context.
+// Owner CR lives in the local kube context, so garbage collector of the remote cluster would delete the object
+func dropRemoteOwnerReferences(ctx context.Context, obj meta.Object) {
+	if isRemoteKubeContext(ctx) {
+		obj.SetOwnerReferences(nil)
+	}
+}
@@ -0,0 +1,95 @@
+package kube
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+const testKubeConfigTemplate = `
+apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: https://remote.example.com:6443
+%s
+users:
+- name: remote
+  user:
+%s
+contexts:
+- name: remote
+  context:
+    cluster: remote
+    user: remote
+current-context: remote
+`
+
+func buildTestKubeConfig(cluster, user string) []byte {
+	return []byte(fmt.Sprintf(testKubeConfigTemplate, cluster, user))
+}
+
+func Test_ClientsRegister(t *testing.T) {
+	cr := &api.ClickHouseInstallation{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "test",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		kubeconfig []byte
+		valid      bool
+	}{
+		{
+			name:       "inline data",
+			kubeconfig: buildTestKubeConfig("    insecure-skip-tls-verify: true", "    token: secret"),
+			valid:      true,
+		},
+		{
+			name:       "exec plugin",
+			kubeconfig: buildTestKubeConfig("", "    exec:\n      apiVersion: client.authentication.k8s.io/v1\n      command: /bin/sh"),
+		},
+		{
+			name:       "auth provider",
+			kubeconfig: buildTestKubeConfig("", "    auth-provider:\n      name: oidc"),
+		},
+		{
+			name:       "token file",
+			kubeconfig: buildTestKubeConfig("", "    tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token"),
+		},
+		{
+			name:       "client certificate file",
+			kubeconfig: buildTestKubeConfig("", "    client-certificate: /etc/tls/tls.crt\n    client-key-data: a2V5"),
+		},
+		{
+			name:       "client key file",
+			kubeconfig: buildTestKubeConfig("", "    client-certificate-data: Y2VydA==\n    client-key: /etc/tls/tls.key"),
+		},
+		{
+			name:       "certificate authority file",
+			kubeconfig: buildTestKubeConfig("    certificate-authority: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt", "    token: secret"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients := NewClients(fake.NewSimpleClientset())
+			err := clients.Register(cr, "remote", tt.kubeconfig)
+			if tt.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			_, err = clients.Get(WithKubeContext(t.Context(), cr, "remote"))
+			require.Error(t, err)
+		})
+	}
+}
@@ -21,7 +21,6 @@ import (
 	policy "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
-	kube "k8s.io/client-go/kubernetes"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	"github.com/altinity/clickhouse-operator/pkg/controller"
@@ -29,33 +28,49 @@ import (
 )
 
 type PDB struct {
-	kubeClient kube.Interface
+	clients *Clients
 }
 
-func NewPDB(kubeClient kube.Interface) *PDB {
+func NewPDB(clients *Clients) *PDB {
 	return &PDB{
-		kubeClient: kubeClient,
+		clients: clients,
 	}
 }
 
 func (c *PDB) Create(ctx context.Context, pdb *policy.PodDisruptionBudget) (*policy.PodDisruptionBudget, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Create(ctx, pdb, controller.NewCreateOptions())
+	return kubeClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Create(ctx, pdb, controller.NewCreateOptions())
 }
 
 func (c *PDB) Get(ctx context.Context, namespace, name string) (*policy.PodDisruptionBudget, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).Get(ctx, name, controller.NewGetOptions())
+	return kubeClient.PolicyV1().PodDisruptionBudgets(namespace).Get(ctx, name, controller.NewGetOptions())
 }
 
 func (c *PDB) Update(ctx context.Context, pdb *policy.PodDisruptionBudget) (*policy.PodDisruptionBudget, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Update(ctx, pdb, controller.NewUpdateOptions())
+	return kubeClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Update(ctx, pdb, controller.NewUpdateOptions())
 }
 
 func (c *PDB) Remove(ctx context.Context, namespace, name string) error {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).Delete(ctx, name, controller.NewDeleteOptions())
+	return kubeClient.PolicyV1().PodDisruptionBudgets(namespace).Delete(ctx, name, controller.NewDeleteOptions())
 }
 
 func (c *PDB) Delete(ctx context.Context, namespace, name string) error {
@@ -77,8 +92,12 @@ func (c *PDB) Delete(ctx context.Context, namespace, name string) error {
 }
 
 func (c *PDB) List(ctx context.Context, namespace string, opts meta.ListOptions) ([]policy.PodDisruptionBudget, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	list, err := c.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, opts)
+	list, err := kubeClient.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
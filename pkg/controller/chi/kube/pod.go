@@ -19,7 +19,6 @@ import (
 
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
-	kube "k8s.io/client-go/kubernetes"
 
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/controller"
@@ -28,14 +27,14 @@ import (
 )
 
 type Pod struct {
-	kubeClient kube.Interface
-	namer      interfaces.INameManager
+	clients *Clients
+	namer   interfaces.INameManager
 }
 
-func NewPod(kubeClient kube.Interface, namer interfaces.INameManager) *Pod {
+func NewPod(clients *Clients, namer interfaces.INameManager) *Pod {
 	return &Pod{
-		kubeClient: kubeClient,
-		namer:      namer,
+		clients: clients,
+		namer:   namer,
 	}
 }
 
@@ -57,6 +56,7 @@ func (c *Pod) Get(ctx context.Context, params ...any) (*core.Pod, error) {
 			name = c.namer.Name(interfaces.NamePod, obj)
 			namespace = typedObj.Namespace
 		case *api.Host:
+			ctx = WithHostKubeContext(ctx, typedObj)
 			name = c.namer.Name(interfaces.NamePod, obj)
 			namespace = typedObj.Runtime.Address.Namespace
 		default:
@@ -65,8 +65,12 @@ func (c *Pod) Get(ctx context.Context, params ...any) (*core.Pod, error) {
 	default:
 		panic(any("incorrect number or params"))
 	}
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().Pods(namespace).Get(ctx, name, controller.NewGetOptions())
+	return kubeClient.CoreV1().Pods(namespace).Get(ctx, name, controller.NewGetOptions())
 }
 
 func (c *Pod) GetRestartCounters(ctx context.Context, params ...any) (map[string]int, error) {
@@ -94,8 +98,12 @@ func (c *Pod) GetAll(ctx context.Context, obj any) []*core.Pod {
 }
 
 func (c *Pod) Update(ctx context.Context, pod *core.Pod) (*core.Pod, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().Pods(pod.GetNamespace()).Update(ctx, pod, controller.NewUpdateOptions())
+	return kubeClient.CoreV1().Pods(pod.GetNamespace()).Update(ctx, pod, controller.NewUpdateOptions())
 }
 
 type IWalkHosts interface {
@@ -120,6 +128,10 @@ func (c *Pod) getPod(ctx context.Context, host *api.Host) (pods []*core.Pod) {
 }
 
 func (c *Pod) Delete(ctx context.Context, namespace, name string) error {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().Pods(namespace).Delete(ctx, name, controller.NewDeleteOptions())
+	return kubeClient.CoreV1().Pods(namespace).Delete(ctx, name, controller.NewDeleteOptions())
 }
@@ -19,7 +19,6 @@ import (
 
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
-	kube "k8s.io/client-go/kubernetes"
 
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/controller"
@@ -28,38 +27,60 @@ import (
 )
 
 type PVC struct {
-	kubeClient kube.Interface
+	clients *Clients
 }
 
-func NewPVC(kubeClient kube.Interface) *PVC {
+func NewPVC(clients *Clients) *PVC {
 	return &PVC{
-		kubeClient: kubeClient,
+		clients: clients,
 	}
 }
 
 func (c *PVC) Create(ctx context.Context, pvc *core.PersistentVolumeClaim) (*core.PersistentVolumeClaim, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dropRemoteOwnerReferences(ctx, pvc)
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Create(ctx, pvc, controller.NewCreateOptions())
+	return kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Create(ctx, pvc, controller.NewCreateOptions())
 }
 
 func (c *PVC) Get(ctx context.Context, namespace, name string) (*core.PersistentVolumeClaim, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, controller.NewGetOptions())
+	return kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, controller.NewGetOptions())
 }
 
 func (c *PVC) Update(ctx context.Context, pvc *core.PersistentVolumeClaim) (*core.PersistentVolumeClaim, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dropRemoteOwnerReferences(ctx, pvc)
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Update(ctx, pvc, controller.NewUpdateOptions())
+	return kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Update(ctx, pvc, controller.NewUpdateOptions())
 }
 
 func (c *PVC) Delete(ctx context.Context, namespace, name string) error {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, name, controller.NewDeleteOptions())
+	return kubeClient.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, name, controller.NewDeleteOptions())
 }
 
 func (c *PVC) List(ctx context.Context, namespace string, opts meta.ListOptions) ([]core.PersistentVolumeClaim, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	list, err := c.kubeClient.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+	list, err := kubeClient.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -70,8 +91,13 @@ func (c *PVC) List(ctx context.Context, namespace string, opts meta.ListOptions)
 }
 
 func (c *PVC) ListForHost(ctx context.Context, host *api.Host) (*core.PersistentVolumeClaimList, error) {
+	ctx = WithHostKubeContext(ctx, host)
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.
+	return kubeClient.
 		CoreV1().
 		PersistentVolumeClaims(host.Runtime.Address.Namespace).
 		List(
@@ -21,7 +21,6 @@ import (
 	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
-	kube "k8s.io/client-go/kubernetes"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
@@ -31,14 +30,14 @@ import (
 )
 
 type Secret struct {
-	kubeClient kube.Interface
-	namer      interfaces.INameManager
+	clients *Clients
+	namer   interfaces.INameManager
 }
 
-func NewSecret(kubeClient kube.Interface, namer interfaces.INameManager) *Secret {
+func NewSecret(clients *Clients, namer interfaces.INameManager) *Secret {
 	return &Secret{
-		kubeClient: kubeClient,
-		namer:      namer,
+		clients: clients,
+		namer:   namer,
 	}
 }
 
@@ -60,27 +59,46 @@ func (c *Secret) Get(ctx context.Context, params ...any) (*core.Secret, error) {
 			name = typedObj.Name
 			namespace = typedObj.Namespace
 		case *api.Host:
+			ctx = WithHostKubeContext(ctx, typedObj)
 			name = c.namer.Name(interfaces.NameStatefulSetService, typedObj)
 			namespace = typedObj.Runtime.Address.Namespace
 		}
 	}
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, controller.NewGetOptions())
+	return kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, controller.NewGetOptions())
 }
 
 func (c *Secret) Create(ctx context.Context, svc *core.Secret) (*core.Secret, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dropRemoteOwnerReferences(ctx, svc)
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().Secrets(svc.Namespace).Create(ctx, svc, controller.NewCreateOptions())
+	return kubeClient.CoreV1().Secrets(svc.Namespace).Create(ctx, svc, controller.NewCreateOptions())
 }
 
 func (c *Secret) Update(ctx context.Context, svc *core.Secret) (*core.Secret, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dropRemoteOwnerReferences(ctx, svc)
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().Secrets(svc.Namespace).Update(ctx, svc, controller.NewUpdateOptions())
+	return kubeClient.CoreV1().Secrets(svc.Namespace).Update(ctx, svc, controller.NewUpdateOptions())
 }
 
 func (c *Secret) Remove(ctx context.Context, namespace, name string) error {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.CoreV1().Secrets(namespace).Delete(ctx, name, controller.NewDeleteOptions())
+	return kubeClient.CoreV1().Secrets(namespace).Delete(ctx, name, controller.NewDeleteOptions())
 }
 
 func (c *Secret) Delete(ctx context.Context, namespace, name string) error {
@@ -102,8 +120,12 @@ func (c *Secret) Delete(ctx context.Context, namespace, name string) error {
 }
 
 func (c *Secret) List(ctx context.Context, namespace string, opts meta.ListOptions) ([]core.Secret, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	list, err := c.kubeClient.CoreV1().Secrets(namespace).List(ctx, opts)
+	list, err := kubeClient.CoreV1().Secrets(namespace).List(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -21,7 +21,6 @@ import (
 	apps "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
-	kube "k8s.io/client-go/kubernetes"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
@@ -31,14 +30,14 @@ import (
 )
 
 type STS struct {
-	kubeClient kube.Interface
-	namer      interfaces.INameManager
+	clients *Clients
+	namer   interfaces.INameManager
 }
 
-func NewSTS(kubeClient kube.Interface, namer interfaces.INameManager) *STS {
+func NewSTS(clients *Clients, namer interfaces.INameManager) *STS {
 	return &STS{
-		kubeClient: kubeClient,
-		namer:      namer,
+		clients: clients,
+		namer:   namer,
 	}
 }
 
@@ -60,6 +59,7 @@ func (c *STS) Get(ctx context.Context, params ...any) (*apps.StatefulSet, error)
 			name = typedObj.GetName()
 			namespace = typedObj.GetNamespace()
 		case *api.Host:
+			ctx = WithHostKubeContext(ctx, typedObj)
 			// Namespaced name
 			name = c.namer.Name(interfaces.NameStatefulSet, obj)
 			namespace = typedObj.Runtime.Address.Namespace
@@ -69,24 +69,42 @@ func (c *STS) Get(ctx context.Context, params ...any) (*apps.StatefulSet, error)
 	default:
 		panic(any("unexpected number of args"))
 	}
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.AppsV1().StatefulSets(namespace).Get(ctx, name, controller.NewGetOptions())
+	return kubeClient.AppsV1().StatefulSets(namespace).Get(ctx, name, controller.NewGetOptions())
 }
 
 func (c *STS) Create(ctx context.Context, statefulSet *apps.StatefulSet) (*apps.StatefulSet, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dropRemoteOwnerReferences(ctx, statefulSet)
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.AppsV1().StatefulSets(statefulSet.Namespace).Create(ctx, statefulSet, controller.NewCreateOptions())
+	return kubeClient.AppsV1().StatefulSets(statefulSet.Namespace).Create(ctx, statefulSet, controller.NewCreateOptions())
 }
 
 // Update is an internal function, used in reconcileStatefulSet only
 func (c *STS) Update(ctx context.Context, sts *apps.StatefulSet) (*apps.StatefulSet, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dropRemoteOwnerReferences(ctx, sts)
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.AppsV1().StatefulSets(sts.Namespace).Update(ctx, sts, controller.NewUpdateOptions())
+	return kubeClient.AppsV1().StatefulSets(sts.Namespace).Update(ctx, sts, controller.NewUpdateOptions())
 }
 
 func (c *STS) Remove(ctx context.Context, namespace, name string) error {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return err
+	}
 	ctx = k8sCtx(ctx)
-	return c.kubeClient.AppsV1().StatefulSets(namespace).Delete(ctx, name, controller.NewDeleteOptions())
+	return kubeClient.AppsV1().StatefulSets(namespace).Delete(ctx, name, controller.NewDeleteOptions())
 }
 
 // Delete gracefully deletes StatefulSet through zeroing Pod's count
@@ -109,8 +127,12 @@ func (c *STS) Delete(ctx context.Context, namespace, name string) error {
 }
 
 func (c *STS) List(ctx context.Context, namespace string, opts meta.ListOptions) ([]apps.StatefulSet, error) {
+	kubeClient, err := c.clients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ctx = k8sCtx(ctx)
-	list, err := c.kubeClient.AppsV1().StatefulSets(namespace).List(ctx, opts)
+	list, err := kubeClient.AppsV1().StatefulSets(namespace).List(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
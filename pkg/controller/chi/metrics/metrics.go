@@ -52,6 +52,9 @@ type Metrics struct {
 	PodAddEvents    metric.Int64Counter
 	PodUpdateEvents metric.Int64Counter
 	PodDeleteEvents metric.Int64Counter
+
+	// ResourcesRecommendation is a gauge of CPU cores and memory bytes recommended for each host of a cluster
+	ResourcesRecommendation metric.Float64ObservableGauge
 }
 
 func createMetrics() *Metrics {
@@ -125,6 +128,12 @@ func createMetrics() *Metrics {
 		metric.WithUnit("items"),
 	)
 
+	m.ResourcesRecommendation, _ = operator.Meter().Float64ObservableGauge(
+		"clickhouse_operator_chi_resources_recommendation",
+		metric.WithDescription("CPU cores and memory bytes recommended for each host of the cluster"),
+		metric.WithFloat64Callback(observeResourcesRecommendations),
+	)
+
 	return m
 }
 
@@ -0,0 +1,95 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// recommendationValue is a single value of the resources recommendation along with its labels
+type recommendationValue struct {
+	attributes []attribute.KeyValue
+	value      float64
+}
+
+// recommendations are the latest resources recommendations indexed by CR and cluster
+var recommendations = map[string][]recommendationValue{}
+var recommendationsMx = sync.Mutex{}
+
+// ResourcesRecommendation publishes resources recommendation of the cluster of the CR
+func ResourcesRecommendation(src labelsSource, recommendation *api.ResourcesRecommendation) {
+	ensureMetrics()
+
+	var values []recommendationValue
+	add := func(resourceName, kind, value string) {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return
+		}
+		values = append(values, recommendationValue{
+			attributes: append(
+				prepareLabels(src),
+				attribute.String("cluster", recommendation.Cluster),
+				attribute.String("resource", resourceName),
+				attribute.String("type", kind),
+			),
+			value: quantity.AsApproximateFloat64(),
+		})
+	}
+	add("cpu", "request", recommendation.CPURequest)
+	add("cpu", "limit", recommendation.CPULimit)
+	add("memory", "request", recommendation.MemoryRequest)
+	add("memory", "limit", recommendation.MemoryLimit)
+
+	recommendationsMx.Lock()
+	defer recommendationsMx.Unlock()
+	recommendations[createRecommendationKey(src, recommendation.Cluster)] = values
+}
+
+// ResourcesRecommendationsDelete deletes all resources recommendations of the CR
+func ResourcesRecommendationsDelete(src labelsSource) {
+	recommendationsMx.Lock()
+	defer recommendationsMx.Unlock()
+	prefix := createRegistryKey(src) + "/"
+	for key := range recommendations {
+		if strings.HasPrefix(key, prefix) {
+			delete(recommendations, key)
+		}
+	}
+}
+
+// observeResourcesRecommendations reports all known resources recommendations
+func observeResourcesRecommendations(_ context.Context, o metric.Float64Observer) error {
+	recommendationsMx.Lock()
+	defer recommendationsMx.Unlock()
+	for _, values := range recommendations {
+		for _, value := range values {
+			o.Observe(value.value, metric.WithAttributes(value.attributes...))
+		}
+	}
+	return nil
+}
+
+func createRecommendationKey(src labelsSource, cluster string) string {
+	return createRegistryKey(src) + "/" + cluster
+}
@@ -163,6 +163,11 @@ func (w *worker) processReconcilePod(ctx context.Context, cmd *cmd_queue.Reconci
 	return nil
 }
 
+func (w *worker) processRebalanceCHI(ctx context.Context, cmd *cmd_queue.RebalanceCHI) error {
+	defer w.c.rebalances.Delete(cmd.Handle())
+	return w.rebalanceCR(ctx, cmd.Namespace, cmd.Name)
+}
+
 // processItem processes one work item according to its type
 func (w *worker) processItem(ctx context.Context, item interface{}) error {
 	if util.IsContextDone(ctx) {
@@ -186,6 +191,8 @@ func (w *worker) processItem(ctx context.Context, item interface{}) error {
 		return w.processReconcileEndpointSlice(ctx, cmd)
 	case *cmd_queue.ReconcilePod:
 		return w.processReconcilePod(ctx, cmd)
+	case *cmd_queue.RebalanceCHI:
+		return w.processRebalanceCHI(ctx, cmd)
 	}
 
 	// Unknown item type, don't know what to do with it
@@ -0,0 +1,359 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeTypes "k8s.io/apimachinery/pkg/types"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/controller"
+	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/interfaces"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// isCloneToBeMaterialized checks whether the CHI is a clone, which topology is not copied from the source yet
+func isCloneToBeMaterialized(chi *api.ClickHouseInstallation) bool {
+	return chi.GetSpecT().GetClone().HasSource() && (len(chi.GetSpecT().Configuration.GetClusters()) == 0)
+}
+
+// materializeClone copies topology of the source CHI into spec of the clone, which specifies no clusters of its own.
+// ZooKeeper config of the source is copied with the root moved under the source root, so replication paths
+// of the clone never collide with replication paths of the source. ZooKeeper config specified by the clone itself
+// is kept, while the root is moved all the same. Spec of the clone is patched, thus
+// reconcile is expected to be run on the patched CHI
+func (w *worker) materializeClone(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	source, normalized, err := w.getCloneSource(ctx, chi)
+	if err != nil {
+		return err
+	}
+	clone := chi.GetSpecT().GetClone()
+
+	var ops []patchOperation
+	if chi.GetSpecT().Configuration == nil {
+		ops = append(ops, patchOperation{
+			Op:    "add",
+			Path:  "/spec/configuration",
+			Value: map[string]any{},
+		})
+	}
+
+	clusters := source.DeepCopy().GetSpecT().Configuration.GetClusters()
+	for _, cluster := range clusters {
+		if cluster.Zookeeper == nil {
+			continue
+		}
+		// Cluster has ZooKeeper config of its own, which root has to be moved as well
+		root := cluster.Zookeeper.Root
+		if normalizedCluster, ok := normalized.FindCluster(cluster.GetName()).(*api.Cluster); ok && (normalizedCluster != nil) {
+			root = normalizedCluster.Zookeeper.Root
+		}
+		cluster.Zookeeper.Root = clone.GetZookeeperRoot(root, chi.GetNamespace(), chi.GetName())
+	}
+	ops = append(ops, patchOperation{
+		Op:    "add",
+		Path:  "/spec/configuration/clusters",
+		Value: clusters,
+	})
+
+	if zk := chi.GetSpecT().Configuration.GetZookeeper(); zk != nil {
+		// Clone has ZooKeeper config of its own, which may point to the ensemble of the source as well,
+		// so the root is moved in the same way the root of the source is
+		root := zk.Root
+		if sourceZk := normalized.GetSpecT().Configuration.GetZookeeper(); (root == "") && (sourceZk != nil) {
+			root = sourceZk.Root
+		}
+		ops = append(ops, patchOperation{
+			Op:    "add",
+			Path:  "/spec/configuration/zookeeper/root",
+			Value: clone.GetZookeeperRoot(root, chi.GetNamespace(), chi.GetName()),
+		})
+	} else if zk := normalized.GetSpecT().Configuration.GetZookeeper(); !zk.IsEmpty() {
+		zk = zk.DeepCopy()
+		zk.Root = clone.GetZookeeperRoot(zk.Root, chi.GetNamespace(), chi.GetName())
+		if zk.HasKeeperRef() {
+			// Nodes are resolved out of the referenced CHK
+			zk.Nodes = nil
+		}
+		ops = append(ops, patchOperation{
+			Op:    "add",
+			Path:  "/spec/configuration/zookeeper",
+			Value: zk,
+		})
+	}
+	if (chi.GetSpecT().Defaults == nil) && (source.GetSpecT().Defaults != nil) {
+		ops = append(ops, patchOperation{
+			Op:    "add",
+			Path:  "/spec/defaults",
+			Value: source.GetSpecT().Defaults,
+		})
+	}
+	if (chi.GetSpecT().Templates == nil) && (source.GetSpecT().Templates != nil) {
+		ops = append(ops, patchOperation{
+			Op:    "add",
+			Path:  "/spec/templates",
+			Value: source.GetSpecT().Templates,
+		})
+	}
+	if (len(chi.GetSpecT().UseTemplates) == 0) && (len(source.GetSpecT().UseTemplates) > 0) {
+		ops = append(ops, patchOperation{
+			Op:    "add",
+			Path:  "/spec/useTemplates",
+			Value: source.GetSpecT().UseTemplates,
+		})
+	}
+	payload, _ := json.Marshal(ops)
+
+	w.a.V(1).
+		WithEvent(chi, a.EventActionReconcile, a.EventReasonReconcileInProgress).
+		WithAction(chi).
+		M(chi).F().
+		Info("Copy topology of %s/%s into clone %s/%s", source.GetNamespace(), source.GetName(), chi.GetNamespace(), chi.GetName())
+
+	_, err = w.c.chopClient.ClickhouseV1().ClickHouseInstallations(chi.GetNamespace()).Patch(ctx, chi.GetName(), kubeTypes.JSONPatchType, payload, controller.NewPatchOptions())
+	return err
+}
+
+// getCloneSource gets the source CHI of the clone as is and normalized
+func (w *worker) getCloneSource(ctx context.Context, chi *api.ClickHouseInstallation) (source, normalized *api.ClickHouseInstallation, err error) {
+	clone := chi.GetSpecT().GetClone()
+	namespace := clone.GetSourceNamespace(chi.GetNamespace())
+	source, err = w.c.chopClient.ClickhouseV1().ClickHouseInstallations(namespace).Get(ctx, clone.Source.Name, controller.NewGetOptions())
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get source CHI %s/%s err: %v", namespace, clone.Source.Name, err)
+	}
+	normalized, err = w.c.normalizeCR(ctx, source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to normalize source CHI %s/%s err: %v", namespace, clone.Source.Name, err)
+	}
+	return source, normalized, nil
+}
+
+// reconcileClusterClone replays schema of the same-named cluster of the source CHI on hosts of the cluster
+// and copies data of the source. Cloning errors do not fail reconcile - cloning is resumed on the next reconcile,
+// shards which data is copied already are not copied again
+func (w *worker) reconcileClusterClone(ctx context.Context, cluster *api.Cluster) error {
+	if util.IsContextDone(ctx) {
+		log.V(1).Info("Reconcile is aborted. Cluster: %s ", cluster.GetName())
+		return nil
+	}
+
+	chi := cluster.GetCR()
+	clone := chi.GetSpecT().GetClone()
+	status := chi.EnsureStatus().GetClone(cluster.GetName())
+	if !clone.HasSource() || cluster.IsStopped() || status.IsCompleted() {
+		return nil
+	}
+
+	w.a.V(1).M(cluster).F().Info("Clone cluster: %s", cluster.GetName())
+
+	if status == nil {
+		status = &api.CloneStatus{
+			Cluster: cluster.GetName(),
+			Source:  clone.GetSourceNamespace(chi.GetNamespace()) + "/" + clone.Source.Name,
+		}
+	}
+	status.Status = api.CloneStatusInProgress
+	status.Error = ""
+
+	if err := w.cloneCluster(ctx, cluster, clone, status); err != nil {
+		w.a.V(1).
+			WithEvent(chi, a.EventActionReconcile, a.EventReasonReconcileFailed).
+			WithAction(chi).
+			M(cluster).F().
+			Warning("Clone of cluster: %s failed. err: %v", cluster.GetName(), err)
+		status.Status = api.CloneStatusFailed
+		status.Error = err.Error()
+	} else {
+		w.a.V(1).M(cluster).F().Info("Clone of cluster: %s completed", cluster.GetName())
+		status.Status = api.CloneStatusCompleted
+	}
+	w.persistCloneStatus(ctx, chi, status)
+
+	return nil
+}
+
+// cloneCluster replays schema and copies data shard by shard.
+// Shard of the clone is seeded from the shard of the source with the same index,
+// so number of shards of the clone has to match the source
+func (w *worker) cloneCluster(ctx context.Context, cluster *api.Cluster, clone *api.Clone, status *api.CloneStatus) error {
+	chi := cluster.GetCR()
+	_, source, err := w.getCloneSource(ctx, chi)
+	if err != nil {
+		return err
+	}
+	sourceCluster, ok := source.FindCluster(cluster.GetName()).(*api.Cluster)
+	if !ok || (sourceCluster == nil) {
+		return fmt.Errorf("source CHI %s has no cluster %s", status.Source, cluster.GetName())
+	}
+	if len(sourceCluster.Layout.Shards) != len(cluster.Layout.Shards) {
+		return fmt.Errorf("cluster %s has %d shards, source cluster has %d shards",
+			cluster.GetName(), len(cluster.Layout.Shards), len(sourceCluster.Layout.Shards))
+	}
+
+	var user, password string
+	if clone.IsDataCopied() {
+		if user, password, err = w.getCloneCredentials(ctx, chi, clone); err != nil {
+			return err
+		}
+	}
+
+	for shardIndex, shard := range cluster.Layout.Shards {
+		sourceShard := sourceCluster.Layout.Shards[shardIndex]
+		if len(sourceShard.Hosts) == 0 {
+			continue
+		}
+		for _, host := range shard.Hosts {
+			if err := w.ensureClusterSchemer(host).HostCloneTables(ctx, sourceShard.FirstHost(), host); err != nil {
+				return fmt.Errorf("unable to clone schema to host %s err: %v", host.GetName(), err)
+			}
+		}
+		if !clone.IsDataCopied() {
+			continue
+		}
+		if err := w.cloneShardData(ctx, chi, shardIndex, shard, sourceShard, user, password, status); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cloneShardData copies data of MergeTree tables of the source shard into the shard.
+// Replicated table is copied into the first replica only, data is replicated to other replicas of the shard.
+// Not replicated table is copied into each replica out of the source replica with the same index, if any.
+// Tables are copied one by one with INSERT ... SELECT, so the copy matches a point in time of the source shard
+// only as long as the source shard takes no writes meanwhile. Writes are detected by marks of the source tables
+// taken before and after the copy. Copy of the shard, which source took writes, is failed and is not recorded,
+// so the shard is copied anew by the next reconcile
+func (w *worker) cloneShardData(
+	ctx context.Context,
+	chi *api.ClickHouseInstallation,
+	shardIndex int,
+	shard, sourceShard *api.ChiShard,
+	user, password string,
+	status *api.CloneStatus,
+) error {
+	tables, err := w.ensureClusterSchemer(sourceShard.FirstHost()).HostRebalanceTables(ctx, sourceShard.FirstHost())
+	if err != nil {
+		return fmt.Errorf("unable to list tables of source shard %d err: %v", shardIndex, err)
+	}
+
+	// Tables of the shard are copied as a whole, so all of them match the same point in time
+	var keys []string
+	copied := true
+	for _, table := range tables {
+		key := fmt.Sprintf("%d/%s.%s", shardIndex, table.Database, table.Name)
+		keys = append(keys, key)
+		copied = copied && status.HasTable(key)
+	}
+	if copied {
+		return nil
+	}
+
+	before, err := w.getSourceShardWriteMarks(ctx, sourceShard)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if util.IsContextDone(ctx) {
+			return nil
+		}
+		for replicaIndex, host := range shard.Hosts {
+			if table.IsReplicated() && (replicaIndex > 0) {
+				break
+			}
+			sourceHost := sourceShard.FirstHost()
+			if replicaIndex < len(sourceShard.Hosts) {
+				sourceHost = sourceShard.Hosts[replicaIndex]
+			}
+			address := fmt.Sprintf("%s:%d", w.c.namer.Name(interfaces.NameFQDN, sourceHost), sourceHost.TCPPort.Value())
+			if err := w.ensureClusterSchemer(host).HostCloneTableData(ctx, host, table, address, user, password); err != nil {
+				return fmt.Errorf("unable to clone data of %s.%s to host %s err: %v", table.Database, table.Name, host.GetName(), err)
+			}
+		}
+	}
+	after, err := w.getSourceShardWriteMarks(ctx, sourceShard)
+	if err != nil {
+		return err
+	}
+	if !maps.EqualFunc(before, after, maps.Equal[map[string]string]) {
+		return fmt.Errorf("source shard %d took writes while being copied, writes into the source have to be stopped for the clone to be seeded", shardIndex)
+	}
+
+	status.Tables = append(status.Tables, keys...)
+	w.persistCloneStatus(ctx, chi, status)
+	return nil
+}
+
+// getSourceShardWriteMarks gets marks of writes into tables of each host of the source shard
+func (w *worker) getSourceShardWriteMarks(ctx context.Context, sourceShard *api.ChiShard) (map[string]map[string]string, error) {
+	marks := make(map[string]map[string]string)
+	for _, host := range sourceShard.Hosts {
+		hostMarks, err := w.ensureClusterSchemer(host).HostTablesWriteMarks(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get write marks of source host %s err: %v", host.GetName(), err)
+		}
+		marks[host.GetName()] = hostMarks
+	}
+	return marks, nil
+}
+
+// getCloneCredentials gets user and password the clone hosts read data of the source hosts with
+func (w *worker) getCloneCredentials(ctx context.Context, chi *api.ClickHouseInstallation, clone *api.Clone) (string, string, error) {
+	if (clone.Password == nil) || (clone.Password.SecretKeyRef == nil) {
+		return clone.User, "", nil
+	}
+	ref := clone.Password.SecretKeyRef
+	secret, err := w.c.getSecret(ctx, &core.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: chi.GetNamespace(),
+			Name:      ref.Name,
+		},
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("unable to get secret %s/%s err: %v", chi.GetNamespace(), ref.Name, err)
+	}
+	password, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", "", fmt.Errorf("secret %s/%s has no key %s", chi.GetNamespace(), ref.Name, ref.Key)
+	}
+	return clone.User, string(password), nil
+}
+
+// persistCloneStatus sets clone status of the cluster and updates status of the CHI
+func (w *worker) persistCloneStatus(ctx context.Context, chi *api.ClickHouseInstallation, status *api.CloneStatus) {
+	status.Time = time.Now().UTC().Format(time.RFC3339)
+	chi.EnsureStatus().SetClone(status)
+	_ = w.c.updateCRObjectStatus(ctx, chi, types.UpdateStatusOptions{
+		CopyStatusOptions: types.CopyStatusOptions{
+			CopyStatusField: types.CopyStatusField{
+				Copy: types.Status{
+					Clone: true,
+				},
+			},
+		},
+	})
+}
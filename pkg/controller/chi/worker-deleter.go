@@ -25,6 +25,7 @@ import (
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
 	"github.com/altinity/clickhouse-operator/pkg/controller"
+	chiKube "github.com/altinity/clickhouse-operator/pkg/controller/chi/kube"
 	"github.com/altinity/clickhouse-operator/pkg/controller/chi/metrics"
 	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common/storage"
@@ -107,6 +108,10 @@ func (w *worker) purge(
 			w.purgePDB(ctx, cr, reconcileFailedObjs, m)
 		}
 	})
+	reg.WalkKubeContexts(func(name string, reg *model.Registry) {
+		// Objects living in remote kube contexts are deleted there
+		cnt += w.purge(chiKube.WithKubeContext(ctx, cr, name), cr, reg, reconcileFailedObjs.KubeContext(name))
+	})
 	return cnt
 }
 
@@ -245,6 +250,7 @@ func (w *worker) discoveryAndDeleteCR(ctx context.Context, cr api.ICustomResourc
 	}
 
 	metrics.CRUnregister(ctx, cr)
+	metrics.ResourcesRecommendationsDelete(cr)
 
 	objs := w.c.discovery(ctx, cr)
 	if objs.NumStatefulSet() > 0 {
@@ -276,6 +282,8 @@ func (w *worker) deleteCHIProtocol(ctx context.Context, chi *api.ClickHouseInsta
 			Error("Delete CHI failed - unable to normalize: %q", err)
 		return err
 	}
+	w.reconcileKubeContexts(ctx, chi)
+	defer w.c.kubeContexts.Unregister(chi)
 
 	// Announce delete procedure
 	w.a.V(1).
@@ -321,7 +329,9 @@ func (w *worker) deleteCHIProtocol(ctx context.Context, chi *api.ClickHouseInsta
 	}
 
 	// Delete ConfigMap(s)
-	_ = w.c.deleteConfigMapsCHI(ctx, chi)
+	_ = w.walkKubeContexts(ctx, chi, func(ctx context.Context, _ string) error {
+		return w.c.deleteConfigMapsCHI(ctx, chi)
+	})
 
 	w.a.V(1).
 		WithEvent(chi, a.EventActionDelete, a.EventReasonDeleteCompleted).
@@ -636,7 +646,9 @@ func (w *worker) deleteCluster(ctx context.Context, chi *api.ClickHouseInstallat
 	// Delete ChkCluster's Auto Secret
 	if cluster.Secret.Source() == api.ClusterSecretSourceAuto {
 		// Delete ChkCluster Secret
-		_ = w.c.deleteSecretCluster(ctx, cluster)
+		_ = w.walkKubeContexts(ctx, chi, func(ctx context.Context, _ string) error {
+			return w.c.deleteSecretCluster(ctx, cluster)
+		})
 	}
 
 	// Delete all shards
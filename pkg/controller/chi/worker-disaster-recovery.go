@@ -0,0 +1,287 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/poller"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/poller/domain"
+	"github.com/altinity/clickhouse-operator/pkg/interfaces"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	// disasterRecoveryCheckPeriod specifies how often replication lag of DR CHIs is checked
+	disasterRecoveryCheckPeriod = time.Minute
+	// disasterRecoveryPromoteTimeout specifies how long replicated tables of a host are waited to switch to
+	// own ZooKeeper root of the promoted DR CHI, which requires updated config to reach the host
+	disasterRecoveryPromoteTimeout = 5 * time.Minute
+)
+
+// getDisasterRecoveryRuntime resolves installations related to the normalized CHI by disaster recovery:
+// the primary, in case the CHI is a DR replica, and DR replicas of the CHI.
+// Installations, which hosts can not safely replicate each other, are left out
+func (c *Controller) getDisasterRecoveryRuntime(ctx context.Context, chi *api.ClickHouseInstallation) *api.DisasterRecoveryRuntime {
+	r := &api.DisasterRecoveryRuntime{}
+
+	if dr := chi.GetSpecT().GetDisasterRecovery(); dr.IsReplica() {
+		primary, err := c.getDisasterRecoveryPrimary(ctx, chi)
+		if err == nil {
+			err = c.checkDisasterRecoveryPeers(primary, chi)
+		}
+		if err == nil {
+			r.Primary = primary
+		} else {
+			log.V(1).M(chi).F().Warning("Primary CHI is not replicated. err: %v", err)
+			r.Error = err.Error()
+		}
+	}
+
+	for _, replica := range c.listDisasterRecoveryReplicas(chi) {
+		normalized, err := c.normalizeCR(ctx, replica)
+		if err == nil {
+			err = c.checkDisasterRecoveryPeers(chi, normalized)
+		}
+		if err != nil {
+			log.V(1).M(chi).F().Warning("DR CHI %s does not replicate the CHI. err: %v", util.NamespaceNameString(replica), err)
+			continue
+		}
+		r.Replicas = append(r.Replicas, normalized)
+	}
+
+	if (r.Primary == nil) && (len(r.Replicas) == 0) && (r.Error == "") {
+		return nil
+	}
+	return r
+}
+
+// getDisasterRecoveryPrimary gets normalized primary of the DR CHI
+func (c *Controller) getDisasterRecoveryPrimary(ctx context.Context, chi *api.ClickHouseInstallation) (*api.ClickHouseInstallation, error) {
+	dr := chi.GetSpecT().GetDisasterRecovery()
+	namespace := dr.GetPrimaryNamespace(chi.GetNamespace())
+	primary, err := c.chiLister.ClickHouseInstallations(namespace).Get(dr.Primary.Name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get primary CHI %s/%s err: %v", namespace, dr.Primary.Name, err)
+	}
+	if primary.GetSpecT().GetDisasterRecovery().IsReplica() {
+		return nil, fmt.Errorf("primary CHI %s/%s is a DR replica itself", namespace, dr.Primary.Name)
+	}
+	return c.normalizeCR(ctx, primary)
+}
+
+// listDisasterRecoveryReplicas lists DR CHIs, which replicate the CHI.
+// CHIs are listed from the informer cache and must not be modified
+func (c *Controller) listDisasterRecoveryReplicas(chi *api.ClickHouseInstallation) []*api.ClickHouseInstallation {
+	list, err := c.chiLister.List(labels.Everything())
+	if err != nil {
+		log.V(1).M(chi).F().Error("unable to list CHIs. err: %v", err)
+		return nil
+	}
+	var replicas []*api.ClickHouseInstallation
+	for _, item := range list {
+		if item.GetSpecT().GetDisasterRecovery().IsReplicaOf(item.GetNamespace(), chi.GetNamespace(), chi.GetName()) {
+			replicas = append(replicas, item)
+		}
+	}
+	return replicas
+}
+
+// checkDisasterRecoveryPeers checks whether hosts of the replica CHI can safely be replicas of the primary CHI
+func (c *Controller) checkDisasterRecoveryPeers(primary, replica *api.ClickHouseInstallation) error {
+	return api.CheckDisasterRecoveryPeers(primary, replica, func(host *api.Host) string {
+		return c.namer.Name(interfaces.NameInstanceHostname, host)
+	})
+}
+
+// isDisasterRecoveryChanged checks whether set of DR CHIs replicating the CHI has changed since the last reconcile,
+// so remote servers of the CHI have to be updated
+func (w *worker) isDisasterRecoveryChanged(ctx context.Context, cr *api.ClickHouseInstallation) bool {
+	if cr.EnsureStatus().GetNormalizedCRCompleted() == nil {
+		// CR was not reconciled yet, nothing to compare with
+		return false
+	}
+	var prev []string
+	if status := cr.EnsureStatus().GetDisasterRecovery(); status != nil {
+		prev = status.Replicas
+	}
+	return !slices.Equal(prev, disasterRecoveryNames(w.c.listDisasterRecoveryReplicas(cr)))
+}
+
+// disasterRecoveryNames lists namespace/name of the CHIs
+func disasterRecoveryNames(chis []*api.ClickHouseInstallation) (names []string) {
+	for _, chi := range chis {
+		names = append(names, util.NamespaceNameString(chi))
+	}
+	slices.Sort(names)
+	return names
+}
+
+// reconcileDisasterRecoveryStatus reports role of the CHI in disaster recovery along with the related installations
+func (w *worker) reconcileDisasterRecoveryStatus(ctx context.Context, cr *api.ClickHouseInstallation) {
+	dr := cr.GetSpecT().GetDisasterRecovery()
+	replicas := disasterRecoveryNames(w.c.listDisasterRecoveryReplicas(cr))
+	prev := cr.EnsureStatus().GetDisasterRecovery()
+
+	var status *api.DisasterRecoveryStatus
+	switch {
+	case dr.IsPromoted():
+		// Promotion completes as soon as replicated tables of all hosts are moved to own ZooKeeper root
+		role := api.DisasterRecoveryRolePromoting
+		if (prev != nil) && (prev.Role == api.DisasterRecoveryRoleStandalone) {
+			role = api.DisasterRecoveryRoleStandalone
+		}
+		status = &api.DisasterRecoveryStatus{
+			Role: role,
+		}
+	case dr.IsReplica():
+		status = &api.DisasterRecoveryStatus{
+			Role:  api.DisasterRecoveryRoleReplica,
+			Error: cr.EnsureRuntime().DisasterRecovery.GetError(),
+		}
+		if prev != nil {
+			// Lag is reported by the monitor
+			status.MaxReplicaDelay = prev.MaxReplicaDelay
+			status.LagTime = prev.LagTime
+			status.LagError = prev.LagError
+		}
+	case len(replicas) > 0:
+		status = &api.DisasterRecoveryStatus{
+			Role: api.DisasterRecoveryRolePrimary,
+		}
+	}
+	if status != nil {
+		if dr.HasPrimary() {
+			status.Primary = dr.GetPrimaryNamespace(cr.GetNamespace()) + "/" + dr.Primary.Name
+		}
+		status.Replicas = replicas
+	}
+	if (status == nil) && (prev == nil) {
+		return
+	}
+
+	cr.EnsureStatus().SetDisasterRecovery(status)
+	w.c.updateDisasterRecoveryStatus(ctx, cr)
+}
+
+// isDisasterRecoveryPromoting checks whether CR is a promoted DR CHI, which replicated tables are not moved
+// to own ZooKeeper root yet
+func isDisasterRecoveryPromoting(cr api.ICustomResource) bool {
+	chi, ok := cr.(*api.ClickHouseInstallation)
+	if !ok {
+		return false
+	}
+	status := chi.EnsureStatus().GetDisasterRecovery()
+	return chi.GetSpecT().GetDisasterRecovery().IsPromoted() && (status != nil) && (status.Role == api.DisasterRecoveryRolePromoting)
+}
+
+// promoteDisasterRecoveryHost moves replicated tables of the host of the promoted DR CHI to own ZooKeeper root.
+// Root is switched by zookeeper config of the host, so tables lose their metadata in ZooKeeper and turn read-only.
+// As soon as all replicated tables of the host are read-only, metadata is restored out of the local parts of the host
+func (w *worker) promoteDisasterRecoveryHost(ctx context.Context, host *api.Host) error {
+	if !isDisasterRecoveryPromoting(host.GetCR()) {
+		return nil
+	}
+
+	w.a.V(1).M(host).F().Info("Move replicated tables of the host %s to own ZooKeeper root", host.GetName())
+	schemer := w.ensureClusterSchemer(host)
+	var readOnly []string
+	err := domain.PollHost(
+		ctx,
+		host,
+		func(_ctx context.Context, _host *api.Host) bool {
+			if err := schemer.HostRestartReplicas(_ctx, _host); err != nil {
+				return false
+			}
+			tables, _readOnly, err := schemer.HostReadOnlyReplicas(_ctx, _host)
+			readOnly = _readOnly
+			return (err == nil) && (len(readOnly) == len(tables))
+		},
+		&poller.Options{
+			Timeout: disasterRecoveryPromoteTimeout,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("replicated tables of the host %s are not switched to own ZooKeeper root. err: %v", host.GetName(), err)
+	}
+	return schemer.HostRestoreReplicas(ctx, host, readOnly)
+}
+
+// completeDisasterRecoveryPromotion reports promotion of the DR CHI completed, as all hosts are reconciled successfully
+func (w *worker) completeDisasterRecoveryPromotion(cr *api.ClickHouseInstallation) {
+	if isDisasterRecoveryPromoting(cr) {
+		cr.EnsureStatus().GetDisasterRecovery().Role = api.DisasterRecoveryRoleStandalone
+	}
+}
+
+// updateDisasterRecoveryStatus updates disaster recovery status of the CHI
+func (c *Controller) updateDisasterRecoveryStatus(ctx context.Context, chi *api.ClickHouseInstallation) {
+	_ = c.updateCRObjectStatus(ctx, chi, types.UpdateStatusOptions{
+		CopyStatusOptions: types.CopyStatusOptions{
+			CopyStatusField: types.CopyStatusField{
+				Copy: types.Status{
+					DisasterRecovery: true,
+				},
+			},
+		},
+	})
+}
+
+// hasDisasterRecoveryLag checks whether replication lag of the DR CHI has to be reported.
+// CHIs being reconciled are skipped, as hosts may be added or removed meanwhile.
+func hasDisasterRecoveryLag(chi *api.ClickHouseInstallation) bool {
+	return chi.GetSpecT().GetDisasterRecovery().IsReplica() &&
+		(chi.EnsureStatus().GetDisasterRecovery() != nil) &&
+		!chi.IsStopped() &&
+		!chi.Spec.Suspend.Value() &&
+		(chi.EnsureStatus().GetStatus() != api.StatusInProgress)
+}
+
+// monitorDisasterRecoveryCR reports max replication delay over the hosts of the DR CHI
+func (c *Controller) monitorDisasterRecoveryCR(ctx context.Context, chi, normalized *api.ClickHouseInstallation) {
+	status := chi.EnsureStatus().GetDisasterRecovery()
+
+	w := c.newWorker(nil, true)
+	delay := 0
+	var unreachable []string
+	normalized.WalkHosts(func(host *api.Host) error {
+		hostDelay, err := w.ensureClusterSchemer(host).HostMaxReplicaDelay(ctx, host)
+		if err != nil {
+			unreachable = append(unreachable, host.GetName())
+			return nil
+		}
+		delay = max(delay, hostDelay)
+		return nil
+	})
+
+	status.MaxReplicaDelay = delay
+	status.LagTime = time.Now().UTC().Format(time.RFC3339)
+	status.LagError = ""
+	if len(unreachable) > 0 {
+		status.LagError = fmt.Sprintf("unable to get replication delay of hosts: %v", unreachable)
+	}
+	log.V(1).M(chi).F().Info("DR CHI max replication delay: %ds", delay)
+
+	chi.EnsureStatus().SetDisasterRecovery(status)
+	c.updateDisasterRecoveryStatus(ctx, chi)
+}
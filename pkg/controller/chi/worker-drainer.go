@@ -0,0 +1,314 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
+	commonNormalizer "github.com/altinity/clickhouse-operator/pkg/model/common/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// keepUndrainedShards keeps shards removed from the clusters of the CR until their data is drained.
+// Removed shards are appended back to the layouts of the clusters, so the rest of the CR is reconciled,
+// while the removed shards stay in remote_servers and are not deleted. Shards are deleted by the reconcile
+// following the completed drain
+func (w *worker) keepUndrainedShards(
+	_cr *api.ClickHouseInstallation,
+	cr *api.ClickHouseInstallation,
+	_opts ...*commonNormalizer.Options[api.ClickHouseInstallation],
+) *api.ClickHouseInstallation {
+	kept := make(map[string][]string)
+	cr.WalkClusters(func(c api.ICluster) error {
+		cluster := c.(*api.Cluster)
+		if removed := removedShards(cluster); (len(removed) > 0) && !isDrained(cluster, removed) {
+			kept[cluster.GetName()] = removed
+		}
+		return nil
+	})
+	if len(kept) == 0 {
+		return cr
+	}
+
+	patched := _cr.DeepCopy()
+	for name, removed := range kept {
+		cluster, _ := patched.FindCluster(name).(*api.Cluster)
+		if cluster == nil {
+			// Cluster comes from the templates, shards are reported as not kept
+			continue
+		}
+		cluster.Layout = cluster.Layout.Ensure()
+		for len(cluster.Layout.Shards) < len(cr.FindCluster(name).(*api.Cluster).Layout.Shards) {
+			cluster.Layout.Shards = append(cluster.Layout.Shards, &api.ChiShard{})
+		}
+		for _, shard := range removed {
+			cluster.Layout.Shards = append(cluster.Layout.Shards, &api.ChiShard{Name: shard})
+		}
+		cluster.Layout.ShardsCount = len(cluster.Layout.Shards)
+	}
+
+	ancestor := cr.GetAncestorT()
+	cr = w.createTemplated(patched, _opts...)
+	cr.SetAncestor(ancestor)
+	for name, removed := range kept {
+		cr.FindCluster(name).(*api.Cluster).Runtime.KeptShards = removed
+	}
+	return cr
+}
+
+// removedShards lists shards of the ancestor of the cluster, which are removed from the cluster
+func removedShards(cluster *api.Cluster) (removed []string) {
+	if cluster.GetAncestor().IsZero() {
+		return nil
+	}
+	cluster.GetAncestor().WalkShards(func(index int, shard api.IShard) error {
+		if cluster.FindShard(shard.GetName()).IsZero() {
+			removed = append(removed, shard.GetName())
+		}
+		return nil
+	})
+	return removed
+}
+
+// isDrained checks whether drain of the shards is completed and verified
+func isDrained(cluster *api.Cluster, shards []string) bool {
+	status := cluster.GetCR().EnsureStatus().GetRebalance(cluster.GetName())
+	return status.IsDrain() && status.IsFinished() && slices.Equal(status.DrainShards, shards)
+}
+
+// isDrainCompleted checks whether drain of some shards is completed, while the shards are not deleted yet
+func isDrainCompleted(cr *api.ClickHouseInstallation) bool {
+	if !cr.HasAncestor() {
+		return false
+	}
+	for _, status := range cr.EnsureStatus().Rebalance {
+		if !status.IsDrain() || !status.IsFinished() {
+			continue
+		}
+		cluster := cr.GetAncestorT().FindCluster(status.Cluster)
+		if cluster.IsZero() {
+			continue
+		}
+		for _, shard := range status.DrainShards {
+			if !cluster.FindShard(shard).IsZero() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isDrainPending checks whether shards are kept in some cluster, while drain of the shards is not running
+func isDrainPending(cr *api.ClickHouseInstallation) bool {
+	pending := false
+	cr.WalkClusters(func(c api.ICluster) error {
+		cluster := c.(*api.Cluster)
+		kept := cluster.Runtime.KeptShards
+		status := cr.EnsureStatus().GetRebalance(cluster.GetName())
+		if (len(kept) > 0) && !(status.IsInProgress() && slices.Equal(status.DrainShards, kept)) {
+			pending = true
+		}
+		return nil
+	})
+	return pending
+}
+
+// drainRemovedShards starts drain of the shards kept in the clusters. Drain runs in background steps,
+// so neither a long drain blocks reconcile nor drain failure fails it - kept shards are just not deleted
+func (w *worker) drainRemovedShards(ctx context.Context, cr *api.ClickHouseInstallation) error {
+	var err error
+	cr.WalkClusters(func(c api.ICluster) error {
+		if err == nil {
+			err = w.drainClusterRemovedShards(ctx, c.(*api.Cluster))
+		}
+		return nil
+	})
+	return err
+}
+
+// drainClusterRemovedShards starts drain of the shards kept in the cluster
+func (w *worker) drainClusterRemovedShards(ctx context.Context, cluster *api.Cluster) error {
+	if util.IsContextDone(ctx) {
+		log.V(1).Info("Reconcile is aborted. Cluster: %s ", cluster.GetName())
+		return nil
+	}
+
+	chi := cluster.GetCR()
+	status := chi.EnsureStatus().GetRebalance(cluster.GetName())
+	kept := cluster.Runtime.KeptShards
+
+	if len(kept) == 0 {
+		if status.IsDrain() && !status.IsFinished() {
+			// Shards are kept in the cluster, drain is abandoned
+			w.abandonDrain(ctx, cluster, status)
+		}
+		return nil
+	}
+
+	for _, shard := range kept {
+		if cluster.FindShard(shard).IsZero() {
+			// Shards can not be kept, so the whole reconcile is refused in order not to delete them
+			return fmt.Errorf("unable to keep removed shards %v of cluster %s until drained", kept, cluster.GetName())
+		}
+	}
+
+	switch {
+	case !cluster.GetReconcile().Drain.IsEnabled():
+		w.pauseDrain(ctx, cluster, status, "drain is disabled")
+		return nil
+	case cluster.IsStopped():
+		w.pauseDrain(ctx, cluster, status, "cluster is stopped")
+		return nil
+	case status.IsInProgress() && slices.Equal(status.DrainShards, kept):
+		// Drain is run in background
+		return nil
+	}
+
+	w.a.V(1).M(cluster).F().Info("Drain shards %v of cluster: %s", kept, cluster.GetName())
+	status = newDrainStatus(cluster, status)
+	status.Status = api.RebalanceStatusInProgress
+	status.Error = ""
+	chi.EnsureStatus().SetRebalance(status)
+	w.updateRebalanceStatus(ctx, chi)
+	return nil
+}
+
+// pauseDrain reports drain of the shards kept in the cluster can not be run
+func (w *worker) pauseDrain(ctx context.Context, cluster *api.Cluster, status *api.RebalanceStatus, reason string) {
+	chi := cluster.GetCR()
+	w.a.V(1).
+		WithEvent(chi, a.EventActionReconcile, a.EventReasonReconcileInProgress).
+		WithError(chi).
+		M(cluster).F().
+		Warning("Shards %v of cluster %s are not deleted, %s. Data of the shards has to be drained",
+			cluster.Runtime.KeptShards, cluster.GetName(), reason)
+	status = newDrainStatus(cluster, status)
+	status.Pause(reason)
+	chi.EnsureStatus().SetRebalance(status)
+	w.updateRebalanceStatus(ctx, chi)
+}
+
+// newDrainStatus prepares rebalance status of the cluster for drain of the kept shards
+func newDrainStatus(cluster *api.Cluster, status *api.RebalanceStatus) *api.RebalanceStatus {
+	kept := cluster.Runtime.KeptShards
+	switch {
+	case status.IsFinished():
+		// Start new drain
+		status = &api.RebalanceStatus{
+			Cluster: cluster.GetName(),
+		}
+	case !status.IsDrain() || !slices.Equal(status.DrainShards, kept):
+		// Status of unfinished rebalance or drain of another shards. Tables are re-evaluated,
+		// interrupted moves are kept in order to be resumed
+		for _, table := range status.Tables {
+			table.Status = ""
+			table.Error = ""
+		}
+	}
+	status.DrainShards = kept
+	return status
+}
+
+// drainClusterStep takes a step of the drain of the shards kept in the cluster. Once data is moved
+// and verified to be gone from the drained shards, drain is completed and the following reconcile deletes the shards
+func (w *worker) drainClusterStep(ctx context.Context, cluster *api.Cluster, status *api.RebalanceStatus) {
+	r := newClusterRebalancer(w, cluster, status)
+	r.background = true
+	err := r.rebalance(ctx)
+	if err == nil {
+		err = r.verifyDrained(ctx)
+	}
+	switch {
+	case errors.Is(err, errRebalanceInProgress):
+		w.a.V(2).M(cluster).F().Info("Drain shards %v of cluster: %s is in progress", status.DrainShards, cluster.GetName())
+	case err != nil:
+		w.a.V(1).M(cluster).F().Warning("Drain shards %v of cluster: %s paused, shards are not deleted. err: %v",
+			status.DrainShards, cluster.GetName(), err)
+		status.Pause(err.Error())
+	default:
+		w.a.V(1).M(cluster).F().Info("Drain shards %v of cluster: %s completed", status.DrainShards, cluster.GetName())
+		status.Status = api.RebalanceStatusCompleted
+	}
+	r.persist(ctx)
+}
+
+// abandonDrain completes moves interrupted by the drain, as shards are kept in the cluster now
+func (w *worker) abandonDrain(ctx context.Context, cluster *api.Cluster, status *api.RebalanceStatus) {
+	status.DrainShards = nil
+	r := newClusterRebalancer(w, cluster, status)
+	for _, table := range status.Tables {
+		if table.Move == nil {
+			continue
+		}
+		err := r.resumeTableMove(ctx, table)
+		if err != nil {
+			w.a.V(1).M(cluster).F().Warning("Unable to complete move of partition of %s.%s. err: %v", table.Database, table.Table, err)
+			status.Pause(err.Error())
+			r.persist(ctx)
+			return
+		}
+	}
+	chi := cluster.GetCR()
+	chi.EnsureStatus().DeleteRebalance(cluster.GetName())
+	w.updateRebalanceStatus(ctx, chi)
+}
+
+// resumeTableMove completes move interrupted previously
+func (r *clusterRebalancer) resumeTableMove(ctx context.Context, status *api.RebalanceTableStatus) error {
+	from, _, err := r.moveHosts(status.Move)
+	if err != nil {
+		return err
+	}
+	tables, err := r.schemer(from).HostRebalanceTables(ctx, from)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if (table.Database == status.Database) && (table.Name == status.Table) {
+			return r.resumeMove(ctx, table, status)
+		}
+	}
+	return fmt.Errorf("table %s.%s is not found on host %s", status.Database, status.Table, from.GetName())
+}
+
+// verifyDrained checks drained shards have no data left
+func (r *clusterRebalancer) verifyDrained(ctx context.Context) error {
+	for _, index := range r.drain {
+		host := r.hosts[index]
+		tables, err := r.schemer(host).HostRebalanceTables(ctx, host)
+		if err != nil {
+			return err
+		}
+		for _, table := range tables {
+			partitions, err := r.schemer(host).HostTablePartitions(ctx, host, table)
+			if err != nil {
+				return err
+			}
+			for _, partition := range partitions {
+				if partition.Rows > 0 {
+					return fmt.Errorf("shard %s still has %d rows in partition %s of %s.%s",
+						r.shardName(index), partition.Rows, partition.PartitionID, table.Database, table.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/poller"
+)
+
+// keeperReadyTimeout specifies how long referenced ClickHouseKeeperInstallation is waited to become ready
+const keeperReadyTimeout = 10 * time.Minute
+
+// waitKeeperReady waits for the ClickHouseKeeperInstallation, referenced by the cluster of the host, to become ready
+func (w *worker) waitKeeperReady(ctx context.Context, host *api.Host) error {
+	zk := host.GetCluster().GetZookeeper()
+	if !zk.HasKeeperRef() {
+		return nil
+	}
+	namespace := keeperRefNamespace(host.GetCR(), zk)
+
+	w.a.V(1).M(host).F().Info("Wait for keeper %s/%s to be ready. Host: %s", namespace, zk.Keeper.Name, host.GetName())
+	return poller.New(ctx, fmt.Sprintf("keeper %s/%s ready", namespace, zk.Keeper.Name)).
+		WithOptions(poller.NewOptionsFromConfig(&poller.Options{Timeout: keeperReadyTimeout})).
+		WithFunctions(&poller.Functions{
+			IsDone: func(_ context.Context, _ any) bool {
+				return w.c.isKeeperReady(namespace, zk.Keeper.Name)
+			},
+		}).Poll()
+}
@@ -0,0 +1,53 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	chiKube "github.com/altinity/clickhouse-operator/pkg/controller/chi/kube"
+	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
+)
+
+// reconcileKubeContexts makes remote kube contexts of the CHI available for the reconcile
+func (w *worker) reconcileKubeContexts(ctx context.Context, chi *api.ClickHouseInstallation) {
+	for _, err := range w.c.registerKubeContexts(ctx, chi) {
+		w.a.V(1).
+			WithEvent(chi, a.EventActionReconcile, a.EventReasonReconcileFailed).
+			WithError(chi).
+			M(chi).F().
+			Error("Kube context is not available. err: %v", err)
+	}
+}
+
+// walkKubeContexts calls f within the local kube context and within each remote kube context hosts of the CHI live in.
+// Objects shared by all hosts, such as common ConfigMaps, have to be present in every kube context
+func (w *worker) walkKubeContexts(
+	ctx context.Context,
+	cr api.ICustomResource,
+	f func(ctx context.Context, kubeContext string) error,
+) (err error) {
+	kubeContexts := []string{""}
+	if chi, ok := cr.(*api.ClickHouseInstallation); ok {
+		kubeContexts = append(kubeContexts, chi.GetHostsKubeContexts()...)
+	}
+	for _, kubeContext := range kubeContexts {
+		if e := f(chiKube.WithKubeContext(ctx, cr, kubeContext), kubeContext); e != nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,162 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
+)
+
+// isDeferredActionsDue checks whether there are deferred actions and maintenance window is open to perform them
+func (w *worker) isDeferredActionsDue(cr *api.ClickHouseInstallation) bool {
+	return cr.EnsureStatus().HasDeferredActions() && cr.GetReconcile().IsInMaintenanceWindow(time.Now())
+}
+
+// getDisruptiveActions gets actions the reconcile is about to perform on the host, which disrupt the host serving
+func (w *worker) getDisruptiveActions(ctx context.Context, host *api.Host) (actions []string) {
+	switch {
+	case !host.HasAncestor():
+		// New host does not serve anything yet
+		return nil
+	case host.IsStopped(), host.IsTroubleshoot():
+		// Explicitly requested
+		return nil
+	case host.IsInMaintenance():
+		// Host in maintenance is excluded from the cluster and does not serve anything
+		return nil
+	}
+
+	if w.shouldForceRestartHost(ctx, host) {
+		actions = append(actions, api.DeferredActionRestart)
+	}
+	w.stsReconciler.PrepareHostStatefulSetWithStatus(ctx, host, host.IsStopped())
+	if host.GetReconcileAttributes().GetStatus().Is(types.ObjectStatusModified) {
+		actions = append(actions, api.DeferredActionStatefulSet)
+	}
+	return actions
+}
+
+// deferHost checks whether disruptive actions on the host have to be deferred till maintenance window opens
+// and lists deferred actions in the status of the CR
+func (w *worker) deferHost(ctx context.Context, host *api.Host) bool {
+	cr, ok := host.GetCR().(*api.ClickHouseInstallation)
+	if !ok {
+		return false
+	}
+
+	now := time.Now().UTC()
+	if cr.GetReconcile().IsInMaintenanceWindow(now) {
+		return false
+	}
+	actions := w.getDisruptiveActions(ctx, host)
+	if len(actions) == 0 {
+		return false
+	}
+
+	deferred := &api.DeferredAction{
+		Host:    host.GetName(),
+		Actions: actions,
+		Since:   now.Format(time.RFC3339),
+	}
+	if prev := cr.EnsureStatus().GetDeferredAction(host.GetName()); prev != nil {
+		deferred.Since = prev.Since
+	}
+	if next := cr.GetReconcile().GetNextMaintenanceWindow(now); !next.IsZero() {
+		deferred.Until = next.UTC().Format(time.RFC3339)
+	}
+
+	w.a.V(1).
+		WithEvent(cr, a.EventActionReconcile, a.EventReasonReconcileInProgress).
+		M(host).F().
+		Info("Disruptive actions (%s) on the host are deferred till maintenance window opens at %s. Host: %s",
+			strings.Join(actions, ","), deferred.Until, host.GetName())
+
+	cr.EnsureStatus().SetDeferredAction(deferred)
+	w.updateDeferredActionsStatus(ctx, cr)
+	return true
+}
+
+// reconcileHostDeferred reconciles non-disruptive objects of the host, such as ConfigMap and Service.
+// Host keeps running and serving as is till maintenance window opens
+func (w *worker) reconcileHostDeferred(ctx context.Context, host *api.Host) error {
+	if err := w.reconcileConfigMapHost(ctx, host); err != nil {
+		w.a.V(1).
+			M(host).F().
+			Warning("Reconcile Host Deferred - unable to reconcile ConfigMap. Host: %s Err: %v", host.GetName(), err)
+		return err
+	}
+	if err := w.reconcileHostService(ctx, host); err != nil {
+		w.a.V(1).
+			M(host).F().
+			Warning("Reconcile Host Deferred - unable to reconcile Service. Host: %s Err: %v", host.GetName(), err)
+	}
+	return nil
+}
+
+// completeDeferredActions removes host from the list of deferred actions, as host is reconciled completely
+func (w *worker) completeDeferredActions(ctx context.Context, host *api.Host) {
+	cr, ok := host.GetCR().(*api.ClickHouseInstallation)
+	if !ok || (cr.EnsureStatus().GetDeferredAction(host.GetName()) == nil) {
+		return
+	}
+
+	w.a.V(1).M(host).F().Info("Deferred actions are completed. Host: %s", host.GetName())
+	cr.EnsureStatus().DeleteDeferredAction(host.GetName())
+	w.updateDeferredActionsStatus(ctx, cr)
+}
+
+// isHostRestartDeferred checks whether host restart was deferred earlier
+func (w *worker) isHostRestartDeferred(host *api.Host) bool {
+	cr, ok := host.GetCR().(*api.ClickHouseInstallation)
+	return ok && cr.EnsureStatus().GetDeferredAction(host.GetName()).HasAction(api.DeferredActionRestart)
+}
+
+// pruneDeferredActions removes deferred actions of hosts which are no longer in the CR
+func (w *worker) pruneDeferredActions(ctx context.Context, cr *api.ClickHouseInstallation) {
+	hosts := make(map[string]bool)
+	cr.WalkHosts(func(host *api.Host) error {
+		hosts[host.GetName()] = true
+		return nil
+	})
+
+	pruned := false
+	for _, deferred := range cr.EnsureStatus().DeferredActions {
+		if !hosts[deferred.Host] {
+			cr.EnsureStatus().DeleteDeferredAction(deferred.Host)
+			pruned = true
+		}
+	}
+	if pruned {
+		w.updateDeferredActionsStatus(ctx, cr)
+	}
+}
+
+// updateDeferredActionsStatus persists list of deferred actions
+func (w *worker) updateDeferredActionsStatus(ctx context.Context, cr *api.ClickHouseInstallation) {
+	_ = w.c.updateCRObjectStatus(ctx, cr, types.UpdateStatusOptions{
+		CopyStatusOptions: types.CopyStatusOptions{
+			CopyStatusField: types.CopyStatusField{
+				Copy: types.Status{
+					DeferredActions: true,
+				},
+			},
+		},
+	})
+}
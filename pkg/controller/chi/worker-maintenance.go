@@ -0,0 +1,69 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"time"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
+)
+
+// updateMaintenanceStatus lists hosts in maintenance in the status of the CR.
+// Hosts which are already in maintenance keep the time they entered maintenance
+func (w *worker) updateMaintenanceStatus(ctx context.Context, cr *api.ClickHouseInstallation) {
+	now := time.Now().UTC()
+	changed := false
+	var maintenance []*api.HostMaintenance
+	cr.WalkHosts(func(host *api.Host) error {
+		prev := cr.EnsureStatus().GetMaintenance(host.GetName())
+		switch {
+		case host.IsInMaintenance() && (prev != nil):
+			maintenance = append(maintenance, prev)
+		case host.IsInMaintenance():
+			w.a.V(1).
+				WithEvent(cr, a.EventActionReconcile, a.EventReasonReconcileInProgress).
+				M(host).F().
+				Info("Host enters maintenance and is excluded from the cluster: %s", host.GetName())
+			maintenance = append(maintenance, api.NewHostMaintenance(host.GetName(), now))
+			changed = true
+		case prev != nil:
+			w.a.V(1).
+				WithEvent(cr, a.EventActionReconcile, a.EventReasonReconcileInProgress).
+				M(host).F().
+				Info("Host leaves maintenance and is included back into the cluster: %s", host.GetName())
+			changed = true
+		}
+		return nil
+	})
+
+	// Hosts removed from the CR while in maintenance are dropped from the list as well
+	if !changed && (len(maintenance) == len(cr.EnsureStatus().Maintenance)) {
+		return
+	}
+
+	cr.EnsureStatus().SetMaintenance(maintenance)
+	_ = w.c.updateCRObjectStatus(ctx, cr, types.UpdateStatusOptions{
+		CopyStatusOptions: types.CopyStatusOptions{
+			CopyStatusField: types.CopyStatusField{
+				Copy: types.Status{
+					Maintenance: true,
+				},
+			},
+		},
+	})
+}
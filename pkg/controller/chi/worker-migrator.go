@@ -176,6 +176,11 @@ func (w *worker) ensureClusterSchemer(host *api.Host) *schemer.ClusterSchemer {
 	case api.ChSchemeHTTPS:
 		clusterConnectionParams.Port = host.HTTPSPort.IntValue()
 	}
+	// Hosts' certificates provisioned by the operator are signed by the CHI's own CA
+	if chi, ok := host.GetCR().(*api.ClickHouseInstallation); ok && chi.EnsureRuntime().TLSRootCA != "" {
+		clusterConnectionParams.RootCA = chi.EnsureRuntime().TLSRootCA
+		clusterConnectionParams.VerifyRootCA = true
+	}
 	w.schemer = schemer.NewClusterSchemer(clusterConnectionParams, host.Runtime.Version)
 
 	return w.schemer
@@ -0,0 +1,662 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/rebalancer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/schemer"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	// rebalanceMovePollInterval specifies how often progress of MOVE PARTITION TO SHARD is checked
+	rebalanceMovePollInterval = 10 * time.Second
+	// rebalanceMoveTimeout specifies how long MOVE PARTITION TO SHARD is waited for
+	rebalanceMoveTimeout = 1 * time.Hour
+)
+
+// errRebalanceInProgress is returned by the rebalancer run in background once the step is taken.
+// Rebalance is continued by the next step
+var errRebalanceInProgress = errors.New("rebalance is in progress")
+
+// reconcileClusterRebalance starts moving partitions from the existing shards to the added ones.
+// Reconcile takes the first step only, the rest of the steps are taken in background by the rebalance monitor,
+// progress is reported in status.
+// Rebalancing errors do not fail reconcile - rebalancing is paused and is resumed on the next reconcile
+func (w *worker) reconcileClusterRebalance(ctx context.Context, cluster *api.Cluster) error {
+	if util.IsContextDone(ctx) {
+		log.V(1).Info("Reconcile is aborted. Cluster: %s ", cluster.GetName())
+		return nil
+	}
+
+	chi := cluster.GetCR()
+	settings := cluster.GetReconcile().Rebalance
+	status := chi.EnsureStatus().GetRebalance(cluster.GetName())
+
+	if status.IsDrain() && !status.IsFinished() {
+		// Drain of the removed shards is run separately
+		return nil
+	}
+
+	if !settings.IsEnabled() || cluster.IsStopped() {
+		if status != nil {
+			chi.EnsureStatus().DeleteRebalance(cluster.GetName())
+			w.updateRebalanceStatus(ctx, chi)
+		}
+		return nil
+	}
+
+	if !w.shouldRebalanceCluster(cluster, status) {
+		return nil
+	}
+
+	w.a.V(1).M(cluster).F().Info("Rebalance cluster: %s", cluster.GetName())
+
+	if status.IsFinished() {
+		// Start new rebalance
+		status = &api.RebalanceStatus{
+			Cluster: cluster.GetName(),
+		}
+	}
+	status.Status = api.RebalanceStatusInProgress
+	status.Error = ""
+
+	w.rebalanceClusterStep(ctx, cluster, status)
+	return nil
+}
+
+// rebalanceClusterStep takes a step of the cluster rebalance. Step moves one partition at most and does not wait
+// for MOVE PARTITION TO SHARD to complete, so neither reconcile nor rebalance monitor is blocked by long moves
+func (w *worker) rebalanceClusterStep(ctx context.Context, cluster *api.Cluster, status *api.RebalanceStatus) {
+	r := newClusterRebalancer(w, cluster, status)
+	r.background = true
+	switch err := r.rebalance(ctx); {
+	case errors.Is(err, errRebalanceInProgress):
+		w.a.V(2).M(cluster).F().Info("Rebalance of cluster: %s is in progress", cluster.GetName())
+	case err != nil:
+		w.a.V(1).M(cluster).F().Warning("Rebalance of cluster: %s paused. err: %v", cluster.GetName(), err)
+		status.Pause(err.Error())
+	default:
+		w.a.V(1).M(cluster).F().Info("Rebalance of cluster: %s completed", cluster.GetName())
+		status.Status = api.RebalanceStatusCompleted
+	}
+	r.persist(ctx)
+}
+
+// rebalanceCR takes a step of the rebalances in progress of the CR. Step is run by the worker the CR is reconciled by,
+// so the step never runs along with reconcile of the CR
+func (w *worker) rebalanceCR(ctx context.Context, namespace, name string) error {
+	cr, err := w.c.kube.CR().Get(ctx, namespace, name)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	chi := cr.(*api.ClickHouseInstallation)
+	if chi.IsStopped() || !hasRebalanceInProgress(chi) {
+		return nil
+	}
+
+	// Drained shards are kept in the clusters of the templated CR
+	w.createTemplatedCR(chi).WalkClusters(func(c api.ICluster) error {
+		cluster := c.(*api.Cluster)
+		status := cluster.GetCR().EnsureStatus().GetRebalance(cluster.GetName())
+		switch {
+		case !status.IsInProgress() || cluster.IsStopped():
+		case status.IsDrain():
+			if cluster.GetReconcile().Drain.IsEnabled() && slices.Equal(status.DrainShards, cluster.Runtime.KeptShards) {
+				w.drainClusterStep(ctx, cluster, status)
+			}
+		case cluster.GetReconcile().Rebalance.IsEnabled():
+			w.rebalanceClusterStep(ctx, cluster, status)
+		}
+		return nil
+	})
+	return nil
+}
+
+// shouldRebalanceCluster checks whether rebalancing has to be started or resumed
+func (w *worker) shouldRebalanceCluster(cluster *api.Cluster, status *api.RebalanceStatus) bool {
+	if !status.IsFinished() {
+		// Unfinished rebalance has to be resumed
+		return true
+	}
+	if cluster.GetAncestor().IsZero() {
+		// New cluster has no data to rebalance
+		return false
+	}
+	shardsAdded := false
+	cluster.WalkShards(func(index int, shard api.IShard) error {
+		if shard.GetAncestor().IsZero() {
+			shardsAdded = true
+		}
+		return nil
+	})
+	return shardsAdded
+}
+
+// updateRebalanceStatus writes rebalance status of the CHI
+func (w *worker) updateRebalanceStatus(ctx context.Context, chi *api.ClickHouseInstallation) {
+	_ = w.c.updateCRObjectStatus(ctx, chi, types.UpdateStatusOptions{
+		CopyStatusOptions: types.CopyStatusOptions{
+			CopyStatusField: types.CopyStatusField{
+				Copy: types.Status{
+					Rebalance: true,
+				},
+			},
+		},
+	})
+}
+
+// clusterRebalancer moves partitions between shards of a cluster
+type clusterRebalancer struct {
+	w        *worker
+	cluster  *api.Cluster
+	settings *api.ClusterRebalance
+	status   *api.RebalanceStatus
+	// hosts contains one host per shard, data is read and written through it
+	hosts []*api.Host
+	// drain contains indexes of shards to be drained, if any
+	drain []int
+	// background specifies rebalance is run in steps, each step moves one partition at most and does not wait
+	// for the move to complete. Otherwise rebalance runs till all partitions are moved
+	background bool
+}
+
+// newClusterRebalancer creates new rebalancer of the cluster.
+// In case removed shards are drained, the shards are kept in the cluster till drained
+func newClusterRebalancer(w *worker, cluster *api.Cluster, status *api.RebalanceStatus) *clusterRebalancer {
+	r := &clusterRebalancer{
+		w:        w,
+		cluster:  cluster,
+		settings: cluster.GetReconcile().Rebalance,
+		status:   status,
+	}
+	cluster.WalkShards(func(index int, shard api.IShard) error {
+		r.hosts = append(r.hosts, shard.(*api.ChiShard).FirstHost())
+		return nil
+	})
+	for _, name := range status.DrainShards {
+		r.drain = append(r.drain, r.shardIndex(name))
+	}
+	return r
+}
+
+// isDrain checks whether shards are drained
+func (r *clusterRebalancer) isDrain() bool {
+	return len(r.drain) > 0
+}
+
+// isDrained checks whether shard with the specified index is drained
+func (r *clusterRebalancer) isDrained(index int) bool {
+	for _, drained := range r.drain {
+		if drained == index {
+			return true
+		}
+	}
+	return false
+}
+
+// persist writes current rebalance status, so rebalance can be resumed safely
+func (r *clusterRebalancer) persist(ctx context.Context) {
+	chi := r.cluster.GetCR()
+	chi.EnsureStatus().SetRebalance(r.status)
+	r.w.updateRebalanceStatus(ctx, chi)
+}
+
+// schemer makes schemer to talk to the host
+func (r *clusterRebalancer) schemer(host *api.Host) *schemer.ClusterSchemer {
+	return r.w.ensureClusterSchemer(host)
+}
+
+func (r *clusterRebalancer) rebalance(ctx context.Context) error {
+	for _, host := range r.hosts {
+		if host == nil {
+			return fmt.Errorf("shard has no hosts")
+		}
+	}
+	for i, index := range r.drain {
+		if index < 0 {
+			return fmt.Errorf("unable to find shard %s to drain", r.status.DrainShards[i])
+		}
+	}
+
+	tables, err := r.tables(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if util.IsContextDone(ctx) {
+			return util.ContextError(ctx)
+		}
+		tableStatus := r.status.EnsureTable(table.Database, table.Name)
+		switch tableStatus.Status {
+		case api.RebalanceTableStatusCompleted, api.RebalanceTableStatusSkipped:
+			continue
+		}
+		if reason := r.skipReason(table); reason != "" {
+			if r.isDrain() {
+				// Data of the drained shards can not be left behind
+				tableStatus.Status = api.RebalanceTableStatusFailed
+				tableStatus.Error = reason
+				return fmt.Errorf("table %s.%s: %s", table.Database, table.Name, reason)
+			}
+			tableStatus.Status = api.RebalanceTableStatusSkipped
+			tableStatus.Error = reason
+			continue
+		}
+		tableStatus.Status = api.RebalanceTableStatusInProgress
+		tableStatus.Error = ""
+		err := r.rebalanceTable(ctx, table, tableStatus)
+		if errors.Is(err, errRebalanceInProgress) {
+			return err
+		}
+		if err != nil {
+			tableStatus.Status = api.RebalanceTableStatusFailed
+			tableStatus.Error = err.Error()
+			return fmt.Errorf("table %s.%s: %v", table.Database, table.Name, err)
+		}
+		tableStatus.Status = api.RebalanceTableStatusCompleted
+		r.persist(ctx)
+	}
+
+	return nil
+}
+
+// tables lists tables to be rebalanced. Tables which are not present on all shards are reported as skipped.
+// In case shards are drained, tables which are not present on the drained shards are not listed
+// and tables which are not present on the remaining shards can not be drained
+func (r *clusterRebalancer) tables(ctx context.Context) ([]schemer.RebalanceTable, error) {
+	var names []string
+	tables := make(map[string]schemer.RebalanceTable)
+	presence := make(map[string]int)
+	drained := make(map[string]bool)
+	for index, host := range r.hosts {
+		hostTables, err := r.schemer(host).HostRebalanceTables(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list tables on host %s: %v", host.GetName(), err)
+		}
+		for _, table := range hostTables {
+			name := table.Database + "." + table.Name
+			if _, found := tables[name]; !found {
+				names = append(names, name)
+				tables[name] = table
+			}
+			presence[name]++
+			if r.isDrained(index) {
+				drained[name] = true
+			}
+		}
+	}
+
+	var res []schemer.RebalanceTable
+	for _, name := range names {
+		table := tables[name]
+		if r.isDrain() {
+			switch {
+			case !drained[name]:
+				continue
+			case presence[name] < len(r.hosts):
+				return nil, fmt.Errorf("table %s is not present on all shards, unable to drain", name)
+			}
+			res = append(res, table)
+			continue
+		}
+		if !r.isTableRequested(name) {
+			continue
+		}
+		if presence[name] < len(r.hosts) {
+			tableStatus := r.status.EnsureTable(table.Database, table.Name)
+			tableStatus.Status = api.RebalanceTableStatusSkipped
+			tableStatus.Error = "table is not present on all shards"
+			continue
+		}
+		res = append(res, table)
+	}
+	return res, nil
+}
+
+// isTableRequested checks whether table is listed in rebalance settings. All tables are requested by default
+func (r *clusterRebalancer) isTableRequested(name string) bool {
+	if len(r.settings.GetTables()) == 0 {
+		return true
+	}
+	for _, requested := range r.settings.GetTables() {
+		if requested == name {
+			return true
+		}
+	}
+	return false
+}
+
+// skipReason explains why the table can not be rebalanced, if it can not be
+func (r *clusterRebalancer) skipReason(table schemer.RebalanceTable) string {
+	switch {
+	case !r.isDrain() && !table.IsPartitioned():
+		return "table is not partitioned"
+	case (r.settings.GetMethod() == api.RebalanceMethodMovePartition) && !table.IsReplicated():
+		return "method movePartition requires Replicated table"
+	case !table.IsReplicated() && (r.cluster.GetLayout().GetReplicasCount() > 1):
+		return "non-replicated table in the cluster with replicas"
+	}
+	return ""
+}
+
+func (r *clusterRebalancer) rebalanceTable(ctx context.Context, table schemer.RebalanceTable, status *api.RebalanceTableStatus) error {
+	// Move interrupted previously has to be completed first
+	if status.Move != nil {
+		if err := r.resumeMove(ctx, table, status); err != nil {
+			return err
+		}
+	}
+
+	moves, err := r.plan(ctx, table)
+	if err != nil {
+		return err
+	}
+	status.PartitionsToMove = status.PartitionsMoved + len(moves)
+	r.persist(ctx)
+
+	for _, move := range moves {
+		if util.IsContextDone(ctx) {
+			return util.ContextError(ctx)
+		}
+		if err := r.move(ctx, table, status, move); err != nil {
+			return err
+		}
+		if r.background {
+			// The rest of the moves is planned anew by the next step
+			return errRebalanceInProgress
+		}
+	}
+	return nil
+}
+
+// plan makes list of partition moves for the table
+func (r *clusterRebalancer) plan(ctx context.Context, table schemer.RebalanceTable) ([]rebalancer.Move, error) {
+	var units []rebalancer.Unit
+	for shard, host := range r.hosts {
+		partitions, err := r.schemer(host).HostTablePartitions(ctx, host, table)
+		if err != nil {
+			return nil, err
+		}
+		for _, partition := range partitions {
+			weight := partition.Bytes
+			if r.settings.GetStrategy() == api.RebalanceStrategyRows {
+				weight = partition.Rows
+			}
+			units = append(units, rebalancer.Unit{
+				Shard:       shard,
+				Partition:   partition.Partition,
+				PartitionID: partition.PartitionID,
+				Weight:      weight,
+				Movable:     partition.IdleSeconds >= int64(r.settings.GetMinPartitionIdleTime()),
+			})
+		}
+	}
+	if r.isDrain() {
+		return rebalancer.Drain(len(r.hosts), r.drain, units), nil
+	}
+	return rebalancer.Plan(len(r.hosts), units), nil
+}
+
+// move moves partition to another shard. Each step is recorded in status before it is taken
+func (r *clusterRebalancer) move(ctx context.Context, table schemer.RebalanceTable, status *api.RebalanceTableStatus, move rebalancer.Move) error {
+	from, to := r.hosts[move.Shard], r.hosts[move.ToShard]
+
+	rows, err := r.schemer(from).HostPartitionRows(ctx, from, table, move.PartitionID)
+	if err != nil {
+		return err
+	}
+	destRowsBefore, err := r.schemer(to).HostPartitionRows(ctx, to, table, move.PartitionID)
+	if err != nil {
+		return err
+	}
+
+	status.Move = &api.RebalanceMove{
+		Partition:      move.Partition,
+		PartitionID:    move.PartitionID,
+		FromShard:      r.shardName(move.Shard),
+		ToShard:        r.shardName(move.ToShard),
+		Phase:          api.RebalanceMovePhaseCopying,
+		Rows:           rows,
+		DestRowsBefore: destRowsBefore,
+		Started:        time.Now().UTC().Format(time.RFC3339),
+	}
+	r.persist(ctx)
+
+	return r.transfer(ctx, table, status)
+}
+
+// transfer copies partition described by the status to the destination shard, verifies the copy and drops the source
+func (r *clusterRebalancer) transfer(ctx context.Context, table schemer.RebalanceTable, status *api.RebalanceTableStatus) error {
+	move := status.Move
+	from, to, err := r.moveHosts(move)
+	if err != nil {
+		return err
+	}
+
+	switch r.settings.GetMethod() {
+	case api.RebalanceMethodMovePartition:
+		if err := r.movePartitionToShard(ctx, table, from, to, move.PartitionID); err != nil {
+			return err
+		}
+		if r.background {
+			// Move is checked for completion by the next step
+			return errRebalanceInProgress
+		}
+		if err := r.waitPartMoves(ctx, table, from, to, move); err != nil {
+			return err
+		}
+		if err := r.verifyRows(ctx, table, to, move.PartitionID, move.DestRowsBefore+move.Rows); err != nil {
+			return err
+		}
+	default:
+		if err := r.schemer(to).HostCopyPartition(ctx, to, table, r.shardIndex(move.FromShard), move.Partition); err != nil {
+			return err
+		}
+		if err := r.verifyRows(ctx, table, to, move.PartitionID, move.DestRowsBefore+move.Rows); err != nil {
+			return err
+		}
+		if err := r.verifyRows(ctx, table, from, move.PartitionID, move.Rows); err != nil {
+			return fmt.Errorf("source partition changed while being copied: %v", err)
+		}
+		move.Phase = api.RebalanceMovePhaseCopied
+		r.persist(ctx)
+
+		if err := r.schemer(from).HostDropPartition(ctx, from, table, move.PartitionID); err != nil {
+			return err
+		}
+	}
+
+	status.PartitionsMoved++
+	status.Move = nil
+	r.persist(ctx)
+	return nil
+}
+
+// resumeMove completes move interrupted previously. Data on both shards is checked in order to find out
+// how far the move went, move is not repeated in case data could be duplicated
+func (r *clusterRebalancer) resumeMove(ctx context.Context, table schemer.RebalanceTable, status *api.RebalanceTableStatus) error {
+	move := status.Move
+	from, to, err := r.moveHosts(move)
+	if err != nil {
+		return err
+	}
+	sourceRows, err := r.schemer(from).HostPartitionRows(ctx, from, table, move.PartitionID)
+	if err != nil {
+		return err
+	}
+	destRows, err := r.schemer(to).HostPartitionRows(ctx, to, table, move.PartitionID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case (r.settings.GetMethod() == api.RebalanceMethodMovePartition) && (move.Phase == api.RebalanceMovePhaseCopying):
+		if r.background {
+			if err := r.checkPartMoves(ctx, table, from, to, move); err != nil {
+				return err
+			}
+		} else if err := r.waitPartMoves(ctx, table, from, to, move); err != nil {
+			return err
+		}
+		if destRows, err = r.schemer(to).HostPartitionRows(ctx, to, table, move.PartitionID); err != nil {
+			return err
+		}
+		if destRows == move.DestRowsBefore+move.Rows {
+			// Move is completed
+			break
+		}
+		if (destRows == move.DestRowsBefore) && (sourceRows == move.Rows) {
+			// Move has not happened, start over
+			return r.transfer(ctx, table, status)
+		}
+		return fmt.Errorf("unable to resume move of partition %s: source rows %d, destination rows %d", move.PartitionID, sourceRows, destRows)
+
+	case move.Phase == api.RebalanceMovePhaseCopied:
+		switch sourceRows {
+		case move.Rows:
+			// Copy is verified, source is not dropped yet
+			if err := r.schemer(from).HostDropPartition(ctx, from, table, move.PartitionID); err != nil {
+				return err
+			}
+		case 0:
+			// Source is dropped already
+		default:
+			return fmt.Errorf("unable to resume move of partition %s: source rows %d, expected %d", move.PartitionID, sourceRows, move.Rows)
+		}
+
+	default:
+		switch {
+		case (destRows == move.DestRowsBefore) && (sourceRows == move.Rows):
+			// Copy has not happened, start over
+			return r.transfer(ctx, table, status)
+		case (destRows == move.DestRowsBefore+move.Rows) && (sourceRows == move.Rows):
+			// Copy is completed, source is not dropped yet
+			move.Phase = api.RebalanceMovePhaseCopied
+			r.persist(ctx)
+			if err := r.schemer(from).HostDropPartition(ctx, from, table, move.PartitionID); err != nil {
+				return err
+			}
+		case (destRows == move.DestRowsBefore+move.Rows) && (sourceRows == 0):
+			// Source is dropped already
+		default:
+			return fmt.Errorf("unable to resume move of partition %s: source rows %d, destination rows %d", move.PartitionID, sourceRows, destRows)
+		}
+	}
+
+	status.PartitionsMoved++
+	status.Move = nil
+	r.persist(ctx)
+	return nil
+}
+
+// movePartitionToShard starts move of the partition with MOVE PARTITION TO SHARD
+func (r *clusterRebalancer) movePartitionToShard(ctx context.Context, table schemer.RebalanceTable, from, to *api.Host, partitionID string) error {
+	zkPath, err := r.schemer(to).HostReplicaZookeeperPath(ctx, to, table)
+	if err != nil {
+		return err
+	}
+	return r.schemer(from).HostMovePartitionToShard(ctx, from, table, partitionID, zkPath)
+}
+
+// waitPartMoves waits for moves of parts of the partition to complete
+func (r *clusterRebalancer) waitPartMoves(ctx context.Context, table schemer.RebalanceTable, from, to *api.Host, move *api.RebalanceMove) error {
+	for {
+		err := r.checkPartMoves(ctx, table, from, to, move)
+		if !errors.Is(err, errRebalanceInProgress) {
+			return err
+		}
+		if util.WaitContextDoneOrTimeout(ctx, rebalanceMovePollInterval) {
+			return util.ContextError(ctx)
+		}
+	}
+}
+
+// checkPartMoves checks whether moves of parts of the partition are completed.
+// errRebalanceInProgress is returned in case parts are still being moved, number of them is recorded in the move
+func (r *clusterRebalancer) checkPartMoves(ctx context.Context, table schemer.RebalanceTable, from, to *api.Host, move *api.RebalanceMove) error {
+	zkPath, err := r.schemer(to).HostReplicaZookeeperPath(ctx, to, table)
+	if err != nil {
+		return err
+	}
+	count, exception, err := r.schemer(from).HostPartMovesInProgress(ctx, from, table, move.PartitionID, zkPath)
+	if err != nil {
+		return err
+	}
+	move.PartsInProgress = count
+	if count == 0 {
+		return nil
+	}
+	started, err := time.Parse(time.RFC3339, move.Started)
+	if err != nil {
+		// Move recorded by the previous version has no start time, timeout is counted from now on
+		move.Started = time.Now().UTC().Format(time.RFC3339)
+		return errRebalanceInProgress
+	}
+	if time.Since(started) > rebalanceMoveTimeout {
+		return fmt.Errorf("move of partition %s is not completed in %s. last exception: %s", move.PartitionID, rebalanceMoveTimeout, exception)
+	}
+	return errRebalanceInProgress
+}
+
+// verifyRows checks number of rows in the partition of the table on the host
+func (r *clusterRebalancer) verifyRows(ctx context.Context, table schemer.RebalanceTable, host *api.Host, partitionID string, expected int64) error {
+	rows, err := r.schemer(host).HostPartitionRows(ctx, host, table, partitionID)
+	if err != nil {
+		return err
+	}
+	if rows != expected {
+		return fmt.Errorf("partition %s on host %s has %d rows, expected %d", partitionID, host.GetName(), rows, expected)
+	}
+	return nil
+}
+
+// moveHosts finds hosts of the shards the partition is moved between
+func (r *clusterRebalancer) moveHosts(move *api.RebalanceMove) (from, to *api.Host, err error) {
+	fromIndex, toIndex := r.shardIndex(move.FromShard), r.shardIndex(move.ToShard)
+	if (fromIndex < 0) || (toIndex < 0) {
+		return nil, nil, fmt.Errorf("unable to find shards %s and %s", move.FromShard, move.ToShard)
+	}
+	return r.hosts[fromIndex], r.hosts[toIndex], nil
+}
+
+func (r *clusterRebalancer) shardName(index int) string {
+	return r.hosts[index].GetShard().GetName()
+}
+
+func (r *clusterRebalancer) shardIndex(name string) int {
+	for index := range r.hosts {
+		if strings.EqualFold(r.shardName(index), name) {
+			return index
+		}
+	}
+	return -1
+}
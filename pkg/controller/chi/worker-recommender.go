@@ -0,0 +1,98 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"time"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+// applyResourcesRecommendations sets resources recommended for the clusters in apply mode to all hosts of the clusters,
+// so StatefulSets of the hosts are built with the recommended resources
+func (w *worker) applyResourcesRecommendations(cr *api.ClickHouseInstallation) {
+	cr.WalkClusters(func(c api.ICluster) error {
+		cluster, ok := c.(*api.Cluster)
+		if !ok || !cluster.GetResourcesRecommender().IsApply() {
+			return nil
+		}
+		recommendation := cr.EnsureStatus().GetResourcesRecommendation(cluster.GetName())
+		if recommendation == nil {
+			return nil
+		}
+		w.a.V(1).M(cr).F().Info(
+			"Apply resources recommendation to the cluster %s: cpu %s/%s memory %s/%s",
+			cluster.GetName(),
+			recommendation.CPURequest, recommendation.CPULimit, recommendation.MemoryRequest, recommendation.MemoryLimit,
+		)
+		cluster.WalkHosts(func(host *api.Host) error {
+			host.Runtime.Resources = recommendation.GetResourceRequirements()
+			return nil
+		})
+		return nil
+	})
+}
+
+// isResourcesRecommendationPending checks whether any cluster in apply mode has recommendation which is not applied yet
+func (w *worker) isResourcesRecommendationPending(cr *api.ClickHouseInstallation) bool {
+	if cr.GetSpecT().Configuration == nil {
+		// Not normalized CR may have no configuration
+		return false
+	}
+	pending := false
+	cr.WalkClusters(func(c api.ICluster) error {
+		cluster, ok := c.(*api.Cluster)
+		if !ok || !cluster.GetResourcesRecommender().IsApply() {
+			return nil
+		}
+		if recommendation := cr.EnsureStatus().GetResourcesRecommendation(cluster.GetName()); recommendation != nil {
+			pending = pending || !recommendation.IsApplied()
+		}
+		return nil
+	})
+	return pending
+}
+
+// markResourcesRecommendationsApplied marks pending recommendations as applied, as the reconcile is about to roll
+// them out. Recommendations are kept applied by all subsequent reconciles, no matter whether this one succeeds
+func (w *worker) markResourcesRecommendationsApplied(ctx context.Context, cr *api.ClickHouseInstallation) {
+	if !w.isResourcesRecommendationPending(cr) {
+		return
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	cr.WalkClusters(func(c api.ICluster) error {
+		cluster, ok := c.(*api.Cluster)
+		if !ok || !cluster.GetResourcesRecommender().IsApply() {
+			return nil
+		}
+		if recommendation := cr.EnsureStatus().GetResourcesRecommendation(cluster.GetName()); (recommendation != nil) && !recommendation.IsApplied() {
+			recommendation.Applied = now
+			cr.EnsureStatus().SetResourcesRecommendation(recommendation)
+		}
+		return nil
+	})
+	_ = w.c.updateCRObjectStatus(ctx, cr, types.UpdateStatusOptions{
+		CopyStatusOptions: types.CopyStatusOptions{
+			CopyStatusField: types.CopyStatusField{
+				Copy: types.Status{
+					Recommendations: true,
+				},
+			},
+		},
+	})
+}
@@ -20,14 +20,17 @@ import (
 	"fmt"
 	"time"
 
+	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	chiKube "github.com/altinity/clickhouse-operator/pkg/controller/chi/kube"
 	"github.com/altinity/clickhouse-operator/pkg/controller/chi/metrics"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common"
 	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/conditions"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common/statefulset"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common/storage"
 	"github.com/altinity/clickhouse-operator/pkg/interfaces"
@@ -49,6 +52,20 @@ func (w *worker) reconcileCR(ctx context.Context, old, new *api.ClickHouseInstal
 	switch {
 	case w.isAfterFinalizerInstalled(old, new):
 		w.a.M(new).F().Info("isAfterFinalizerInstalled - continue reconcile-1")
+	case w.isCertificatesRenewalRequired(ctx, new):
+		w.a.M(new).F().Info("isCertificatesRenewalRequired - continue reconcile-1")
+	case w.isResourcesRecommendationPending(new):
+		w.a.M(new).F().Info("isResourcesRecommendationPending - continue reconcile-1")
+	case w.isDeferredActionsDue(new):
+		w.a.M(new).F().Info("isDeferredActionsDue - continue reconcile-1")
+	case w.c.isKeeperTopologyChanged(new):
+		w.a.M(new).F().Info("isKeeperTopologyChanged - continue reconcile-1")
+	case w.isDisasterRecoveryChanged(ctx, new):
+		w.a.M(new).F().Info("isDisasterRecoveryChanged - continue reconcile-1")
+	case isDisasterRecoveryPromoting(new):
+		w.a.M(new).F().Info("isDisasterRecoveryPromoting - continue reconcile-1")
+	case isDrainCompleted(new):
+		w.a.M(new).F().Info("isDrainCompleted - continue reconcile-1")
 	case w.isGenerationTheSame(old, new):
 		log.V(2).M(new).F().Info("isGenerationTheSame() - nothing to do here, exit")
 		return nil
@@ -57,6 +74,17 @@ func (w *worker) reconcileCR(ctx context.Context, old, new *api.ClickHouseInstal
 	w.a.M(new).S().P()
 	defer w.a.M(new).E().P()
 
+	if isCloneToBeMaterialized(new) {
+		// Topology of the clone is copied from the source, reconcile continues on the patched CR
+		if err := w.materializeClone(ctx, new); err != nil {
+			w.a.WithEvent(new, a.EventActionReconcile, a.EventReasonReconcileFailed).
+				WithError(new).
+				M(new).F().
+				Error("FAILED to copy topology of the clone %s, err: %v", util.NamespaceNameString(new), err)
+		}
+		return nil
+	}
+
 	metrics.CRInitZeroValues(ctx, new)
 	metrics.CRReconcilesStarted(ctx, new)
 	startTime := time.Now()
@@ -70,6 +98,7 @@ func (w *worker) reconcileCR(ctx context.Context, old, new *api.ClickHouseInstal
 		if new.EnsureStatus().GetStatus() == api.StatusInProgress || new.EnsureRuntime().ActionPlan.HasActionsToDo() {
 			// Either was mid-reconcile when suspended, or has pending changes suppressed by suspend — mark as Aborted
 			new.EnsureStatus().ReconcileAbort()
+			conditions.Suspended(new, true)
 			_ = w.c.updateCRObjectStatus(ctx, new, types.UpdateStatusOptions{
 				CopyStatusOptions: types.CopyStatusOptions{
 					CopyStatusFieldGroup: types.CopyStatusFieldGroup{
@@ -84,6 +113,16 @@ func (w *worker) reconcileCR(ctx context.Context, old, new *api.ClickHouseInstal
 				Warning("reconcile aborted due to suspend")
 			metrics.CRReconcilesAborted(ctx, new)
 		} else {
+			conditions.Suspended(new, false)
+			_ = w.c.updateCRObjectStatus(ctx, new, types.UpdateStatusOptions{
+				CopyStatusOptions: types.CopyStatusOptions{
+					CopyStatusField: types.CopyStatusField{
+						Copy: types.Status{
+							Conditions: true,
+						},
+					},
+				},
+			})
 			metrics.CRReconcilesCompleted(ctx, new)
 		}
 		return nil
@@ -91,6 +130,18 @@ func (w *worker) reconcileCR(ctx context.Context, old, new *api.ClickHouseInstal
 		w.a.M(new).F().Info("ActionPlan has actions - continue reconcile")
 	case w.isAfterFinalizerInstalled(new.GetAncestorT(), new):
 		w.a.M(new).F().Info("isAfterFinalizerInstalled - continue reconcile-2")
+	case w.isCertificatesRenewalRequired(ctx, new):
+		w.a.M(new).F().Info("isCertificatesRenewalRequired - continue reconcile-2")
+	case w.isResourcesRecommendationPending(new):
+		w.a.M(new).F().Info("isResourcesRecommendationPending - continue reconcile-2")
+	case w.isDeferredActionsDue(new):
+		w.a.M(new).F().Info("isDeferredActionsDue - continue reconcile-2")
+	case w.isDisasterRecoveryChanged(ctx, new):
+		w.a.M(new).F().Info("isDisasterRecoveryChanged - continue reconcile-2")
+	case isDisasterRecoveryPromoting(new):
+		w.a.M(new).F().Info("isDisasterRecoveryPromoting - continue reconcile-2")
+	case isDrainPending(new):
+		w.a.M(new).F().Info("isDrainPending - continue reconcile-2")
 	default:
 		w.a.M(new).F().Info("ActionPlan has no actions - abort reconcile")
 		metrics.CRReconcilesCompleted(ctx, new)
@@ -98,6 +149,10 @@ func (w *worker) reconcileCR(ctx context.Context, old, new *api.ClickHouseInstal
 	}
 
 	w.markReconcileStart(ctx, new)
+	w.reconcileDisasterRecoveryStatus(ctx, new)
+	w.markResourcesRecommendationsApplied(ctx, new)
+	w.updateMaintenanceStatus(ctx, new)
+	w.pruneDeferredActions(ctx, new)
 	w.prepareMonitoring(new)
 	w.setHostStatusesPreliminary(ctx, new)
 
@@ -123,6 +178,7 @@ func (w *worker) reconcileCR(ctx context.Context, old, new *api.ClickHouseInstal
 		w.clean(ctx, new)
 		w.addToMonitoring(new)
 		w.waitForIPAddresses(ctx, new)
+		w.completeDisasterRecoveryPromotion(new)
 		w.finalizeReconcileAndMarkCompleted(ctx, new)
 
 		w.dropZKReplicas(ctx, new)
@@ -136,6 +192,10 @@ func (w *worker) reconcileCR(ctx context.Context, old, new *api.ClickHouseInstal
 
 func (w *worker) buildCR(ctx context.Context, _cr *api.ClickHouseInstallation) *api.ClickHouseInstallation {
 	cr := w.createTemplatedCR(_cr)
+	// Remote kube contexts have to be available before any host object is looked up
+	w.reconcileKubeContexts(ctx, cr)
+	dr := w.c.getDisasterRecoveryRuntime(ctx, cr)
+	cr.EnsureRuntime().DisasterRecovery = dr
 	w.newTask(cr, cr.GetAncestorT())
 	w.findMinMaxVersions(ctx, cr)
 	common.LogOldAndNew("norm stage 1:", cr.GetAncestorT(), cr)
@@ -149,6 +209,7 @@ func (w *worker) buildCR(ctx context.Context, _cr *api.ClickHouseInstallation) *
 		opts.DefaultUserAdditionalIPs = ips
 		opts.Templates = templates
 		cr = w.createTemplatedCR(_cr, opts)
+		cr.EnsureRuntime().DisasterRecovery = dr
 		w.newTask(cr, cr.GetAncestorT())
 		w.findMinMaxVersions(ctx, cr)
 		common.LogOldAndNew("norm stage 2:", cr.GetAncestorT(), cr)
@@ -229,6 +290,11 @@ func (w *worker) reconcile(ctx context.Context, cr *api.ClickHouseInstallation)
 		w.a.V(1).M(cr).Info("Unable to use full fan-out mode. Counters: %s. CR: %s", counters, util.NamespaceNameString(cr))
 	}
 
+	// Removed shards are kept in the remote_servers until their data is drained in background
+	if err := w.drainRemovedShards(ctx, cr); err != nil {
+		return err
+	}
+
 	return cr.WalkTillError(
 		ctx,
 		w.reconcileCRAuxObjectsPreliminary,
@@ -263,7 +329,8 @@ func (w *worker) reconcileCRAuxObjectsPreliminary(ctx context.Context, cr *api.C
 }
 
 func (w *worker) reconcileCRAuxObjectsPreliminaryDomain(ctx context.Context, cr *api.ClickHouseInstallation) error {
-	return nil
+	// Certificates have to be in place before any host is reconciled, as hosts mount them
+	return w.reconcileCRCertificates(ctx, cr)
 }
 
 // reconcileCRServicePreliminary runs first stage of CR reconcile process
@@ -345,13 +412,7 @@ func (w *worker) reconcileConfigMapCommon(
 	// contains several sections, mapped as separated chopConfig files,
 	// such as remote servers, zookeeper setup, etc
 	configMapCommon := w.task.Creator().CreateConfigMap(interfaces.ConfigMapCommon, opts)
-	err := w.reconcileConfigMap(ctx, cr, configMapCommon)
-	if err == nil {
-		w.task.RegistryReconciled().RegisterConfigMap(configMapCommon.GetObjectMeta())
-	} else {
-		w.task.RegistryFailed().RegisterConfigMap(configMapCommon.GetObjectMeta())
-	}
-	return err
+	return w.reconcileConfigMapInKubeContexts(ctx, cr, configMapCommon)
 }
 
 // reconcileConfigMapCommonUsers reconciles all CHI's users ConfigMap
@@ -359,13 +420,21 @@ func (w *worker) reconcileConfigMapCommon(
 func (w *worker) reconcileConfigMapCommonUsers(ctx context.Context, cr api.ICustomResource) error {
 	// ConfigMap common for all users resources in CHI
 	configMapUsers := w.task.Creator().CreateConfigMap(interfaces.ConfigMapCommonUsers)
-	err := w.reconcileConfigMap(ctx, cr, configMapUsers)
-	if err == nil {
-		w.task.RegistryReconciled().RegisterConfigMap(configMapUsers.GetObjectMeta())
-	} else {
-		w.task.RegistryFailed().RegisterConfigMap(configMapUsers.GetObjectMeta())
-	}
-	return err
+	return w.reconcileConfigMapInKubeContexts(ctx, cr, configMapUsers)
+}
+
+// reconcileConfigMapInKubeContexts reconciles ConfigMap shared by all hosts in every kube context hosts live in
+func (w *worker) reconcileConfigMapInKubeContexts(ctx context.Context, cr api.ICustomResource, configMap *core.ConfigMap) error {
+	return w.walkKubeContexts(ctx, cr, func(ctx context.Context, kubeContext string) error {
+		configMap := configMap.DeepCopy()
+		err := w.reconcileConfigMap(ctx, cr, configMap)
+		if err == nil {
+			w.task.RegistryReconciled().KubeContext(kubeContext).RegisterConfigMap(configMap.GetObjectMeta())
+		} else {
+			w.task.RegistryFailed().KubeContext(kubeContext).RegisterConfigMap(configMap.GetObjectMeta())
+		}
+		return err
+	})
 }
 
 // reconcileConfigMapHost reconciles host's personal ConfigMap
@@ -374,9 +443,9 @@ func (w *worker) reconcileConfigMapHost(ctx context.Context, host *api.Host) err
 	configMap := w.task.Creator().CreateConfigMap(interfaces.ConfigMapHost, host)
 	err := w.reconcileConfigMap(ctx, host.GetCR(), configMap)
 	if err == nil {
-		w.task.RegistryReconciled().RegisterConfigMap(configMap.GetObjectMeta())
+		w.task.RegistryReconciled().KubeContext(host.GetKubeContext()).RegisterConfigMap(configMap.GetObjectMeta())
 	} else {
-		w.task.RegistryFailed().RegisterConfigMap(configMap.GetObjectMeta())
+		w.task.RegistryFailed().KubeContext(host.GetKubeContext()).RegisterConfigMap(configMap.GetObjectMeta())
 		return err
 	}
 
@@ -403,9 +472,9 @@ func (w *worker) reconcileHostStatefulSet(ctx context.Context, host *api.Host, o
 	w.a.V(1).M(host).F().Info("Reconcile host STS: %s. Reconcile StatefulSet", host.GetName())
 	err := w.stsReconciler.ReconcileStatefulSet(ctx, host, true, opts)
 	if err == nil {
-		w.task.RegistryReconciled().RegisterStatefulSet(host.Runtime.DesiredStatefulSet.GetObjectMeta())
+		w.task.RegistryReconciled().KubeContext(host.GetKubeContext()).RegisterStatefulSet(host.Runtime.DesiredStatefulSet.GetObjectMeta())
 	} else {
-		w.task.RegistryFailed().RegisterStatefulSet(host.Runtime.DesiredStatefulSet.GetObjectMeta())
+		w.task.RegistryFailed().KubeContext(host.GetKubeContext()).RegisterStatefulSet(host.Runtime.DesiredStatefulSet.GetObjectMeta())
 		if err == common.ErrCRUDIgnore {
 			// Pretend nothing happened in case of ignore
 			err = nil
@@ -536,10 +605,10 @@ func (w *worker) reconcileHostService(ctx context.Context, host *api.Host) error
 	err := w.reconcileService(ctx, host.GetCR(), service, prevService)
 	if err == nil {
 		w.a.V(1).M(host).F().Info("DONE Reconcile service of the host: %s", host.GetName())
-		w.task.RegistryReconciled().RegisterService(service.GetObjectMeta())
+		w.task.RegistryReconciled().KubeContext(host.GetKubeContext()).RegisterService(service.GetObjectMeta())
 	} else {
 		w.a.V(1).M(host).F().Warning("FAILED Reconcile service of the host: %s", host.GetName())
-		w.task.RegistryFailed().RegisterService(service.GetObjectMeta())
+		w.task.RegistryFailed().KubeContext(host.GetKubeContext()).RegisterService(service.GetObjectMeta())
 	}
 	return err
 }
@@ -569,6 +638,15 @@ func (w *worker) reconcileCluster(ctx context.Context, cluster *api.Cluster) err
 	if err := w.reconcileClusterShardsAndHosts(ctx, cluster); err != nil {
 		return err
 	}
+	if err := w.reconcileClusterClone(ctx, cluster); err != nil {
+		return err
+	}
+	if err := w.reconcileClusterSchema(ctx, cluster); err != nil {
+		return err
+	}
+	if err := w.reconcileClusterRebalance(ctx, cluster); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -592,6 +670,7 @@ func (w *worker) reconcileClusterSecret(ctx context.Context, cluster *api.Cluste
 		if secret := w.task.Creator().CreateClusterSecret(cluster); secret != nil {
 			if err := w.reconcileSecret(ctx, cluster.Runtime.CHI, secret); err == nil {
 				w.task.RegistryReconciled().RegisterSecret(secret.GetObjectMeta())
+				w.reconcileClusterSecretRemote(ctx, cluster, secret)
 			} else {
 				w.task.RegistryFailed().RegisterSecret(secret.GetObjectMeta())
 			}
@@ -600,6 +679,35 @@ func (w *worker) reconcileClusterSecret(ctx context.Context, cluster *api.Cluste
 	return nil
 }
 
+// reconcileClusterSecretRemote copies cluster's Auto Secret into remote kube contexts,
+// since hosts of all kube contexts have to share the same secret
+func (w *worker) reconcileClusterSecretRemote(ctx context.Context, cluster *api.Cluster, secret *core.Secret) {
+	chi := cluster.Runtime.CHI
+	if !chi.IsMultiKubeContext() {
+		return
+	}
+	local, err := w.c.getSecret(chiKube.WithKubeContext(ctx, chi, ""), secret)
+	if err != nil {
+		w.a.V(1).M(chi).F().Warning("Unable to get cluster secret %s. err: %v", util.NamespacedName(secret), err)
+		return
+	}
+	_ = w.walkKubeContexts(ctx, chi, func(ctx context.Context, kubeContext string) error {
+		if kubeContext == "" {
+			return nil
+		}
+		remote := secret.DeepCopy()
+		remote.StringData = nil
+		remote.Data = local.Data
+		err := w.reconcileSecret(ctx, chi, remote)
+		if err == nil {
+			w.task.RegistryReconciled().KubeContext(kubeContext).RegisterSecret(remote.GetObjectMeta())
+		} else {
+			w.task.RegistryFailed().KubeContext(kubeContext).RegisterSecret(remote.GetObjectMeta())
+		}
+		return err
+	})
+}
+
 func (w *worker) reconcileClusterPodDisruptionBudget(ctx context.Context, cluster *api.Cluster) error {
 	if cluster.GetPDBManaged().IsFalse() {
 		return nil
@@ -628,6 +736,11 @@ func (w *worker) reconcileClusterShardsAndHosts(ctx context.Context, cluster *ap
 
 	opts := w.reconcileShardsAndHostsFetchOpts(ctx)
 
+	if w.isStagedUpgrade(cluster) {
+		// Image of the hosts is changed, upgrade cluster in stages
+		return w.reconcileClusterShardsAndHostsStaged(ctx, cluster, opts)
+	}
+
 	// Which shard to start concurrent processing with
 	var startShard int
 	if opts.FullFanOut {
@@ -718,15 +831,25 @@ func (w *worker) reconcileHost(ctx context.Context, host *api.Host) error {
 		_ = w.reconcileCRServicePreliminary(ctx, host.GetCR())
 		defer w.reconcileCRServiceFinal(ctx, host.GetCR())
 	}
+	// Objects of the host live in the kube context of the host
+	ctx = chiKube.WithHostKubeContext(ctx, host)
 
 	w.a.V(1).M(host).F().Info("Reconcile host: %s. App version: %s", host.GetName(), host.Runtime.Version.Render())
 
+	if w.deferHost(ctx, host) {
+		// Disruptive actions wait for maintenance window, apply what is safe to apply right now
+		return w.reconcileHostDeferred(ctx, host)
+	}
+
 	if err := w.reconcileHostPrepare(ctx, host); err != nil {
 		return err
 	}
 	if err := w.reconcileHostMain(ctx, host); err != nil {
 		return err
 	}
+	if err := w.promoteDisasterRecoveryHost(ctx, host); err != nil {
+		return err
+	}
 	// Host is now added and functional
 
 	if host.GetReconcileAttributes().GetStatus().Is(types.ObjectStatusRequested) {
@@ -735,6 +858,7 @@ func (w *worker) reconcileHost(ctx context.Context, host *api.Host) error {
 	if err := w.reconcileHostIncludeIntoAllActivities(ctx, host); err != nil {
 		return err
 	}
+	w.completeDeferredActions(ctx, host)
 
 	now := time.Now()
 	hostsCompleted := 0
@@ -915,6 +1039,14 @@ func (w *worker) reconcileHostTables(ctx context.Context, host *api.Host, opts *
 		M(host).F().
 		Info("Check host for ClickHouse availability before migrating tables. Host: %s ClickHouse version available: %s", host.GetName(), version)
 
+	// Replicated tables require keeper to be in place
+	if err := w.waitKeeperReady(ctx, host); err != nil {
+		w.a.V(1).
+			M(host).F().
+			Warning("Check keeper for readiness before migrating tables. Host: %s Err: %s", host.GetName(), err)
+		return err
+	}
+
 	return w.migrateTables(ctx, host, opts)
 }
 
@@ -0,0 +1,159 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/schemer"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// reconcileClusterSchema creates objects of the schema declared in the CHI on every host of the cluster,
+// adds missing columns into the declared tables and reports objects drifted from their definitions.
+// Schema errors do not fail reconcile - they are reported in status and schema is applied again on the next reconcile
+func (w *worker) reconcileClusterSchema(ctx context.Context, cluster *api.Cluster) error {
+	if util.IsContextDone(ctx) {
+		log.V(1).Info("Reconcile is aborted. Cluster: %s ", cluster.GetName())
+		return nil
+	}
+
+	chi := cluster.GetCR()
+	objects := schemer.DeclaredObjects(chi.GetSpecT().Configuration.GetSchema())
+
+	if (len(objects) == 0) || cluster.IsStopped() {
+		if chi.EnsureStatus().GetSchema(cluster.GetName()) != nil {
+			chi.EnsureStatus().DeleteSchema(cluster.GetName())
+			w.updateSchemaStatus(ctx, chi)
+		}
+		return nil
+	}
+
+	w.a.V(1).M(cluster).F().Info("Reconcile schema of cluster: %s", cluster.GetName())
+
+	status := &api.SchemaStatus{
+		Cluster: cluster.GetName(),
+	}
+	cluster.WalkHosts(func(host *api.Host) error {
+		if util.IsContextDone(ctx) {
+			return nil
+		}
+		drift, err := w.reconcileHostSchema(ctx, host, objects)
+		if err != nil {
+			w.a.V(1).M(host).F().Warning("Unable to reconcile schema on host: %s err: %v", host.GetName(), err)
+			status.Errors = append(status.Errors, fmt.Sprintf("%s: %v", host.GetName(), err))
+		}
+		for _, d := range drift {
+			status.AddDrift(host.GetName(), d)
+		}
+		return nil
+	})
+
+	switch {
+	case len(status.Errors) > 0:
+		status.Status = api.SchemaStatusFailed
+	case len(status.Drift) > 0:
+		status.Status = api.SchemaStatusDrifted
+		w.a.V(1).M(cluster).F().Warning("Schema of cluster: %s drifted: %d objects differ from their definitions", cluster.GetName(), len(status.Drift))
+	default:
+		status.Status = api.SchemaStatusInSync
+	}
+
+	chi.EnsureStatus().SetSchema(status)
+	w.updateSchemaStatus(ctx, chi)
+
+	return nil
+}
+
+// reconcileHostSchema creates missing objects and columns on the host and lists objects drifted from their definitions
+func (w *worker) reconcileHostSchema(ctx context.Context, host *api.Host, objects []*schemer.DeclaredObject) ([]*api.SchemaDrift, error) {
+	s := w.ensureClusterSchemer(host)
+	existing, err := s.HostDeclaredObjects(ctx, host, objects)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	for _, object := range objects {
+		actual, found := existing[object.FullName()]
+		switch {
+		case !found:
+			if err := s.HostCreateDeclaredObject(ctx, host, object); err != nil {
+				return nil, fmt.Errorf("unable to create %s %s: %w", object.Kind, object.FullName(), err)
+			}
+			changed = true
+		case object.IsTable():
+			// Schema evolves by adding columns. Other changes may be destructive and are reported as drift
+			for _, column := range schemer.MissingColumns(object.SQL, actual) {
+				if err := s.HostAddColumn(ctx, host, object, column); err != nil {
+					return nil, fmt.Errorf("unable to add column into %s: %w", object.FullName(), err)
+				}
+				changed = true
+			}
+		}
+	}
+
+	if changed {
+		if existing, err = s.HostDeclaredObjects(ctx, host, objects); err != nil {
+			return nil, err
+		}
+	}
+
+	// Tables are compared to the statements ClickHouse keeps for tables created out of the declared ones,
+	// so settings and formatting ClickHouse adds on its own are not reported as drift
+	references, err := s.HostReferenceStatements(ctx, host, objects)
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []*api.SchemaDrift
+	for _, object := range objects {
+		actual, found := existing[object.FullName()]
+		var differences []string
+		switch {
+		case !found:
+			differences = []string{"missing"}
+		case object.IsTable():
+			differences = schemer.DiffTableStatements(object.SQL, references[object.FullName()], actual)
+		default:
+			differences = schemer.DiffStatements(object.SQL, actual)
+		}
+		if len(differences) > 0 {
+			drift = append(drift, &api.SchemaDrift{
+				Kind:        object.Kind,
+				Object:      object.FullName(),
+				Differences: differences,
+			})
+		}
+	}
+	return drift, nil
+}
+
+// updateSchemaStatus writes schema status of the CHI
+func (w *worker) updateSchemaStatus(ctx context.Context, chi *api.ClickHouseInstallation) {
+	_ = w.c.updateCRObjectStatus(ctx, chi, types.UpdateStatusOptions{
+		CopyStatusOptions: types.CopyStatusOptions{
+			CopyStatusField: types.CopyStatusField{
+				Copy: types.Status{
+					Schema: true,
+				},
+			},
+		},
+	})
+}
@@ -20,7 +20,9 @@ import (
 	core "k8s.io/api/core/v1"
 
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	chiKube "github.com/altinity/clickhouse-operator/pkg/controller/chi/kube"
 	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/certificate"
 )
 
 // reconcileSecret reconciles core.Secret
@@ -67,3 +69,26 @@ func (w *worker) createSecret(ctx context.Context, cr api.ICustomResource, secre
 
 	return err
 }
+
+// reconcileCRCertificates provisions TLS certificates of the CR, in case it is requested
+func (w *worker) reconcileCRCertificates(ctx context.Context, cr *api.ClickHouseInstallation) error {
+	rootCA, err := certificate.NewReconciler(w.task, w.c.namer, w.c.kube.Secret()).
+		SetHostContext(chiKube.WithHostKubeContext).
+		Reconcile(ctx, cr)
+	if err != nil {
+		w.a.WithEvent(cr, a.EventActionReconcile, a.EventReasonReconcileFailed).
+			WithAction(cr).
+			WithError(cr).
+			M(cr).F().
+			Error("FAILED to reconcile TLS certificates. CHI: %s err: %v", cr.GetName(), err)
+		return err
+	}
+	// Operator's own connections to the hosts have to trust the CA as well
+	cr.EnsureRuntime().TLSRootCA = string(rootCA)
+	return nil
+}
+
+// isCertificatesRenewalRequired checks whether TLS certificates of the CR are due for renewal
+func (w *worker) isCertificatesRenewalRequired(ctx context.Context, cr *api.ClickHouseInstallation) bool {
+	return certificate.IsRenewalRequired(ctx, cr, w.c.namer, w.c.kube.Secret())
+}
@@ -22,6 +22,7 @@ import (
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/chop"
+	chiKube "github.com/altinity/clickhouse-operator/pkg/controller/chi/kube"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common"
 	"github.com/altinity/clickhouse-operator/pkg/interfaces"
 	"github.com/altinity/clickhouse-operator/pkg/util"
@@ -74,22 +75,7 @@ func (c *Controller) OnStatefulSetUpdateFailed(ctx context.Context, rollbackStat
 	case api.OnStatefulSetUpdateFailureActionRollback:
 		// Need to revert current StatefulSet to oldStatefulSet
 		log.V(1).M(host).F().Info("ROLLBACK StatefulSet: %s", util.NamespaceNameString(rollbackStatefulSet.GetObjectMeta()))
-		curStatefulSet, err := kubeSTS.Get(ctx, host)
-		if err != nil {
-			log.V(1).M(host).F().Warning("Unable to fetch current StatefulSet %s. err: %q", util.NamespaceNameString(rollbackStatefulSet.GetObjectMeta()), err)
-			return c.shouldContinueOnUpdateFailed()
-		}
-
-		// Make copy of "rollback to" .Spec just to be sure nothing gets corrupted
-		// Update StatefulSet to its 'rollback to' StatefulSet - this is expected to rollback inapplicable changes
-		// Having StatefulSet .spec in rolled back status we need to delete current Pod - because in case of Pod
-		// being seriously broken, it is the only way to go.
-		// Just delete Pod and StatefulSet will recreated Pod with current .spec
-		// This will rollback Pod to "rollback to" .spec
-		curStatefulSet.Spec = *rollbackStatefulSet.Spec.DeepCopy()
-		curStatefulSet, _ = kubeSTS.Update(ctx, curStatefulSet)
-		_ = c.statefulSetDeletePod(ctx, curStatefulSet, host)
-
+		_ = c.rollbackStatefulSet(ctx, rollbackStatefulSet, host, kubeSTS)
 		return c.shouldContinueOnUpdateFailed()
 
 	case api.OnStatefulSetUpdateFailureActionIgnore:
@@ -105,6 +91,32 @@ func (c *Controller) OnStatefulSetUpdateFailed(ctx context.Context, rollbackStat
 	return common.ErrCRUDUnexpectedFlow
 }
 
+// rollbackStatefulSet reverts current StatefulSet of the host to its previous version, specified in rollbackStatefulSet
+func (c *Controller) rollbackStatefulSet(ctx context.Context, rollbackStatefulSet *apps.StatefulSet, host *api.Host, kubeSTS interfaces.IKubeSTS) error {
+	// StatefulSet and Pod of the host live in the kube context of the host
+	ctx = chiKube.WithHostKubeContext(ctx, host)
+
+	curStatefulSet, err := kubeSTS.Get(ctx, host)
+	if err != nil {
+		log.V(1).M(host).F().Warning("Unable to fetch current StatefulSet %s. err: %q", util.NamespaceNameString(rollbackStatefulSet.GetObjectMeta()), err)
+		return err
+	}
+
+	// Make copy of "rollback to" .Spec just to be sure nothing gets corrupted
+	// Update StatefulSet to its 'rollback to' StatefulSet - this is expected to rollback inapplicable changes
+	// Having StatefulSet .spec in rolled back status we need to delete current Pod - because in case of Pod
+	// being seriously broken, it is the only way to go.
+	// Just delete Pod and StatefulSet will recreated Pod with current .spec
+	// This will rollback Pod to "rollback to" .spec
+	curStatefulSet.Spec = *rollbackStatefulSet.Spec.DeepCopy()
+	curStatefulSet, err = kubeSTS.Update(ctx, curStatefulSet)
+	if err != nil {
+		log.V(1).M(host).F().Warning("Unable to update StatefulSet %s. err: %q", util.NamespaceNameString(rollbackStatefulSet.GetObjectMeta()), err)
+		return err
+	}
+	return c.statefulSetDeletePod(ctx, curStatefulSet, host)
+}
+
 // shouldContinueOnCreateFailed return nil in case 'continue' or error in case 'do not continue'
 func (c *Controller) shouldContinueOnCreateFailed() common.ErrorCRUD {
 	// Check configuration option regarding should we continue when errors met on the way
@@ -0,0 +1,207 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common"
+	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/poller"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/poller/domain"
+	stagedUpgrade "github.com/altinity/clickhouse-operator/pkg/controller/common/upgrade"
+	metricsClickHouse "github.com/altinity/clickhouse-operator/pkg/metrics/clickhouse"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/config"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
+)
+
+// isStagedUpgrade checks whether the cluster has staged upgrade enabled and image of any host of the cluster is changed
+func (w *worker) isStagedUpgrade(cluster *api.Cluster) bool {
+	if !cluster.GetReconcile().Upgrade.IsEnabled() {
+		return false
+	}
+	upgrade := false
+	cluster.WalkHosts(func(host *api.Host) error {
+		upgrade = upgrade || isHostImageChanged(host)
+		return nil
+	})
+	return upgrade
+}
+
+// isHostImageChanged checks whether image of the ClickHouse container of the existing host is changed
+func isHostImageChanged(host *api.Host) bool {
+	cur, desired := host.Runtime.CurStatefulSet, host.Runtime.DesiredStatefulSet
+	if (cur == nil) || (desired == nil) {
+		return false
+	}
+	curContainer, curFound := k8s.StatefulSetContainerGet(cur, config.ClickHouseContainerName, 0)
+	desiredContainer, desiredFound := k8s.StatefulSetContainerGet(desired, config.ClickHouseContainerName, 0)
+	return curFound && desiredFound && (curContainer.Image != desiredContainer.Image)
+}
+
+// reconcileClusterShardsAndHostsStaged reconciles shards and hosts of the cluster upgrading them in stages.
+// Canary host or shard is upgraded first, the rest of the shards are upgraded in waves afterwards.
+// Each stage has to pass health gate, otherwise hosts of the stage are rolled back and reconcile is stopped
+func (w *worker) reconcileClusterShardsAndHostsStaged(ctx context.Context, cluster *api.Cluster, opts *common.ReconcileShardsAndHostsOptions) error {
+	upgrade := cluster.GetReconcile().Upgrade
+
+	w.a.V(1).
+		WithEvent(cluster.GetCR(), a.EventActionReconcile, a.EventReasonReconcileInProgress).
+		WithAction(cluster.GetCR()).
+		M(cluster).F().
+		Info("Staged upgrade of cluster: %s canary: %s wave: %d%%", cluster.GetName(), upgrade.GetCanary(), upgrade.GetWavePercent())
+
+	err := stagedUpgrade.Run(ctx, cluster, w.newUpgradeReconciler(cluster, opts))
+	if err != nil {
+		w.a.V(1).
+			WithEvent(cluster.GetCR(), a.EventActionReconcile, a.EventReasonReconcileFailed).
+			WithAction(cluster.GetCR()).
+			WithError(cluster.GetCR()).
+			M(cluster).F().
+			Error("Staged upgrade of cluster: %s failed. err: %v", cluster.GetName(), err)
+	}
+	return err
+}
+
+// upgradeReconciler reconciles shards and hosts of the cluster on behalf of the staged upgrade
+type upgradeReconciler struct {
+	w          *worker
+	cluster    *api.Cluster
+	workersNum int
+
+	// rollback keeps StatefulSets the hosts of the stage are upgraded from
+	rollback map[*api.Host]*apps.StatefulSet
+	// baseline keeps health of the hosts of the stage taken before the stage is started
+	baseline map[*api.Host]*metricsClickHouse.UpgradeHealth
+}
+
+// newUpgradeReconciler creates new upgrade reconciler of the cluster
+func (w *worker) newUpgradeReconciler(cluster *api.Cluster, opts *common.ReconcileShardsAndHostsOptions) *upgradeReconciler {
+	return &upgradeReconciler{
+		w:          w,
+		cluster:    cluster,
+		workersNum: w.getReconcileShardsWorkersNum(cluster, opts),
+	}
+}
+
+// ReconcileShard reconciles the shard without its hosts
+func (r *upgradeReconciler) ReconcileShard(ctx context.Context, shard *api.ChiShard) error {
+	return r.w.reconcileShard(ctx, shard)
+}
+
+// ReconcileShardWithHosts reconciles the shard along with its hosts
+func (r *upgradeReconciler) ReconcileShardWithHosts(ctx context.Context, shard *api.ChiShard) error {
+	return r.w.reconcileShardWithHosts(ctx, shard)
+}
+
+// ReconcileHost reconciles the host
+func (r *upgradeReconciler) ReconcileHost(ctx context.Context, host *api.Host) error {
+	return r.w.reconcileHost(ctx, host)
+}
+
+// ReconcileShards reconciles the shards concurrently
+func (r *upgradeReconciler) ReconcileShards(ctx context.Context, startShardIndex int, shards []*api.ChiShard) error {
+	return r.w.runConcurrently(ctx, r.workersNum, startShardIndex, shards)
+}
+
+// Prepare keeps StatefulSets the hosts are upgraded from, so it would be possible to roll back,
+// and health of the hosts before the stage, so errors happened before the stage are not counted
+func (r *upgradeReconciler) Prepare(ctx context.Context, hosts []*api.Host) (upgraded []*api.Host) {
+	r.rollback = make(map[*api.Host]*apps.StatefulSet)
+	r.baseline = make(map[*api.Host]*metricsClickHouse.UpgradeHealth)
+	for _, host := range hosts {
+		if !isHostImageChanged(host) {
+			continue
+		}
+		upgraded = append(upgraded, host)
+		r.rollback[host] = host.Runtime.CurStatefulSet.DeepCopy()
+		if health, err := r.w.c.newHostMetricsFetcher(host).GetUpgradeHealth(ctx); err == nil {
+			r.baseline[host] = health
+		} else {
+			r.w.a.V(1).M(host).F().Warning("Unable to get health of host: %s before upgrade, all errors are counted. err: %v", host.GetName(), err)
+		}
+	}
+	return upgraded
+}
+
+// CheckHealthGate checks upgraded hosts pass health gate within the health gate timeout.
+// Hosts which are not upgraded on this reconcile, such as deferred ones, are not checked
+func (r *upgradeReconciler) CheckHealthGate(ctx context.Context, hosts []*api.Host) error {
+	cr := r.cluster.GetCR()
+	gate := r.cluster.GetReconcile().Upgrade.GetHealthGate()
+	deadline := time.Now().Add(time.Duration(gate.GetTimeout()) * time.Second)
+
+	for _, host := range hosts {
+		if host.IsStopped() || (cr.EnsureStatus().GetDeferredAction(host.GetName()) != nil) {
+			continue
+		}
+		var reason error
+		err := domain.PollHost(
+			ctx,
+			host,
+			func(_ctx context.Context, _host *api.Host) bool {
+				reason = r.checkHostHealth(_ctx, _host, gate)
+				return reason == nil
+			},
+			&poller.Options{
+				Timeout: max(time.Until(deadline), time.Second),
+			},
+		)
+		if reason != nil {
+			return fmt.Errorf("host %s: %v", host.GetName(), reason)
+		}
+		if err != nil {
+			return fmt.Errorf("host %s: %v", host.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// checkHostHealth checks the upgraded host runs ClickHouse version of the image,
+// reports not too many errors since the stage is started and its replicas are not lagging behind
+func (r *upgradeReconciler) checkHostHealth(ctx context.Context, host *api.Host, gate *api.UpgradeHealthGate) error {
+	version := r.w.getHostClickHouseVersion(ctx, host)
+	if version.IsUnknown() {
+		return fmt.Errorf("ClickHouse version is not available")
+	}
+	if expected := r.w.getTagBasedVersion(host); gate.ShouldValidateVersion() && expected.IsKnown() && (version.Cmp(expected) < 0) {
+		return fmt.Errorf("ClickHouse version %s is older than the image version %s", version, expected)
+	}
+
+	health, err := r.w.c.newHostMetricsFetcher(host).GetUpgradeHealth(ctx)
+	if err != nil {
+		return err
+	}
+	return stagedUpgrade.CheckHealth(gate, r.baseline[host], health)
+}
+
+// Rollback rolls the upgraded hosts back to their previous StatefulSets
+func (r *upgradeReconciler) Rollback(ctx context.Context, hosts []*api.Host) {
+	for _, host := range hosts {
+		statefulSet, ok := r.rollback[host]
+		if !ok {
+			continue
+		}
+		r.w.a.V(1).M(host).F().Info("Staged upgrade of cluster: %s. Roll back host: %s", r.cluster.GetName(), host.GetName())
+		if err := r.w.c.rollbackStatefulSet(ctx, statefulSet, host, r.w.c.kube.STS()); err != nil {
+			r.w.a.V(1).M(host).F().Error("Unable to roll back host: %s err: %v", host.GetName(), err)
+		}
+	}
+}
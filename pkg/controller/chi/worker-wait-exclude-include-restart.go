@@ -94,6 +94,11 @@ func (w *worker) excludeHost(ctx context.Context, host *api.Host) bool {
 			host.Runtime.Address.ReplicaIndex, host.Runtime.Address.ShardIndex, host.Runtime.Address.ClusterName)
 
 	_ = w.excludeHostFromService(ctx, host)
+	if host.IsInMaintenance() {
+		// Host in maintenance has to be removed from the cluster completely, not just descended
+		w.excludeHostFromClickHouseCluster(ctx, host)
+		return true
+	}
 	w.descendHostInClickHouseCluster(ctx, host)
 	//w.excludeHostFromClickHouseCluster(ctx, host)
 	return true
@@ -117,6 +122,9 @@ func (w *worker) shouldIncludeHost(host *api.Host) bool {
 	case host.IsStopped():
 		// No need to include stopped host
 		return false
+	case host.IsInMaintenance():
+		// Host in maintenance is kept out of the cluster until maintenance is cleared
+		return false
 	}
 	return true
 }
@@ -152,6 +160,13 @@ func (w *worker) shouldWaitReplicationHost(host *api.Host) bool {
 				host.Runtime.Address.ReplicaIndex, host.Runtime.Address.ShardIndex, host.Runtime.Address.ClusterName)
 		return false
 
+	case isAutoscaled(host) && host.GetReconcileAttributes().GetStatus().Is(types.ObjectStatusCreated):
+		// Replicas added by autoscaler have to be ready to serve queries before they join the cluster
+		w.a.V(1).
+			M(host).F().
+			Info("New replica of the autoscaled cluster has to wait for replication to catch-up")
+		return true
+
 	case chop.Config().Reconcile.Host.Wait.Replicas.All.IsTrue():
 		w.a.V(1).
 			M(host).F().
@@ -191,6 +206,12 @@ func (w *worker) shouldWaitReplicationHost(host *api.Host) bool {
 	return false
 }
 
+// isAutoscaled checks whether host belongs to the cluster with autoscaling enabled
+func isAutoscaled(host *api.Host) bool {
+	cluster, ok := host.GetCluster().(*api.Cluster)
+	return ok && cluster.GetAutoscaling().IsEnabled()
+}
+
 // includeHost includes host back into all activities - such as cluster, service, etc
 func (w *worker) includeHost(ctx context.Context, host *api.Host) error {
 	w.a.V(1).
@@ -373,6 +394,13 @@ func (w *worker) shouldExcludeHost(ctx context.Context, host *api.Host) bool {
 				host.Runtime.Address.ReplicaIndex, host.Runtime.Address.ShardIndex, host.Runtime.Address.ClusterName)
 		return false
 
+	case host.IsInMaintenance():
+		w.a.V(1).
+			M(host).F().
+			Info("Host is in maintenance, need to exclude. Host/shard/cluster: %d/%d/%s",
+				host.Runtime.Address.ReplicaIndex, host.Runtime.Address.ShardIndex, host.Runtime.Address.ClusterName)
+		return true
+
 	case host.GetShard().HostsCount() == 1:
 		w.a.V(1).
 			M(host).F().
@@ -447,6 +475,13 @@ func (w *worker) shouldWaitQueries(host *api.Host) bool {
 				"Host/shard/cluster: %d/%d/%s",
 				host.Runtime.Address.ReplicaIndex, host.Runtime.Address.ShardIndex, host.Runtime.Address.ClusterName)
 		return false
+	case host.IsInMaintenance():
+		w.a.V(1).
+			M(host).F().
+			Info("Will wait for queries to complete on a host, host enters maintenance. "+
+				"Host/shard/cluster: %d/%d/%s",
+				host.Runtime.Address.ReplicaIndex, host.Runtime.Address.ShardIndex, host.Runtime.Address.ClusterName)
+		return true
 	case chop.Config().Reconcile.Host.Wait.Queries.Value():
 		w.a.V(1).
 			M(host).F().
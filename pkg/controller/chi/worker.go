@@ -30,6 +30,7 @@ import (
 	"github.com/altinity/clickhouse-operator/pkg/controller/chi/metrics"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common"
 	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/conditions"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common/poller/domain"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common/statefulset"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common/storage"
@@ -95,6 +96,8 @@ func (c *Controller) newWorker(q queue.PriorityQueue, sys bool) *worker {
 					Name:      name,
 				},
 			})
+		}, func(namespace, name string) (api.ZookeeperNodes, error) {
+			return c.getKeeperNodes(namespace, name)
 		}),
 		start: start,
 		task:  nil,
@@ -109,6 +112,8 @@ func configGeneratorOptions(cr *api.ClickHouseInstallation) *config.GeneratorOpt
 		Settings:       cr.GetSpecT().GetConfiguration().GetSettings(),
 		Files:          cr.GetSpecT().GetConfiguration().GetFiles(),
 		DistributedDDL: cr.GetSpecT().GetDefaults().GetDistributedDDL(),
+
+		DisasterRecovery: cr.EnsureRuntime().DisasterRecovery,
 	}
 }
 
@@ -174,6 +179,10 @@ func (w *worker) shouldForceRestartHost(ctx context.Context, host *api.Host) boo
 		w.a.V(1).M(host).F().Info("Host has no ancestor, no restart applicable. Host: %s", host.GetName())
 		return false
 
+	case w.isHostRestartDeferred(host):
+		w.a.V(1).M(host).F().Info("Host restart was deferred till maintenance window. Host: %s", host.GetName())
+		return true
+
 	case host.GetCR().IsRollingUpdate():
 		w.a.V(1).M(host).F().Info("RollingUpdate requires force restart. Host: %s", host.GetName())
 		return true
@@ -339,6 +348,7 @@ func (w *worker) markReconcileStart(ctx context.Context, cr *api.ClickHouseInsta
 
 	// Write desired normalized CHI with initialized .Status, so it would be possible to monitor progress
 	cr.EnsureStatus().ReconcileStart(cr.EnsureRuntime().ActionPlan)
+	conditions.ReconcileStarted(cr)
 	_ = w.c.updateCRObjectStatus(ctx, cr, types.UpdateStatusOptions{
 		CopyStatusOptions: types.CopyStatusOptions{
 			CopyStatusFieldGroup: types.CopyStatusFieldGroup{
@@ -379,6 +389,7 @@ func (w *worker) finalizeReconcileAndMarkCompleted(ctx context.Context, _cr *api
 			c.SetAncestor(c.GetTarget())
 			c.SetTarget(nil)
 			c.EnsureStatus().ReconcileComplete()
+			conditions.ReconcileCompleted(c)
 		},
 	)
 
@@ -402,6 +413,7 @@ func (w *worker) markReconcileCompletedUnsuccessfully(ctx context.Context, cr *a
 	case errors.Is(err, common.ErrCRUDAbort):
 		cr.EnsureStatus().ReconcileAbort()
 	}
+	conditions.ReconcileFailed(cr, err)
 	w.c.updateCRObjectStatus(ctx, cr, types.UpdateStatusOptions{
 		CopyStatusOptions: types.CopyStatusOptions{
 			CopyStatusFieldGroup: types.CopyStatusFieldGroup{
@@ -540,6 +552,8 @@ func (w *worker) createTemplatedCR(_cr *api.ClickHouseInstallation, _opts ...*co
 
 	cr := w.createTemplated(_cr, _opts...)
 	cr.SetAncestor(w.createTemplated(_cr.GetAncestorT()))
+	cr = w.keepUndrainedShards(_cr, cr, _opts...)
+	w.applyResourcesRecommendations(cr)
 
 	return cr
 }
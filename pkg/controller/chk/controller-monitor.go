@@ -0,0 +1,89 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chk
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// MonitorSource provides CHKs enqueued for reconcile by the monitor
+func (c *Controller) MonitorSource() source.Source {
+	return &source.Channel{
+		Source: c.enqueued(),
+	}
+}
+
+// enqueued provides channel the monitor enqueues CHKs into
+func (c *Controller) enqueued() chan event.GenericEvent {
+	c.enqueuedOnce.Do(func() {
+		c.enqueuedCh = make(chan event.GenericEvent)
+	})
+	return c.enqueuedCh
+}
+
+// RunMonitor periodically checks health of the watched CHKs and takes their scheduled snapshots.
+// It runs apart from the reconcile, so reconcile is not requeued just to run periodic tasks
+func (c *Controller) RunMonitor(ctx context.Context) error {
+	wait.UntilWithContext(ctx, c.runMonitorPass, healthCheckPeriod)
+	return nil
+}
+
+// runMonitorPass runs periodic tasks over all watched CHKs
+func (c *Controller) runMonitorPass(ctx context.Context) {
+	list := &apiChk.ClickHouseKeeperInstallationList{}
+	if err := c.Client.List(ctx, list); err != nil {
+		log.V(1).F().Error("unable to list CHKs. err: %v", err)
+		return
+	}
+
+	c.new()
+	for i := range list.Items {
+		if util.IsContextDone(ctx) {
+			return
+		}
+		// Objects of the cache must not be modified
+		cr := list.Items[i].DeepCopy()
+		if !chop.Config().IsNamespaceWatched(cr.GetNamespace()) || cr.Spec.Suspend.Value() {
+			continue
+		}
+		c.runMonitorCR(ctx, cr)
+	}
+}
+
+// runMonitorCR checks health of the CHK and takes its scheduled snapshots.
+// CHK with hosts, which restart is held back, is enqueued for reconcile, so the restart is retried
+func (c *Controller) runMonitorCR(ctx context.Context, cr *apiChk.ClickHouseKeeperInstallation) {
+	w := c.newWorker()
+	w.checkHealth(ctx, cr)
+	w.reconcileSnapshots(ctx, cr)
+
+	if !c.hasHeldBack(cr) {
+		return
+	}
+	log.V(1).M(cr).F().Info("Restart of hosts is held back - enqueue reconcile")
+	select {
+	case c.enqueued() <- event.GenericEvent{Object: cr}:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chk
+
+import (
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/metrics"
+	"github.com/altinity/clickhouse-operator/pkg/metrics/clickhouse"
+)
+
+// updateWatch
+func (c *Controller) updateWatch(cr *apiChk.ClickHouseKeeperInstallation) {
+	watched := metrics.NewWatchedCR(cr)
+	go c.updateWatchAsync(watched)
+}
+
+// allocateWatch
+func (c *Controller) allocateWatch(cr *apiChk.ClickHouseKeeperInstallation) {
+	watched := metrics.NewWatchedCR(cr)
+	watched.Clusters = nil
+	go c.updateWatchAsync(watched)
+}
+
+// updateWatchAsync
+func (c *Controller) updateWatchAsync(cr *metrics.WatchedCR) {
+	if err := clickhouse.InformMetricsExporterAboutWatchedCHI(cr); err != nil {
+		log.V(1).F().Info("FAIL update watch (%s/%s): %q", cr.Namespace, cr.Name, err)
+	} else {
+		log.V(1).Info("OK update watch (%s/%s): %s", cr.Namespace, cr.Name, cr)
+	}
+}
+
+// deleteWatch
+func (c *Controller) deleteWatch(namespace, name string) {
+	watched := &metrics.WatchedCR{
+		Kind:      apiChk.ClickHouseKeeperInstallationCRDResourceKind,
+		Namespace: namespace,
+		Name:      name,
+	}
+	go c.deleteWatchAsync(watched)
+}
+
+// deleteWatchAsync
+func (c *Controller) deleteWatchAsync(cr *metrics.WatchedCR) {
+	if err := clickhouse.InformMetricsExporterToDeleteWatchedCHI(cr); err != nil {
+		log.V(1).F().Info("FAIL delete watch (%s/%s): %q", cr.Namespace, cr.Name, err)
+	} else {
+		log.V(1).Info("OK delete watch (%s/%s)", cr.Namespace, cr.Name)
+	}
+}
@@ -17,12 +17,14 @@ package chk
 import (
 	"context"
 	"github.com/altinity/clickhouse-operator/pkg/chop"
+	"sync"
 	"time"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
 	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/controller/chk/kube"
+	"github.com/altinity/clickhouse-operator/pkg/controller/chk/metrics"
 	"github.com/altinity/clickhouse-operator/pkg/interfaces"
 	"github.com/altinity/clickhouse-operator/pkg/model/managers"
 	"github.com/altinity/clickhouse-operator/pkg/util"
@@ -30,6 +32,7 @@ import (
 	apiMachinery "k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
 // Controller reconciles a ClickHouseKeeper object
@@ -41,13 +44,22 @@ type Controller struct {
 	kube  interfaces.IKube
 	//labeler    *Labeler
 	//pvcDeleter *volume.PVCDeleter
+
+	// enqueuedCh passes CHKs enqueued by the monitor to the reconcile
+	enqueuedCh   chan event.GenericEvent
+	enqueuedOnce sync.Once
+
+	// newOnce ensures the controller is set up once, as reconcile and monitor run concurrently
+	newOnce sync.Once
 }
 
 func (c *Controller) new() {
-	c.namer = managers.NewNameManager(managers.NameManagerTypeKeeper)
-	c.kube = kube.NewAdapter(c.Client, c.namer)
-	//labeler:                 NewLabeler(kube),
-	//pvcDeleter :=              volume.NewPVCDeleter(managers.NewNameManager(managers.NameManagerTypeKeeper))
+	c.newOnce.Do(func() {
+		c.namer = managers.NewNameManager(managers.NameManagerTypeKeeper)
+		c.kube = kube.NewAdapter(c.Client, c.namer)
+		//labeler:                 NewLabeler(kube),
+		//pvcDeleter :=              volume.NewPVCDeleter(managers.NewNameManager(managers.NameManagerTypeKeeper))
+	})
 }
 
 func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -64,6 +76,8 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			// Owned objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			metrics.KeeperHealthDelete(req.Namespace, req.Name)
+			c.deleteWatch(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		// Return and requeue
@@ -75,6 +89,7 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	if new.Spec.Suspend.Value() {
 		log.V(2).M(new).F().Info("CR is suspended, skip reconcile")
+		w.markSuspended(context.TODO(), new)
 		return ctrl.Result{}, nil
 	}
 
@@ -116,3 +131,14 @@ func ShouldEnqueue(cr *apiChk.ClickHouseKeeperInstallation) bool {
 
 	return true
 }
+
+// hasHeldBack checks whether restart of any host of the CR is held back, as it is reported in status of the CR
+func (c *Controller) hasHeldBack(cr *apiChk.ClickHouseKeeperInstallation) bool {
+	return cr.EnsureStatus().HasRestartHeldBack()
+}
+
+// isRestartHeldBack checks whether restart of the host is held back, as it is reported in status of the CR
+func (c *Controller) isRestartHeldBack(host *api.Host) bool {
+	cr, ok := host.GetCR().(*apiChk.ClickHouseKeeperInstallation)
+	return ok && cr.EnsureStatus().GetKeeper(host.GetName()).IsRestartHeldBack()
+}
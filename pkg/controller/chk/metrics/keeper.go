@@ -0,0 +1,117 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/metrics/operator"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// keeperValue is health of a single Keeper host along with its labels
+type keeperValue struct {
+	attributes []attribute.KeyValue
+	health     *apiChk.KeeperHealth
+}
+
+// keepers is the latest health of the Keeper hosts indexed by CR
+var keepers = map[string][]keeperValue{}
+var keepersMx = sync.Mutex{}
+var keepersMetricsOnce sync.Once
+
+// KeeperHealth publishes health of the Keeper hosts of the CR
+func KeeperHealth(src labelsSource, health []*apiChk.KeeperHealth) {
+	keepersMetricsOnce.Do(createKeeperMetrics)
+
+	var values []keeperValue
+	for _, h := range health {
+		values = append(values, keeperValue{
+			attributes: append(
+				prepareLabels(src),
+				attribute.String("host", h.Host),
+				attribute.String("role", h.Role),
+			),
+			health: h,
+		})
+	}
+
+	keepersMx.Lock()
+	defer keepersMx.Unlock()
+	keepers[util.NamespaceNameString(src)] = values
+}
+
+// KeeperHealthDelete deletes health of the Keeper hosts of the CR
+func KeeperHealthDelete(namespace, name string) {
+	keepersMx.Lock()
+	defer keepersMx.Unlock()
+	delete(keepers, namespace+"/"+name)
+}
+
+func createKeeperMetrics() {
+	gauge := func(name, description string, value func(*apiChk.KeeperHealth) int64) {
+		_, _ = operator.Meter().Int64ObservableGauge(
+			name,
+			metric.WithDescription(description),
+			metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+				keepersMx.Lock()
+				defer keepersMx.Unlock()
+				for _, values := range keepers {
+					for _, v := range values {
+						o.Observe(value(v.health), metric.WithAttributes(v.attributes...))
+					}
+				}
+				return nil
+			}),
+		)
+	}
+	gauge(
+		"clickhouse_operator_chk_keeper_healthy",
+		"whether Keeper host serves requests and keeps up with the leader",
+		func(h *apiChk.KeeperHealth) int64 { return boolToInt64(h.Healthy) },
+	)
+	gauge(
+		"clickhouse_operator_chk_keeper_leader",
+		"whether Keeper host is the leader of the ensemble",
+		func(h *apiChk.KeeperHealth) int64 { return boolToInt64(h.Role == apiChk.KeeperRoleLeader) },
+	)
+	gauge(
+		"clickhouse_operator_chk_keeper_zxid_lag",
+		"number of transactions Keeper host is behind the leader",
+		func(h *apiChk.KeeperHealth) int64 { return h.ZxidLag },
+	)
+	gauge(
+		"clickhouse_operator_chk_keeper_outstanding_requests",
+		"number of requests queued by Keeper host",
+		func(h *apiChk.KeeperHealth) int64 { return h.OutstandingRequests },
+	)
+	gauge(
+		"clickhouse_operator_chk_keeper_ephemerals",
+		"number of ephemeral nodes on Keeper host",
+		func(h *apiChk.KeeperHealth) int64 { return h.EphemeralsCount },
+	)
+}
+
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
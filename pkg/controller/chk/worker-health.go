@@ -0,0 +1,203 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chk
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/controller/chk/metrics"
+	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/conditions"
+	"github.com/altinity/clickhouse-operator/pkg/model/chk/raft"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
+	"github.com/altinity/clickhouse-operator/pkg/model/zookeeper"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	// healthCheckPeriod specifies how often health of the CR is checked
+	healthCheckPeriod = time.Minute
+	// keeperMaxZxidLag specifies how many transactions a follower may be behind the leader and still be considered healthy
+	keeperMaxZxidLag = 10000
+)
+
+// checkHealth checks readiness of the Keeper hosts and quorum of the ensemble, queries each host with four-letter-word commands
+// and updates health-related conditions, health of the Keeper hosts in status and metrics of the CR.
+// CRs being reconciled are skipped, as conditions are maintained by the reconcile meanwhile.
+func (w *worker) checkHealth(ctx context.Context, cr *apiChk.ClickHouseKeeperInstallation) {
+	if util.IsContextDone(ctx) {
+		log.V(1).Info("Health check is aborted. cr: %s ", cr.GetName())
+		return
+	}
+	if cr.EnsureStatus().GetStatus() == apiChk.StatusInProgress {
+		return
+	}
+
+	health := &conditions.Health{
+		Reconciled: cr.EnsureStatus().GetStatus() == apiChk.StatusCompleted,
+	}
+	var keepers []*apiChk.KeeperHealth
+	if !cr.IsStopped() {
+		templated := w.createTemplated(cr)
+		ready := 0
+		templated.WalkHosts(func(host *api.Host) error {
+			health.Hosts++
+			if pod, err := w.c.kube.Pod().Get(ctx, host); (err == nil) && k8s.IsPodOK(pod) {
+				ready++
+			} else {
+				health.NotReadyHosts = append(health.NotReadyHosts, host.GetName())
+			}
+			return nil
+		})
+		// Keeper ensemble serves requests as long as majority of the members is alive
+		health.QuorumLost = ready < raft.Quorum(health.Hosts)
+		keepers = w.checkKeepers(ctx, templated)
+	}
+
+	// Report health-related conditions only, so conditions set by the reconcile meanwhile are not overwritten
+	cr.EnsureStatus().Conditions = nil
+	conditions.Observe(cr, health)
+	// Held back restarts are reported by the reconcile, which retries them
+	for _, keeper := range keepers {
+		keeper.RestartHeldBack = cr.EnsureStatus().GetKeeper(keeper.Host).IsRestartHeldBack()
+	}
+	cr.EnsureStatus().SetKeepers(keepers)
+	metrics.KeeperHealth(cr, keepers)
+	_ = w.c.updateCRObjectStatus(ctx, cr, types.UpdateStatusOptions{
+		CopyStatusOptions: types.CopyStatusOptions{
+			CopyStatusField: types.CopyStatusField{
+				Copy: types.Status{
+					Conditions: true,
+					Keepers:    true,
+				},
+			},
+		},
+	})
+}
+
+// checkKeepers queries each Keeper host of the CR with ruok, mntr, srvr and lgif commands and derives health of the hosts.
+// Follower is considered healthy as long as it is not too far behind the leader.
+func (w *worker) checkKeepers(ctx context.Context, cr api.ICustomResource) (keepers []*apiChk.KeeperHealth) {
+	var leader *zookeeper.ServerState
+	cr.WalkHosts(func(host *api.Host) error {
+		keeper := &apiChk.KeeperHealth{
+			Host: host.GetName(),
+		}
+		state, err := zookeeper.GetServerState(ctx, w.keeperAddress(host))
+		if state != nil {
+			keeper.Role = state.Mode
+			keeper.Zxid = state.Zxid
+			keeper.OutstandingRequests = state.OutstandingRequests
+			keeper.EphemeralsCount = state.EphemeralsCount
+			if state.IsLeader() {
+				leader = state
+			}
+		}
+		switch {
+		case err != nil:
+			keeper.Error = err.Error()
+		case !state.OK:
+			keeper.Error = "host is running in an error state"
+		default:
+			keeper.Healthy = true
+		}
+		keepers = append(keepers, keeper)
+		return nil
+	})
+
+	if leader == nil {
+		// Lag is not known without the leader
+		return keepers
+	}
+	for _, keeper := range keepers {
+		if !keeper.IsFollower() {
+			continue
+		}
+		keeper.ZxidLag = max(leader.Zxid-keeper.Zxid, 0)
+		if keeper.Healthy && (keeper.ZxidLag > keeperMaxZxidLag) {
+			keeper.Healthy = false
+			keeper.Error = fmt.Sprintf("zxid lag %d exceeds %d", keeper.ZxidLag, keeperMaxZxidLag)
+		}
+	}
+	return keepers
+}
+
+// isUnhealthyKeeperFollower checks whether the host is a follower, which does not keep up with the leader.
+// Keeper hosts are queried once per reconcile, as soon as restart of any host is considered
+func (w *worker) isUnhealthyKeeperFollower(ctx context.Context, host *api.Host) bool {
+	if w.keepers == nil {
+		w.keepers = make(map[string]*apiChk.KeeperHealth)
+		for _, keeper := range w.checkKeepers(ctx, host.GetCR()) {
+			w.keepers[keeper.Host] = keeper
+		}
+	}
+	return w.keepers[host.GetName()].IsUnhealthyFollower()
+}
+
+// reportHeldBack reports hosts, which restart is held back by the reconcile, in status of the CR,
+// so the restart is retried by the next reconcile. Status is written along with the completed reconcile
+func (w *worker) reportHeldBack(cr *apiChk.ClickHouseKeeperInstallation) {
+	// Health of the hosts queried by the reconcile is preferred over the one reported by the latest health check
+	keepers := cr.EnsureStatus().GetKeepers()
+	if w.keepers != nil {
+		keepers = nil
+		cr.WalkHosts(func(host *api.Host) error {
+			if keeper := w.keepers[host.GetName()]; keeper != nil {
+				keepers = append(keepers, keeper.DeepCopy())
+			}
+			return nil
+		})
+	}
+	reported := make(map[string]bool)
+	for _, keeper := range keepers {
+		keeper.RestartHeldBack = slices.Contains(w.heldBack, keeper.Host)
+		reported[keeper.Host] = true
+	}
+	for _, host := range w.heldBack {
+		if !reported[host] {
+			keepers = append(keepers, &apiChk.KeeperHealth{Host: host, RestartHeldBack: true})
+		}
+	}
+	cr.EnsureStatus().SetKeepers(keepers)
+
+	if len(w.heldBack) > 0 {
+		w.a.V(1).
+			WithEvent(cr, a.EventActionReconcile, a.EventReasonReconcileInProgress).
+			WithAction(cr).
+			M(cr).F().
+			Warning("Restart of unhealthy followers is held back till the next reconcile: %v", w.heldBack)
+	}
+}
+
+// markSuspended reports the CR is suspended in status conditions
+func (w *worker) markSuspended(ctx context.Context, cr *apiChk.ClickHouseKeeperInstallation) {
+	conditions.Suspended(cr, false)
+	_ = w.c.updateCRObjectStatus(ctx, cr, types.UpdateStatusOptions{
+		CopyStatusOptions: types.CopyStatusOptions{
+			CopyStatusField: types.CopyStatusField{
+				Copy: types.Status{
+					Conditions: true,
+				},
+			},
+		},
+	})
+}
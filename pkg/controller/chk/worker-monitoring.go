@@ -16,12 +16,57 @@ package chk
 
 import (
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
 )
 
-// prepareMonitoring prepares monitoring state before reconcile begins
+// prepareMonitoring prepares monitoring state before reconcile begins.
+// For stopped CR - excludes from monitoring.
+// For running CR with ancestor - preserves old topology in monitoring.
+// For new running CR - allocates an empty slot in monitoring index.
 func (w *worker) prepareMonitoring(cr *api.ClickHouseKeeperInstallation) {
+	if cr.IsStopped() {
+		// CR is stopped
+		// Exclude it from monitoring cause it makes no sense to query stopped instances
+
+		w.a.V(1).
+			WithEvent(cr, a.EventActionReconcile, a.EventReasonReconcileInProgress).
+			WithAction(cr).
+			M(cr).F().
+			Info("exclude CHK from monitoring")
+		w.c.deleteWatch(cr.GetNamespace(), cr.GetName())
+	} else {
+		// CR is NOT stopped, it is running
+		// Ensure CR is registered in monitoring
+		w.a.V(1).
+			WithEvent(cr, a.EventActionReconcile, a.EventReasonReconcileInProgress).
+			WithAction(cr).
+			M(cr).F().
+			Info("ensure CHK in monitoring")
+
+		if cr.HasAncestor() {
+			// Ensure CR is watched
+			w.c.updateWatch(cr.GetAncestorT())
+		} else {
+			// CR is a new one - allocate monitoring
+			w.c.allocateWatch(cr)
+		}
+	}
 }
 
 // addToMonitoring adds CR to monitoring
 func (w *worker) addToMonitoring(cr *api.ClickHouseKeeperInstallation) {
+	// Include into monitoring RUN-ning CR
+	// Stopped CR is not touched
+
+	if cr.IsStopped() {
+		// No need to add stopped CR
+		return
+	}
+
+	w.a.V(1).
+		WithEvent(cr, a.EventActionReconcile, a.EventReasonReconcileInProgress).
+		WithAction(cr).
+		M(cr).F().
+		Info("add CHK to monitoring")
+	w.c.updateWatch(cr)
 }
@@ -0,0 +1,249 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chk
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/poller"
+	"github.com/altinity/clickhouse-operator/pkg/interfaces"
+	"github.com/altinity/clickhouse-operator/pkg/model/chk/config"
+	"github.com/altinity/clickhouse-operator/pkg/model/chk/raft"
+	"github.com/altinity/clickhouse-operator/pkg/model/zookeeper"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// raftCatchUpTimeout specifies how long joining member is waited to catch up with the leader
+const raftCatchUpTimeout = 10 * time.Minute
+
+// raftEnsemble is a running Keeper ensemble, which membership is being reconciled
+type raftEnsemble struct {
+	conn *zookeeper.Connection
+	// clients maps raft server id to the address of the client port of the server
+	clients map[int]string
+	members raft.Members
+}
+
+// reconcileRaftMembership brings raft configuration of the running ensemble in line with the hosts of the CR.
+// Members are added and removed one at a time via reconfig. Each step is taken only when the ensemble keeps its quorum,
+// and each joining member is waited to catch up with the leader before the next step is taken.
+func (w *worker) reconcileRaftMembership(ctx context.Context, cr *apiChk.ClickHouseKeeperInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.V(1).Info("Reconcile is aborted. CR raft membership: %s ", cr.GetName())
+		return nil
+	}
+	if cr.IsStopped() {
+		return nil
+	}
+	if opts, ok := ctx.Value(common.ReconcileShardsAndHostsOptionsCtxKey).(*common.ReconcileShardsAndHostsOptions); ok && opts.FullFanOut {
+		// Brand-new ensemble is bootstrapped with complete raft configuration
+		return nil
+	}
+
+	w.a.V(2).M(cr).S().P()
+	defer w.a.V(2).M(cr).E().P()
+
+	ensemble := w.newRaftEnsemble(cr)
+	defer ensemble.conn.Close()
+
+	if err := ensemble.fetchMembers(ctx); err != nil {
+		// Keeper does not expose its configuration, thus it is not able to reconfig.
+		// Membership is maintained by raft configuration in the config files then.
+		w.a.V(1).M(cr).F().Warning("Unable to fetch raft configuration, membership is managed by config files. err: %v", err)
+		return nil
+	}
+
+	joining, leaving := raft.Diff(ensemble.members, w.desiredRaftMembers(cr))
+	if len(joining) == 0 && len(leaving) == 0 {
+		w.a.V(2).M(cr).F().Info("Raft configuration is up to date")
+		return nil
+	}
+	w.a.V(1).M(cr).F().Info("Raft configuration change. Joining: %v leaving: %v", joining, leaving)
+
+	// Add members first, so ensemble gets bigger before it gets smaller and quorum is easier to keep
+	for _, member := range joining {
+		if err := w.addRaftMember(ctx, ensemble, member); err != nil {
+			return err
+		}
+	}
+	for _, member := range ensemble.sortLeaderLast(ctx, leaving) {
+		if err := w.removeRaftMember(ctx, ensemble, member); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addRaftMember adds member to the ensemble and waits for it to catch up with the leader
+func (w *worker) addRaftMember(ctx context.Context, ensemble *raftEnsemble, member *raft.Member) error {
+	participants := ensemble.members.Participants()
+	synced, err := ensemble.syncedParticipants(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to add raft member %s: %w", member, err)
+	}
+	if !raft.CanAdd(participants, synced) {
+		return fmt.Errorf("unable to add raft member %s: only %d of %d participants are in sync", member, synced, participants)
+	}
+
+	w.a.V(1).Info("Add raft member: %s", member)
+	if _, err := ensemble.conn.Reconfig(ctx, []string{member.String()}, nil); err != nil {
+		return fmt.Errorf("unable to add raft member %s: %w", member, err)
+	}
+	if err := ensemble.fetchMembers(ctx); err != nil {
+		return err
+	}
+
+	// Wait for all participants, including the joined one, to be in sync with the leader
+	participants = ensemble.members.Participants()
+	return poller.New(ctx, fmt.Sprintf("raft member %s to catch up", member)).
+		WithOptions(poller.NewOptionsFromConfig(&poller.Options{Timeout: raftCatchUpTimeout})).
+		WithFunctions(&poller.Functions{
+			IsDone: func(_ctx context.Context, _ any) bool {
+				synced, err := ensemble.syncedParticipants(_ctx)
+				return (err == nil) && (synced >= participants)
+			},
+		}).Poll()
+}
+
+// removeRaftMember removes member from the ensemble
+func (w *worker) removeRaftMember(ctx context.Context, ensemble *raftEnsemble, member *raft.Member) error {
+	participants := ensemble.members.Participants()
+	synced, err := ensemble.syncedParticipants(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to remove raft member %s: %w", member, err)
+	}
+	// Leaving member is considered to be in sync, so the remaining ones have to form quorum by themselves
+	if !member.Learner && !raft.CanRemove(participants, synced, true) {
+		return fmt.Errorf("unable to remove raft member %s: only %d of %d participants are in sync", member, synced, participants)
+	}
+
+	w.a.V(1).Info("Remove raft member: %s", member)
+	if _, err := ensemble.conn.Reconfig(ctx, nil, []string{strconv.Itoa(member.ID)}); err != nil {
+		return fmt.Errorf("unable to remove raft member %s: %w", member, err)
+	}
+	return ensemble.fetchMembers(ctx)
+}
+
+// desiredRaftMembers builds raft configuration as specified by the CR
+func (w *worker) desiredRaftMembers(cr *apiChk.ClickHouseKeeperInstallation) (members raft.Members) {
+	cr.WalkHosts(func(host *api.Host) error {
+		members = append(members, raft.NewMember(
+			config.GetServerId(host),
+			w.c.namer.Name(interfaces.NameInstanceHostname, host),
+			int(host.RaftPort.Value()),
+		))
+		return nil
+	})
+	return members
+}
+
+// newRaftEnsemble creates connection to the ensemble of the CR.
+// Hosts being removed from the CR are still running, so they are addressable as well.
+func (w *worker) newRaftEnsemble(cr *apiChk.ClickHouseKeeperInstallation) *raftEnsemble {
+	ensemble := &raftEnsemble{
+		clients: make(map[int]string),
+	}
+	var nodes api.ZookeeperNodes
+	collect := func(host *api.Host) error {
+		fqdn := w.c.namer.Name(interfaces.NameFQDN, host)
+		ensemble.clients[config.GetServerId(host)] = net.JoinHostPort(fqdn, strconv.Itoa(int(host.ZKPort.Value())))
+		return nil
+	}
+	if ancestor := cr.GetAncestorT(); ancestor != nil {
+		ancestor.WalkHosts(collect)
+	}
+	cr.WalkHosts(func(host *api.Host) error {
+		nodes = append(nodes, api.ZookeeperNode{
+			Host: w.c.namer.Name(interfaces.NameFQDN, host),
+			Port: types.NewInt32(host.ZKPort.Value()),
+		})
+		return collect(host)
+	})
+	ensemble.conn = zookeeper.NewConnection(nodes, &zookeeper.ConnectionParams{MaxRetriesNum: 3})
+	return ensemble
+}
+
+// fetchMembers fetches current raft configuration of the ensemble
+func (e *raftEnsemble) fetchMembers(ctx context.Context) error {
+	data, _, err := e.conn.Get(ctx, raft.ConfigPath)
+	if err != nil {
+		return err
+	}
+	members, err := raft.ParseMembers(string(data))
+	if err != nil {
+		return err
+	}
+	if len(members) == 0 {
+		return fmt.Errorf("empty raft configuration")
+	}
+	e.members = members
+	return nil
+}
+
+// leader finds id of the leader of the ensemble and its monitoring variables
+func (e *raftEnsemble) leader(ctx context.Context) (int, map[string]string, error) {
+	for _, member := range e.members {
+		address, ok := e.clients[member.ID]
+		if !ok {
+			continue
+		}
+		mntr, err := zookeeper.Mntr(ctx, address)
+		if err != nil {
+			continue
+		}
+		switch mntr["zk_server_state"] {
+		case "leader", "standalone":
+			return member.ID, mntr, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("unable to find raft leader")
+}
+
+// syncedParticipants returns number of participants, which are in sync with the leader, including the leader itself
+func (e *raftEnsemble) syncedParticipants(ctx context.Context) (int, error) {
+	_, mntr, err := e.leader(ctx)
+	if err != nil {
+		return 0, err
+	}
+	followers, _ := strconv.Atoi(mntr["zk_synced_followers"])
+	return followers + 1, nil
+}
+
+// sortLeaderLast moves current leader to the end of the members list, so leadership changes at most once
+func (e *raftEnsemble) sortLeaderLast(ctx context.Context, members raft.Members) raft.Members {
+	leader, _, err := e.leader(ctx)
+	if err != nil {
+		return members
+	}
+	var res, last raft.Members
+	for _, member := range members {
+		if member.ID == leader {
+			last = append(last, member)
+		} else {
+			res = append(res, member)
+		}
+	}
+	return append(res, last...)
+}
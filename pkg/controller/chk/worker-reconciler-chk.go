@@ -29,6 +29,7 @@ import (
 	"github.com/altinity/clickhouse-operator/pkg/controller/chk/metrics"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common"
 	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/conditions"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common/statefulset"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common/storage"
 	"github.com/altinity/clickhouse-operator/pkg/interfaces"
@@ -49,6 +50,8 @@ func (w *worker) reconcileCR(ctx context.Context, old, new *apiChk.ClickHouseKee
 	switch {
 	case w.isAfterFinalizerInstalled(old, new):
 		w.a.M(new).F().Info("isAfterFinalizerInstalled - continue reconcile-1")
+	case w.isCertificatesRenewalRequired(ctx, new):
+		w.a.M(new).F().Info("isCertificatesRenewalRequired - continue reconcile-1")
 	case w.isGenerationTheSame(old, new):
 		log.V(2).M(new).F().Info("isGenerationTheSame() - nothing to do here, exit")
 		return nil
@@ -70,6 +73,7 @@ func (w *worker) reconcileCR(ctx context.Context, old, new *apiChk.ClickHouseKee
 		if new.EnsureStatus().GetStatus() == api.StatusInProgress || new.EnsureRuntime().ActionPlan.HasActionsToDo() {
 			// Either was mid-reconcile when suspended, or has pending changes suppressed by suspend — mark as Aborted
 			new.EnsureStatus().ReconcileAbort()
+			conditions.Suspended(new, true)
 			_ = w.c.updateCRObjectStatus(ctx, new, types.UpdateStatusOptions{
 				CopyStatusOptions: types.CopyStatusOptions{
 					CopyStatusFieldGroup: types.CopyStatusFieldGroup{
@@ -91,6 +95,10 @@ func (w *worker) reconcileCR(ctx context.Context, old, new *apiChk.ClickHouseKee
 		w.a.M(new).F().Info("ActionPlan has actions - continue reconcile")
 	case w.isAfterFinalizerInstalled(new.GetAncestorT(), new):
 		w.a.M(new).F().Info("isAfterFinalizerInstalled - continue reconcile-2")
+	case w.isCertificatesRenewalRequired(ctx, new):
+		w.a.M(new).F().Info("isCertificatesRenewalRequired - continue reconcile-2")
+	case w.c.hasHeldBack(new):
+		w.a.M(new).F().Info("Restart of hosts is held back - continue reconcile")
 	default:
 		w.a.M(new).F().Info("ActionPlan has no actions - abort reconcile")
 		metrics.CRReconcilesCompleted(ctx, new)
@@ -123,6 +131,7 @@ func (w *worker) reconcileCR(ctx context.Context, old, new *apiChk.ClickHouseKee
 		w.clean(ctx, new)
 		w.addToMonitoring(new)
 		w.waitForIPAddresses(ctx, new)
+		w.reportHeldBack(new)
 		w.finalizeReconcileAndMarkCompleted(ctx, new)
 
 		metrics.CRReconcilesCompleted(ctx, new)
@@ -222,12 +231,18 @@ func (w *worker) reconcile(ctx context.Context, cr *apiChk.ClickHouseKeeperInsta
 		w.a.V(1).M(cr).Info("Unable to use full fan-out mode. Counters: %s. CR: %s", counters, util.NamespaceNameString(cr))
 	}
 
-	return cr.WalkTillError(
+	if err := cr.WalkTillError(
 		ctx,
 		w.reconcileCRAuxObjectsPreliminary,
 		w.reconcileCluster,
 		w.reconcileCRAuxObjectsFinal,
-	)
+	); err != nil {
+		return err
+	}
+
+	// Hosts are up and running, so raft configuration of the ensemble can follow.
+	// Removed hosts are deleted later on by clean, thus they leave the ensemble before they are gone.
+	return w.reconcileRaftMembership(ctx, cr)
 }
 
 // reconcileCRAuxObjectsPreliminary reconciles CR preliminary in order to ensure that ConfigMaps are in place
@@ -256,6 +271,11 @@ func (w *worker) reconcileCRAuxObjectsPreliminary(ctx context.Context, cr *apiCh
 }
 
 func (w *worker) reconcileCRAuxObjectsPreliminaryDomain(ctx context.Context, cr *apiChk.ClickHouseKeeperInstallation) error {
+	// Certificates have to be in place before any host is reconciled, as hosts mount them
+	if err := w.reconcileCRCertificates(ctx, cr); err != nil {
+		return err
+	}
+
 	switch {
 	case cr.HostsCount() < cr.GetAncestor().HostsCount():
 		// Downscale
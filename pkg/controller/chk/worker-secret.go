@@ -0,0 +1,41 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chk
+
+import (
+	"context"
+
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/certificate"
+)
+
+// reconcileCRCertificates provisions TLS certificates of the CR, in case it is requested
+func (w *worker) reconcileCRCertificates(ctx context.Context, cr *apiChk.ClickHouseKeeperInstallation) error {
+	if _, err := certificate.NewReconciler(w.task, w.c.namer, w.c.kube.Secret()).Reconcile(ctx, cr); err != nil {
+		w.a.WithEvent(cr, a.EventActionReconcile, a.EventReasonReconcileFailed).
+			WithAction(cr).
+			WithError(cr).
+			M(cr).F().
+			Error("FAILED to reconcile TLS certificates. CHK: %s err: %v", cr.GetName(), err)
+		return err
+	}
+	return nil
+}
+
+// isCertificatesRenewalRequired checks whether TLS certificates of the CR are due for renewal
+func (w *worker) isCertificatesRenewalRequired(ctx context.Context, cr *apiChk.ClickHouseKeeperInstallation) bool {
+	return certificate.IsRenewalRequired(ctx, cr, w.c.namer, w.c.kube.Secret())
+}
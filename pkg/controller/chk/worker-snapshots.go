@@ -0,0 +1,271 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chk
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	batch "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiMachineryTypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/interfaces"
+	"github.com/altinity/clickhouse-operator/pkg/model/chk/snapshot"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
+	"github.com/altinity/clickhouse-operator/pkg/model/managers"
+	"github.com/altinity/clickhouse-operator/pkg/model/zookeeper"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+const (
+	// annotationSnapshotPath specifies annotation of the snapshot job, which keeps path of the snapshot in the storage
+	annotationSnapshotPath = "clickhouse-keeper.altinity.com/snapshot"
+	// snapshotJobBackoffLimit specifies how many times snapshot job is retried before it is considered failed
+	snapshotJobBackoffLimit = 2
+)
+
+// reconcileSnapshots takes scheduled snapshots of each cluster of the CR and keeps track of the running snapshot jobs.
+// Snapshot is taken on the leader of the cluster and copied from the coordination directory into the storage by a job,
+// which runs on the node of the leader and mounts its data volume.
+func (w *worker) reconcileSnapshots(ctx context.Context, cr *apiChk.ClickHouseKeeperInstallation) {
+	if util.IsContextDone(ctx) {
+		log.V(1).Info("Snapshots reconcile is aborted. cr: %s ", cr.GetName())
+		return
+	}
+	snapshots := cr.GetSpecT().GetSnapshots()
+	if !snapshots.HasSchedule() || cr.IsStopped() {
+		return
+	}
+	if cr.EnsureStatus().GetStatus() == apiChk.StatusInProgress {
+		// Hosts may be moving around, wait for the reconcile to complete
+		return
+	}
+
+	changed := false
+	w.createTemplated(cr).WalkClusters(func(cluster api.ICluster) error {
+		if w.reconcileClusterSnapshot(ctx, cr, cluster, snapshots) {
+			changed = true
+		}
+		return nil
+	})
+	if !changed {
+		return
+	}
+
+	_ = w.c.updateCRObjectStatus(ctx, cr, types.UpdateStatusOptions{
+		CopyStatusOptions: types.CopyStatusOptions{
+			CopyStatusField: types.CopyStatusField{
+				Copy: types.Status{
+					Snapshots: true,
+				},
+			},
+		},
+	})
+}
+
+// reconcileClusterSnapshot completes snapshot job of the cluster or starts a new one in case snapshot is due.
+// Returns whether snapshot of the cluster in status has changed
+func (w *worker) reconcileClusterSnapshot(
+	ctx context.Context,
+	cr *apiChk.ClickHouseKeeperInstallation,
+	cluster api.ICluster,
+	snapshots *apiChk.Snapshots,
+) bool {
+	name := snapshotJobName(cr, cluster)
+	job := &batch.Job{}
+	err := w.c.Client.Get(ctx, apiMachineryTypes.NamespacedName{Namespace: cr.GetNamespace(), Name: name}, job)
+	switch {
+	case err == nil:
+		return w.completeSnapshotJob(ctx, cr, cluster, job)
+	case !apiErrors.IsNotFound(err):
+		log.V(1).M(cr).F().Error("unable to get snapshot job %s err: %v", name, err)
+		return false
+	}
+
+	last := cr.EnsureStatus().GetSnapshot(cluster.GetName()).LastAttempt()
+	if last.IsZero() {
+		last = cr.GetCreationTimestamp().Time
+	}
+	if !snapshots.IsDue(last, time.Now()) {
+		return false
+	}
+
+	if err := w.startSnapshot(ctx, cr, cluster, snapshots, name); err != nil {
+		log.V(1).M(cr).F().Error("unable to take snapshot of cluster %s err: %v", cluster.GetName(), err)
+		w.setSnapshotError(cr, cluster, err.Error())
+		return true
+	}
+	log.V(1).M(cr).F().Info("snapshot of cluster %s is started", cluster.GetName())
+	return false
+}
+
+// startSnapshot creates snapshot on the leader of the cluster and starts job copying the snapshot into the storage
+func (w *worker) startSnapshot(
+	ctx context.Context,
+	cr *apiChk.ClickHouseKeeperInstallation,
+	cluster api.ICluster,
+	snapshots *apiChk.Snapshots,
+	name string,
+) error {
+	leader := w.findLeader(ctx, cluster)
+	if leader == nil {
+		return fmt.Errorf("no leader found")
+	}
+
+	dataVolume := leader.GetTemplates().GetDataVolumeClaimTemplate()
+	pod, err := w.c.kube.Pod().Get(ctx, leader)
+	if err != nil {
+		return err
+	}
+	var claim string
+	for _, volume := range pod.Spec.Volumes {
+		if (volume.Name == dataVolume) && (volume.PersistentVolumeClaim != nil) {
+			claim = volume.PersistentVolumeClaim.ClaimName
+		}
+	}
+	if claim == "" {
+		return fmt.Errorf("data of the host %s is not persisted", leader.GetName())
+	}
+
+	idx, err := zookeeper.Csnp(ctx, w.keeperAddress(leader))
+	if err != nil {
+		return err
+	}
+
+	snapshotPath := snapshot.Path(cr.GetNamespace(), cr.GetName(), cluster.GetName(), time.Now())
+	volume := k8s.CreateVolumeForPVC(dataVolume, claim)
+	volume.PersistentVolumeClaim.ReadOnly = true
+	backoffLimit := int32(snapshotJobBackoffLimit)
+	job := &batch.Job{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      name,
+			Namespace: cr.GetNamespace(),
+			Annotations: map[string]string{
+				annotationSnapshotPath: snapshotPath,
+			},
+			OwnerReferences: managers.NewOwnerReferencesManager(managers.OwnerReferencesManagerTypeKeeper).CreateOwnerReferences(cr),
+		},
+		Spec: batch.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: core.PodTemplateSpec{
+				Spec: core.PodSpec{
+					// Data volume may be mounted on the node of the leader only
+					NodeName:         pod.Spec.NodeName,
+					RestartPolicy:    core.RestartPolicyNever,
+					ImagePullSecrets: pod.Spec.ImagePullSecrets,
+					Containers: []core.Container{
+						snapshot.NewUploadContainer(snapshots, dataVolume, snapshotPath, snapshot.Pattern(strconv.FormatInt(idx, 10))),
+					},
+					Volumes: append([]core.Volume{volume}, snapshot.Volumes(snapshots)...),
+				},
+			},
+		},
+	}
+	return w.c.Client.Create(ctx, job)
+}
+
+// completeSnapshotJob reports result of the finished snapshot job in status and deletes the job.
+// Returns whether snapshot of the cluster in status has changed
+func (w *worker) completeSnapshotJob(
+	ctx context.Context,
+	cr *apiChk.ClickHouseKeeperInstallation,
+	cluster api.ICluster,
+	job *batch.Job,
+) bool {
+	var finished *batch.JobCondition
+	for i := range job.Status.Conditions {
+		condition := &job.Status.Conditions[i]
+		if ((condition.Type == batch.JobComplete) || (condition.Type == batch.JobFailed)) && (condition.Status == core.ConditionTrue) {
+			finished = condition
+		}
+	}
+	if finished == nil {
+		// Job is still running
+		return false
+	}
+
+	if err := w.c.Client.Delete(ctx, job, client.PropagationPolicy(meta.DeletePropagationBackground)); err != nil {
+		log.V(1).M(cr).F().Error("unable to delete snapshot job %s err: %v", job.GetName(), err)
+	}
+
+	if finished.Type == batch.JobFailed {
+		log.V(1).M(cr).F().Error("snapshot of cluster %s failed: %s", cluster.GetName(), finished.Message)
+		w.setSnapshotError(cr, cluster, fmt.Sprintf("snapshot job failed: %s %s", finished.Reason, finished.Message))
+		return true
+	}
+
+	completed := finished.LastTransitionTime.Time
+	if job.Status.CompletionTime != nil {
+		completed = job.Status.CompletionTime.Time
+	}
+	log.V(1).M(cr).F().Info("snapshot of cluster %s is completed", cluster.GetName())
+	cr.EnsureStatus().SetSnapshot(&apiChk.KeeperSnapshot{
+		Cluster:  cluster.GetName(),
+		Snapshot: job.GetAnnotations()[annotationSnapshotPath],
+		Time:     completed.UTC().Format(time.RFC3339),
+	})
+	return true
+}
+
+// setSnapshotError reports failed snapshot attempt of the cluster in status, keeping the last successful snapshot
+func (w *worker) setSnapshotError(cr *apiChk.ClickHouseKeeperInstallation, cluster api.ICluster, err string) {
+	last := cr.EnsureStatus().GetSnapshot(cluster.GetName())
+	if last == nil {
+		last = &apiChk.KeeperSnapshot{
+			Cluster: cluster.GetName(),
+		}
+	}
+	last.Error = err
+	last.ErrorTime = time.Now().UTC().Format(time.RFC3339)
+	cr.EnsureStatus().SetSnapshot(last)
+}
+
+// findLeader finds host of the cluster, which is the leader of the ensemble
+func (w *worker) findLeader(ctx context.Context, cluster api.ICluster) *api.Host {
+	var leader *api.Host
+	cluster.WalkHosts(func(host *api.Host) error {
+		if state, err := zookeeper.GetServerState(ctx, w.keeperAddress(host)); (err == nil) && state.IsLeader() {
+			leader = host
+		}
+		return nil
+	})
+	return leader
+}
+
+// keeperAddress builds address the host serves client connections at
+func (w *worker) keeperAddress(host *api.Host) string {
+	return net.JoinHostPort(w.c.namer.Name(interfaces.NameFQDN, host), strconv.Itoa(int(host.ZKPort.Value())))
+}
+
+// snapshotJobName builds name of the snapshot job of the cluster.
+// Job name is used as a label value of the job pods, so it has to fit into 63 chars
+func snapshotJobName(cr api.ICustomResource, cluster api.ICluster) string {
+	name := fmt.Sprintf("%s-%s-snapshot", cr.GetName(), cluster.GetName())
+	if len(name) <= 63 {
+		return name
+	}
+	return util.StringHead(name, 54) + "-" + util.CreateStringID(name, 8)
+}
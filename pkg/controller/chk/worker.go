@@ -27,6 +27,7 @@ import (
 	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common"
 	a "github.com/altinity/clickhouse-operator/pkg/controller/common/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common/conditions"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common/poller/domain"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common/statefulset"
 	"github.com/altinity/clickhouse-operator/pkg/controller/common/storage"
@@ -53,6 +54,11 @@ type worker struct {
 	task          *common.Task
 	stsReconciler *statefulset.Reconciler
 
+	// keepers keeps health of the Keeper hosts by host name. Health is checked once per reconcile
+	keepers map[string]*apiChk.KeeperHealth
+	// heldBack lists hosts, which restart is held back by the reconcile
+	heldBack []string
+
 	start time.Time
 }
 
@@ -121,8 +127,22 @@ func (w *worker) newTask(new, old *apiChk.ClickHouseKeeperInstallation) {
 	)
 }
 
-// shouldForceRestartHost checks whether cluster requires hosts restart
+// shouldForceRestartHost checks whether cluster requires hosts restart.
+// Restart of a follower, which does not keep up with the leader, is held back, so quorum is not put at risk
 func (w *worker) shouldForceRestartHost(ctx context.Context, host *api.Host) bool {
+	if !w.isForceRestartRequired(ctx, host) {
+		return false
+	}
+	if w.isUnhealthyKeeperFollower(ctx, host) {
+		w.a.V(1).M(host).F().Warning("Host is an unhealthy follower, held out of restart. Host: %s", host.GetName())
+		w.heldBack = append(w.heldBack, host.GetName())
+		return false
+	}
+	return true
+}
+
+// isForceRestartRequired checks whether host has to be restarted
+func (w *worker) isForceRestartRequired(ctx context.Context, host *api.Host) bool {
 	switch {
 	case host.HasAncestor() && host.GetAncestor().IsStopped():
 		w.a.V(1).M(host).F().Info("Host ancestor is stopped, no restart applicable. Host: %s", host.GetName())
@@ -148,6 +168,10 @@ func (w *worker) shouldForceRestartHost(ctx context.Context, host *api.Host) boo
 		w.a.V(1).M(host).F().Info("Host has no ancestor, no restart applicable. Host: %s", host.GetName())
 		return false
 
+	case w.c.isRestartHeldBack(host):
+		w.a.V(1).M(host).F().Info("Host restart was held back by the previous reconcile. Host: %s", host.GetName())
+		return true
+
 	case host.GetCR().IsRollingUpdate():
 		w.a.V(1).M(host).F().Info("RollingUpdate requires force restart. Host: %s", host.GetName())
 		return true
@@ -195,6 +219,7 @@ func (w *worker) markReconcileStart(ctx context.Context, cr *apiChk.ClickHouseKe
 
 	// Write desired normalized CHI with initialized .Status, so it would be possible to monitor progress
 	cr.EnsureStatus().ReconcileStart(cr.EnsureRuntime().ActionPlan)
+	conditions.ReconcileStarted(cr)
 	_ = w.c.updateCRObjectStatus(ctx, cr, types.UpdateStatusOptions{
 		CopyStatusOptions: types.CopyStatusOptions{
 			CopyStatusFieldGroup: types.CopyStatusFieldGroup{
@@ -235,6 +260,7 @@ func (w *worker) finalizeReconcileAndMarkCompleted(ctx context.Context, _cr *api
 			c.SetAncestor(c.GetTarget())
 			c.SetTarget(nil)
 			c.EnsureStatus().ReconcileComplete()
+			conditions.ReconcileCompleted(c)
 		},
 	)
 
@@ -258,6 +284,7 @@ func (w *worker) markReconcileCompletedUnsuccessfully(ctx context.Context, cr *a
 	case errors.Is(err, common.ErrCRUDAbort):
 		cr.EnsureStatus().ReconcileAbort()
 	}
+	conditions.ReconcileFailed(cr, err)
 	w.c.updateCRObjectStatus(ctx, cr, types.UpdateStatusOptions{
 		CopyStatusOptions: types.CopyStatusOptions{
 			CopyStatusFieldGroup: types.CopyStatusFieldGroup{
@@ -0,0 +1,262 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/controller/common"
+	"github.com/altinity/clickhouse-operator/pkg/interfaces"
+	cert "github.com/altinity/clickhouse-operator/pkg/model/common/certificate"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// Reconciler provisions CA and per-host certificates of a CR into Secrets
+type Reconciler struct {
+	task   *common.Task
+	namer  interfaces.INameManager
+	secret interfaces.IKubeSecret
+	// hostContext specifies context Secrets of a host are accessed in, so hosts may live in different kube contexts
+	hostContext func(ctx context.Context, host *api.Host) context.Context
+}
+
+// NewReconciler creates new certificates reconciler
+func NewReconciler(task *common.Task, namer interfaces.INameManager, secret interfaces.IKubeSecret) *Reconciler {
+	return &Reconciler{
+		task:   task,
+		namer:  namer,
+		secret: secret,
+	}
+}
+
+// SetHostContext sets function, which specifies context Secrets of a host are accessed in
+func (r *Reconciler) SetHostContext(f func(ctx context.Context, host *api.Host) context.Context) *Reconciler {
+	r.hostContext = f
+	return r
+}
+
+// IsRenewalRequired checks whether certificates of the CR have to be provisioned or renewed.
+// It is cheap enough to be called on every resync, since only CA Secret is fetched.
+func IsRenewalRequired(ctx context.Context, cr api.ICustomResource, namer interfaces.INameManager, secret interfaces.IKubeSecret) bool {
+	if !cr.GetSpec().GetConfiguration().GetTLS().IsEnabled() {
+		return false
+	}
+	ca, err := secret.Get(ctx, cr.GetNamespace(), namer.Name(interfaces.NameTLSSecretCA, cr))
+	if err != nil {
+		// Not provisioned yet or unable to check - let reconcile sort it out
+		return true
+	}
+	renewAt, err := time.Parse(time.RFC3339, string(ca.Data[cert.SecretKeyRenewAt]))
+	if err != nil {
+		return true
+	}
+	return time.Now().After(renewAt)
+}
+
+// Reconcile provisions CA and per-host certificates, renewing the ones which are about to expire.
+// Host runtime TLS version is set to the fingerprint of the host certificate, so hosts are rolled
+// as soon as the certificate is re-issued.
+// Returns PEM-encoded bundle of the CAs hosts' certificates may be signed by.
+func (r *Reconciler) Reconcile(ctx context.Context, cr api.ICustomResource) ([]byte, error) {
+	tls := cr.GetSpec().GetConfiguration().GetTLS()
+	if !tls.IsEnabled() {
+		return nil, nil
+	}
+
+	now := time.Now()
+	caSecret := r.task.Creator().CreateTLSSecretCA()
+	ca, previous, err := r.reconcileCA(ctx, cr, caSecret, tls, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trust both current and previous CA until the previous one expires,
+	// so rolled and not rolled yet hosts are able to talk to each other during CA rotation
+	trusted := ca.Cert
+	if previous != nil && previous.NotAfter().After(now) {
+		trusted = append(append([]byte{}, ca.Cert...), previous.Cert...)
+	}
+
+	renewAt := ca.NotAfter().Add(-tls.GetRenewBefore())
+	var errs []error
+	cr.WalkHosts(func(host *api.Host) error {
+		hostCert, err := r.reconcileHost(ctx, host, ca, trusted, tls, now)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		host.Runtime.TLSVersion = hostCert.Fingerprint()
+		if at := hostCert.NotAfter().Add(-tls.GetRenewBefore()); at.Before(renewAt) {
+			renewAt = at
+		}
+		return nil
+	})
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	// Remember when the next renewal is due, so resync is able to trigger it
+	caSecret.Data[cert.SecretKeyRenewAt] = []byte(renewAt.UTC().Format(time.RFC3339))
+	if err := r.apply(ctx, caSecret, ""); err != nil {
+		return nil, err
+	}
+
+	return trusted, nil
+}
+
+// reconcileCA gets CA from the CA Secret, issuing new CA in case there is none or it is about to expire.
+// Returns current and previous CA, previous one may be nil.
+func (r *Reconciler) reconcileCA(
+	ctx context.Context,
+	cr api.ICustomResource,
+	secret *core.Secret,
+	tls *api.TLS,
+	now time.Time,
+) (ca, previous *cert.Certificate, err error) {
+	cur, err := r.secret.Get(ctx, secret.Namespace, secret.Name)
+	switch {
+	case err == nil:
+		if len(cur.Data[cert.SecretKeyCAKey]) > 0 {
+			// CA without private key is not able to issue anything, so it is treated as absent
+			ca, _ = cert.Load(cur.Data[cert.SecretKeyCACert], cur.Data[cert.SecretKeyCAKey])
+		}
+		previous, _ = cert.Load(cur.Data[cert.SecretKeyCACertPrevious], nil)
+		secret.Data = cur.Data
+	case apiErrors.IsNotFound(err):
+	default:
+		return nil, nil, err
+	}
+
+	if ca.NeedsRenewal(tls.GetRenewBefore(), now) {
+		log.V(1).M(cr).F().Info("Issue CA certificate. Secret: %s/%s", secret.Namespace, secret.Name)
+		if ca != nil {
+			previous = ca
+		}
+		if ca, err = cert.NewCA(secret.Name, tls.GetValidity(), now); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	data := map[string][]byte{
+		cert.SecretKeyCACert: ca.Cert,
+		cert.SecretKeyCAKey:  ca.Key,
+	}
+	if previous != nil {
+		data[cert.SecretKeyCACertPrevious] = previous.Cert
+	}
+	if renewAt, ok := secret.Data[cert.SecretKeyRenewAt]; ok {
+		data[cert.SecretKeyRenewAt] = renewAt
+	}
+	secret.Data = data
+
+	// CA has to be stored before any host certificate is issued by it
+	if err := r.apply(ctx, secret, ""); err != nil {
+		return nil, nil, err
+	}
+	return ca, previous, nil
+}
+
+// reconcileHost gets host certificate from the host Secret, issuing new one in case there is none,
+// it is about to expire, it is not issued by the current CA or host names have changed
+func (r *Reconciler) reconcileHost(
+	ctx context.Context,
+	host *api.Host,
+	ca *cert.Certificate,
+	trusted []byte,
+	tls *api.TLS,
+	now time.Time,
+) (*cert.Certificate, error) {
+	if r.hostContext != nil {
+		ctx = r.hostContext(ctx, host)
+	}
+	secret := r.task.Creator().CreateTLSSecretHost(host)
+	names := r.hostNames(host)
+
+	var hostCert *cert.Certificate
+	cur, err := r.secret.Get(ctx, secret.Namespace, secret.Name)
+	switch {
+	case err == nil:
+		hostCert, _ = cert.Load(cur.Data[cert.SecretKeyCert], cur.Data[cert.SecretKeyKey])
+	case apiErrors.IsNotFound(err):
+	default:
+		return nil, err
+	}
+
+	if hostCert.NeedsRenewal(tls.GetRenewBefore(), now) || !hostCert.IsIssuedBy(ca) || !hostCert.HasNames(names) {
+		log.V(1).M(host).F().Info("Issue host certificate. Secret: %s/%s", secret.Namespace, secret.Name)
+		if hostCert, err = ca.Issue(r.namer.Name(interfaces.NameInstanceHostname, host), names, tls.GetValidity(), now); err != nil {
+			return nil, fmt.Errorf("unable to issue certificate for host %s: %w", host.GetName(), err)
+		}
+	}
+
+	secret.Data = map[string][]byte{
+		cert.SecretKeyCACert: trusted,
+		cert.SecretKeyCert:   hostCert.Cert,
+		cert.SecretKeyKey:    hostCert.Key,
+	}
+	if err := r.apply(ctx, secret, host.GetKubeContext()); err != nil {
+		return nil, err
+	}
+	return hostCert, nil
+}
+
+// hostNames builds list of names host certificate is issued for
+func (r *Reconciler) hostNames(host *api.Host) []string {
+	service := r.namer.Name(interfaces.NameStatefulSetService, host)
+	names := []string{
+		strings.TrimSuffix(r.namer.Name(interfaces.NameFQDN, host), "."),
+		service,
+		service + "." + host.Runtime.Address.Namespace,
+		r.namer.Name(interfaces.NamePodHostname, host),
+		r.namer.Name(interfaces.NameInstanceHostname, host),
+		"localhost",
+		"127.0.0.1",
+	}
+	return util.Unique(util.NonEmpty(names))
+}
+
+// apply creates or updates Secret and registers it as reconciled within the named kube context
+func (r *Reconciler) apply(ctx context.Context, secret *core.Secret, kubeContext string) error {
+	cur, err := r.secret.Get(ctx, secret.Namespace, secret.Name)
+	switch {
+	case err == nil:
+		if !reflect.DeepEqual(cur.Data, secret.Data) || !reflect.DeepEqual(cur.Labels, secret.Labels) {
+			cur.Data = secret.Data
+			cur.Labels = secret.Labels
+			cur.Annotations = secret.Annotations
+			cur.OwnerReferences = secret.OwnerReferences
+			_, err = r.secret.Update(ctx, cur)
+		}
+	case apiErrors.IsNotFound(err):
+		_, err = r.secret.Create(ctx, secret)
+	}
+
+	if err != nil {
+		r.task.RegistryFailed().KubeContext(kubeContext).RegisterSecret(secret.GetObjectMeta())
+		return fmt.Errorf("unable to reconcile Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	r.task.RegistryReconciled().KubeContext(kubeContext).RegisterSecret(secret.GetObjectMeta())
+	return nil
+}
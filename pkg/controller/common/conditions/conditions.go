@@ -0,0 +1,153 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conditions
+
+import (
+	"fmt"
+	"strings"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// maxListedItems limits number of items listed in the condition message
+const maxListedItems = 5
+
+// set sets condition of the CR observed on the current generation of the CR
+func set(cr api.ICustomResource, conditionType string, status bool, reason, message string) {
+	cr.IEnsureStatus().SetCondition(api.NewCondition(conditionType, status, reason, message, cr.GetGeneration()))
+}
+
+// ReconcileStarted sets conditions of the CR reconcile has started for
+func ReconcileStarted(cr api.ICustomResource) {
+	set(cr, api.ConditionTypeReconciling, true, api.ConditionReasonReconcileStarted, "Reconcile is in progress")
+	set(cr, api.ConditionTypeReady, false, api.ConditionReasonReconcileStarted, "Reconcile is in progress")
+	set(cr, api.ConditionTypeSuspended, false, api.ConditionReasonNotSuspended, "")
+}
+
+// ReconcileCompleted sets conditions of the CR reconcile has completed successfully for
+func ReconcileCompleted(cr api.ICustomResource) {
+	set(cr, api.ConditionTypeReconciling, false, api.ConditionReasonReconcileCompleted, "")
+	if cr.IsStopped() {
+		set(cr, api.ConditionTypeReady, false, api.ConditionReasonStopped, "All hosts are stopped")
+		return
+	}
+	set(cr, api.ConditionTypeReady, true, api.ConditionReasonReconcileCompleted, "")
+}
+
+// ReconcileFailed sets conditions of the CR reconcile has failed for
+func ReconcileFailed(cr api.ICustomResource, err error) {
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+	set(cr, api.ConditionTypeReconciling, false, api.ConditionReasonReconcileFailed, message)
+	set(cr, api.ConditionTypeReady, false, api.ConditionReasonReconcileFailed, message)
+}
+
+// Suspended sets conditions of the suspended CR
+func Suspended(cr api.ICustomResource, aborted bool) {
+	set(cr, api.ConditionTypeSuspended, true, api.ConditionReasonSuspended, "Reconcile is suspended")
+	if aborted {
+		set(cr, api.ConditionTypeReconciling, false, api.ConditionReasonReconcileAborted, "Reconcile is aborted due to suspend")
+	}
+}
+
+// Health describes health of the CR observed by a periodic health check
+type Health struct {
+	// Reconciled specifies whether the last reconcile has completed successfully
+	Reconciled bool
+	// Hosts is a number of hosts the CR has
+	Hosts int
+	// NotReadyHosts lists hosts which are not ready
+	NotReadyHosts []string
+	// UnhealthyReplicas lists replicas with unhealthy replication, such as read-only or lagging ones
+	UnhealthyReplicas []string
+	// QuorumLost specifies whether replication has no quorum, as it is for Keeper ensemble
+	QuorumLost bool
+	// SchemaManaged specifies whether the CR has declarative schema
+	SchemaManaged bool
+	// SchemaDrifted lists clusters the schema is not in sync on
+	SchemaDrifted []string
+}
+
+// Observe sets health-related conditions of the CR
+func Observe(cr api.ICustomResource, health *Health) {
+	if cr.IsStopped() {
+		set(cr, api.ConditionTypeReady, false, api.ConditionReasonStopped, "All hosts are stopped")
+		set(cr, api.ConditionTypeDegraded, false, api.ConditionReasonStopped, "")
+		return
+	}
+
+	hostsReady := len(health.NotReadyHosts) == 0
+	replicationHealthy := (len(health.UnhealthyReplicas) == 0) && !health.QuorumLost
+
+	switch {
+	case !hostsReady:
+		set(cr, api.ConditionTypeReady, false, api.ConditionReasonHostsNotReady, notReadyMessage(health))
+	case health.Reconciled:
+		set(cr, api.ConditionTypeReady, true, api.ConditionReasonHostsReady, fmt.Sprintf("%d hosts are ready", health.Hosts))
+	}
+
+	switch {
+	case !hostsReady:
+		set(cr, api.ConditionTypeDegraded, true, api.ConditionReasonHostsNotReady, notReadyMessage(health))
+	case !replicationHealthy:
+		set(cr, api.ConditionTypeDegraded, true, replicationReason(health), replicationMessage(health))
+	default:
+		set(cr, api.ConditionTypeDegraded, false, api.ConditionReasonHostsReady, "")
+	}
+
+	if replicationHealthy {
+		set(cr, api.ConditionTypeReplicationHealthy, true, api.ConditionReasonReplicasHealthy, "")
+	} else {
+		set(cr, api.ConditionTypeReplicationHealthy, false, replicationReason(health), replicationMessage(health))
+	}
+
+	switch {
+	case !health.SchemaManaged:
+		set(cr, api.ConditionTypeSchemaInSync, true, api.ConditionReasonSchemaNotManaged, "")
+	case len(health.SchemaDrifted) > 0:
+		set(cr, api.ConditionTypeSchemaInSync, false, api.ConditionReasonSchemaDrifted,
+			"Schema is not in sync in clusters: "+list(health.SchemaDrifted))
+	default:
+		set(cr, api.ConditionTypeSchemaInSync, true, api.ConditionReasonSchemaInSync, "")
+	}
+}
+
+func notReadyMessage(health *Health) string {
+	return fmt.Sprintf("%d of %d hosts are not ready: %s", len(health.NotReadyHosts), health.Hosts, list(health.NotReadyHosts))
+}
+
+func replicationReason(health *Health) string {
+	if health.QuorumLost {
+		return api.ConditionReasonQuorumLost
+	}
+	return api.ConditionReasonReplicasUnhealthy
+}
+
+func replicationMessage(health *Health) string {
+	if health.QuorumLost {
+		return "Quorum is lost"
+	}
+	return fmt.Sprintf("%d replicas are unhealthy: %s", len(health.UnhealthyReplicas), list(health.UnhealthyReplicas))
+}
+
+// list lists items in the message, limiting number of items listed
+func list(items []string) string {
+	if len(items) > maxListedItems {
+		return strings.Join(items[:maxListedItems], ", ") + fmt.Sprintf(" and %d more", len(items)-maxListedItems)
+	}
+	return strings.Join(items, ", ")
+}
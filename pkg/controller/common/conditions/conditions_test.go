@@ -0,0 +1,78 @@
+package conditions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+func newCR(generation int64) *api.ClickHouseInstallation {
+	cr := &api.ClickHouseInstallation{}
+	cr.SetGeneration(generation)
+	return cr
+}
+
+func requireCondition(t *testing.T, cr api.ICustomResource, conditionType string, status meta.ConditionStatus, reason string) {
+	condition := cr.IEnsureStatus().GetCondition(conditionType)
+	require.NotNil(t, condition, conditionType)
+	require.Equal(t, status, condition.Status, conditionType)
+	require.Equal(t, reason, condition.Reason, conditionType)
+	require.Equal(t, cr.GetGeneration(), condition.ObservedGeneration, conditionType)
+}
+
+func TestReconcile(t *testing.T) {
+	cr := newCR(3)
+
+	ReconcileStarted(cr)
+	requireCondition(t, cr, api.ConditionTypeReconciling, meta.ConditionTrue, api.ConditionReasonReconcileStarted)
+	requireCondition(t, cr, api.ConditionTypeReady, meta.ConditionFalse, api.ConditionReasonReconcileStarted)
+	requireCondition(t, cr, api.ConditionTypeSuspended, meta.ConditionFalse, api.ConditionReasonNotSuspended)
+
+	ReconcileCompleted(cr)
+	requireCondition(t, cr, api.ConditionTypeReconciling, meta.ConditionFalse, api.ConditionReasonReconcileCompleted)
+	requireCondition(t, cr, api.ConditionTypeReady, meta.ConditionTrue, api.ConditionReasonReconcileCompleted)
+
+	cr.SetGeneration(4)
+	ReconcileFailed(cr, errors.New("boom"))
+	requireCondition(t, cr, api.ConditionTypeReady, meta.ConditionFalse, api.ConditionReasonReconcileFailed)
+	require.Equal(t, "boom", cr.IEnsureStatus().GetCondition(api.ConditionTypeReady).Message)
+}
+
+func TestObserve(t *testing.T) {
+	cr := newCR(1)
+
+	Observe(cr, &Health{
+		Reconciled: true,
+		Hosts:      2,
+	})
+	requireCondition(t, cr, api.ConditionTypeReady, meta.ConditionTrue, api.ConditionReasonHostsReady)
+	requireCondition(t, cr, api.ConditionTypeDegraded, meta.ConditionFalse, api.ConditionReasonHostsReady)
+	requireCondition(t, cr, api.ConditionTypeReplicationHealthy, meta.ConditionTrue, api.ConditionReasonReplicasHealthy)
+	requireCondition(t, cr, api.ConditionTypeSchemaInSync, meta.ConditionTrue, api.ConditionReasonSchemaNotManaged)
+
+	Observe(cr, &Health{
+		Reconciled:        true,
+		Hosts:             2,
+		UnhealthyReplicas: []string{"chi-0-0/db.t1", "chi-0-0/db.t2", "chi-0-0/db.t3", "chi-0-0/db.t4", "chi-0-0/db.t5", "chi-0-0/db.t6"},
+		SchemaManaged:     true,
+		SchemaDrifted:     []string{"default"},
+	})
+	requireCondition(t, cr, api.ConditionTypeReady, meta.ConditionTrue, api.ConditionReasonHostsReady)
+	requireCondition(t, cr, api.ConditionTypeDegraded, meta.ConditionTrue, api.ConditionReasonReplicasUnhealthy)
+	requireCondition(t, cr, api.ConditionTypeReplicationHealthy, meta.ConditionFalse, api.ConditionReasonReplicasUnhealthy)
+	requireCondition(t, cr, api.ConditionTypeSchemaInSync, meta.ConditionFalse, api.ConditionReasonSchemaDrifted)
+	require.Contains(t, cr.IEnsureStatus().GetCondition(api.ConditionTypeReplicationHealthy).Message, "and 1 more")
+
+	Observe(cr, &Health{
+		Hosts:         3,
+		NotReadyHosts: []string{"chk-0-0", "chk-0-1"},
+		QuorumLost:    true,
+	})
+	requireCondition(t, cr, api.ConditionTypeReady, meta.ConditionFalse, api.ConditionReasonHostsNotReady)
+	requireCondition(t, cr, api.ConditionTypeDegraded, meta.ConditionTrue, api.ConditionReasonHostsNotReady)
+	requireCondition(t, cr, api.ConditionTypeReplicationHealthy, meta.ConditionFalse, api.ConditionReasonQuorumLost)
+}
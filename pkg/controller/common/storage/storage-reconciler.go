@@ -172,9 +172,9 @@ func (w *Reconciler) reconcilePVCFromVolumeMount(
 	//
 
 	if pvcReconciled, err := w.reconcilePVC(ctx, pvc, host, volumeClaimTemplate); err == nil {
-		w.task.RegistryReconciled().RegisterPVC(pvcReconciled.GetObjectMeta())
+		w.task.RegistryReconciled().KubeContext(host.GetKubeContext()).RegisterPVC(pvcReconciled.GetObjectMeta())
 	} else {
-		w.task.RegistryFailed().RegisterPVC(pvc.GetObjectMeta())
+		w.task.RegistryFailed().KubeContext(host.GetKubeContext()).RegisterPVC(pvc.GetObjectMeta())
 		log.M(host).F().Error("Unable to reconcile PVC: %s err: %v", util.NamespacedName(pvc), err)
 	}
 
@@ -0,0 +1,34 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"fmt"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/metrics/clickhouse"
+)
+
+// CheckHealth checks health of the upgraded host passes health gate.
+// Errors are counted since the baseline health taken before the stage is started
+func CheckHealth(gate *api.UpgradeHealthGate, baseline, health *clickhouse.UpgradeHealth) error {
+	if errors := health.CountErrorsSince(baseline, gate.IsErrorIgnored); errors > gate.GetMaxErrors() {
+		return fmt.Errorf("%d errors reported since the stage is started while %d errors allowed", errors, gate.GetMaxErrors())
+	}
+	if health.ReplicationDelay > gate.GetMaxReplicationDelay() {
+		return fmt.Errorf("replication delay is %ds while %ds allowed", health.ReplicationDelay, gate.GetMaxReplicationDelay())
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/metrics/clickhouse"
+)
+
+func TestCheckHealth(t *testing.T) {
+	gate := &api.UpgradeHealthGate{
+		MaxErrors:           types.NewInt32(2),
+		MaxReplicationDelay: types.NewInt32(60),
+	}
+	baseline := &clickhouse.UpgradeHealth{
+		Uptime: 3600,
+		Errors: map[string]int{"NETWORK_ERROR": 1000, "UNKNOWN_TABLE": 10},
+	}
+
+	// Errors accumulated before the stage do not fail the gate
+	require.NoError(t, CheckHealth(gate, baseline, &clickhouse.UpgradeHealth{
+		Uptime: 3660,
+		Errors: map[string]int{"NETWORK_ERROR": 1002, "UNKNOWN_TABLE": 10},
+	}))
+	// Errors since the stage fail the gate
+	require.Error(t, CheckHealth(gate, baseline, &clickhouse.UpgradeHealth{
+		Uptime: 3660,
+		Errors: map[string]int{"NETWORK_ERROR": 1003},
+	}))
+	// Errors caused by clients are ignored by default
+	require.NoError(t, CheckHealth(gate, baseline, &clickhouse.UpgradeHealth{
+		Uptime: 20,
+		Errors: map[string]int{"UNKNOWN_TABLE": 100, "SYNTAX_ERROR": 100},
+	}))
+	// Errors of the restarted host are counted since the start
+	require.Error(t, CheckHealth(gate, baseline, &clickhouse.UpgradeHealth{
+		Uptime: 20,
+		Errors: map[string]int{"CANNOT_READ_ALL_DATA": 3},
+	}))
+	// Replication delay
+	require.Error(t, CheckHealth(gate, baseline, &clickhouse.UpgradeHealth{
+		Uptime:           3660,
+		Errors:           baseline.Errors,
+		ReplicationDelay: 61,
+	}))
+
+	// Explicitly specified ignored errors replace the default ones
+	gate.IgnoreErrors = []string{"CANNOT_READ_ALL_DATA"}
+	require.NoError(t, CheckHealth(gate, baseline, &clickhouse.UpgradeHealth{
+		Uptime: 20,
+		Errors: map[string]int{"CANNOT_READ_ALL_DATA": 3},
+	}))
+	require.Error(t, CheckHealth(gate, baseline, &clickhouse.UpgradeHealth{
+		Uptime: 20,
+		Errors: map[string]int{"UNKNOWN_TABLE": 3},
+	}))
+}
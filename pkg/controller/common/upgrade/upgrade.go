@@ -0,0 +1,169 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// IReconciler reconciles shards and hosts of the cluster being upgraded in stages
+type IReconciler interface {
+	// ReconcileShard reconciles the shard without its hosts
+	ReconcileShard(ctx context.Context, shard *api.ChiShard) error
+	// ReconcileShardWithHosts reconciles the shard along with its hosts
+	ReconcileShardWithHosts(ctx context.Context, shard *api.ChiShard) error
+	// ReconcileHost reconciles the host
+	ReconcileHost(ctx context.Context, host *api.Host) error
+	// ReconcileShards reconciles the shards along with their hosts, shards start at the specified index in the cluster
+	ReconcileShards(ctx context.Context, startShardIndex int, shards []*api.ChiShard) error
+	// Prepare keeps state of the hosts of the stage before the stage is reconciled,
+	// so the hosts could be checked and rolled back afterwards. Returns hosts to be upgraded
+	Prepare(ctx context.Context, hosts []*api.Host) []*api.Host
+	// CheckHealthGate checks the upgraded hosts pass health gate
+	CheckHealthGate(ctx context.Context, hosts []*api.Host) error
+	// Rollback rolls the upgraded hosts back to the state kept by Prepare
+	Rollback(ctx context.Context, hosts []*api.Host)
+}
+
+// stage is a set of hosts reconciled and checked together
+type stage struct {
+	name      string
+	hosts     []*api.Host
+	reconcile func(ctx context.Context) error
+}
+
+// Run reconciles shards and hosts of the cluster upgrading them in stages.
+// Canary host or shard is upgraded first, the rest of the shards are upgraded in waves afterwards.
+// Each stage has to pass health gate, otherwise hosts of the stage are rolled back and reconcile is stopped
+func Run(ctx context.Context, cluster *api.Cluster, r IReconciler) error {
+	for _, stage := range plan(cluster, r) {
+		if util.IsContextDone(ctx) {
+			log.V(1).Info("Reconcile is aborted. Cluster: %s ", cluster.GetName())
+			return nil
+		}
+		if err := runStage(ctx, cluster, stage, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// plan splits shards and hosts of the cluster into stages
+func plan(cluster *api.Cluster, r IReconciler) (stages []*stage) {
+	upgrade := cluster.GetReconcile().Upgrade
+	shards := cluster.Layout.Shards[:]
+	if len(shards) == 0 {
+		return nil
+	}
+
+	// Canary
+	first := shards[0]
+	switch upgrade.GetCanary() {
+	case api.UpgradeCanaryShard:
+		stages = append(stages, &stage{
+			name:  "canary shard",
+			hosts: shardsHosts(first),
+			reconcile: func(ctx context.Context) error {
+				return r.ReconcileShardWithHosts(ctx, first)
+			},
+		})
+	default:
+		canary := first.FirstHost()
+		var rest []*api.Host
+		first.WalkHosts(func(host *api.Host) error {
+			if host != canary {
+				rest = append(rest, host)
+			}
+			return nil
+		})
+		stages = append(stages,
+			&stage{
+				name:  "canary host",
+				hosts: []*api.Host{canary},
+				reconcile: func(ctx context.Context) error {
+					if err := r.ReconcileShard(ctx, first); err != nil {
+						return err
+					}
+					return r.ReconcileHost(ctx, canary)
+				},
+			},
+			&stage{
+				name:  "first shard",
+				hosts: rest,
+				reconcile: func(ctx context.Context) error {
+					for _, host := range rest {
+						if err := r.ReconcileHost(ctx, host); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			},
+		)
+	}
+
+	// Waves
+	size := upgrade.GetWaveSize(len(shards) - 1)
+	for start, wave := 1, 1; start < len(shards); start, wave = start+size, wave+1 {
+		end := min(start+size, len(shards))
+		waveShards := shards[start:end]
+		waveStart := start
+		stages = append(stages, &stage{
+			name:  fmt.Sprintf("wave %d", wave),
+			hosts: shardsHosts(waveShards...),
+			reconcile: func(ctx context.Context) error {
+				return r.ReconcileShards(ctx, waveStart, waveShards)
+			},
+		})
+	}
+
+	return stages
+}
+
+// runStage reconciles hosts of the stage and checks upgraded hosts pass health gate.
+// Upgraded hosts of the stage are rolled back in case health gate is not passed
+func runStage(ctx context.Context, cluster *api.Cluster, stage *stage, r IReconciler) error {
+	upgraded := r.Prepare(ctx, stage.hosts)
+
+	log.V(1).M(cluster).F().Info("Staged upgrade of cluster: %s. Reconcile %s: %d hosts to upgrade", cluster.GetName(), stage.name, len(upgraded))
+	if err := stage.reconcile(ctx); err != nil {
+		return err
+	}
+
+	err := r.CheckHealthGate(ctx, upgraded)
+	if err == nil {
+		log.V(1).M(cluster).F().Info("Staged upgrade of cluster: %s. Health gate passed by %s", cluster.GetName(), stage.name)
+		return nil
+	}
+
+	r.Rollback(ctx, upgraded)
+	return fmt.Errorf("staged upgrade of cluster %s stopped on %s, hosts are rolled back. err: %v", cluster.GetName(), stage.name, err)
+}
+
+// shardsHosts lists hosts of the shards
+func shardsHosts(shards ...*api.ChiShard) (hosts []*api.Host) {
+	for _, shard := range shards {
+		shard.WalkHosts(func(host *api.Host) error {
+			hosts = append(hosts, host)
+			return nil
+		})
+	}
+	return hosts
+}
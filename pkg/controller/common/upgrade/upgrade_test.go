@@ -0,0 +1,118 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+// fakeReconciler records calls and fails health gate of the specified hosts
+type fakeReconciler struct {
+	calls     []string
+	unhealthy map[string]bool
+}
+
+func (r *fakeReconciler) ReconcileShard(_ context.Context, shard *api.ChiShard) error {
+	r.calls = append(r.calls, "shard "+shard.Name)
+	return nil
+}
+
+func (r *fakeReconciler) ReconcileShardWithHosts(_ context.Context, shard *api.ChiShard) error {
+	r.calls = append(r.calls, "shard with hosts "+shard.Name)
+	return nil
+}
+
+func (r *fakeReconciler) ReconcileHost(_ context.Context, host *api.Host) error {
+	r.calls = append(r.calls, "host "+host.Name)
+	return nil
+}
+
+func (r *fakeReconciler) ReconcileShards(_ context.Context, startShardIndex int, shards []*api.ChiShard) error {
+	r.calls = append(r.calls, fmt.Sprintf("shards from %d: %d", startShardIndex, len(shards)))
+	return nil
+}
+
+func (r *fakeReconciler) Prepare(_ context.Context, hosts []*api.Host) []*api.Host {
+	return hosts
+}
+
+func (r *fakeReconciler) CheckHealthGate(_ context.Context, hosts []*api.Host) error {
+	for _, host := range hosts {
+		r.calls = append(r.calls, "check "+host.Name)
+		if r.unhealthy[host.Name] {
+			return fmt.Errorf("host %s is unhealthy", host.Name)
+		}
+	}
+	return nil
+}
+
+func (r *fakeReconciler) Rollback(_ context.Context, hosts []*api.Host) {
+	for _, host := range hosts {
+		r.calls = append(r.calls, "rollback "+host.Name)
+	}
+}
+
+func newCluster(canary string, wavePercent int, shardsCount, replicasCount int) *api.Cluster {
+	cluster := &api.Cluster{
+		Name: "cluster",
+		Reconcile: &api.ClusterReconcile{
+			Upgrade: &api.ClusterUpgrade{
+				Enabled:     types.NewStringBool(true),
+				Canary:      canary,
+				WavePercent: types.NewInt32(int32(wavePercent)),
+			},
+		},
+		Layout: &api.ChiClusterLayout{},
+	}
+	for s := 0; s < shardsCount; s++ {
+		shard := &api.ChiShard{Name: fmt.Sprintf("%d", s)}
+		for r := 0; r < replicasCount; r++ {
+			shard.Hosts = append(shard.Hosts, &api.Host{Name: fmt.Sprintf("%d-%d", s, r)})
+		}
+		cluster.Layout.Shards = append(cluster.Layout.Shards, shard)
+	}
+	return cluster
+}
+
+func TestRunCanaryHost(t *testing.T) {
+	r := &fakeReconciler{}
+	require.NoError(t, Run(context.Background(), newCluster(api.UpgradeCanaryHost, 50, 5, 2), r))
+	require.Equal(t, []string{
+		"shard 0", "host 0-0", "check 0-0",
+		"host 0-1", "check 0-1",
+		"shards from 1: 2", "check 1-0", "check 1-1", "check 2-0", "check 2-1",
+		"shards from 3: 2", "check 3-0", "check 3-1", "check 4-0", "check 4-1",
+	}, r.calls)
+}
+
+func TestRunCanaryShard(t *testing.T) {
+	r := &fakeReconciler{}
+	require.NoError(t, Run(context.Background(), newCluster(api.UpgradeCanaryShard, 100, 3, 2), r))
+	require.Equal(t, []string{
+		"shard with hosts 0", "check 0-0", "check 0-1",
+		"shards from 1: 2", "check 1-0", "check 1-1", "check 2-0", "check 2-1",
+	}, r.calls)
+}
+
+func TestRunCanaryFailureRollsBackAndStops(t *testing.T) {
+	r := &fakeReconciler{unhealthy: map[string]bool{"0-0": true}}
+	require.Error(t, Run(context.Background(), newCluster(api.UpgradeCanaryHost, 50, 3, 2), r))
+	require.Equal(t, []string{
+		"shard 0", "host 0-0", "check 0-0", "rollback 0-0",
+	}, r.calls)
+}
+
+func TestRunWaveFailureRollsBackWaveAndStops(t *testing.T) {
+	r := &fakeReconciler{unhealthy: map[string]bool{"2-1": true}}
+	require.Error(t, Run(context.Background(), newCluster(api.UpgradeCanaryShard, 50, 5, 2), r))
+	require.Equal(t, []string{
+		"shard with hosts 0", "check 0-0", "check 0-1",
+		"shards from 1: 2", "check 1-0", "check 1-1", "check 2-0", "check 2-1",
+		"rollback 1-0", "rollback 1-1", "rollback 2-0", "rollback 2-1",
+	}, r.calls)
+}
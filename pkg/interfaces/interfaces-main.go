@@ -105,6 +105,8 @@ type ICreator interface {
 		template *api.VolumeClaimTemplate,
 	) *core.PersistentVolumeClaim
 	CreateClusterSecret(cluster api.ICluster) *core.Secret
+	CreateTLSSecretCA() *core.Secret
+	CreateTLSSecretHost(host *api.Host) *core.Secret
 	CreateService(what ServiceType, params ...any) util.Slice[*core.Service]
 	CreateStatefulSet(host *api.Host, shutdown bool) *apps.StatefulSet
 	GetAppImageTag(host *api.Host) (string, bool)
@@ -41,4 +41,6 @@ const (
 	NamePVCNameByVolumeClaimTemplate NameType = "NamePVCNameByVolumeClaimTemplate"
 	NameClusterAutoSecret            NameType = "NameClusterAutoSecret"
 	NameClusterPDB                   NameType = "NameClusterPDB"
+	NameTLSSecretCA                  NameType = "NameTLSSecretCA"
+	NameTLSSecretHost                NameType = "NameTLSSecretHost"
 )
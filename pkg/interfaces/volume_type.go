@@ -19,4 +19,5 @@ type VolumeType string
 const (
 	VolumesForConfigMaps          VolumeType = "VolumesForConfigMaps"
 	VolumesUserDataWithFixedPaths VolumeType = "VolumesUserDataWithFixedPaths"
+	VolumesForTLS                 VolumeType = "VolumesForTLS"
 )
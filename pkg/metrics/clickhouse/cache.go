@@ -0,0 +1,136 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// cachedFetch keeps results of a fetch
+type cachedFetch struct {
+	fetch *fetch
+	// data is the last good result of the fetch
+	data Table
+	// fetched specifies when the last good result was fetched
+	fetched time.Time
+	// failed specifies whether the latest attempt of the fetch failed
+	failed bool
+}
+
+// HostCache keeps the last good results of metrics fetches of a host, so scrapes do not query the host
+type HostCache struct {
+	mutex   sync.RWMutex
+	fetches []*cachedFetch
+	// duration of the latest collection
+	duration time.Duration
+	// collected specifies when the latest collection started
+	collected time.Time
+	// collecting specifies whether collection is running right now
+	collecting atomic.Bool
+	// queryLog accumulates query latencies of the host across collections
+	queryLog *QueryLogHistograms
+}
+
+// NewHostCache creates new host cache
+func NewHostCache() *HostCache {
+	return &HostCache{
+		queryLog: NewQueryLogHistograms(),
+	}
+}
+
+// QueryLog gets query latency histograms of the host
+func (c *HostCache) QueryLog() *QueryLogHistograms {
+	return c.queryLog
+}
+
+// StartCollect marks collection as running. Returns false in case collection is running already
+func (c *HostCache) StartCollect() bool {
+	return c.collecting.CompareAndSwap(false, true)
+}
+
+// FinishCollect marks collection as completed
+func (c *HostCache) FinishCollect() {
+	c.collecting.Store(false)
+}
+
+// Store stores result of the fetch. Failed fetch keeps the last good result
+func (c *HostCache) Store(f *fetch, data Table, err error, fetched time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cached := c.get(f.name)
+	if cached == nil {
+		cached = &cachedFetch{}
+		c.fetches = append(c.fetches, cached)
+	}
+	cached.fetch = f
+	cached.failed = err != nil
+	if err == nil {
+		cached.data = data
+		cached.fetched = fetched
+	}
+}
+
+// get gets cached fetch by name
+func (c *HostCache) get(name string) *cachedFetch {
+	for _, cached := range c.fetches {
+		if cached.fetch.name == name {
+			return cached
+		}
+	}
+	return nil
+}
+
+// SetCollected sets start and duration of the latest collection
+func (c *HostCache) SetCollected(start time.Time, duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.collected = start
+	c.duration = duration
+}
+
+// IsDue checks whether the next collection is due
+func (c *HostCache) IsDue(interval time.Duration, now time.Time) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return now.Sub(c.collected) >= interval
+}
+
+// Write writes cached metrics along with their staleness
+func (c *HostCache) Write(w *CHIPrometheusWriter, now time.Time) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, cached := range c.fetches {
+		if !cached.fetched.IsZero() {
+			cached.fetch.write(w, cached.data)
+		}
+		for _, fetchType := range cached.fetch.fetchTypes {
+			if cached.failed {
+				w.WriteErrorFetch(fetchType)
+			} else {
+				w.WriteOKFetch(fetchType)
+			}
+			if !cached.fetched.IsZero() {
+				w.WriteFetchAge(fetchType, now.Sub(cached.fetched))
+			}
+		}
+	}
+	if !c.collected.IsZero() {
+		w.WriteCollectDuration(c.duration)
+	}
+}
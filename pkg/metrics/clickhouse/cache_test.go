@@ -0,0 +1,40 @@
+package clickhouse
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostCacheKeepsLastGoodResult(t *testing.T) {
+	cache := NewHostCache()
+	f := &fetch{name: "system.metrics", fetchTypes: []string{"system.metrics"}}
+	first := time.Now()
+
+	cache.Store(f, Table{{"metric.Query", "1"}}, nil, first)
+	cache.Store(f, nil, errors.New("timeout"), first.Add(time.Minute))
+
+	cached := cache.get(f.name)
+	require.NotNil(t, cached)
+	require.True(t, cached.failed)
+	require.Equal(t, Table{{"metric.Query", "1"}}, cached.data)
+	require.Equal(t, first, cached.fetched)
+	require.Len(t, cache.fetches, 1)
+}
+
+func TestHostCacheSchedule(t *testing.T) {
+	cache := NewHostCache()
+	now := time.Now()
+	require.True(t, cache.IsDue(time.Minute, now))
+
+	require.True(t, cache.StartCollect())
+	require.False(t, cache.StartCollect(), "collection is running already")
+	cache.SetCollected(now, time.Second)
+	cache.FinishCollect()
+
+	require.False(t, cache.IsDue(time.Minute, now.Add(30*time.Second)))
+	require.True(t, cache.IsDue(time.Minute, now.Add(time.Minute)))
+	require.True(t, cache.StartCollect())
+}
@@ -18,9 +18,13 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc"
 
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/model/clickhouse"
 	"github.com/altinity/clickhouse-operator/pkg/util"
 )
@@ -136,6 +140,66 @@ const (
 			disk,
 			reason
     `
+
+	queryResourceUsageSQL = `
+		SELECT
+			(SELECT toString(sum(value)) FROM system.events WHERE event IN ('UserTimeMicroseconds', 'SystemTimeMicroseconds')) AS cpu_time_microseconds,
+			(SELECT toString(sum(value)) FROM system.asynchronous_metrics WHERE metric = 'MemoryResident')                     AS memory_resident,
+			(SELECT toString(max(peak_memory_usage)) FROM system.processes)                                                     AS query_peak_memory
+	`
+
+	queryUpgradeHealthSQL = `
+		SELECT
+			toString(uptime())                                          AS uptime,
+			(SELECT toString(max(absolute_delay)) FROM system.replicas) AS replication_delay
+	`
+
+	queryUpgradeErrorsSQL = `
+		SELECT
+			name,
+			toString(value) AS value
+		FROM system.errors
+		WHERE NOT remote
+	`
+
+	// queryQueryLogSQLTemplate aggregates queries finished since the specified unix time.
+	// Recent events are skipped, so events not flushed into query_log yet are not missed.
+	// Bucket counts are cumulative, each bucket counts queries not longer than bucket bound
+	queryQueryLogSQLTemplate = `
+		WITH
+			greatest(fromUnixTimestamp(%d), now() - INTERVAL %d SECOND) AS since,
+			now() - INTERVAL %d SECOND                                   AS till
+		SELECT
+			user,
+			toString(query_kind)                                   AS query_kind,
+			toString(normalized_query_hash)                        AS query_hash,
+			toString(count())                                      AS count,
+			toString(countIf(type != 'QueryFinish'))               AS failed,
+			toString(sum(query_duration_ms) / 1000)                AS sum,
+			arrayStringConcat(
+				arrayMap(x -> toString(x), sumForEach(arrayMap(b -> toUInt64(query_duration_ms <= b), [%s]))),
+				','
+			)                                                      AS buckets,
+			toString(toUnixTimestamp(max(event_time)))             AS last_event_time
+		FROM system.query_log
+		WHERE (event_date >= toDate(since)) AND (event_time > since) AND (event_time <= till)
+			AND (type IN ('QueryFinish', 'ExceptionBeforeStart', 'ExceptionWhileProcessing'))
+		GROUP BY user, query_kind, query_hash
+		ORDER BY count() DESC
+	`
+
+	// queryLogFlushLag specifies how long it takes for events to be flushed into query_log. In seconds
+	queryLogFlushLag = 15
+	// queryLogLookBehind limits how far back query_log is read. In seconds
+	queryLogLookBehind = 600
+
+	queryUnhealthyReplicasSQL = `
+		SELECT
+			concat(database, '.', table) AS replica
+		FROM system.replicas
+		WHERE is_readonly OR is_session_expired OR (absolute_delay > %d)
+		ORDER BY database, table
+	`
 )
 
 // MetricsFetcher specifies clickhouse fetcher object
@@ -185,6 +249,181 @@ func (f *MetricsFetcher) getClickHouseQueryMetrics(ctx context.Context) (Table,
 	)
 }
 
+// GetMetricValues requests current values of the specified metrics, named the same way the exporter reports them,
+// such as metric.Query. Metrics which are not reported by the host are omitted
+func (f *MetricsFetcher) GetMetricValues(ctx context.Context, names ...string) (map[string]float64, error) {
+	data, err := f.getClickHouseQueryMetrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]float64)
+	for _, row := range data {
+		if !util.InArray(row[0], names) {
+			continue
+		}
+		if value, err := strconv.ParseFloat(row[1], 64); err == nil {
+			values[row[0]] = value
+		}
+	}
+	return values, nil
+}
+
+// ResourceUsage describes resources consumed by the ClickHouse process
+type ResourceUsage struct {
+	// CPUTime is a cumulative CPU time, in seconds
+	CPUTime float64
+	// Memory is a resident memory, in bytes
+	Memory float64
+	// QueryMemory is a peak memory of the largest running query, in bytes
+	QueryMemory float64
+}
+
+// GetResourceUsage requests resources consumed by ClickHouse
+func (f *MetricsFetcher) GetResourceUsage(ctx context.Context) (*ResourceUsage, error) {
+	data, err := f.clickHouseQueryScanRows(
+		ctx,
+		queryResourceUsageSQL,
+		func(rows *sql.Rows, data *Table) error {
+			var cpuTime, memory, queryMemory string
+			if err := rows.Scan(&cpuTime, &memory, &queryMemory); err == nil {
+				*data = append(*data, []string{cpuTime, memory, queryMemory})
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no resource usage reported")
+	}
+	var values [3]float64
+	for i := range values {
+		values[i], _ = strconv.ParseFloat(data[0][i], 64)
+	}
+	return &ResourceUsage{
+		CPUTime:     values[0] / 1e6,
+		Memory:      values[1],
+		QueryMemory: values[2],
+	}, nil
+}
+
+// GetUnhealthyReplicas requests replicated tables which are read-only, have no active Keeper session
+// or lag behind other replicas more than maxDelay seconds. Tables are reported as database.table
+func (f *MetricsFetcher) GetUnhealthyReplicas(ctx context.Context, maxDelay int) ([]string, error) {
+	data, err := f.clickHouseQueryScanRows(
+		ctx,
+		fmt.Sprintf(queryUnhealthyReplicasSQL, maxDelay),
+		func(rows *sql.Rows, data *Table) error {
+			var replica string
+			if err := rows.Scan(&replica); err == nil {
+				*data = append(*data, []string{replica})
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var replicas []string
+	for _, row := range data {
+		replicas = append(replicas, row[0])
+	}
+	return replicas, nil
+}
+
+// UpgradeHealth describes health of the host checked after the host is upgraded
+type UpgradeHealth struct {
+	// Uptime is a number of seconds the host is running
+	Uptime int
+	// Errors maps names of the errors happened on the host since the host is started to their numbers
+	Errors map[string]int
+	// ReplicationDelay is a max replication delay of the replicas of the host, in seconds
+	ReplicationDelay int
+}
+
+// GetUpgradeHealth requests uptime, errors and replication delay of the host
+func (f *MetricsFetcher) GetUpgradeHealth(ctx context.Context) (*UpgradeHealth, error) {
+	data, err := f.clickHouseQueryScanRows(
+		ctx,
+		queryUpgradeHealthSQL,
+		func(rows *sql.Rows, data *Table) error {
+			var uptime, delay string
+			if err := rows.Scan(&uptime, &delay); err == nil {
+				*data = append(*data, []string{uptime, delay})
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no health reported")
+	}
+	uptime, _ := strconv.Atoi(data[0][0])
+	delay, _ := strconv.Atoi(data[0][1])
+
+	errors, err := f.clickHouseQueryScanRows(
+		ctx,
+		queryUpgradeErrorsSQL,
+		func(rows *sql.Rows, data *Table) error {
+			var name, value string
+			if err := rows.Scan(&name, &value); err == nil {
+				*data = append(*data, []string{name, value})
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	health := &UpgradeHealth{
+		Uptime:           uptime,
+		Errors:           make(map[string]int),
+		ReplicationDelay: delay,
+	}
+	for _, row := range errors {
+		value, _ := strconv.Atoi(row[1])
+		health.Errors[row[0]] += value
+	}
+	return health, nil
+}
+
+// IsRestartedSince checks whether the host is restarted since the baseline health is taken.
+// Uptime of the restarted host is less than the baseline one or counters of the errors are reset
+func (h *UpgradeHealth) IsRestartedSince(baseline *UpgradeHealth) bool {
+	if h.Uptime < baseline.Uptime {
+		return true
+	}
+	for name, value := range baseline.Errors {
+		if h.Errors[name] < value {
+			return true
+		}
+	}
+	return false
+}
+
+// CountErrorsSince counts errors happened since the baseline health is taken, ignored errors are not counted.
+// All errors are counted in case there is no baseline or the host is restarted since the baseline is taken
+func (h *UpgradeHealth) CountErrorsSince(baseline *UpgradeHealth, ignored func(name string) bool) int {
+	if (baseline != nil) && h.IsRestartedSince(baseline) {
+		baseline = nil
+	}
+	count := 0
+	for name, value := range h.Errors {
+		if ignored(name) {
+			continue
+		}
+		if baseline != nil {
+			value -= baseline.Errors[name]
+		}
+		count += value
+	}
+	return count
+}
+
 // getClickHouseSystemParts requests data sizes from ClickHouse
 func (f *MetricsFetcher) getClickHouseSystemParts(ctx context.Context) (Table, error) {
 	return f.clickHouseQueryScanRows(
@@ -222,6 +461,27 @@ func (f *MetricsFetcher) getClickHouseQuerySystemReplicas(ctx context.Context) (
 	)
 }
 
+// getClickHouseQueryLog requests query latencies aggregated from system.query_log events happened after since.
+// Buckets specify latency histogram bounds, in seconds
+func (f *MetricsFetcher) getClickHouseQueryLog(ctx context.Context, buckets []float64, since int64) (Table, error) {
+	var bounds []string
+	for _, bucket := range buckets {
+		// query_log reports duration in milliseconds
+		bounds = append(bounds, strconv.FormatFloat(bucket*1000, 'f', -1, 64))
+	}
+	return f.clickHouseQueryScanRows(
+		ctx,
+		fmt.Sprintf(queryQueryLogSQLTemplate, since, queryLogLookBehind, queryLogFlushLag, strings.Join(bounds, ", ")),
+		func(rows *sql.Rows, data *Table) error {
+			var user, queryKind, queryHash, count, failed, sum, bucketCounts, lastEventTime string
+			if err := rows.Scan(&user, &queryKind, &queryHash, &count, &failed, &sum, &bucketCounts, &lastEventTime); err == nil {
+				*data = append(*data, []string{user, queryKind, queryHash, count, failed, sum, bucketCounts, lastEventTime})
+			}
+			return nil
+		},
+	)
+}
+
 // getClickHouseQueryMutations requests mutations information from ClickHouse
 func (f *MetricsFetcher) getClickHouseQueryMutations(ctx context.Context) (Table, error) {
 	return f.clickHouseQueryScanRows(
@@ -267,6 +527,38 @@ func (f *MetricsFetcher) getClickHouseQueryDetachedParts(ctx context.Context) (T
 	)
 }
 
+// getClickHouseCustomQuery runs custom metrics query on ClickHouse.
+// Rows consist of values of the label columns followed by values of the value columns of the query
+func (f *MetricsFetcher) getClickHouseCustomQuery(ctx context.Context, query *api.OperatorConfigMetricsQuery) (Table, error) {
+	columns := append(append([]string{}, query.Labels...), query.Values...)
+	return f.clickHouseQueryScanRows(
+		ctx,
+		query.SQL,
+		func(rows *sql.Rows, data *Table) error {
+			names, err := rows.Columns()
+			if err != nil {
+				return err
+			}
+			values := make([]sql.NullString, len(names))
+			dest := make([]any, len(names))
+			for i := range values {
+				dest[i] = &values[i]
+			}
+			if err := rows.Scan(dest...); err != nil {
+				return err
+			}
+			row := make([]string, len(columns))
+			for i, column := range columns {
+				if j := slices.Index(names, column); j >= 0 {
+					row[i] = values[j].String
+				}
+			}
+			*data = append(*data, row)
+			return nil
+		},
+	)
+}
+
 // clickHouseQueryScanRows scan all rows by external scan function
 func (f *MetricsFetcher) clickHouseQueryScanRows(
 	ctx context.Context,
@@ -16,130 +16,199 @@ package clickhouse
 
 import (
 	"context"
+	"slices"
 	"sync"
 	"time"
 
 	log "github.com/golang/glog"
 
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/apis/metrics"
 )
 
-// Collector collects metrics from a single ClickHouse host
+// fetch describes query, which fetches metrics of a host, and the way fetched metrics are written out
+type fetch struct {
+	// name of the fetch, results are cached by
+	name string
+	// fetchTypes are reported as status of the fetch
+	fetchTypes []string
+	query      func(ctx context.Context) (Table, error)
+	write      func(w *CHIPrometheusWriter, data [][]string)
+	// timeout limits the query in addition to the collection timeout, if specified
+	timeout time.Duration
+}
+
+// Collector collects metrics of a host into the host cache
 type Collector struct {
-	fetcher *MetricsFetcher
-	writer  *CHIPrometheusWriter
+	fetcher       *MetricsFetcher
+	keeperFetcher *KeeperMetricsFetcher
+	cache         *HostCache
+	queries       []*api.OperatorConfigMetricsQuery
+	queryLog      *api.OperatorConfigMetricsQueryLog
 }
 
-// NewCollector creates a new Collector instance
-func NewCollector(fetcher *MetricsFetcher, writer *CHIPrometheusWriter) *Collector {
+// NewCollector creates new collector, which runs custom queries in addition to the built-in ones
+// and reads query latencies from query_log, if enabled
+func NewCollector(
+	fetcher *MetricsFetcher,
+	cache *HostCache,
+	queries []*api.OperatorConfigMetricsQuery,
+	queryLog *api.OperatorConfigMetricsQueryLog,
+) *Collector {
 	return &Collector{
-		fetcher: fetcher,
-		writer:  writer,
+		fetcher:  fetcher,
+		cache:    cache,
+		queries:  queries,
+		queryLog: queryLog,
 	}
 }
 
-// CollectHostMetrics runs all metric collectors for a host in parallel
-func (c *Collector) CollectHostMetrics(ctx context.Context, host *metrics.WatchedHost) {
-	wg := sync.WaitGroup{}
-	wg.Add(6)
-	go func() { defer wg.Done(); c.collectSystemMetrics(ctx, host) }()
-	go func() { defer wg.Done(); c.collectSystemParts(ctx, host) }()
-	go func() { defer wg.Done(); c.collectSystemReplicas(ctx, host) }()
-	go func() { defer wg.Done(); c.collectMutations(ctx, host) }()
-	go func() { defer wg.Done(); c.collectSystemDisks(ctx, host) }()
-	go func() { defer wg.Done(); c.collectDetachedParts(ctx, host) }()
-	wg.Wait()
+// NewKeeperCollector creates new collector of ClickHouse Keeper host metrics
+func NewKeeperCollector(fetcher *KeeperMetricsFetcher, cache *HostCache) *Collector {
+	return &Collector{
+		keeperFetcher: fetcher,
+		cache:         cache,
+	}
 }
 
-func (c *Collector) collectSystemMetrics(ctx context.Context, host *metrics.WatchedHost) {
-	log.V(1).Infof("Querying system metrics for host %s", host.Hostname)
-	start := time.Now()
-	metrics, err := c.fetcher.getClickHouseQueryMetrics(ctx)
-	elapsed := time.Since(start)
-	if err == nil {
-		log.V(1).Infof("Extracted [%s] %d system metrics for host %s", elapsed, len(metrics), host.Hostname)
-		c.writer.WriteMetrics(metrics)
-		c.writer.WriteOKFetch("system.metrics")
-	} else {
-		log.Warningf("Error [%s] querying system.metrics for host %s err: %s", elapsed, host.Hostname, err)
-		c.writer.WriteErrorFetch("system.metrics")
+// fetches lists all fetches of a host
+func (c *Collector) fetches() []*fetch {
+	if c.keeperFetcher != nil {
+		return c.keeperFetches()
 	}
+	return slices.Concat(c.builtinFetches(), c.queryLogFetches(), c.customFetches())
 }
 
-func (c *Collector) collectSystemParts(ctx context.Context, host *metrics.WatchedHost) {
-	log.V(1).Infof("Querying table sizes for host %s", host.Hostname)
-	start := time.Now()
-	systemPartsData, err := c.fetcher.getClickHouseSystemParts(ctx)
-	elapsed := time.Since(start)
-	if err == nil {
-		log.V(1).Infof("Extracted [%s] %d table sizes for host %s", elapsed, len(systemPartsData), host.Hostname)
-		c.writer.WriteTableSizes(systemPartsData)
-		c.writer.WriteOKFetch("table sizes")
-		c.writer.WriteSystemParts(systemPartsData)
-		c.writer.WriteOKFetch("system parts")
-	} else {
-		log.Warningf("Error [%s] querying system.parts for host %s err: %s", elapsed, host.Hostname, err)
-		c.writer.WriteErrorFetch("table sizes")
-		c.writer.WriteErrorFetch("system parts")
+// queryLogFetches lists fetches of the query latencies, in case they are enabled
+func (c *Collector) queryLogFetches() []*fetch {
+	if !c.queryLog.IsEnabled() {
+		return nil
+	}
+	return []*fetch{
+		{
+			name:       "system.query_log",
+			fetchTypes: []string{"system.query_log"},
+			query: func(ctx context.Context) (Table, error) {
+				histograms := c.cache.QueryLog()
+				data, err := c.fetcher.getClickHouseQueryLog(ctx, c.queryLog.Buckets, histograms.Since())
+				if err != nil {
+					return nil, err
+				}
+				histograms.Merge(data, len(c.queryLog.Buckets), c.queryLog.TopQueries)
+				return histograms.Table(), nil
+			},
+			write: func(w *CHIPrometheusWriter, data [][]string) {
+				w.WriteQueryLatencies(c.queryLog.Buckets, data)
+			},
+		},
 	}
 }
 
-func (c *Collector) collectSystemReplicas(ctx context.Context, host *metrics.WatchedHost) {
-	log.V(1).Infof("Querying system replicas for host %s", host.Hostname)
-	start := time.Now()
-	systemReplicas, err := c.fetcher.getClickHouseQuerySystemReplicas(ctx)
-	elapsed := time.Since(start)
-	if err == nil {
-		log.V(1).Infof("Extracted [%s] %d system replicas for host %s", elapsed, len(systemReplicas), host.Hostname)
-		c.writer.WriteSystemReplicas(systemReplicas)
-		c.writer.WriteOKFetch("system.replicas")
-	} else {
-		log.Warningf("Error [%s] querying system.replicas for host %s err: %s", elapsed, host.Hostname, err)
-		c.writer.WriteErrorFetch("system.replicas")
+// keeperFetches lists fetches of a ClickHouse Keeper host
+func (c *Collector) keeperFetches() []*fetch {
+	return []*fetch{
+		{
+			name:       "keeper.mntr",
+			fetchTypes: []string{"keeper.mntr"},
+			query:      c.keeperFetcher.getKeeperMntr,
+			write:      (*CHIPrometheusWriter).WriteKeeperMetrics,
+		},
 	}
 }
 
-func (c *Collector) collectMutations(ctx context.Context, host *metrics.WatchedHost) {
-	log.V(1).Infof("Querying mutations for host %s", host.Hostname)
-	start := time.Now()
-	mutations, err := c.fetcher.getClickHouseQueryMutations(ctx)
-	elapsed := time.Since(start)
-	if err == nil {
-		log.V(1).Infof("Extracted [%s] %d mutations for %s", elapsed, len(mutations), host.Hostname)
-		c.writer.WriteMutations(mutations)
-		c.writer.WriteOKFetch("system.mutations")
-	} else {
-		log.Warningf("Error [%s] querying system.mutations for host %s err: %s", elapsed, host.Hostname, err)
-		c.writer.WriteErrorFetch("system.mutations")
+// customFetches lists fetches of the custom queries
+func (c *Collector) customFetches() (fetches []*fetch) {
+	for _, query := range c.queries {
+		fetches = append(fetches, &fetch{
+			name:       "custom." + query.Name,
+			fetchTypes: []string{"custom." + query.Name},
+			query: func(ctx context.Context) (Table, error) {
+				return c.fetcher.getClickHouseCustomQuery(ctx, query)
+			},
+			write: func(w *CHIPrometheusWriter, data [][]string) {
+				w.WriteCustomMetrics(query, data)
+			},
+			timeout: query.Timeout,
+		})
 	}
+	return fetches
 }
 
-func (c *Collector) collectSystemDisks(ctx context.Context, host *metrics.WatchedHost) {
-	log.V(1).Infof("Querying disks for host %s", host.Hostname)
+// builtinFetches lists fetches of the built-in queries
+func (c *Collector) builtinFetches() []*fetch {
+	return []*fetch{
+		{
+			name:       "system.metrics",
+			fetchTypes: []string{"system.metrics"},
+			query:      c.fetcher.getClickHouseQueryMetrics,
+			write:      (*CHIPrometheusWriter).WriteMetrics,
+		},
+		{
+			name:       "system.parts",
+			fetchTypes: []string{"table sizes", "system parts"},
+			query:      c.fetcher.getClickHouseSystemParts,
+			write: func(w *CHIPrometheusWriter, data [][]string) {
+				w.WriteTableSizes(data)
+				w.WriteSystemParts(data)
+			},
+		},
+		{
+			name:       "system.replicas",
+			fetchTypes: []string{"system.replicas"},
+			query:      c.fetcher.getClickHouseQuerySystemReplicas,
+			write:      (*CHIPrometheusWriter).WriteSystemReplicas,
+		},
+		{
+			name:       "system.mutations",
+			fetchTypes: []string{"system.mutations"},
+			query:      c.fetcher.getClickHouseQueryMutations,
+			write:      (*CHIPrometheusWriter).WriteMutations,
+		},
+		{
+			name:       "system.disks",
+			fetchTypes: []string{"system.disks"},
+			query:      c.fetcher.getClickHouseQuerySystemDisks,
+			write:      (*CHIPrometheusWriter).WriteSystemDisks,
+		},
+		{
+			name:       "system.detached_parts",
+			fetchTypes: []string{"system.detached_parts"},
+			query:      c.fetcher.getClickHouseQueryDetachedParts,
+			write:      (*CHIPrometheusWriter).WriteDetachedParts,
+		},
+	}
+}
+
+// CollectHostMetrics runs all fetches of the host concurrently and caches their results
+func (c *Collector) CollectHostMetrics(ctx context.Context, host *metrics.WatchedHost) {
 	start := time.Now()
-	disks, err := c.fetcher.getClickHouseQuerySystemDisks(ctx)
-	elapsed := time.Since(start)
-	if err == nil {
-		log.V(1).Infof("Extracted [%s] %d disks for host %s", elapsed, len(disks), host.Hostname)
-		c.writer.WriteSystemDisks(disks)
-		c.writer.WriteOKFetch("system.disks")
-	} else {
-		log.Warningf("Error [%s] querying system.disks for host %s err: %s", elapsed, host.Hostname, err)
-		c.writer.WriteErrorFetch("system.disks")
+	wg := sync.WaitGroup{}
+	for _, f := range c.fetches() {
+		wg.Add(1)
+		go func(f *fetch) {
+			defer wg.Done()
+			c.collect(ctx, host, f)
+		}(f)
 	}
+	wg.Wait()
+	c.cache.SetCollected(start, time.Since(start))
 }
 
-func (c *Collector) collectDetachedParts(ctx context.Context, host *metrics.WatchedHost) {
-	log.V(1).Infof("Querying detached parts for host %s", host.Hostname)
+// collect runs the fetch and caches its result
+func (c *Collector) collect(ctx context.Context, host *metrics.WatchedHost, f *fetch) {
+	log.V(1).Infof("Querying %s for host %s", f.name, host.Hostname)
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.timeout)
+		defer cancel()
+	}
 	start := time.Now()
-	detachedParts, err := c.fetcher.getClickHouseQueryDetachedParts(ctx)
+	data, err := f.query(ctx)
 	elapsed := time.Since(start)
 	if err == nil {
-		log.V(1).Infof("Extracted [%s] %d detached parts info for host %s", elapsed, len(detachedParts), host.Hostname)
-		c.writer.WriteDetachedParts(detachedParts)
-		c.writer.WriteOKFetch("system.detached_parts")
+		log.V(1).Infof("Extracted [%s] %d rows of %s for host %s", elapsed, len(data), f.name, host.Hostname)
 	} else {
-		log.Warningf("Error [%s] querying system.detached_parts for host %s err: %s", elapsed, host.Hostname, err)
-		c.writer.WriteErrorFetch("system.detached_parts")
+		log.Warningf("Error [%s] querying %s for host %s err: %s", elapsed, f.name, host.Hostname, err)
 	}
+	c.cache.Store(f, data, err, start)
 }
@@ -24,7 +24,9 @@ import (
 
 	core "k8s.io/api/core/v1"
 	kube "k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
 	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
 	"github.com/altinity/clickhouse-operator/pkg/apis/metrics"
@@ -32,6 +34,7 @@ import (
 	chopAPI "github.com/altinity/clickhouse-operator/pkg/client/clientset/versioned"
 	"github.com/altinity/clickhouse-operator/pkg/controller"
 	chiNormalizer "github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	chkNormalizer "github.com/altinity/clickhouse-operator/pkg/model/chk/normalizer"
 	"github.com/altinity/clickhouse-operator/pkg/model/clickhouse"
 	normalizerCommon "github.com/altinity/clickhouse-operator/pkg/model/common/normalizer"
 )
@@ -39,21 +42,51 @@ import (
 // Exporter implements prometheus.Collector interface
 type Exporter struct {
 	collectorTimeout time.Duration
+	collectInterval  time.Duration
 	registry         *CRRegistry
+
+	// caches keep the last good metrics of the watched hosts by host key
+	caches      map[string]*HostCache
+	cachesMutex sync.Mutex
 }
 
+// collectCheckPeriod specifies how often hosts are checked for being due to the next metrics collection
+const collectCheckPeriod = time.Second
+
 // Type compatibility
 var _ prometheus.Collector = &Exporter{}
 
 // NewExporter returns a new instance of Exporter type
-func NewExporter(registry *CRRegistry, collectorTimeout time.Duration) *Exporter {
+func NewExporter(registry *CRRegistry, collectorTimeout, collectInterval time.Duration) *Exporter {
 	return &Exporter{
 		registry:         registry,
 		collectorTimeout: collectorTimeout,
+		collectInterval:  collectInterval,
+		caches:           make(map[string]*HostCache),
 	}
 }
 
-// Collect implements prometheus.Collector Collect method
+// watchedHost is a host along with the CR it belongs to
+type watchedHost struct {
+	cr   *metrics.WatchedCR
+	host *metrics.WatchedHost
+}
+
+// key builds key host cache is kept by
+func (h *watchedHost) key() string {
+	return h.cr.IndexKey() + "/" + h.host.Hostname
+}
+
+// listHosts lists watched hosts, so registry is not locked while hosts are processed
+func (e *Exporter) listHosts() (hosts []*watchedHost) {
+	e.registry.Walk(func(cr *metrics.WatchedCR, _ *metrics.WatchedCluster, host *metrics.WatchedHost) {
+		hosts = append(hosts, &watchedHost{cr: cr, host: host})
+	})
+	return hosts
+}
+
+// Collect implements prometheus.Collector Collect method.
+// Metrics of the watched hosts are served from the host caches, so scrapes do not query ClickHouse
 func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	if ch == nil {
 		log.Warning("Prometheus channel is closed. Unable to write metrics")
@@ -67,21 +100,11 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 		log.V(1).Infof("Collect completed [%s]", time.Since(start))
 	}()
 
-	// Collection process should have limited duration
-	ctx, cancel := context.WithTimeout(context.Background(), e.collectorTimeout)
-	defer cancel()
-
-	log.V(1).Infof("Launching host collectors [%s]", time.Since(start))
-
-	var wg = sync.WaitGroup{}
-	e.registry.Walk(func(cr *metrics.WatchedCR, _ *metrics.WatchedCluster, host *metrics.WatchedHost) {
-		wg.Add(1)
-		go func(ctx context.Context, cr *metrics.WatchedCR, host *metrics.WatchedHost, ch chan<- prometheus.Metric) {
-			defer wg.Done()
-			e.collectHostMetrics(ctx, cr, host, ch)
-		}(ctx, cr, host, ch)
-	})
-	wg.Wait()
+	for _, host := range e.listHosts() {
+		if cache := e.getHostCache(host.key()); cache != nil {
+			cache.Write(NewCHIPrometheusWriter(ch, host.cr, host.host), start)
+		}
+	}
 }
 
 // Describe implements prometheus.Collector Describe method
@@ -89,13 +112,92 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	prometheus.DescribeByCollect(e, ch)
 }
 
-// collectHostMetrics collects metrics from one host and writes them into chan
-func (e *Exporter) collectHostMetrics(ctx context.Context, chi *metrics.WatchedCR, host *metrics.WatchedHost, c chan<- prometheus.Metric) {
-	collector := NewCollector(
+// Run collects metrics of the watched hosts in background, each host on its own schedule, until ctx is done
+func (e *Exporter) Run(ctx context.Context) {
+	log.V(1).Infof("Background metrics collection started. Interval: %s", e.collectInterval)
+	ticker := time.NewTicker(collectCheckPeriod)
+	defer ticker.Stop()
+	for {
+		e.collectDueHosts(ctx)
+		select {
+		case <-ctx.Done():
+			log.V(1).Info("Background metrics collection stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collectDueHosts launches metrics collection of the hosts, which are due to it,
+// and drops caches of the hosts which are not watched any more
+func (e *Exporter) collectDueHosts(ctx context.Context) {
+	now := time.Now()
+	hosts := e.listHosts()
+
+	e.cachesMutex.Lock()
+	watched := make(map[string]bool)
+	for _, host := range hosts {
+		key := host.key()
+		watched[key] = true
+		if _, ok := e.caches[key]; !ok {
+			e.caches[key] = NewHostCache()
+		}
+	}
+	for key := range e.caches {
+		if !watched[key] {
+			delete(e.caches, key)
+		}
+	}
+	e.cachesMutex.Unlock()
+
+	for _, host := range hosts {
+		cache := e.getHostCache(host.key())
+		if !cache.IsDue(e.collectInterval, now) || !cache.StartCollect() {
+			// Either collected recently or collection is still running
+			continue
+		}
+		go e.collectHostMetrics(ctx, host.cr, host.host, cache)
+	}
+}
+
+// getHostCache gets cache of the host by host key
+func (e *Exporter) getHostCache(key string) *HostCache {
+	e.cachesMutex.Lock()
+	defer e.cachesMutex.Unlock()
+	return e.caches[key]
+}
+
+// collectHostMetrics collects metrics of the host into the host cache. Collection has limited duration
+func (e *Exporter) collectHostMetrics(ctx context.Context, cr *metrics.WatchedCR, host *metrics.WatchedHost, cache *HostCache) {
+	defer cache.FinishCollect()
+
+	ctx, cancel := context.WithTimeout(ctx, e.collectorTimeout)
+	defer cancel()
+
+	if cr.IsKeeper() {
+		NewKeeperCollector(e.newKeeperHostFetcher(host), cache).CollectHostMetrics(ctx, host)
+		return
+	}
+	NewCollector(
 		e.newHostFetcher(host),
-		NewCHIPrometheusWriter(c, chi, host),
-	)
-	collector.CollectHostMetrics(ctx, host)
+		cache,
+		e.selectQueries(cr),
+		&chop.Config().ClickHouse.Metrics.QueryLog,
+	).CollectHostMetrics(ctx, host)
+}
+
+// selectQueries selects custom metrics queries to be run on hosts of the CR
+func (e *Exporter) selectQueries(cr *metrics.WatchedCR) (queries []*api.OperatorConfigMetricsQuery) {
+	for _, query := range chop.Config().ClickHouse.Metrics.Queries {
+		if !query.IsValid() {
+			log.V(1).Infof("Skip incomplete metrics query: %s", query.GetName())
+			continue
+		}
+		if query.Selects(cr.GetLabels()) {
+			queries = append(queries, query)
+		}
+	}
+	return queries
 }
 
 // newHostFetcher returns new Metrics Fetcher for specified host
@@ -125,6 +227,14 @@ func (e *Exporter) newHostFetcher(host *metrics.WatchedHost) *MetricsFetcher {
 	)
 }
 
+func (e *Exporter) newKeeperHostFetcher(host *metrics.WatchedHost) *KeeperMetricsFetcher {
+	port := host.ZKPort
+	if !types.IsPortAssigned(port) {
+		port = api.KpDefaultZKPortNumber
+	}
+	return NewKeeperMetricsFetcher(host.Hostname, port)
+}
+
 // DiscoveryWatchedCHIs discovers all ClickHouseInstallation objects available for monitoring and adds them to watched list
 func (e *Exporter) DiscoveryWatchedCHIs(kubeClient kube.Interface, chopClient *chopAPI.Clientset) {
 	// Get all CHI objects from watched namespace(s)
@@ -153,7 +263,7 @@ func (e *Exporter) processDiscoveredCR(kubeClient kube.Interface, chi *api.Click
 	log.V(1).Infof("Add discovered CHI: %s/%s", chi.Namespace, chi.Name)
 	normalizer := chiNormalizer.New(func(namespace, name string) (*core.Secret, error) {
 		return kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, controller.NewGetOptions())
-	})
+	}, nil)
 
 	normalized, _ := normalizer.CreateTemplated(chi, normalizerCommon.NewOptions[api.ClickHouseInstallation]())
 
@@ -161,11 +271,40 @@ func (e *Exporter) processDiscoveredCR(kubeClient kube.Interface, chi *api.Click
 	e.registry.AddCR(watchedCR)
 }
 
-func (e *Exporter) shouldWatchCR(chi *api.ClickHouseInstallation) bool {
-	if chi.IsStopped() {
-		log.V(1).Infof("CHI %s/%s is stopped, unable to watch it", chi.Namespace, chi.Name)
+func (e *Exporter) shouldWatchCR(cr api.ICustomResource) bool {
+	if cr.IsStopped() {
+		log.V(1).Infof("CR %s/%s is stopped, unable to watch it", cr.GetNamespace(), cr.GetName())
 		return false
 	}
 
 	return true
 }
+
+// DiscoveryWatchedCHKs discovers all ClickHouseKeeperInstallation objects available for monitoring and adds them to watched list
+func (e *Exporter) DiscoveryWatchedCHKs(keeperClient client.Client) {
+	// Get all CHK objects from watched namespace(s)
+	watchedNamespace := chop.Config().GetInformerNamespace()
+	list := &apiChk.ClickHouseKeeperInstallationList{}
+	if err := keeperClient.List(context.TODO(), list, client.InNamespace(watchedNamespace)); err != nil {
+		log.V(1).Infof("Error read ClickHouseKeeperInstallations %v", err)
+		return
+	}
+
+	// Walk over the list of ClickHouseKeeperInstallation objects and add them as watched
+	for i := range list.Items {
+		e.processDiscoveredCHK(&list.Items[i])
+	}
+}
+
+func (e *Exporter) processDiscoveredCHK(chk *apiChk.ClickHouseKeeperInstallation) {
+	if !e.shouldWatchCR(chk) {
+		log.V(1).Infof("Skip discovered CHK: %s/%s", chk.Namespace, chk.Name)
+		return
+	}
+
+	log.V(1).Infof("Add discovered CHK: %s/%s", chk.Namespace, chk.Name)
+	normalized, _ := chkNormalizer.New().CreateTemplated(chk, normalizerCommon.NewOptions[apiChk.ClickHouseKeeperInstallation]())
+
+	watchedCR := metrics.NewWatchedCR(normalized)
+	e.registry.AddCR(watchedCR)
+}
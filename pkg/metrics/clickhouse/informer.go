@@ -0,0 +1,123 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"time"
+
+	log "github.com/golang/glog"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	kube "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/metrics"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	chopAPI "github.com/altinity/clickhouse-operator/pkg/client/clientset/versioned"
+	chopInformers "github.com/altinity/clickhouse-operator/pkg/client/informers/externalversions"
+)
+
+// informerResyncPeriod specifies how often watched CRs are re-read from informers caches
+const informerResyncPeriod = 10 * time.Minute
+
+// WatchCRs keeps watched list in sync with ClickHouseInstallation and ClickHouseKeeperInstallation objects
+// by means of informers until ctx is done.
+// Used in read-only mode, when the operator does not inform exporter about watched CRs
+func (e *Exporter) WatchCRs(
+	ctx context.Context,
+	kubeClient kube.Interface,
+	chopClient *chopAPI.Clientset,
+	keeperClient client.WithWatch,
+) {
+	namespace := chop.Config().GetInformerNamespace()
+	log.V(1).Infof("Watch CRs with informers in namespace: '%s'", namespace)
+
+	chopInformerFactory := chopInformers.NewSharedInformerFactoryWithOptions(
+		chopClient,
+		informerResyncPeriod,
+		chopInformers.WithNamespace(namespace),
+	)
+	_, _ = chopInformerFactory.Clickhouse().V1().ClickHouseInstallations().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			e.watchCHI(kubeClient, obj.(*api.ClickHouseInstallation))
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			e.watchCHI(kubeClient, obj.(*api.ClickHouseInstallation))
+		},
+		DeleteFunc: e.unwatchCR,
+	})
+	chopInformerFactory.Start(ctx.Done())
+
+	keeperInformer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options meta.ListOptions) (runtime.Object, error) {
+				list := &apiChk.ClickHouseKeeperInstallationList{}
+				err := keeperClient.List(ctx, list, client.InNamespace(namespace), &client.ListOptions{Raw: &options})
+				return list, err
+			},
+			WatchFunc: func(options meta.ListOptions) (watch.Interface, error) {
+				list := &apiChk.ClickHouseKeeperInstallationList{}
+				return keeperClient.Watch(ctx, list, client.InNamespace(namespace), &client.ListOptions{Raw: &options})
+			},
+		},
+		&apiChk.ClickHouseKeeperInstallation{},
+		informerResyncPeriod,
+		cache.Indexers{},
+	)
+	_, _ = keeperInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			e.watchCHK(obj.(*apiChk.ClickHouseKeeperInstallation))
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			e.watchCHK(obj.(*apiChk.ClickHouseKeeperInstallation))
+		},
+		DeleteFunc: e.unwatchCR,
+	})
+	go keeperInformer.Run(ctx.Done())
+}
+
+// watchCHI adds CHI to watched list, stopped CHI is removed from watched list
+func (e *Exporter) watchCHI(kubeClient kube.Interface, chi *api.ClickHouseInstallation) {
+	if !e.shouldWatchCR(chi) {
+		e.registry.RemoveCR(metrics.NewWatchedCR(chi))
+		return
+	}
+	e.processDiscoveredCR(kubeClient, chi)
+}
+
+// watchCHK adds CHK to watched list, stopped CHK is removed from watched list
+func (e *Exporter) watchCHK(chk *apiChk.ClickHouseKeeperInstallation) {
+	if !e.shouldWatchCR(chk) {
+		e.registry.RemoveCR(metrics.NewWatchedCR(chk))
+		return
+	}
+	e.processDiscoveredCHK(chk)
+}
+
+// unwatchCR removes deleted CR from watched list
+func (e *Exporter) unwatchCR(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if cr, ok := obj.(api.ICustomResource); ok {
+		e.registry.RemoveCR(metrics.NewWatchedCR(cr))
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/altinity/clickhouse-operator/pkg/model/zookeeper"
+)
+
+// KeeperMetricsFetcher specifies ClickHouse Keeper host metrics fetcher object
+type KeeperMetricsFetcher struct {
+	// address of the Keeper client port
+	address string
+}
+
+// NewKeeperMetricsFetcher creates new Keeper metrics fetcher object
+func NewKeeperMetricsFetcher(hostname string, port int32) *KeeperMetricsFetcher {
+	return &KeeperMetricsFetcher{
+		address: fmt.Sprintf("%s:%d", hostname, port),
+	}
+}
+
+// getKeeperMntr requests monitoring variables of the Keeper host with "mntr" command
+// Expected data structure: name, value
+func (f *KeeperMetricsFetcher) getKeeperMntr(ctx context.Context) (Table, error) {
+	mntr, err := zookeeper.Mntr(ctx, f.address)
+	if err != nil {
+		return nil, err
+	}
+	data := newTable()
+	for _, name := range slices.Sorted(maps.Keys(mntr)) {
+		data = append(data, []string{name, mntr[name]})
+	}
+	return data, nil
+}
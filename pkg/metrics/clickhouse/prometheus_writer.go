@@ -17,12 +17,14 @@ package clickhouse
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	log "github.com/golang/glog"
 	// log "k8s.io/klog"
 	"github.com/prometheus/client_golang/prometheus"
 
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/apis/metrics"
 	"github.com/altinity/clickhouse-operator/pkg/chop"
 	"github.com/altinity/clickhouse-operator/pkg/metrics/operator"
@@ -212,6 +214,104 @@ func (w *CHIPrometheusWriter) WriteDetachedParts(data [][]string) {
 	}
 }
 
+// WriteCustomMetrics writes metrics fetched by custom query.
+// Expected data structure: values of the label columns followed by values of the value columns of the query
+func (w *CHIPrometheusWriter) WriteCustomMetrics(query *api.OperatorConfigMetricsQuery, data [][]string) {
+	metricType := prometheus.GaugeValue
+	if query.IsCounter() {
+		metricType = prometheus.CounterValue
+	}
+	for _, row := range data {
+		if len(row) < len(query.Labels)+len(query.Values) {
+			continue
+		}
+		labels := make(map[string]string)
+		for i, label := range query.Labels {
+			labels[label] = row[i]
+		}
+		for i, value := range query.Values {
+			w.writeSingleMetricToPrometheus(
+				query.GetMetricName(value), query.Description,
+				metricType, row[len(query.Labels)+i],
+				labels)
+		}
+	}
+}
+
+// WriteQueryLatencies writes query latency histograms along with numbers of failed queries.
+// Bounds specify upper bounds of the histogram buckets, in seconds.
+// Expected data structure: user, query_kind, query_hash, count, failed, sum, buckets
+func (w *CHIPrometheusWriter) WriteQueryLatencies(bounds []float64, data [][]string) {
+	for _, row := range data {
+		if len(row) < 7 {
+			continue
+		}
+		bucketCounts := strings.Split(row[6], ",")
+		if len(bucketCounts) != len(bounds) {
+			continue
+		}
+		buckets := make(map[float64]uint64)
+		for i, bound := range bounds {
+			buckets[bound], _ = strconv.ParseUint(bucketCounts[i], 10, 64)
+		}
+		count, _ := strconv.ParseUint(row[3], 10, 64)
+		sum, _ := strconv.ParseFloat(row[5], 64)
+		labels := map[string]string{
+			"user":       row[0],
+			"query_kind": row[1],
+			"query_hash": row[2],
+		}
+		w.writeHistogramToPrometheus(
+			"query_duration_seconds", "duration of the queries finished by ClickHouse according to query_log",
+			count, sum, buckets,
+			labels)
+		w.writeSingleMetricToPrometheus(
+			"query_failed", "number of the queries failed by ClickHouse according to query_log",
+			prometheus.CounterValue, row[4],
+			labels)
+	}
+}
+
+// WriteKeeperMetrics writes metrics reported by "mntr" command of ClickHouse Keeper.
+// Expected data structure: name, value
+func (w *CHIPrometheusWriter) WriteKeeperMetrics(data [][]string) {
+	for _, metric := range data {
+		if len(metric) < 2 {
+			continue
+		}
+		name, value := metric[0], metric[1]
+		if name == "zk_server_state" {
+			isLeader := "0"
+			if value == "leader" || value == "standalone" {
+				isLeader = "1"
+			}
+			w.writeSingleMetricToPrometheus(
+				"keeper_is_leader", "whether Keeper host is the leader of the ensemble 1 - leader, 0 - follower",
+				prometheus.GaugeValue, isLeader,
+				nil)
+			continue
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			// Non-numeric values, such as version, are not metrics
+			continue
+		}
+		metricType := prometheus.GaugeValue
+		if keeperCounters[name] {
+			metricType = prometheus.CounterValue
+		}
+		w.writeSingleMetricToPrometheus(
+			"keeper_"+strings.TrimPrefix(name, "zk_"), fmt.Sprintf("%s reported by Keeper mntr command", name),
+			metricType, value,
+			nil)
+	}
+}
+
+// keeperCounters lists Keeper mntr variables, which are counters. The rest are gauges
+var keeperCounters = map[string]bool{
+	"zk_packets_received": true,
+	"zk_packets_sent":     true,
+}
+
 // WriteErrorFetch writes error fetch
 func (w *CHIPrometheusWriter) WriteErrorFetch(fetchType string) {
 	labels := map[string]string{
@@ -234,6 +334,25 @@ func (w *CHIPrometheusWriter) WriteOKFetch(fetchType string) {
 		labels)
 }
 
+// WriteFetchAge writes age of the cached result of the fetch
+func (w *CHIPrometheusWriter) WriteFetchAge(fetchType string, age time.Duration) {
+	labels := map[string]string{
+		"fetch_type": fetchType,
+	}
+	w.writeSingleMetricToPrometheus(
+		"metric_fetch_age_seconds", "seconds since the served metrics were fetched from ClickHouse",
+		prometheus.GaugeValue, fmt.Sprintf("%f", age.Seconds()),
+		labels)
+}
+
+// WriteCollectDuration writes duration of the latest metrics collection from the host
+func (w *CHIPrometheusWriter) WriteCollectDuration(duration time.Duration) {
+	w.writeSingleMetricToPrometheus(
+		"metric_collect_duration_seconds", "duration of the latest metrics collection from ClickHouse",
+		prometheus.GaugeValue, fmt.Sprintf("%f", duration.Seconds()),
+		nil)
+}
+
 func (w *CHIPrometheusWriter) appendHostLabel(labels map[string]string) map[string]string {
 	return util.MergeStringMapsOverwrite(labels, map[string]string{
 		"hostname": w.host.Hostname,
@@ -271,7 +390,36 @@ func (w *CHIPrometheusWriter) writeSingleMetricToPrometheus(
 		log.Warningf("Error creating metric: %s err: %s", name, err)
 		return
 	}
-	// Send metric into channel
+	w.sendToPrometheus(name, metric)
+}
+
+func (w *CHIPrometheusWriter) writeHistogramToPrometheus(
+	name string,
+	desc string,
+	count uint64,
+	sum float64,
+	buckets map[float64]uint64,
+	metricLabels map[string]string,
+) {
+	// Prepare metrics labels
+	labelNames, labelValues := w.prepareLabels(metricLabels)
+	// Prepare histogram from buckets and labels
+	metric, err := prometheus.NewConstHistogram(
+		newMetricDescriptor(name, desc, labelNames),
+		count,
+		sum,
+		buckets,
+		labelValues...,
+	)
+	if err != nil {
+		log.Warningf("Error creating histogram: %s err: %s", name, err)
+		return
+	}
+	w.sendToPrometheus(name, metric)
+}
+
+// sendToPrometheus sends metric into channel
+func (w *CHIPrometheusWriter) sendToPrometheus(name string, metric prometheus.Metric) {
 	select {
 	case w.out <- metric:
 	case <-time.After(writeMetricWaitTimeout):
@@ -0,0 +1,189 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// queryLogOtherQueries is a query hash latencies of the queries out of the top queries are accounted under
+const queryLogOtherQueries = "other"
+
+const (
+	// queryLogRankDecay is a factor ranks of the queries decay by on every merge, so top queries follow recent load
+	queryLogRankDecay = 0.5
+	// queryLogRankMin is a rank queries not executed for a while are aged out below
+	queryLogRankMin = 0.01
+	// queryLogRankCandidates is a number of ranked queries per top query.
+	// Queries ranked below the top are kept as candidates, so they are able to climb up into the top
+	queryLogRankCandidates = 2
+)
+
+// queryLogKey identifies latency histogram
+type queryLogKey struct {
+	user      string
+	queryKind string
+	queryHash string
+}
+
+// queryLogHistogram is a cumulative latency histogram
+type queryLogHistogram struct {
+	count  uint64
+	failed uint64
+	sum    float64
+	// buckets keep cumulative counts of the queries not longer than bucket bounds
+	buckets []uint64
+}
+
+// QueryLogHistograms accumulates query latency histograms of a host read incrementally from system.query_log
+type QueryLogHistograms struct {
+	mutex sync.Mutex
+	// since is unix time of the latest query_log event accounted
+	since      int64
+	histograms map[queryLogKey]*queryLogHistogram
+	// ranks keep decayed counts of normalized queries. Top ranked queries have histograms of their own
+	ranks map[string]float64
+}
+
+// NewQueryLogHistograms creates new query latency histograms
+func NewQueryLogHistograms() *QueryLogHistograms {
+	return &QueryLogHistograms{
+		histograms: make(map[queryLogKey]*queryLogHistogram),
+		ranks:      make(map[string]float64),
+	}
+}
+
+// Since gets unix time of the latest query_log event accounted
+func (h *QueryLogHistograms) Since() int64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.since
+}
+
+// Merge accounts query latencies aggregated from query_log.
+// Normalized queries are ranked by counts decayed on every merge, top ranked queries up to topQueries
+// get histograms of their own. Latencies of the rest of the queries are accounted under "other" query hash.
+// Histograms of the queries dropped out of the top are removed, so their series go stale.
+// Expected data structure: user, query_kind, query_hash, count, failed, sum, buckets, last_event_time
+func (h *QueryLogHistograms) Merge(data Table, buckets int, topQueries int) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	data = slices.DeleteFunc(slices.Clone(data), func(row []string) bool {
+		return (len(row) < 8) || (len(strings.Split(row[6], ",")) != buckets)
+	})
+
+	top := h.rank(data, topQueries)
+	for key := range h.histograms {
+		if (key.queryHash != queryLogOtherQueries) && !top[key.queryHash] {
+			delete(h.histograms, key)
+		}
+	}
+
+	for _, row := range data {
+		queryHash := row[2]
+		if !top[queryHash] {
+			queryHash = queryLogOtherQueries
+		}
+
+		key := queryLogKey{user: row[0], queryKind: row[1], queryHash: queryHash}
+		histogram, ok := h.histograms[key]
+		if !ok {
+			histogram = &queryLogHistogram{buckets: make([]uint64, buckets)}
+			h.histograms[key] = histogram
+		}
+		count, _ := strconv.ParseUint(row[3], 10, 64)
+		failed, _ := strconv.ParseUint(row[4], 10, 64)
+		sum, _ := strconv.ParseFloat(row[5], 64)
+		histogram.count += count
+		histogram.failed += failed
+		histogram.sum += sum
+		for i, bucketCount := range strings.Split(row[6], ",") {
+			value, _ := strconv.ParseUint(bucketCount, 10, 64)
+			histogram.buckets[i] += value
+		}
+
+		if lastEventTime, err := strconv.ParseInt(row[7], 10, 64); err == nil && lastEventTime > h.since {
+			h.since = lastEventTime
+		}
+	}
+}
+
+// rank updates ranks of the queries with counts of the merged data and lists top ranked queries
+func (h *QueryLogHistograms) rank(data Table, topQueries int) map[string]bool {
+	for queryHash := range h.ranks {
+		h.ranks[queryHash] *= queryLogRankDecay
+	}
+	for _, row := range data {
+		count, _ := strconv.ParseUint(row[3], 10, 64)
+		h.ranks[row[2]] += float64(count)
+	}
+
+	var ranked []string
+	for queryHash, rank := range h.ranks {
+		if rank < queryLogRankMin {
+			delete(h.ranks, queryHash)
+			continue
+		}
+		ranked = append(ranked, queryHash)
+	}
+	slices.SortFunc(ranked, func(a, b string) int {
+		if c := cmp.Compare(h.ranks[b], h.ranks[a]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	for _, queryHash := range ranked[min(len(ranked), queryLogRankCandidates*topQueries):] {
+		delete(h.ranks, queryHash)
+	}
+
+	top := make(map[string]bool)
+	for _, queryHash := range ranked[:min(len(ranked), topQueries)] {
+		top[queryHash] = true
+	}
+	return top
+}
+
+// Table lists accumulated histograms
+// Result data structure: user, query_kind, query_hash, count, failed, sum, buckets
+func (h *QueryLogHistograms) Table() Table {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	data := newTable()
+	for key, histogram := range h.histograms {
+		var bucketCounts []string
+		for _, value := range histogram.buckets {
+			bucketCounts = append(bucketCounts, strconv.FormatUint(value, 10))
+		}
+		data = append(data, []string{
+			key.user,
+			key.queryKind,
+			key.queryHash,
+			strconv.FormatUint(histogram.count, 10),
+			strconv.FormatUint(histogram.failed, 10),
+			strconv.FormatFloat(histogram.sum, 'f', -1, 64),
+			strings.Join(bucketCounts, ","),
+		})
+	}
+	slices.SortFunc(data, func(a, b []string) int {
+		return slices.Compare(a[:3], b[:3])
+	})
+	return data
+}
@@ -0,0 +1,62 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryLogHistogramsMerge(t *testing.T) {
+	histograms := NewQueryLogHistograms()
+	require.Equal(t, int64(0), histograms.Since())
+
+	histograms.Merge(Table{
+		{"default", "Select", "101", "3", "1", "0.6", "1,2,3", "1700000010"},
+		{"default", "Insert", "102", "2", "0", "0.2", "2,2,2", "1700000020"},
+		{"default", "Select", "103", "1", "0", "5", "0,0,1", "1700000005"},
+	}, 3, 2)
+	require.Equal(t, int64(1700000020), histograms.Since())
+
+	histograms.Merge(Table{
+		{"default", "Select", "101", "1", "0", "0.1", "1,1,1", "1700000030"},
+		{"default", "Select", "104", "1", "1", "1", "0,1,1", "1700000025"},
+		{"default", "Select", "105", "1", "0", "0.5", "broken", "1700000040"},
+	}, 3, 2)
+	require.Equal(t, int64(1700000030), histograms.Since())
+
+	require.Equal(t, Table{
+		{"default", "Insert", "102", "2", "0", "0.2", "2,2,2"},
+		{"default", "Select", "101", "4", "1", "0.7", "2,3,4"},
+		{"default", "Select", "other", "2", "1", "6", "0,1,2"},
+	}, histograms.Table())
+}
+
+func TestQueryLogHistogramsMergeReranks(t *testing.T) {
+	histograms := NewQueryLogHistograms()
+
+	histograms.Merge(Table{
+		{"default", "Select", "101", "10", "0", "1", "10,10,10", "1700000010"},
+	}, 3, 1)
+	histograms.Merge(Table{
+		{"default", "Select", "102", "5", "0", "0.5", "5,5,5", "1700000020"},
+	}, 3, 1)
+	require.Equal(t, Table{
+		{"default", "Select", "101", "10", "0", "1", "10,10,10"},
+		{"default", "Select", "other", "5", "0", "0.5", "5,5,5"},
+	}, histograms.Table())
+
+	// Query 101 is not executed anymore, so it is outranked by query 102 and its histogram is dropped
+	histograms.Merge(Table{
+		{"default", "Select", "102", "5", "0", "0.5", "5,5,5", "1700000030"},
+	}, 3, 1)
+	require.Equal(t, Table{
+		{"default", "Select", "102", "5", "0", "0.5", "5,5,5"},
+		{"default", "Select", "other", "5", "0", "0.5", "5,5,5"},
+	}, histograms.Table())
+
+	// Ranks of the queries not executed anymore age out
+	for i := 0; i < 20; i++ {
+		histograms.Merge(nil, 3, 1)
+	}
+	require.Empty(t, histograms.ranks)
+}
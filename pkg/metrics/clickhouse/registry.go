@@ -49,7 +49,7 @@ func (r *CRRegistry) AddHost(req *HostRequest) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	crKey := (&metrics.WatchedCR{Namespace: req.CRNamespace, Name: req.CRName}).IndexKey()
+	crKey := req.crIndexKey()
 	cr, ok := r.index.get(crKey)
 	if !ok || cr == nil {
 		return fmt.Errorf("CR not found: %s", crKey)
@@ -98,7 +98,7 @@ func (r *CRRegistry) RemoveHost(req *HostRequest) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	crKey := (&metrics.WatchedCR{Namespace: req.CRNamespace, Name: req.CRName}).IndexKey()
+	crKey := req.crIndexKey()
 	cr, ok := r.index.get(crKey)
 	if !ok || cr == nil {
 		log.V(1).Infof("Registry: Cannot remove host, CR not found: %s", crKey)
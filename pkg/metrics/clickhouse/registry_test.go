@@ -0,0 +1,38 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/metrics"
+)
+
+func TestRegistryKeepsCHKApartFromCHI(t *testing.T) {
+	registry := NewCRRegistry()
+	registry.AddCR(&metrics.WatchedCR{Namespace: "ns", Name: "same"})
+	registry.AddCR(&metrics.WatchedCR{Kind: apiChk.ClickHouseKeeperInstallationCRDResourceKind, Namespace: "ns", Name: "same"})
+	require.Len(t, registry.List(), 2)
+
+	host := &metrics.WatchedHost{Hostname: "keeper-0", ZKPort: 2181}
+	require.NoError(t, registry.AddHost(&HostRequest{
+		CRKind:      apiChk.ClickHouseKeeperInstallationCRDResourceKind,
+		CRNamespace: "ns",
+		CRName:      "same",
+		ClusterName: "cluster",
+		Host:        host,
+	}))
+
+	var hosts []*metrics.WatchedHost
+	registry.Walk(func(cr *metrics.WatchedCR, _ *metrics.WatchedCluster, h *metrics.WatchedHost) {
+		require.True(t, cr.IsKeeper())
+		hosts = append(hosts, h)
+	})
+	require.Equal(t, []*metrics.WatchedHost{host}, hosts)
+
+	registry.RemoveCR(&metrics.WatchedCR{Namespace: "ns", Name: "same"})
+	list := registry.List()
+	require.Len(t, list, 1)
+	require.Equal(t, apiChk.ClickHouseKeeperInstallationCRDResourceKind, list[0].GetKind())
+}
@@ -0,0 +1,150 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	authentication "k8s.io/api/authentication/v1"
+	kube "k8s.io/client-go/kubernetes"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/controller"
+)
+
+const (
+	// tokenReviewTimeout limits TokenReview request
+	tokenReviewTimeout = 10 * time.Second
+	// tokenReviewCacheTTL specifies how long result of a successful TokenReview is reused
+	tokenReviewCacheTTL = time.Minute
+	// serviceAccountUsernamePrefix prefixes usernames service accounts are authenticated as
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
+)
+
+// RESTAuthenticator authenticates requests to the REST API
+type RESTAuthenticator struct {
+	kubeClient kube.Interface
+	// usernames lists usernames of the service accounts allowed to call REST API
+	usernames map[string]bool
+	// requireClientCert specifies whether verified client certificate is required
+	requireClientCert bool
+	// tokenAuthentication specifies whether bearer token is required
+	tokenAuthentication bool
+
+	// reviewed keeps expiration of the successfully reviewed tokens by token hash
+	reviewed map[[sha256.Size]byte]time.Time
+	mutex    sync.Mutex
+}
+
+// NewRESTAuthenticator creates new REST API authenticator
+func NewRESTAuthenticator(config *api.OperatorConfigMetricsAPI, kubeClient kube.Interface) *RESTAuthenticator {
+	usernames := make(map[string]bool)
+	for _, account := range config.ServiceAccounts {
+		namespace, name, found := strings.Cut(account, "/")
+		if !found {
+			log.Warningf("Skip malformed service account: %s. Expected format: namespace/name", account)
+			continue
+		}
+		usernames[serviceAccountUsernamePrefix+namespace+":"+name] = true
+	}
+	return &RESTAuthenticator{
+		kubeClient:          kubeClient,
+		usernames:           usernames,
+		requireClientCert:   config.IsMutualTLS(),
+		tokenAuthentication: config.IsTokenAuthentication(),
+		reviewed:            make(map[[sha256.Size]byte]time.Time),
+	}
+}
+
+// Authenticate checks whether request is allowed to call REST API
+func (a *RESTAuthenticator) Authenticate(r *http.Request) error {
+	if a == nil {
+		return nil
+	}
+	if a.requireClientCert {
+		if (r.TLS == nil) || (len(r.TLS.VerifiedChains) == 0) {
+			return fmt.Errorf("verified client certificate is required")
+		}
+	}
+	if a.tokenAuthentication {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || (token == "") {
+			return fmt.Errorf("bearer token is required")
+		}
+		return a.reviewToken(r.Context(), token)
+	}
+	return nil
+}
+
+// reviewToken checks with TokenReview whether the token belongs to one of the allowed service accounts
+func (a *RESTAuthenticator) reviewToken(ctx context.Context, token string) error {
+	key := sha256.Sum256([]byte(token))
+	if a.isReviewed(key) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tokenReviewTimeout)
+	defer cancel()
+	review, err := a.kubeClient.AuthenticationV1().TokenReviews().Create(
+		ctx,
+		&authentication.TokenReview{
+			Spec: authentication.TokenReviewSpec{
+				Token: token,
+			},
+		},
+		controller.NewCreateOptions(),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to review token: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return fmt.Errorf("token is not authenticated: %s", review.Status.Error)
+	}
+	if !a.usernames[review.Status.User.Username] {
+		return fmt.Errorf("%s is not allowed to call REST API", review.Status.User.Username)
+	}
+
+	a.setReviewed(key)
+	return nil
+}
+
+// isReviewed checks whether the token was successfully reviewed recently
+func (a *RESTAuthenticator) isReviewed(key [sha256.Size]byte) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	expiration, ok := a.reviewed[key]
+	return ok && time.Now().Before(expiration)
+}
+
+// setReviewed remembers the successfully reviewed token
+func (a *RESTAuthenticator) setReviewed(key [sha256.Size]byte) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	now := time.Now()
+	// Drop expired tokens, which are not used any more
+	for k, expiration := range a.reviewed {
+		if now.After(expiration) {
+			delete(a.reviewed, k)
+		}
+	}
+	a.reviewed[key] = now.Add(tokenReviewCacheTTL)
+}
@@ -16,14 +16,35 @@ package clickhouse
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strings"
+
+	log "github.com/golang/glog"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
 )
 
+// serviceAccountTokenFile specifies where service account token is mounted into the pod
+const serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
 func makeRESTCall(restReq *RESTRequest, method string) error {
+	apiConfig := &chop.Config().Metrics.API
+	if apiConfig.IsReadOnly() {
+		// Exporter discovers watched CRs on its own
+		log.V(2).Infof("Exporter REST API is read-only, skip %s request", method)
+		return nil
+	}
+
 	url := "http://127.0.0.1:8888/chi"
+	if apiConfig.IsTLS() {
+		url = "https://127.0.0.1:8888/chi"
+	}
 
 	payload, err := json.Marshal(restReq)
 	if err != nil {
@@ -34,13 +55,63 @@ func makeRESTCall(restReq *RESTRequest, method string) error {
 	if err != nil {
 		return err
 	}
-	_, err = doRequest(httpReq)
+	if apiConfig.IsTokenAuthentication() {
+		// Token is read on each request, since mounted token is rotated
+		token, err := os.ReadFile(serviceAccountTokenFile)
+		if err != nil {
+			return fmt.Errorf("unable to read service account token: %w", err)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	client, err := newRESTClient(apiConfig)
+	if err != nil {
+		return err
+	}
+	_, err = doRequest(client, httpReq)
 
 	return err
 }
 
-func doRequest(req *http.Request) ([]byte, error) {
-	client := &http.Client{}
+// newRESTClient creates HTTP client of the exporter REST API.
+// Exporter certificate is verified with the CA, in case CA is specified, and exporter certificate is presented
+// as a client certificate in case of mutual TLS
+func newRESTClient(apiConfig *api.OperatorConfigMetricsAPI) (*http.Client, error) {
+	if !apiConfig.IsTLS() {
+		return &http.Client{}, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if apiConfig.IsMutualTLS() {
+		pool, err := loadCertPool(apiConfig.TLS.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.LoadX509KeyPair(apiConfig.TLS.CertFile, apiConfig.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		tlsConfig.RootCAs = pool
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	} else {
+		// Exporter certificate is trusted as is, since exporter runs in the same pod
+		pool, err := loadCertPool(apiConfig.TLS.CertFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
+
+func doRequest(client *http.Client, req *http.Request) ([]byte, error) {
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
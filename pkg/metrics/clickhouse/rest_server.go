@@ -15,15 +15,20 @@
 package clickhouse
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	log "github.com/golang/glog"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	kube "k8s.io/client-go/kubernetes"
 
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/apis/metrics"
 )
 
@@ -42,6 +47,8 @@ type RESTRequest struct {
 
 // HostRequest contains host details with parent context
 type HostRequest struct {
+	// CRKind is the kind of the CR the host belongs to. Empty kind stands for ClickHouseInstallation
+	CRKind      string               `json:"crKind,omitempty"`
 	CRNamespace string               `json:"crNamespace"`
 	CRName      string               `json:"crName"`
 	ClusterName string               `json:"clusterName"`
@@ -53,15 +60,25 @@ func (r *HostRequest) IsValid() bool {
 	return r.CRNamespace != "" && r.CRName != "" && r.ClusterName != "" && r.Host != nil && r.Host.Hostname != ""
 }
 
+// crIndexKey builds index key of the CR the host belongs to
+func (r *HostRequest) crIndexKey() string {
+	return (&metrics.WatchedCR{Kind: r.CRKind, Namespace: r.CRNamespace, Name: r.CRName}).IndexKey()
+}
+
 // RESTServer provides HTTP API for managing watched CRs and Hosts
 type RESTServer struct {
-	registry *CRRegistry
+	registry      *CRRegistry
+	authenticator *RESTAuthenticator
+	// readOnly specifies whether modifications of the watched CRs are rejected
+	readOnly bool
 }
 
 // NewRESTServer creates a new RESTServer instance
-func NewRESTServer(registry *CRRegistry) *RESTServer {
+func NewRESTServer(registry *CRRegistry, authenticator *RESTAuthenticator, readOnly bool) *RESTServer {
 	return &RESTServer{
-		registry: registry,
+		registry:      registry,
+		authenticator: authenticator,
+		readOnly:      readOnly,
 	}
 }
 
@@ -72,22 +89,45 @@ func (s *RESTServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := s.authenticator.Authenticate(r); err != nil {
+		log.Warningf("Reject unauthorized %s request from %s err: %v", r.Method, r.RemoteAddr, err)
+		http.Error(w, "401 unauthorized.", http.StatusUnauthorized)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		s.handleGet(w, r)
-	case http.MethodPost:
-		s.handlePost(w, r)
-	case http.MethodDelete:
-		s.handleDelete(w, r)
+	case http.MethodPost, http.MethodDelete:
+		if s.readOnly {
+			http.Error(w, "403 watched CRs are discovered by exporter, REST API is read-only.", http.StatusForbidden)
+			return
+		}
+		if r.Method == http.MethodPost {
+			s.handlePost(w, r)
+		} else {
+			s.handleDelete(w, r)
+		}
 	default:
 		_, _ = fmt.Fprintf(w, "Sorry, only GET, POST and DELETE methods are supported.")
 	}
 }
 
-// handleGet serves HTTP GET request to get list of watched CRs
+// handleGet serves HTTP GET request to get list of watched CRs.
+// List can be narrowed down to CRs of one kind with "kind" query parameter
 func (s *RESTServer) handleGet(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(s.registry.List())
+	list := s.registry.List()
+	if kind := r.URL.Query().Get("kind"); kind != "" {
+		filtered := make([]*metrics.WatchedCR, 0, len(list))
+		for _, cr := range list {
+			if cr.GetKind() == kind {
+				filtered = append(filtered, cr)
+			}
+		}
+		list = filtered
+	}
+	_ = json.NewEncoder(w).Encode(list)
 }
 
 // handlePost serves HTTP POST request to add CR or Host
@@ -159,8 +199,11 @@ func StartMetricsREST(
 	metricsAddress string,
 	metricsPath string,
 	collectorTimeout time.Duration,
+	collectInterval time.Duration,
 	chiListAddress string,
 	chiListPath string,
+	apiConfig *api.OperatorConfigMetricsAPI,
+	kubeClient kube.Interface,
 ) *Exporter {
 	log.V(1).Infof("Starting metrics exporter at '%s%s'\n", metricsAddress, metricsPath)
 
@@ -168,21 +211,85 @@ func StartMetricsREST(
 	registry := NewCRRegistry()
 
 	// Create and register Prometheus exporter
-	exporter := NewExporter(registry, collectorTimeout)
+	exporter := NewExporter(registry, collectorTimeout, collectInterval)
 	prometheus.MustRegister(exporter)
 
 	// Create REST server
-	restServer := NewRESTServer(registry)
+	restServer := NewRESTServer(registry, NewRESTAuthenticator(apiConfig, kubeClient), apiConfig.IsReadOnly())
 
 	// Setup HTTP handlers
-	http.Handle(metricsPath, promhttp.Handler())
-	http.Handle(chiListPath, restServer)
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle(metricsPath, promhttp.Handler())
+	restMux := metricsMux
+	if metricsAddress != chiListAddress {
+		restMux = http.NewServeMux()
+	}
+	restMux.Handle(chiListPath, restServer)
 
 	// Start HTTP servers
-	go http.ListenAndServe(metricsAddress, nil)
-	if metricsAddress != chiListAddress {
-		go http.ListenAndServe(chiListAddress, nil)
+	if metricsAddress == chiListAddress {
+		if apiConfig.IsTLS() {
+			log.Warningf("REST API shares address %s with metrics, metrics are served over TLS as well", chiListAddress)
+		}
+	} else {
+		go serveHTTP(metricsAddress, metricsMux, nil)
 	}
+	go serveHTTP(chiListAddress, restMux, apiConfig)
 
 	return exporter
 }
+
+// serveHTTP serves HTTP requests at the address. Requests are served over TLS in case TLS is configured
+func serveHTTP(address string, handler http.Handler, apiConfig *api.OperatorConfigMetricsAPI) {
+	if !apiConfig.IsTLS() {
+		if err := http.ListenAndServe(address, handler); err != nil {
+			log.Errorf("Unable to serve HTTP at %s err: %v", address, err)
+		}
+		return
+	}
+
+	tlsConfig, err := newServerTLSConfig(apiConfig)
+	if err != nil {
+		log.Errorf("Unable to serve HTTPS at %s err: %v", address, err)
+		return
+	}
+	server := &http.Server{
+		Addr:      address,
+		Handler:   handler,
+		TLSConfig: tlsConfig,
+	}
+	if err := server.ListenAndServeTLS(apiConfig.TLS.CertFile, apiConfig.TLS.KeyFile); err != nil {
+		log.Errorf("Unable to serve HTTPS at %s err: %v", address, err)
+	}
+}
+
+// newServerTLSConfig builds TLS config of the REST API server.
+// Client certificates are verified in case CA is specified, REST API handler requires them,
+// while metrics served at the same address remain available to Prometheus without client certificate
+func newServerTLSConfig(apiConfig *api.OperatorConfigMetricsAPI) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if apiConfig.IsMutualTLS() {
+		pool, err := loadCertPool(apiConfig.TLS.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConfig, nil
+}
+
+// loadCertPool loads CA certificates from PEM file
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CA file %s: %w", file, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no CA certificates found in %s", file)
+	}
+	return pool, nil
+}
@@ -0,0 +1,75 @@
+package clickhouse
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	authentication "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	kubeTesting "k8s.io/client-go/testing"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+// newTokenReviewClient creates kube client, which authenticates tokens as service accounts by tokens map
+func newTokenReviewClient(tokens map[string]string) *fake.Clientset {
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "tokenreviews", func(action kubeTesting.Action) (bool, runtime.Object, error) {
+		review := action.(kubeTesting.CreateAction).GetObject().(*authentication.TokenReview)
+		username, ok := tokens[review.Spec.Token]
+		review.Status.Authenticated = ok
+		review.Status.User.Username = username
+		return true, review, nil
+	})
+	return kubeClient
+}
+
+func serveRESTRequest(server *RESTServer, method, token string) int {
+	body := `{"type":"cr","cr":{"namespace":"ns","name":"chi"}}`
+	req := httptest.NewRequest(method, "/chi", bytes.NewBufferString(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestRESTServerTokenAuthentication(t *testing.T) {
+	config := &api.OperatorConfigMetricsAPI{
+		Authentication:  api.MetricsAPIAuthenticationToken,
+		ServiceAccounts: []string{"operator/clickhouse-operator"},
+	}
+	kubeClient := newTokenReviewClient(map[string]string{
+		"operator-token": "system:serviceaccount:operator:clickhouse-operator",
+		"other-token":    "system:serviceaccount:default:default",
+	})
+	registry := NewCRRegistry()
+	server := NewRESTServer(registry, NewRESTAuthenticator(config, kubeClient), false)
+
+	require.Equal(t, http.StatusUnauthorized, serveRESTRequest(server, http.MethodPost, ""))
+	require.Equal(t, http.StatusUnauthorized, serveRESTRequest(server, http.MethodPost, "unknown-token"))
+	require.Equal(t, http.StatusUnauthorized, serveRESTRequest(server, http.MethodPost, "other-token"))
+	require.Empty(t, registry.List())
+
+	require.Equal(t, http.StatusOK, serveRESTRequest(server, http.MethodPost, "operator-token"))
+	require.Len(t, registry.List(), 1)
+}
+
+func TestRESTServerReadOnly(t *testing.T) {
+	config := &api.OperatorConfigMetricsAPI{
+		ReadOnly: types.NewStringBool(true),
+	}
+	registry := NewCRRegistry()
+	server := NewRESTServer(registry, NewRESTAuthenticator(config, nil), config.IsReadOnly())
+
+	require.Equal(t, http.StatusForbidden, serveRESTRequest(server, http.MethodPost, ""))
+	require.Equal(t, http.StatusForbidden, serveRESTRequest(server, http.MethodDelete, ""))
+	require.Equal(t, http.StatusOK, serveRESTRequest(server, http.MethodGet, ""))
+	require.Empty(t, registry.List())
+}
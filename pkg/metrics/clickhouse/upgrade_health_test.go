@@ -0,0 +1,42 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeHealthCountErrorsSince(t *testing.T) {
+	ignored := func(name string) bool { return name == "UNKNOWN_TABLE" }
+	baseline := &UpgradeHealth{
+		Uptime: 100,
+		Errors: map[string]int{"NETWORK_ERROR": 50, "UNKNOWN_TABLE": 7},
+	}
+
+	// No baseline, all not ignored errors are counted
+	current := &UpgradeHealth{
+		Uptime: 160,
+		Errors: map[string]int{"NETWORK_ERROR": 53, "UNKNOWN_TABLE": 20, "CANNOT_READ_ALL_DATA": 1},
+	}
+	require.Equal(t, 54, current.CountErrorsSince(nil, ignored))
+
+	// Errors since the baseline are counted
+	require.False(t, current.IsRestartedSince(baseline))
+	require.Equal(t, 4, current.CountErrorsSince(baseline, ignored))
+
+	// Uptime is reset by restart
+	restarted := &UpgradeHealth{
+		Uptime: 30,
+		Errors: map[string]int{"NETWORK_ERROR": 2},
+	}
+	require.True(t, restarted.IsRestartedSince(baseline))
+	require.Equal(t, 2, restarted.CountErrorsSince(baseline, ignored))
+
+	// Counters are reset by restart, even though uptime is longer than the baseline one
+	restarted = &UpgradeHealth{
+		Uptime: 700,
+		Errors: map[string]int{"NETWORK_ERROR": 3},
+	}
+	require.True(t, restarted.IsRestartedSince(baseline))
+	require.Equal(t, 3, restarted.CountErrorsSince(baseline, ignored))
+}
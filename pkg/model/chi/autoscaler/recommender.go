@@ -0,0 +1,82 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// tolerance specifies how far average value of a metric may deviate from the target without scaling
+const tolerance = 0.1
+
+// Recommend calculates desired number of replicas of the cluster out of the current number of replicas
+// and metric values reported by the cluster's hosts, indexed by metric name.
+// Each metric suggests number of replicas which brings average value of the metric to the target, the largest
+// suggestion wins. Scale-out is performed at once, while scale-in removes one replica at a time, as every removed
+// replica has to be drained. Result is always within min and max replicas of the policy.
+func Recommend(policy *api.ClusterAutoscaling, current int, values map[string][]float64) (desired int, reason string) {
+	desired = 0
+	var reasons []string
+	for _, metric := range policy.GetMetrics() {
+		name := metric.GetName()
+		target := metric.GetTarget()
+		avg, ok := average(values[name])
+		if (name == "") || (target == 0) || !ok {
+			continue
+		}
+
+		suggested := current
+		if ratio := avg / target; math.Abs(ratio-1) > tolerance {
+			suggested = int(math.Ceil(float64(current) * ratio))
+		}
+		if suggested > desired {
+			desired = suggested
+		}
+		reasons = append(reasons, fmt.Sprintf("%s avg %.2f target %.2f", name, avg, target))
+	}
+
+	if len(reasons) == 0 {
+		// No metrics available - keep as is, but within the limits
+		desired = current
+		reasons = append(reasons, "no metrics")
+	}
+	if desired < current-1 {
+		desired = current - 1
+	}
+	if desired < policy.GetMinReplicas() {
+		desired = policy.GetMinReplicas()
+	}
+	if desired > policy.GetMaxReplicas() {
+		desired = policy.GetMaxReplicas()
+	}
+
+	return desired, strings.Join(reasons, ", ")
+}
+
+// average calculates average of the values
+func average(values []float64) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values)), true
+}
@@ -0,0 +1,72 @@
+package autoscaler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+func TestRecommend(t *testing.T) {
+	policy := &api.ClusterAutoscaling{
+		Enabled:     types.NewStringBool(true),
+		MinReplicas: types.NewInt32(2),
+		MaxReplicas: types.NewInt32(6),
+		Metrics: []api.AutoscalingMetric{
+			{Type: api.AutoscalingMetricConcurrentQueries, Target: "10"},
+			{Type: api.AutoscalingMetricCPU, Target: "0.7"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		current int
+		values  map[string][]float64
+		desired int
+	}{
+		{
+			name:    "within tolerance",
+			current: 2,
+			values:  map[string][]float64{"metric.Query": {10, 10.5}, "metric.OSUserTimeNormalized": {0.7, 0.72}},
+			desired: 2,
+		},
+		{
+			name:    "largest suggestion wins",
+			current: 2,
+			values:  map[string][]float64{"metric.Query": {15, 15}, "metric.OSUserTimeNormalized": {1.4, 1.4}},
+			desired: 4,
+		},
+		{
+			name:    "max replicas",
+			current: 4,
+			values:  map[string][]float64{"metric.Query": {100, 100, 100, 100}},
+			desired: 6,
+		},
+		{
+			name:    "scale in one replica at a time",
+			current: 5,
+			values:  map[string][]float64{"metric.Query": {1, 1, 1, 1, 1}},
+			desired: 4,
+		},
+		{
+			name:    "min replicas",
+			current: 2,
+			values:  map[string][]float64{"metric.Query": {0, 0}},
+			desired: 2,
+		},
+		{
+			name:    "no metrics brings into limits",
+			current: 1,
+			values:  nil,
+			desired: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired, _ := Recommend(policy, tt.current, tt.values)
+			require.Equal(t, tt.desired, desired)
+		})
+	}
+}
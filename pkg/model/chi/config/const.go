@@ -56,6 +56,9 @@ const (
 	// 5. operator-provided additional config files
 	DirPathConfigHost = DirPathConfigRoot + "/" + HostConfigDir + "/"
 
+	// DirPathTLS specifies full path to folder, where certificates provisioned by the operator are mounted
+	DirPathTLS = DirPathConfigRoot + "/" + "tls" + "/"
+
 	// DirPathSecretFilesConfig specifies full path to folder, where secrets are mounted
 	DirPathSecretFilesConfig = DirPathConfigRoot + "/" + "secrets.d" + "/"
 
@@ -93,6 +96,7 @@ const (
 const (
 	configMacros        = "macros"
 	configHostnamePorts = "hostname-ports"
+	configOpenSSL       = "openssl"
 	configProfiles      = "profiles"
 	configQuotas        = "quotas"
 	configRemoteServers = "remote_servers"
@@ -30,6 +30,7 @@ func NewFilesGeneratorDomain(configGenerator *Generator) *FilesGeneratorDomain {
 
 func (c *FilesGeneratorDomain) CreateConfigFilesGroupCommon(configSections map[string]string, options *FilesGeneratorOptions) {
 	util.IncludeNonEmpty(configSections, createConfigSectionFilename(configRemoteServers), c.configGenerator.getRemoteServers(options.GetRemoteServersOptions()))
+	util.IncludeNonEmpty(configSections, createConfigSectionFilename(configOpenSSL), c.configGenerator.getOpenSSL())
 }
 
 func (c *FilesGeneratorDomain) CreateConfigFilesGroupUsers(configSections map[string]string) {
@@ -197,7 +197,7 @@ func (c *Generator) getHostZookeeper(host *chi.Host) string {
 func (c *Generator) chiHostsNum(selector *config.HostSelector) int {
 	num := 0
 	c.cr.WalkHosts(func(host *chi.Host) error {
-		if selector.Include(host) {
+		if includeHost(selector, host) {
 			num++
 		}
 		return nil
@@ -216,11 +216,21 @@ func (c *Generator) clusterHostsNum(cluster chi.ICluster, selector *config.HostS
 	return num
 }
 
+// clusterShardsNum count shards of the cluster
+func (c *Generator) clusterShardsNum(cluster chi.ICluster) int {
+	num := 0
+	cluster.WalkShards(func(index int, shard chi.IShard) error {
+		num++
+		return nil
+	})
+	return num
+}
+
 // shardHostsNum count hosts according to the options
 func (c *Generator) shardHostsNum(shard chi.IShard, selector *config.HostSelector) int {
 	num := 0
 	shard.WalkHosts(func(host *chi.Host) error {
-		if selector.Include(host) {
+		if includeHost(selector, host) {
 			num++
 		}
 		return nil
@@ -229,6 +239,11 @@ func (c *Generator) shardHostsNum(shard chi.IShard, selector *config.HostSelecto
 }
 
 func (c *Generator) getRemoteServersReplica(host *chi.Host, b *bytes.Buffer) {
+	c.getRemoteServersReplicaWithHostname(host, c.getRemoteServersReplicaHostname(host), b)
+}
+
+// getRemoteServersReplicaWithHostname writes replica of the host addressed by the specified hostname
+func (c *Generator) getRemoteServersReplicaWithHostname(host *chi.Host, hostname string, b *bytes.Buffer) {
 	// <replica>
 	//		<host>XXX</host>
 	//		<port>XXX</port>
@@ -247,7 +262,7 @@ func (c *Generator) getRemoteServersReplica(host *chi.Host, b *bytes.Buffer) {
 	//		<priority>[PRIORITY]</priority>
 	// </replica>
 	util.Iline(b, 16, "<replica>")
-	util.Iline(b, 16, "    <host>%s</host>", c.getRemoteServersReplicaHostname(host))
+	util.Iline(b, 16, "    <host>%s</host>", hostname)
 	util.Iline(b, 16, "    <port>%d</port>", port)
 	util.Iline(b, 16, "    <secure>%d</secure>", c.getSecure(host))
 	if host.GetReconcileAttributes().IsLowPriority() {
@@ -268,8 +283,9 @@ func (c *Generator) getRemoteServers(selector *config.HostSelector) string {
 
 	clusters := c.getRemoteServerClusters(selector, indent+8)
 	clustersAutoGenerated := c.getRemoteClustersAutogenerated(selector, indent+8)
+	clustersDisasterRecovery := c.getRemoteClustersDisasterRecovery(selector, indent+8)
 
-	if clusters.Len()+clustersAutoGenerated.Len() > 0 {
+	if clusters.Len()+clustersAutoGenerated.Len()+clustersDisasterRecovery.Len() > 0 {
 		// <yandex>
 		//		<remote_servers>
 		util.Iline(b, indent+0, "<%s>", xmlTagYandex)
@@ -289,6 +305,13 @@ func (c *Generator) getRemoteServers(selector *config.HostSelector) string {
 			}
 		}
 
+		if clustersDisasterRecovery.Len() > 0 {
+			_, err := b.Write(clustersDisasterRecovery.Bytes())
+			if err != nil {
+				log.Error("FAILED to write buffer err: %v", err)
+			}
+		}
+
 		// 		</remote_servers>
 		// </yandex>
 		util.Iline(b, indent+4, "</remote_servers>")
@@ -347,7 +370,7 @@ func (c *Generator) getRemoteServerClusters(selector *config.HostSelector, inden
 			}
 
 			shard.WalkHosts(func(host *chi.Host) error {
-				if selector.Include(host) {
+				if includeHost(selector, host) {
 					log.V(2).M(host).Info("Adding host to remote servers: %s", host.GetName())
 					c.getRemoteServersReplica(host, b)
 				} else {
@@ -390,7 +413,7 @@ func (c *Generator) getRemoteClustersAutogenerated(selector *config.HostSelector
 	util.Iline(b, indent, "    <shard>")
 	util.Iline(b, indent, "        <internal_replication>true</internal_replication>")
 	c.cr.WalkHosts(func(host *chi.Host) error {
-		if selector.Include(host) {
+		if includeHost(selector, host) {
 			c.getRemoteServersReplica(host, b)
 		}
 		return nil // Walk hosts
@@ -420,7 +443,7 @@ func (c *Generator) getRemoteClustersAutogenerated(selector *config.HostSelector
 	}
 
 	c.cr.WalkHosts(func(host *chi.Host) error {
-		if selector.Include(host) {
+		if includeHost(selector, host) {
 			// <shard>
 			//     <internal_replication>
 			util.Iline(b, indent+4, "<shard>")
@@ -451,7 +474,7 @@ func (c *Generator) getRemoteClustersAutogenerated(selector *config.HostSelector
 			util.Iline(b, indent+4, "    <internal_replication>%s</internal_replication>", shard.GetInternalReplication())
 
 			shard.WalkHosts(func(host *chi.Host) error {
-				if selector.Include(host) {
+				if includeHost(selector, host) {
 					c.getRemoteServersReplica(host, b)
 				}
 				return nil // Walk hosts
@@ -540,23 +563,90 @@ func (c *Generator) getHostHostnameAndPorts(host *chi.Host) string {
 	return b.String()
 }
 
+// getRemoteClustersDisasterRecovery builds clusters, which lay over hosts of the same-named clusters of the CHI and
+// of the installations related by disaster recovery. Shards are matched by index. Hosts of other installations
+// are addressed by FQDN, as installations may live in different namespaces.
+// ON CLUSTER queries over such a cluster reach replicas in all the installations
+func (c *Generator) getRemoteClustersDisasterRecovery(selector *config.HostSelector, indent int) *bytes.Buffer {
+	b := &bytes.Buffer{}
+
+	peers := c.opts.DisasterRecovery.GetPeers()
+	if (len(peers) == 0) || (c.chiHostsNum(selector) < 1) {
+		return b
+	}
+
+	util.Iline(b, indent, "<!-- Disaster recovery clusters -->")
+
+	c.cr.WalkClusters(func(cluster chi.ICluster) error {
+		var peerClusters []*chi.Cluster
+		for _, peer := range peers {
+			peerCluster, ok := peer.FindCluster(cluster.GetName()).(*chi.Cluster)
+			if ok && (peerCluster != nil) && (len(peerCluster.Layout.Shards) == c.clusterShardsNum(cluster)) {
+				peerClusters = append(peerClusters, peerCluster)
+			}
+		}
+		if len(peerClusters) == 0 {
+			return nil // Walk clusters
+		}
+
+		// <my_cluster_name-dr>
+		util.Iline(b, indent, "<%s>", cluster.GetName()+chi.DisasterRecoveryClusterNameSuffix)
+		cluster.WalkShards(func(index int, shard chi.IShard) error {
+			util.Iline(b, indent+4, "<shard>")
+			util.Iline(b, indent+4, "    <internal_replication>%s</internal_replication>", shard.GetInternalReplication())
+			shard.WalkHosts(func(host *chi.Host) error {
+				if includeHost(selector, host) {
+					c.getRemoteServersReplica(host, b)
+				}
+				return nil // Walk hosts
+			})
+			for _, peerCluster := range peerClusters {
+				for _, host := range peerCluster.GetShard(index).Hosts {
+					c.getRemoteServersReplicaWithHostname(host, c.namer.Name(interfaces.NameFQDN, host), b)
+				}
+			}
+			util.Iline(b, indent+4, "</shard>")
+			return nil // Walk shards
+		})
+		// </my_cluster_name-dr>
+		util.Iline(b, indent, "</%s>", cluster.GetName()+chi.DisasterRecoveryClusterNameSuffix)
+
+		return nil // Walk clusters
+	})
+
+	return b
+}
+
 //
 // Paths and Names section
 //
 
 // getDistributedDDLPath returns string path used in <distributed_ddl><path>XXX</path></distributed_ddl>
 func (c *Generator) getDistributedDDLPath() string {
-	return fmt.Sprintf(DistributedDDLPathPattern, c.cr.GetName())
+	return fmt.Sprintf(DistributedDDLPathPattern, c.getDistributedDDLName())
 }
 
 // getDistributedDDLReplicasPath returns string path used in <distributed_ddl><replicas_path>XXX</replicas_path></distributed_ddl>
 func (c *Generator) getDistributedDDLReplicasPath() string {
-	return fmt.Sprintf(DistributedDDLReplicasPathPattern, c.cr.GetName())
+	return fmt.Sprintf(DistributedDDLReplicasPathPattern, c.getDistributedDDLName())
+}
+
+// getDistributedDDLName returns name distributed DDL paths are built of.
+// DR replica shares distributed DDL queue of the primary, so ON CLUSTER queries of the primary reach DR hosts
+func (c *Generator) getDistributedDDLName() string {
+	if primary := c.opts.DisasterRecovery.GetPrimary(); primary != nil {
+		return primary.GetName()
+	}
+	return c.cr.GetName()
 }
 
 // getRemoteServersReplicaHostname returns hostname (podhostname + service or FQDN) for "remote_servers.xml"
-// based on .Spec.Defaults.ReplicasUseFQDN
+// based on .Spec.Defaults.ReplicasUseFQDN.
+// Hosts of CHI spanning multiple kube contexts are not reachable by service name from other clusters, so FQDN is used
 func (c *Generator) getRemoteServersReplicaHostname(host *chi.Host) string {
+	if cr, ok := c.cr.(*chi.ClickHouseInstallation); ok && cr.IsMultiKubeContext() {
+		return c.namer.Name(interfaces.NameFQDN, host)
+	}
 	return c.namer.Name(interfaces.NameInstanceHostname, host)
 }
 
@@ -572,3 +662,8 @@ func (c *Generator) getSecure(host Secured) int {
 	}
 	return 0
 }
+
+// getOpenSSL builds openSSL config for the certificates provisioned by the operator
+func (c *Generator) getOpenSSL() string {
+	return config.OpenSSL(c.cr.GetSpec().GetConfiguration().GetTLS(), DirPathTLS).ClickHouseConfig()
+}
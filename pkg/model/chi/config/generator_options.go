@@ -27,8 +27,16 @@ type GeneratorOptions struct {
 
 	Settings *api.Settings
 	Files    *api.Settings
+
+	DisasterRecovery *api.DisasterRecoveryRuntime
 }
 
 func defaultSelectorIncludeAll() *config.HostSelector {
 	return config.NewHostSelector()
 }
+
+// includeHost tells whether to include the host into remote servers.
+// Host in maintenance is kept out of the clusters until maintenance is cleared
+func includeHost(selector *config.HostSelector, host *api.Host) bool {
+	return selector.Include(host) && !host.IsInMaintenance()
+}
@@ -39,6 +39,16 @@ func (n *Namer) createConfigMapNameHost(host *api.Host) string {
 	return n.macro.Scope(host).Line(patterns.Get(patternConfigMapHostName))
 }
 
+// createTLSSecretCAName returns a name of the Secret with CA certificate
+func (n *Namer) createTLSSecretCAName(cr api.ICustomResource) string {
+	return n.macro.Scope(cr).Line(patterns.Get(patternTLSSecretCAName))
+}
+
+// createTLSSecretHostName returns a name of the Secret with host certificate
+func (n *Namer) createTLSSecretHostName(host *api.Host) string {
+	return n.macro.Scope(host).Line(patterns.Get(patternTLSSecretHostName))
+}
+
 // createCRServiceName creates a name of a root ClickHouseInstallation Service resource
 func (n *Namer) createCRServiceName(cr api.ICustomResource, templates ...*api.ServiceTemplate) string {
 	// Name can be generated either from default name pattern,
@@ -213,6 +223,10 @@ func (n *Namer) createPodFQDN(host *api.Host) string {
 		// NamespaceDomainPattern has been explicitly specified
 		pattern = "%s." + host.GetCR().GetSpec().GetNamespaceDomainPattern().Value()
 	}
+	if kubeContext := n.getHostKubeContext(host); kubeContext.GetNamespaceDomainPattern().HasValue() {
+		// Host lives in remote kube context, which is reachable by its own domain
+		pattern = "%s." + kubeContext.GetNamespaceDomainPattern().Value()
+	}
 
 	// Create FQDN based on pattern available
 	return fmt.Sprintf(
@@ -222,6 +236,18 @@ func (n *Namer) createPodFQDN(host *api.Host) string {
 	)
 }
 
+// getHostKubeContext gets remote kube context the host lives in
+func (n *Namer) getHostKubeContext(host *api.Host) *api.KubeContext {
+	if host.GetKubeContext() == "" {
+		return nil
+	}
+	spec, ok := host.GetCR().GetSpec().(*api.ChiSpec)
+	if !ok {
+		return nil
+	}
+	return spec.GetKubeContext(host.GetKubeContext())
+}
+
 // createPodFQDNsOfCluster creates fully qualified domain names of all pods in a cluster
 func (n *Namer) createPodFQDNsOfCluster(cluster api.ICluster) (fqdns []string) {
 	cluster.WalkHosts(func(host *api.Host) error {
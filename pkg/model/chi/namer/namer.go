@@ -93,6 +93,12 @@ func (n *Namer) Name(what interfaces.NameType, params ...any) string {
 		host := params[0].(*api.Host)
 		volumeClaimTemplate := params[1].(*api.VolumeClaimTemplate)
 		return n.createPVCNameByVolumeClaimTemplate(host, volumeClaimTemplate)
+	case interfaces.NameTLSSecretCA:
+		cr := params[0].(api.ICustomResource)
+		return n.createTLSSecretCAName(cr)
+	case interfaces.NameTLSSecretHost:
+		host := params[0].(*api.Host)
+		return n.createTLSSecretHostName(host)
 	case interfaces.NameClusterPDB:
 		cluster := params[0].(api.ICluster)
 		return n.createClusterPDBName(cluster)
@@ -42,6 +42,12 @@ var patterns = types.List{
 	// patternReplicaServiceName is a template of replica Service name. "shard-{chi}-{cluster}-{replica}"
 	patternReplicaServiceName: "shard-" + macrosList.Get().Get(macro.MacrosCRName) + "-" + macrosList.Get().Get(macro.MacrosClusterName) + "-" + macrosList.Get().Get(macro.MacrosReplicaName),
 
+	// patternTLSSecretCAName is a template of the Secret with CA certificate. "chi-{chi}-tls-ca"
+	patternTLSSecretCAName: "chi-" + macrosList.Get().Get(macro.MacrosCRName) + "-tls-ca",
+
+	// patternTLSSecretHostName is a template of the Secret with host certificate. "chi-{chi}-{cluster}-{host}-tls"
+	patternTLSSecretHostName: "chi-" + macrosList.Get().Get(macro.MacrosCRName) + "-" + macrosList.Get().Get(macro.MacrosClusterName) + "-" + macrosList.Get().Get(macro.MacrosHostName) + "-tls",
+
 	// patternStatefulSetName is a template of host StatefulSet's name. "chi-{chi}-{cluster}-{shard}-{host}"
 	patternStatefulSetName: "chi-" + macrosList.Get().Get(macro.MacrosCRName) + "-" + macrosList.Get().Get(macro.MacrosClusterName) + "-" + macrosList.Get().Get(macro.MacrosHostName),
 
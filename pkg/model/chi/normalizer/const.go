@@ -32,4 +32,7 @@ const (
 
 	// defaultStatefulSetUpdatePollInterval specifies the default poll interval in seconds for StatefulSet update
 	defaultStatefulSetUpdatePollInterval = 5
+
+	// defaultSchemaDatabase specifies the database schema objects are created in unless database is specified
+	defaultSchemaDatabase = "default"
 )
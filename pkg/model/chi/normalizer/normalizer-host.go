@@ -229,6 +229,7 @@ func (n *Normalizer) normalizeHostStage2(
 	host.InheritFilesFrom(src)
 	host.Files = n.normalizeConfigurationFiles(host.Files, host)
 	host.InheritTemplatesFrom(src)
+	host.InheritKubeContextFrom(replica)
 
 	n.normalizeHostEnvVars()
 }
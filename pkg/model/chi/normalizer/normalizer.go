@@ -43,16 +43,22 @@ import (
 // Normalizer specifies structures normalizer
 type Normalizer struct {
 	secretGet subst.SecretGetter
+	keeperGet KeeperNodesGetter
 	req       *Request
 	namer     interfaces.INameManager
 	macro     interfaces.IMacro
 	labeler   interfaces.ILabeler
 }
 
-// New creates new normalizer
-func New(secretGet subst.SecretGetter) *Normalizer {
+// KeeperNodesGetter resolves hosts of the referenced ClickHouseKeeperInstallation into zookeeper nodes
+type KeeperNodesGetter func(namespace, name string) (chi.ZookeeperNodes, error)
+
+// New creates new normalizer.
+// keeperGet may be nil, in this case references to ClickHouseKeeperInstallation are left unresolved
+func New(secretGet subst.SecretGetter, keeperGet KeeperNodesGetter) *Normalizer {
 	return &Normalizer{
 		secretGet: secretGet,
+		keeperGet: keeperGet,
 		namer:     managers.NewNameManager(managers.NameManagerTypeClickHouse),
 		macro:     macro.New(),
 		labeler:   labeler.New(nil),
@@ -324,11 +330,38 @@ func (n *Normalizer) normalizeConfigurationStage1(c *chi.Configuration) *chi.Con
 func (n *Normalizer) normalizeConfigurationStage2(c *chi.Configuration) *chi.Configuration {
 	c.Zookeeper = n.normalizeConfigurationZookeeper(c.Zookeeper)
 	n.normalizeConfigurationAllSettingsBasedSections(c)
+	c.Schema = n.normalizeConfigurationSchema(c.Schema)
+	c.TLS = c.TLS.Normalize()
 
 	c.Clusters = n.normalizeClustersStage2(c.Clusters)
 	return c
 }
 
+// normalizeConfigurationSchema normalizes .spec.configuration.schema
+func (n *Normalizer) normalizeConfigurationSchema(schema *chi.Schema) *chi.Schema {
+	if schema.IsEmpty() {
+		return nil
+	}
+
+	// Objects are created in default database unless database is specified
+	for _, table := range schema.Tables {
+		if table.Database == "" {
+			table.Database = defaultSchemaDatabase
+		}
+	}
+	for _, view := range schema.MaterializedViews {
+		if view.Database == "" {
+			view.Database = defaultSchemaDatabase
+		}
+	}
+	for _, dictionary := range schema.Dictionaries {
+		if dictionary.Database == "" {
+			dictionary.Database = defaultSchemaDatabase
+		}
+	}
+	return schema
+}
+
 // normalizeConfigurationAllSettingsBasedSections normalizes Settings-based configuration
 func (n *Normalizer) normalizeConfigurationAllSettingsBasedSections(conf *chi.Configuration) {
 	conf.Users = n.normalizeConfigurationUsers(conf.Users, n.req.GetTarget())
@@ -623,6 +656,19 @@ func (n *Normalizer) normalizeConfigurationZookeeper(zk *chi.ZookeeperConfig) *c
 		return nil
 	}
 
+	// Nodes of the referenced Keeper take place of the explicitly specified ones
+	if zk.HasKeeperRef() && (n.keeperGet != nil) {
+		namespace := zk.Keeper.Namespace
+		if namespace == "" {
+			namespace = n.req.GetTarget().GetNamespace()
+		}
+		if nodes, err := n.keeperGet(namespace, zk.Keeper.Name); err == nil {
+			zk.Nodes = nodes
+		} else {
+			log.V(1).M(n.req.GetTarget()).F().Warning("unable to resolve keeper %s/%s err: %v", namespace, zk.Keeper.Name, err)
+		}
+	}
+
 	// In case no ZK port specified - assign default
 	for i := range zk.Nodes {
 		// Convenience wrapper
@@ -640,6 +686,17 @@ func (n *Normalizer) normalizeConfigurationZookeeper(zk *chi.ZookeeperConfig) *c
 	return zk
 }
 
+// normalizeClusterZookeeperDisasterRecovery moves ZooKeeper root of the cluster of the promoted DR CHI to own one,
+// so replicated tables of the promoted CHI stop sharing ZooKeeper paths with the primary
+func (n *Normalizer) normalizeClusterZookeeperDisasterRecovery(zk *chi.ZookeeperConfig) *chi.ZookeeperConfig {
+	dr := n.req.GetTarget().GetSpecT().GetDisasterRecovery()
+	if zk.IsEmpty() || !dr.IsPromoted() {
+		return zk
+	}
+	zk.Root = dr.GetPromotedZookeeperRoot(zk.Root, n.req.GetTarget().GetNamespace(), n.req.GetTarget().GetName())
+	return zk
+}
+
 func (n *Normalizer) appendClusterSecretEnvVar(cluster chi.ICluster) {
 	switch cluster.GetSecret().Source() {
 	case chi.ClusterSecretSourcePlaintext:
@@ -877,6 +934,7 @@ func (n *Normalizer) normalizeClusterStage2(cluster *chi.Cluster) *chi.Cluster {
 	cluster.InheritTemplatesFrom(n.req.GetTarget())
 
 	cluster.Zookeeper = n.normalizeConfigurationZookeeper(cluster.Zookeeper)
+	cluster.Zookeeper = n.normalizeClusterZookeeperDisasterRecovery(cluster.Zookeeper)
 	cluster.Settings = n.normalizeConfigurationSettings(cluster.Settings, cluster)
 	cluster.Files = n.normalizeConfigurationFiles(cluster.Files, cluster)
 
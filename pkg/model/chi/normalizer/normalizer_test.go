@@ -0,0 +1,138 @@
+package normalizer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	chi "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	commonNormalizer "github.com/altinity/clickhouse-operator/pkg/model/common/normalizer"
+)
+
+func init() {
+	chop.New(nil, nil, "")
+	chop.Config().AddCHITemplate(newCHI(`
+metadata:
+  name: keeper-ref
+  namespace: test
+spec:
+  configuration:
+    zookeeper:
+      keeper:
+        name: chk-template
+`))
+}
+
+func newCHI(manifest string) *chi.ClickHouseInstallation {
+	cr := &chi.ClickHouseInstallation{}
+	if err := yaml.Unmarshal([]byte(manifest), cr); err != nil {
+		panic(err)
+	}
+	return cr
+}
+
+// keepers resolves a keeper by its namespaced name into the single node named after it
+func keepers(namespace, name string) (chi.ZookeeperNodes, error) {
+	if name == "missing" {
+		return nil, fmt.Errorf("keeper %s/%s not found", namespace, name)
+	}
+	return chi.ZookeeperNodes{
+		{
+			Host: fmt.Sprintf("%s.%s.svc", name, namespace),
+			Port: types.NewInt32(2181),
+		},
+	}, nil
+}
+
+func normalize(t *testing.T, manifest string) *chi.ClickHouseInstallation {
+	normalized, err := New(nil, keepers).CreateTemplated(newCHI(manifest), commonNormalizer.NewOptions[chi.ClickHouseInstallation]())
+	require.NoError(t, err)
+	return normalized
+}
+
+func clusterNodes(cr *chi.ClickHouseInstallation) (nodes []string) {
+	cr.WalkClusters(func(cluster chi.ICluster) error {
+		nodes = append(nodes, cluster.GetZookeeper().Nodes.Servers()...)
+		return nil
+	})
+	return nodes
+}
+
+func TestNormalizeKeeperRef(t *testing.T) {
+	cr := normalize(t, `
+metadata:
+  name: chi
+  namespace: test
+spec:
+  configuration:
+    zookeeper:
+      nodes:
+        - host: zookeeper
+      keeper:
+        name: chk
+    clusters:
+      - name: own-namespace
+      - name: other-namespace
+        zookeeper:
+          keeper:
+            name: chk
+            namespace: other
+`)
+	require.Equal(t, []string{"chk.test.svc:2181"}, cr.GetSpecT().Configuration.Zookeeper.Nodes.Servers())
+	require.Equal(t, []string{"chk.test.svc:2181", "chk.other.svc:2181"}, clusterNodes(cr))
+}
+
+func TestNormalizeKeeperRefMissing(t *testing.T) {
+	cr := normalize(t, `
+metadata:
+  name: chi
+  namespace: test
+spec:
+  configuration:
+    zookeeper:
+      nodes:
+        - host: zookeeper
+      keeper:
+        name: missing
+    clusters:
+      - name: c1
+`)
+	// Explicitly specified nodes are kept as a fallback
+	require.Equal(t, []string{"zookeeper:2181"}, clusterNodes(cr))
+}
+
+func TestNormalizeKeeperRefFromTemplate(t *testing.T) {
+	cr := normalize(t, `
+metadata:
+  name: chi
+  namespace: test
+spec:
+  useTemplates:
+    - name: keeper-ref
+  configuration:
+    clusters:
+      - name: c1
+`)
+	require.Equal(t, []string{"chk-template.test.svc:2181"}, clusterNodes(cr))
+
+	// Reference of the CR itself overrides the one of the template
+	cr = normalize(t, `
+metadata:
+  name: chi
+  namespace: test
+spec:
+  useTemplates:
+    - name: keeper-ref
+  configuration:
+    zookeeper:
+      keeper:
+        name: chk
+    clusters:
+      - name: c1
+`)
+	require.Equal(t, []string{"chk.test.svc:2181"}, clusterNodes(cr))
+}
@@ -0,0 +1,170 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rebalancer
+
+import "sort"
+
+// Unit describes a piece of data which can be moved between shards as a whole, which is a partition of a table
+type Unit struct {
+	// Shard is an index of the shard the unit resides on
+	Shard       int
+	Partition   string
+	PartitionID string
+	// Weight is a size of the unit in terms of the rebalance strategy - bytes or rows
+	Weight int64
+	// Movable specifies whether the unit can be moved, e.g. it is not being written into
+	Movable bool
+}
+
+// Move describes planned move of the unit to the shard
+type Move struct {
+	Unit
+	ToShard int
+}
+
+// Plan builds list of moves which makes shards load as even as possible.
+// Each unit is moved at most once. Moves are planned greedily - the largest unit which makes difference between
+// the most and the least loaded shards smaller is moved first. In case the most loaded shard has no such unit,
+// less loaded shards are unloaded instead.
+func Plan(shards int, units []Unit) []Move {
+	if shards < 2 {
+		return nil
+	}
+
+	load := make([]int64, shards)
+	for _, unit := range units {
+		if (unit.Shard >= 0) && (unit.Shard < shards) {
+			load[unit.Shard] += unit.Weight
+		}
+	}
+
+	// Candidates are sorted by weight descending, so the first suitable one is the largest
+	candidates := make([]int, 0, len(units))
+	for i, unit := range units {
+		if unit.Movable && (unit.Weight > 0) && (unit.Shard >= 0) && (unit.Shard < shards) {
+			candidates = append(candidates, i)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return units[candidates[i]].Weight > units[candidates[j]].Weight
+	})
+
+	moved := make(map[int]bool)
+	var moves []Move
+	for {
+		min := leastLoaded(load)
+		found := -1
+		// Shard which has nothing to move, e.g. all of its units are being written into, is skipped,
+		// so the next loaded one is unloaded instead
+		for _, shard := range byLoad(load) {
+			gap := load[shard] - load[min]
+			if gap <= 0 {
+				break
+			}
+			for _, i := range candidates {
+				if !moved[i] && (units[i].Shard == shard) && (units[i].Weight < gap) {
+					found = i
+					break
+				}
+			}
+			if found >= 0 {
+				break
+			}
+		}
+		if found < 0 {
+			return moves
+		}
+
+		moved[found] = true
+		load[units[found].Shard] -= units[found].Weight
+		load[min] += units[found].Weight
+		moves = append(moves, Move{
+			Unit:    units[found],
+			ToShard: min,
+		})
+	}
+}
+
+// Drain builds list of moves which moves all units from the drained shards to the remaining ones.
+// Units are moved largest first, each one to the least loaded remaining shard
+func Drain(shards int, drained []int, units []Unit) []Move {
+	isDrained := make(map[int]bool)
+	for _, shard := range drained {
+		isDrained[shard] = true
+	}
+
+	load := make(map[int]int64)
+	for shard := 0; shard < shards; shard++ {
+		if !isDrained[shard] {
+			load[shard] = 0
+		}
+	}
+	if len(load) == 0 {
+		return nil
+	}
+
+	var candidates []Unit
+	for _, unit := range units {
+		switch {
+		case isDrained[unit.Shard]:
+			candidates = append(candidates, unit)
+		case (unit.Shard >= 0) && (unit.Shard < shards):
+			load[unit.Shard] += unit.Weight
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Weight > candidates[j].Weight
+	})
+
+	var moves []Move
+	for _, unit := range candidates {
+		to := -1
+		for shard := 0; shard < shards; shard++ {
+			if _, remaining := load[shard]; remaining && ((to < 0) || (load[shard] < load[to])) {
+				to = shard
+			}
+		}
+		load[to] += unit.Weight
+		moves = append(moves, Move{
+			Unit:    unit,
+			ToShard: to,
+		})
+	}
+	return moves
+}
+
+// byLoad returns indexes of shards ordered by load descending. Shards of the same load keep their order
+func byLoad(load []int64) []int {
+	res := make([]int, len(load))
+	for i := range res {
+		res[i] = i
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return load[res[i]] > load[res[j]]
+	})
+	return res
+}
+
+// leastLoaded returns index of the least loaded shard. The last one is returned in case of a tie,
+// so newly added shards are preferred as destinations
+func leastLoaded(load []int64) int {
+	res := 0
+	for i := range load {
+		if load[i] <= load[res] {
+			res = i
+		}
+	}
+	return res
+}
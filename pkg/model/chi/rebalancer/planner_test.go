@@ -0,0 +1,107 @@
+package rebalancer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func resultLoad(shards int, units []Unit, moves []Move) []int64 {
+	load := make([]int64, shards)
+	for _, unit := range units {
+		load[unit.Shard] += unit.Weight
+	}
+	for _, move := range moves {
+		load[move.Shard] -= move.Weight
+		load[move.ToShard] += move.Weight
+	}
+	return load
+}
+
+func TestPlanSpreadsToNewShard(t *testing.T) {
+	units := []Unit{
+		{Shard: 0, PartitionID: "202401", Weight: 100, Movable: true},
+		{Shard: 0, PartitionID: "202402", Weight: 100, Movable: true},
+		{Shard: 1, PartitionID: "202401", Weight: 100, Movable: true},
+		{Shard: 1, PartitionID: "202402", Weight: 100, Movable: true},
+	}
+	moves := Plan(3, units)
+	require.Len(t, moves, 1)
+	require.Equal(t, 2, moves[0].ToShard)
+	require.Equal(t, []int64{100, 200, 100}, resultLoad(3, units, moves))
+}
+
+func TestPlanSkipsNotMovable(t *testing.T) {
+	units := []Unit{
+		{Shard: 0, PartitionID: "202401", Weight: 100, Movable: false},
+		{Shard: 0, PartitionID: "202402", Weight: 100, Movable: false},
+	}
+	require.Empty(t, Plan(2, units))
+}
+
+func TestPlanSkipsShardWithoutCandidates(t *testing.T) {
+	units := []Unit{
+		{Shard: 0, PartitionID: "a", Weight: 300, Movable: false},
+		{Shard: 1, PartitionID: "b", Weight: 100, Movable: true},
+		{Shard: 1, PartitionID: "c", Weight: 100, Movable: true},
+	}
+	moves := Plan(3, units)
+	require.Len(t, moves, 1)
+	require.Equal(t, 1, moves[0].Shard)
+	require.Equal(t, 2, moves[0].ToShard)
+	require.Equal(t, []int64{300, 100, 100}, resultLoad(3, units, moves))
+}
+
+func TestPlanDoesNotOvershoot(t *testing.T) {
+	units := []Unit{
+		{Shard: 0, PartitionID: "a", Weight: 300, Movable: true},
+		{Shard: 0, PartitionID: "b", Weight: 50, Movable: true},
+		{Shard: 1, PartitionID: "c", Weight: 250, Movable: true},
+	}
+	moves := Plan(2, units)
+	require.Len(t, moves, 1)
+	require.Equal(t, "b", moves[0].PartitionID)
+	require.Equal(t, []int64{300, 300}, resultLoad(2, units, moves))
+}
+
+func TestPlanMovesEachUnitOnce(t *testing.T) {
+	units := []Unit{
+		{Shard: 0, PartitionID: "a", Weight: 10, Movable: true},
+		{Shard: 0, PartitionID: "b", Weight: 10, Movable: true},
+		{Shard: 0, PartitionID: "c", Weight: 10, Movable: true},
+		{Shard: 0, PartitionID: "d", Weight: 10, Movable: true},
+	}
+	moves := Plan(4, units)
+	require.Len(t, moves, 3)
+	seen := map[string]bool{}
+	for _, move := range moves {
+		require.False(t, seen[move.PartitionID])
+		seen[move.PartitionID] = true
+	}
+	require.Equal(t, []int64{10, 10, 10, 10}, resultLoad(4, units, moves))
+}
+
+func TestPlanSingleShard(t *testing.T) {
+	require.Empty(t, Plan(1, []Unit{{Shard: 0, Weight: 10, Movable: true}}))
+}
+
+func TestDrainMovesAllUnits(t *testing.T) {
+	units := []Unit{
+		{Shard: 0, PartitionID: "a", Weight: 100},
+		{Shard: 1, PartitionID: "b", Weight: 50},
+		{Shard: 2, PartitionID: "c", Weight: 70},
+		{Shard: 2, PartitionID: "d", Weight: 40},
+		{Shard: 2, PartitionID: "e", Weight: 0},
+	}
+	moves := Drain(3, []int{2}, units)
+	require.Len(t, moves, 3)
+	for _, move := range moves {
+		require.Equal(t, 2, move.Shard)
+		require.NotEqual(t, 2, move.ToShard)
+	}
+	require.Equal(t, []int64{140, 120, 0}, resultLoad(3, units, moves))
+}
+
+func TestDrainAllShards(t *testing.T) {
+	require.Empty(t, Drain(2, []int{0, 1}, []Unit{{Shard: 0, Weight: 10}}))
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommender
+
+import (
+	"sync"
+	"time"
+)
+
+// Usage is resources usage of a host observed at a moment
+type Usage struct {
+	// Time of the observation
+	Time time.Time
+	// CPUTime is a cumulative CPU time consumed by the ClickHouse process, in seconds
+	CPUTime float64
+	// Memory is a resident memory of the ClickHouse process, in bytes
+	Memory float64
+	// QueryMemory is a peak memory of the largest running query, in bytes
+	QueryMemory float64
+}
+
+// Sample is resources usage of a host between two consecutive observations
+type Sample struct {
+	// Time of the sample
+	Time time.Time
+	// CPU is a number of cores consumed
+	CPU float64
+	// Memory is a resident memory, in bytes
+	Memory float64
+	// QueryMemory is a peak memory of the largest running query, in bytes
+	QueryMemory float64
+}
+
+// History keeps resources usage samples of the hosts
+type History struct {
+	mx      sync.Mutex
+	last    map[string]Usage
+	samples map[string][]Sample
+}
+
+// NewHistory creates new History
+func NewHistory() *History {
+	return &History{
+		last:    make(map[string]Usage),
+		samples: make(map[string][]Sample),
+	}
+}
+
+// Observe registers usage of the host. CPU consumption is calculated out of the previous observation of the host,
+// thus the first observation and observation after the process restart produce no sample.
+// Samples older than the window are dropped.
+func (h *History) Observe(host string, usage Usage, window time.Duration) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	last, found := h.last[host]
+	h.last[host] = usage
+	if !found || !usage.Time.After(last.Time) || (usage.CPUTime < last.CPUTime) {
+		return
+	}
+
+	samples := append(h.samples[host], Sample{
+		Time:        usage.Time,
+		CPU:         (usage.CPUTime - last.CPUTime) / usage.Time.Sub(last.Time).Seconds(),
+		Memory:      usage.Memory,
+		QueryMemory: usage.QueryMemory,
+	})
+	cutoff := usage.Time.Add(-window)
+	for len(samples) > 0 && samples[0].Time.Before(cutoff) {
+		samples = samples[1:]
+	}
+	h.samples[host] = samples
+}
+
+// Samples gets samples of the specified hosts
+func (h *History) Samples(hosts ...string) []Sample {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	var samples []Sample
+	for _, host := range hosts {
+		samples = append(samples, h.samples[host]...)
+	}
+	return samples
+}
+
+// Forget drops hosts which were not observed since the specified time
+func (h *History) Forget(before time.Time) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	for host, usage := range h.last {
+		if usage.Time.Before(before) {
+			delete(h.last, host)
+			delete(h.samples, host)
+		}
+	}
+}
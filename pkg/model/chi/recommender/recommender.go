@@ -0,0 +1,125 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommender
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+const (
+	// minCPU is a min CPU recommended, in millicores
+	minCPU = 100
+	// minMemory is a min memory recommended, in bytes
+	minMemory = 256 * 1024 * 1024
+	// memoryStep is a granularity memory recommendation is rounded up to, in bytes
+	memoryStep = 64 * 1024 * 1024
+	// requestPercentile is a percentile of the observed usage requests are based on
+	requestPercentile = 0.9
+)
+
+// Recommend calculates CPU and memory recommended for each host of the cluster out of the usage samples of the
+// cluster's hosts and the headroom ratio added on top of the observed usage.
+// Requests cover 90th percentile of the observed usage, CPU limit covers the peak CPU consumption and memory limit
+// covers the peak resident memory along with one more query as large as the largest observed one.
+// Returns nil in case there are no samples.
+func Recommend(cluster string, samples []Sample, headroom float64) *api.ResourcesRecommendation {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	var cpu, memory, queryMemory []float64
+	for _, sample := range samples {
+		cpu = append(cpu, sample.CPU)
+		memory = append(memory, sample.Memory)
+		queryMemory = append(queryMemory, sample.QueryMemory)
+	}
+
+	factor := 1 + headroom
+	cpuRequest := roundCPU(percentile(cpu, requestPercentile) * factor)
+	cpuLimit := max(roundCPU(percentile(cpu, 1)*factor), cpuRequest)
+	memoryRequest := roundMemory(percentile(memory, requestPercentile) * factor)
+	memoryLimit := max(roundMemory((percentile(memory, 1)+percentile(queryMemory, 1))*factor), memoryRequest)
+
+	return &api.ResourcesRecommendation{
+		Cluster:       cluster,
+		Time:          time.Now().UTC().Format(time.RFC3339),
+		Samples:       len(samples),
+		CPURequest:    resource.NewMilliQuantity(cpuRequest, resource.DecimalSI).String(),
+		CPULimit:      resource.NewMilliQuantity(cpuLimit, resource.DecimalSI).String(),
+		MemoryRequest: resource.NewQuantity(memoryRequest, resource.BinarySI).String(),
+		MemoryLimit:   resource.NewQuantity(memoryLimit, resource.BinarySI).String(),
+	}
+}
+
+// IsSignificantChange checks whether any value of the recommendation differs from the previous one by more than
+// the specified ratio. Any recommendation is significant in case there is no previous one.
+func IsSignificantChange(prev, next *api.ResourcesRecommendation, ratio float64) bool {
+	if prev == nil {
+		return next != nil
+	}
+	if next == nil {
+		return false
+	}
+	pairs := [][2]string{
+		{prev.CPURequest, next.CPURequest},
+		{prev.CPULimit, next.CPULimit},
+		{prev.MemoryRequest, next.MemoryRequest},
+		{prev.MemoryLimit, next.MemoryLimit},
+	}
+	for _, pair := range pairs {
+		a, errA := resource.ParseQuantity(pair[0])
+		b, errB := resource.ParseQuantity(pair[1])
+		if (errA != nil) || (errB != nil) {
+			return true
+		}
+		from, to := a.AsApproximateFloat64(), b.AsApproximateFloat64()
+		if (from == 0) || (math.Abs(to-from)/from > ratio) {
+			return true
+		}
+	}
+	return false
+}
+
+// percentile calculates percentile of the values, p is within [0, 1]
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index]
+}
+
+// roundCPU rounds number of cores up to 10 millicores
+func roundCPU(cores float64) int64 {
+	millicores := int64(math.Ceil(cores*100)) * 10
+	return max(millicores, minCPU)
+}
+
+// roundMemory rounds bytes up to the memory step
+func roundMemory(bytes float64) int64 {
+	rounded := int64(math.Ceil(bytes/memoryStep)) * memoryStep
+	return max(rounded, minMemory)
+}
@@ -0,0 +1,82 @@
+package recommender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+const gi = 1024 * 1024 * 1024
+
+func TestHistory(t *testing.T) {
+	h := NewHistory()
+	now := time.Now()
+	window := 10 * time.Minute
+
+	// First observation produces no sample
+	h.Observe("host", Usage{Time: now, CPUTime: 100, Memory: gi}, window)
+	require.Empty(t, h.Samples("host"))
+
+	// Two cores consumed within a minute
+	h.Observe("host", Usage{Time: now.Add(time.Minute), CPUTime: 220, Memory: 2 * gi, QueryMemory: gi}, window)
+	samples := h.Samples("host")
+	require.Len(t, samples, 1)
+	require.InDelta(t, 2.0, samples[0].CPU, 0.001)
+	require.Equal(t, float64(2*gi), samples[0].Memory)
+
+	// Process restart resets CPU time counter
+	h.Observe("host", Usage{Time: now.Add(2 * time.Minute), CPUTime: 10}, window)
+	require.Len(t, h.Samples("host"), 1)
+
+	// Samples out of the window are dropped
+	h.Observe("host", Usage{Time: now.Add(20 * time.Minute), CPUTime: 70}, window)
+	samples = h.Samples("host", "unknown")
+	require.Len(t, samples, 1)
+	require.InDelta(t, 60.0/(18*60), samples[0].CPU, 0.001)
+
+	h.Forget(now.Add(time.Hour))
+	require.Empty(t, h.Samples("host"))
+}
+
+func TestRecommend(t *testing.T) {
+	require.Nil(t, Recommend("cluster", nil, 0.2))
+
+	var samples []Sample
+	for i := 1; i <= 10; i++ {
+		samples = append(samples, Sample{
+			CPU:         float64(i) / 10,
+			Memory:      float64(i) * gi,
+			QueryMemory: gi,
+		})
+	}
+	recommendation := Recommend("cluster", samples, 0)
+	require.Equal(t, "cluster", recommendation.Cluster)
+	require.Equal(t, 10, recommendation.Samples)
+	require.Equal(t, "900m", recommendation.CPURequest)
+	require.Equal(t, "1", recommendation.CPULimit)
+	require.Equal(t, "9Gi", recommendation.MemoryRequest)
+	require.Equal(t, "11Gi", recommendation.MemoryLimit)
+
+	// Idle hosts get min resources
+	idle := Recommend("cluster", []Sample{{}}, 0.2)
+	require.Equal(t, "100m", idle.CPURequest)
+	require.Equal(t, "256Mi", idle.MemoryLimit)
+
+	resources := recommendation.GetResourceRequirements()
+	require.Equal(t, "900m", resources.Requests.Cpu().String())
+	require.Equal(t, "11Gi", resources.Limits.Memory().String())
+}
+
+func TestIsSignificantChange(t *testing.T) {
+	prev := &api.ResourcesRecommendation{CPURequest: "1", CPULimit: "2", MemoryRequest: "4Gi", MemoryLimit: "8Gi"}
+	small := &api.ResourcesRecommendation{CPURequest: "1050m", CPULimit: "2", MemoryRequest: "4Gi", MemoryLimit: "8Gi"}
+	large := &api.ResourcesRecommendation{CPURequest: "1", CPULimit: "2", MemoryRequest: "4Gi", MemoryLimit: "10Gi"}
+
+	require.True(t, IsSignificantChange(nil, prev, 0.1))
+	require.False(t, IsSignificantChange(prev, small, 0.1))
+	require.True(t, IsSignificantChange(prev, large, 0.1))
+	require.False(t, IsSignificantChange(prev, nil, 0.1))
+}
@@ -0,0 +1,151 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/interfaces"
+	"github.com/altinity/clickhouse-operator/pkg/model/clickhouse"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// HostCloneTables replays schema of the cluster of the source host on the host.
+// Schema is read from the source hosts the same way it is read from sibling hosts for a new replica,
+// however schema policy of the cluster is not considered, since the whole cluster of the host is empty
+func (s *ClusterSchemer) HostCloneTables(ctx context.Context, source, host *api.Host) error {
+	if util.IsContextDone(ctx) {
+		log.V(1).Info("ctx is done")
+		return nil
+	}
+
+	log.V(1).M(host).F().S().Info("Cloning schema objects from %s to host %s", source.Runtime.Address.HostName, host.Runtime.Address.HostName)
+	defer log.V(1).M(host).F().E().Info("Cloning schema objects from %s to host %s", source.Runtime.Address.HostName, host.Runtime.Address.HostName)
+
+	sources := s.Names(interfaces.NameFQDNs, source, api.ClickHouseInstallation{}, false)
+	cluster := source.Runtime.Address.ClusterName
+
+	queries := []struct {
+		query func(ctx context.Context, endpoints []string, sql string) ([]string, []string, error)
+		sql   string
+	}{
+		{s.QueryUnzip2Columns, s.sqlCreateDatabaseReplicated(cluster)},
+		{s.QueryUnzipAndApplyUUIDs, s.sqlCreateTableReplicated(cluster)},
+		{s.QueryUnzip2Columns, s.sqlCreateFunction(cluster)},
+		{s.QueryUnzip2Columns, s.sqlCreateDatabaseDistributed(cluster)},
+		{s.QueryUnzipAndApplyUUIDs, s.sqlCreateTableDistributed(cluster)},
+	}
+
+	var names, sqls []string
+	for _, q := range queries {
+		_names, _sqls, err := q.query(ctx, sources, q.sql)
+		if err != nil {
+			return err
+		}
+		names = append(names, _names...)
+		sqls = append(sqls, _sqls...)
+	}
+
+	if len(sqls) == 0 {
+		log.V(1).M(host).F().Info("No schema objects to clone from %s", source.Runtime.Address.HostName)
+		return nil
+	}
+
+	log.V(1).M(host).F().Info("Creating cloned objects at %s: %v", host.Runtime.Address.HostName, names)
+	log.V(2).M(host).F().Info("\n%v", sqls)
+	return s.ExecHost(ctx, host, sqls, clickhouse.NewQueryOptions().SetRetry(true).SetLogQueries(true))
+}
+
+// HostCloneTableData replaces data of the table on the host with data of the same table of the source host.
+// Source host is addressed as host:port and is read with remote() table function as the specified user.
+// Queries are executed silently, since they carry password of the user
+func (s *ClusterSchemer) HostCloneTableData(ctx context.Context, host *api.Host, table RebalanceTable, address, user, password string) error {
+	log.V(1).M(host).F().Info("Clone data of %s.%s from %s", table.Database, table.Name, address)
+	opts := clickhouse.NewQueryOptions().SetRetry(false).SetSilent(true)
+	opts.SetQueryTimeout(rebalanceQueryTimeout)
+	return s.ExecHost(ctx, host, s.sqlCloneTableData(table, address, user, password), opts)
+}
+
+// sqlCloneTableData builds queries to copy the table over remote() table function.
+// The table is truncated first, so interrupted copy can be safely restarted.
+// Truncate of a replicated table is replicated, so replicated tables are expected to be copied on one replica only
+func (s *ClusterSchemer) sqlCloneTableData(table RebalanceTable, address, user, password string) []string {
+	return []string{
+		fmt.Sprintf("TRUNCATE TABLE IF EXISTS %s.%s", quoteIdentifier(table.Database), quoteIdentifier(table.Name)),
+		heredoc.Docf(`
+			INSERT INTO %s.%s
+			SELECT
+				*
+			FROM
+				remote(%s, %s, %s, %s, %s)
+			`,
+			quoteIdentifier(table.Database),
+			quoteIdentifier(table.Name),
+			quoteString(address),
+			quoteString(table.Database),
+			quoteString(table.Name),
+			quoteString(user),
+			quoteString(password),
+		),
+	}
+}
+
+// HostTablesWriteMarks gets marks of writes into MergeTree tables of the host by 'database.table'.
+// Mark is built out of the latest block numbers and data versions of partitions of the table.
+// It is changed by inserts, mutations and dropped partitions, while merges keep it as it is
+func (s *ClusterSchemer) HostTablesWriteMarks(ctx context.Context, host *api.Host) (map[string]string, error) {
+	var databases, names, marks []string
+	if err := s.queryHostColumns(ctx, host, s.sqlTablesWriteMarks(), &databases, &names, &marks); err != nil {
+		return nil, err
+	}
+	res := make(map[string]string)
+	for i := range names {
+		res[databases[i]+"."+names[i]] = marks[i]
+	}
+	return res, nil
+}
+
+func (s *ClusterSchemer) sqlTablesWriteMarks() string {
+	return heredoc.Doc(`
+		SELECT
+			database,
+			table,
+			concat(toString(count()), '/', toString(sum(max_block)), '/', toString(sum(max_version)))
+		FROM
+		(
+			SELECT
+				database,
+				table,
+				partition_id,
+				max(max_block_number) AS max_block,
+				max(data_version) AS max_version
+			FROM
+				system.parts
+			WHERE
+				active AND
+				database NOT IN ('system', 'INFORMATION_SCHEMA', 'information_schema')
+			GROUP BY
+				database, table, partition_id
+		)
+		GROUP BY
+			database, table
+		`,
+	)
+}
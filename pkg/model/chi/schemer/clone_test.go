@@ -0,0 +1,19 @@
+package schemer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLCloneTableData(t *testing.T) {
+	s := &ClusterSchemer{}
+	table := RebalanceTable{Database: "default", Name: "ev`ents", Engine: "ReplicatedMergeTree"}
+
+	sqls := s.sqlCloneTableData(table, "chi-src-c-0-0.prod.svc.cluster.local:9000", "reader", `pa'ss`)
+	require.Len(t, sqls, 2)
+	require.Equal(t, "TRUNCATE TABLE IF EXISTS `default`.`ev\\`ents`", sqls[0])
+	require.True(t, strings.HasPrefix(sqls[1], "INSERT INTO `default`.`ev\\`ents`"))
+	require.Contains(t, sqls[1], `remote('chi-src-c-0-0.prod.svc.cluster.local:9000', 'default', 'ev`+"`"+`ents', 'reader', 'pa\'ss')`)
+}
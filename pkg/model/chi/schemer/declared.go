@@ -0,0 +1,335 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/MakeNowJust/heredoc"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/clickhouse"
+)
+
+// DeclaredObject is an object of the schema declared in the CHI
+type DeclaredObject struct {
+	Kind     string
+	Database string
+	Name     string
+	// SQL is CREATE statement of the object
+	SQL string
+}
+
+// FullName gets name of the object in 'database.name' form. Database is named by its name only
+func (o *DeclaredObject) FullName() string {
+	if o.Kind == api.SchemaObjectDatabase {
+		return o.Name
+	}
+	return o.Database + "." + o.Name
+}
+
+// IsTable checks whether object is a table
+func (o *DeclaredObject) IsTable() bool {
+	return o.Kind == api.SchemaObjectTable
+}
+
+// DeclaredObjects lists objects of the schema in the order they have to be created in
+func DeclaredObjects(schema *api.Schema) (objects []*DeclaredObject) {
+	if schema == nil {
+		return nil
+	}
+	for _, database := range schema.Databases {
+		objects = append(objects, &DeclaredObject{
+			Kind: api.SchemaObjectDatabase,
+			Name: database.Name,
+			SQL:  sqlCreateDatabase(database),
+		})
+	}
+	for _, table := range schema.Tables {
+		objects = append(objects, &DeclaredObject{
+			Kind:     api.SchemaObjectTable,
+			Database: table.Database,
+			Name:     table.Name,
+			SQL:      sqlCreateTable(table),
+		})
+	}
+	// Dictionaries may be sourced from tables, while views may read from dictionaries
+	for _, dictionary := range schema.Dictionaries {
+		objects = append(objects, &DeclaredObject{
+			Kind:     api.SchemaObjectDictionary,
+			Database: dictionary.Database,
+			Name:     dictionary.Name,
+			SQL:      sqlCreateDictionary(dictionary),
+		})
+	}
+	for _, view := range schema.MaterializedViews {
+		objects = append(objects, &DeclaredObject{
+			Kind:     api.SchemaObjectMaterializedView,
+			Database: view.Database,
+			Name:     view.Name,
+			SQL:      sqlCreateMaterializedView(view),
+		})
+	}
+	return objects
+}
+
+// HostDeclaredObjects gets CREATE statements of the objects existing on the host, mapped by full name of the object
+func (s *ClusterSchemer) HostDeclaredObjects(ctx context.Context, host *api.Host, objects []*DeclaredObject) (map[string]string, error) {
+	var names, statements []string
+	if err := s.queryHostColumns(ctx, host, s.sqlDeclaredObjects(objects), &names, &statements); err != nil {
+		return nil, err
+	}
+	existing := make(map[string]string)
+	for i := range names {
+		existing[names[i]] = statements[i]
+	}
+	return existing, nil
+}
+
+// referenceDatabase is a scratch database reference tables are created in
+const referenceDatabase = "_clickhouse_operator_schema_reference"
+
+// referenceStatements caches reference statements by ClickHouse version and declared statement
+var referenceStatements sync.Map
+
+// HostReferenceStatements gets statements ClickHouse keeps for tables created out of the declared ones,
+// mapped by full name of the declared table. Reference tables are created in a scratch database, which is dropped afterwards.
+// Reference statements depend on ClickHouse version only, so they are cached by version
+func (s *ClusterSchemer) HostReferenceStatements(ctx context.Context, host *api.Host, objects []*DeclaredObject) (map[string]string, error) {
+	version, err := s.HostClickHouseVersion(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	references := make(map[string]string)
+	var missing []*DeclaredObject
+	for _, object := range objects {
+		if !object.IsTable() {
+			continue
+		}
+		if reference, found := referenceStatements.Load(version + "\n" + object.SQL); found {
+			references[object.FullName()] = reference.(string)
+		} else {
+			missing = append(missing, object)
+		}
+	}
+	if len(missing) == 0 {
+		return references, nil
+	}
+
+	log.V(1).M(host).F().Info("Create %d reference tables", len(missing))
+	opts := clickhouse.NewQueryOptions().SetRetry(false)
+	sqls := []string{
+		"DROP DATABASE IF EXISTS " + quoteIdentifier(referenceDatabase) + " SYNC",
+		"CREATE DATABASE " + quoteIdentifier(referenceDatabase),
+	}
+	for i, object := range missing {
+		sqls = append(sqls, ReferenceTableSQL(object.SQL, referenceDatabase, referenceTableName(i)))
+	}
+	defer func() {
+		_ = s.ExecHost(ctx, host, []string{"DROP DATABASE IF EXISTS " + quoteIdentifier(referenceDatabase) + " SYNC"}, opts)
+	}()
+	if err := s.ExecHost(ctx, host, sqls, opts); err != nil {
+		return nil, fmt.Errorf("unable to create reference tables: %w", err)
+	}
+
+	var names, statements []string
+	sql := "SELECT name, create_table_query FROM system.tables WHERE database = " + quoteString(referenceDatabase)
+	if err := s.queryHostColumns(ctx, host, sql, &names, &statements); err != nil {
+		return nil, err
+	}
+	created := make(map[string]string)
+	for i := range names {
+		created[names[i]] = statements[i]
+	}
+	for i, object := range missing {
+		reference, found := created[referenceTableName(i)]
+		if !found {
+			return nil, fmt.Errorf("reference table of %s is not found", object.FullName())
+		}
+		referenceStatements.Store(version+"\n"+object.SQL, reference)
+		references[object.FullName()] = reference
+	}
+	return references, nil
+}
+
+func referenceTableName(i int) string {
+	return fmt.Sprintf("table_%d", i)
+}
+
+// HostCreateDeclaredObject creates the object on the host
+func (s *ClusterSchemer) HostCreateDeclaredObject(ctx context.Context, host *api.Host, object *DeclaredObject) error {
+	log.V(1).M(host).F().Info("Create %s %s", object.Kind, object.FullName())
+	opts := clickhouse.NewQueryOptions().SetRetry(false).SetLogQueries(true)
+	return s.ExecHost(ctx, host, []string{object.SQL}, opts)
+}
+
+// HostAddColumn adds missing column into the table on the host
+func (s *ClusterSchemer) HostAddColumn(ctx context.Context, host *api.Host, object *DeclaredObject, column ColumnAddition) error {
+	log.V(1).M(host).F().Info("Add column %s into %s", column.Definition, object.FullName())
+	opts := clickhouse.NewQueryOptions().SetRetry(false).SetLogQueries(true)
+	return s.ExecHost(ctx, host, []string{s.sqlAddColumn(object, column)}, opts)
+}
+
+func (s *ClusterSchemer) sqlDeclaredObjects(objects []*DeclaredObject) string {
+	var databases, tables []string
+	for _, object := range objects {
+		if object.Kind == api.SchemaObjectDatabase {
+			databases = append(databases, quoteString(object.Name))
+		} else {
+			tables = append(tables, fmt.Sprintf("(%s, %s)", quoteString(object.Database), quoteString(object.Name)))
+		}
+	}
+	// Empty lists are not allowed by IN operator
+	databases = append(databases, "''")
+	tables = append(tables, "('', '')")
+
+	return heredoc.Docf(`
+		SELECT
+			name,
+			concat('CREATE DATABASE ', name, ' ENGINE = ', engine)
+		FROM
+			system.databases
+		WHERE
+			name IN (%s)
+		UNION ALL
+		SELECT
+			concat(database, '.', name),
+			create_table_query
+		FROM
+			system.tables
+		WHERE
+			(database, name) IN (%s)
+		`,
+		strings.Join(databases, ", "),
+		strings.Join(tables, ", "),
+	)
+}
+
+func (s *ClusterSchemer) sqlAddColumn(object *DeclaredObject, column ColumnAddition) string {
+	position := "FIRST"
+	if column.After != "" {
+		position = "AFTER " + quoteIdentifier(column.After)
+	}
+	return fmt.Sprintf(
+		"ALTER TABLE %s.%s ADD COLUMN IF NOT EXISTS %s %s",
+		quoteIdentifier(object.Database),
+		quoteIdentifier(object.Name),
+		column.Definition,
+		position,
+	)
+}
+
+func sqlCreateDatabase(database *api.SchemaDatabase) string {
+	if database.SQL != "" {
+		return database.SQL
+	}
+	sql := "CREATE DATABASE IF NOT EXISTS " + quoteIdentifier(database.Name)
+	if database.Engine != "" {
+		sql += " ENGINE = " + database.Engine
+	}
+	return sql
+}
+
+func sqlCreateTable(table *api.SchemaTable) string {
+	if table.SQL != "" {
+		return table.SQL
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "CREATE TABLE IF NOT EXISTS %s.%s\n", quoteIdentifier(table.Database), quoteIdentifier(table.Name))
+	fmt.Fprintf(b, "(\n%s\n)\n", sqlColumns(table.Columns))
+	fmt.Fprintf(b, "ENGINE = %s\n", table.Engine)
+	clauses := []struct {
+		name  string
+		value string
+	}{
+		{"PARTITION BY", table.PartitionBy},
+		{"PRIMARY KEY", table.PrimaryKey},
+		{"ORDER BY", table.OrderBy},
+		{"TTL", table.TTL},
+	}
+	for _, clause := range clauses {
+		if clause.value != "" {
+			fmt.Fprintf(b, "%s %s\n", clause.name, clause.value)
+		}
+	}
+	if len(table.Settings) > 0 {
+		var names []string
+		for name := range table.Settings {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var settings []string
+		for _, name := range names {
+			settings = append(settings, fmt.Sprintf("%s = %s", name, table.Settings[name]))
+		}
+		fmt.Fprintf(b, "SETTINGS %s\n", strings.Join(settings, ", "))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func sqlCreateMaterializedView(view *api.SchemaMaterializedView) string {
+	if view.SQL != "" {
+		return view.SQL
+	}
+	return fmt.Sprintf(
+		"CREATE MATERIALIZED VIEW IF NOT EXISTS %s.%s TO %s AS %s",
+		quoteIdentifier(view.Database),
+		quoteIdentifier(view.Name),
+		view.To,
+		view.Query,
+	)
+}
+
+func sqlCreateDictionary(dictionary *api.SchemaDictionary) string {
+	if dictionary.SQL != "" {
+		return dictionary.SQL
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "CREATE DICTIONARY IF NOT EXISTS %s.%s\n", quoteIdentifier(dictionary.Database), quoteIdentifier(dictionary.Name))
+	fmt.Fprintf(b, "(\n%s\n)\n", sqlColumns(dictionary.Columns))
+	fmt.Fprintf(b, "PRIMARY KEY %s\n", dictionary.PrimaryKey)
+	fmt.Fprintf(b, "SOURCE(%s)\n", dictionary.Source)
+	fmt.Fprintf(b, "LAYOUT(%s)\n", dictionary.Layout)
+	fmt.Fprintf(b, "LIFETIME(%s)", dictionary.Lifetime)
+	return b.String()
+}
+
+func sqlColumns(columns []*api.SchemaColumn) string {
+	var definitions []string
+	for _, column := range columns {
+		definition := quoteIdentifier(column.Name) + " " + column.Type
+		if column.Default != "" {
+			definition += " " + column.Default
+		}
+		if column.Codec != "" {
+			definition += " CODEC(" + column.Codec + ")"
+		}
+		if column.Comment != "" {
+			definition += " COMMENT " + quoteString(column.Comment)
+		}
+		definitions = append(definitions, "    "+definition)
+	}
+	return strings.Join(definitions, ",\n")
+}
+
+func quoteIdentifier(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "\\`") + "`"
+}
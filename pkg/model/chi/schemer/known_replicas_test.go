@@ -0,0 +1,59 @@
+package schemer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+	chiNormalizer "github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/chi/schemer"
+	commonNormalizer "github.com/altinity/clickhouse-operator/pkg/model/common/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/model/managers"
+)
+
+func init() {
+	chop.New(nil, nil, "")
+}
+
+const fqdnCHI = `
+apiVersion: clickhouse.altinity.com/v1
+kind: ClickHouseInstallation
+metadata:
+  name: fqdn
+  namespace: test
+spec:
+  defaults:
+    replicasUseFQDN: "yes"
+  configuration:
+    clusters:
+      - name: c
+        layout:
+          shardsCount: 1
+          replicasCount: 2
+`
+
+func TestKnownReplicasUseFQDN(t *testing.T) {
+	chi := &api.ClickHouseInstallation{}
+	require.NoError(t, yaml.Unmarshal([]byte(fqdnCHI), chi))
+	normalized, err := chiNormalizer.New(nil, nil).CreateTemplated(chi, commonNormalizer.NewOptions[api.ClickHouseInstallation]())
+	require.NoError(t, err)
+
+	known := schemer.KnownReplicas(managers.NewNameManager(managers.NameManagerTypeClickHouse), normalized)
+	// Tables are registered by {replica} macro, which is pod hostname
+	require.True(t, known["chi-fqdn-c-0-0"])
+	require.True(t, known["chi-fqdn-c-0-1"])
+	require.True(t, known["chi-fqdn-c-0-1.test.svc.cluster.local."])
+
+	registered := []schemer.RegisteredReplica{
+		{Database: "default", Table: "events", ZookeeperPath: "/clickhouse/tables/0/events", Replica: "chi-fqdn-c-0-0", Active: true},
+		// Own replica, which is down at the moment
+		{Database: "default", Table: "events", ZookeeperPath: "/clickhouse/tables/0/events", Replica: "chi-fqdn-c-0-1", Active: false},
+		{Database: "default", Table: "events", ZookeeperPath: "/clickhouse/tables/0/events", Replica: "chi-fqdn-c-0-2", Active: false},
+	}
+	orphans := schemer.OrphanReplicas(registered, func(replica string) bool { return known[replica] })
+	require.Len(t, orphans, 1)
+	require.Equal(t, "chi-fqdn-c-0-2", orphans[0].Replica)
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/MakeNowJust/heredoc"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/interfaces"
+	"github.com/altinity/clickhouse-operator/pkg/model/clickhouse"
+)
+
+// RegisteredReplica describes replica registered in ZooKeeper for a replicated table
+type RegisteredReplica struct {
+	Database      string
+	Table         string
+	ZookeeperPath string
+	Replica       string
+	Active        bool
+}
+
+// HostRegisteredReplicas lists replicas registered in ZooKeeper for all replicated tables of the host
+func (s *ClusterSchemer) HostRegisteredReplicas(ctx context.Context, host *api.Host) ([]RegisteredReplica, error) {
+	var databases, tables, paths, replicas, actives []string
+	if err := s.queryHostColumns(ctx, host, s.sqlRegisteredReplicas(), &databases, &tables, &paths, &replicas, &actives); err != nil {
+		return nil, err
+	}
+	var res []RegisteredReplica
+	for i := range replicas {
+		res = append(res, RegisteredReplica{
+			Database:      databases[i],
+			Table:         tables[i],
+			ZookeeperPath: paths[i],
+			Replica:       replicas[i],
+			Active:        actives[i] == "1",
+		})
+	}
+	return res, nil
+}
+
+// HostDropReplicaByZookeeperPath removes metadata of the replica from ZooKeeper path of a replicated table.
+// The host has to have access to the ZooKeeper the table is registered in
+func (s *ClusterSchemer) HostDropReplicaByZookeeperPath(ctx context.Context, host *api.Host, zookeeperPath, replica string) error {
+	log.V(1).M(host).F().Info("Drop replica %s from ZooKeeper path %s", replica, zookeeperPath)
+	opts := clickhouse.NewQueryOptions().SetRetry(false).SetLogQueries(true)
+	return s.ExecHost(ctx, host, []string{s.sqlDropReplicaByZookeeperPath(zookeeperPath, replica)}, opts)
+}
+
+// KnownReplicas builds set of names the hosts of the CRs may be registered by as replicas.
+// Replicated tables are registered by {replica} macro, which is pod hostname, while instance hostname
+// turns into FQDN in case of .spec.defaults.replicasUseFQDN, so both names are known
+func KnownReplicas(namer interfaces.INameManager, crs ...*api.ClickHouseInstallation) map[string]bool {
+	known := make(map[string]bool)
+	for _, cr := range crs {
+		cr.WalkHosts(func(host *api.Host) error {
+			known[namer.Name(interfaces.NamePodHostname, host)] = true
+			known[namer.Name(interfaces.NameInstanceHostname, host)] = true
+			return nil
+		})
+	}
+	return known
+}
+
+// OrphanReplicas finds inactive replicas, which are unknown. Active replicas are served by some server
+// and are never considered orphaned. Each orphaned replica of a ZooKeeper path is reported once,
+// even in case multiple hosts report the path.
+func OrphanReplicas(registered []RegisteredReplica, known func(replica string) bool) []RegisteredReplica {
+	seen := make(map[string]bool)
+	var orphans []RegisteredReplica
+	for _, replica := range registered {
+		key := replica.ZookeeperPath + "/replicas/" + replica.Replica
+		if replica.Active || known(replica.Replica) || seen[key] {
+			continue
+		}
+		seen[key] = true
+		orphans = append(orphans, replica)
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].ZookeeperPath != orphans[j].ZookeeperPath {
+			return orphans[i].ZookeeperPath < orphans[j].ZookeeperPath
+		}
+		return orphans[i].Replica < orphans[j].Replica
+	})
+	return orphans
+}
+
+// sqlRegisteredReplicas lists all replicas registered for the replicated tables of the host,
+// as replica_is_active map lists all replicas of the ZooKeeper path of the table
+func (s *ClusterSchemer) sqlRegisteredReplicas() string {
+	return heredoc.Doc(`
+		SELECT
+			database,
+			table,
+			zookeeper_path,
+			replica,
+			replica_is_active[replica]
+		FROM
+			system.replicas
+		ARRAY JOIN
+			mapKeys(replica_is_active) AS replica
+		ORDER BY
+			database, table, replica
+		`,
+	)
+}
+
+func (s *ClusterSchemer) sqlDropReplicaByZookeeperPath(zookeeperPath, replica string) string {
+	return fmt.Sprintf("SYSTEM DROP REPLICA %s FROM ZKPATH %s", quoteString(replica), quoteString(zookeeperPath))
+}
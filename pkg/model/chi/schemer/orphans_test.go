@@ -0,0 +1,38 @@
+package schemer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrphanReplicas(t *testing.T) {
+	registered := []RegisteredReplica{
+		// Reported by the first host
+		{Database: "default", Table: "events", ZookeeperPath: "/clickhouse/tables/0/events", Replica: "chi-a-c-0-0", Active: true},
+		{Database: "default", Table: "events", ZookeeperPath: "/clickhouse/tables/0/events", Replica: "chi-a-c-0-1", Active: false},
+		{Database: "default", Table: "events", ZookeeperPath: "/clickhouse/tables/0/events", Replica: "chi-a-c-0-2", Active: false},
+		{Database: "default", Table: "events", ZookeeperPath: "/clickhouse/tables/0/events", Replica: "lost-host", Active: true},
+		// Reported by the second host
+		{Database: "default", Table: "events", ZookeeperPath: "/clickhouse/tables/0/events", Replica: "chi-a-c-0-2", Active: false},
+		{Database: "default", Table: "users", ZookeeperPath: "/clickhouse/tables/0/users", Replica: "chi-a-c-0-2", Active: false},
+	}
+	known := func(replica string) bool {
+		return (replica == "chi-a-c-0-0") || (replica == "chi-a-c-0-1")
+	}
+
+	orphans := OrphanReplicas(registered, known)
+	require.Len(t, orphans, 2)
+	require.Equal(t, "/clickhouse/tables/0/events", orphans[0].ZookeeperPath)
+	require.Equal(t, "chi-a-c-0-2", orphans[0].Replica)
+	require.Equal(t, "/clickhouse/tables/0/users", orphans[1].ZookeeperPath)
+	require.Equal(t, "chi-a-c-0-2", orphans[1].Replica)
+}
+
+func TestSqlDropReplicaByZookeeperPath(t *testing.T) {
+	s := &ClusterSchemer{}
+	require.Equal(t,
+		`SYSTEM DROP REPLICA 'chi-a-c-0-2' FROM ZKPATH '/clickhouse/tables/0/events'`,
+		s.sqlDropReplicaByZookeeperPath("/clickhouse/tables/0/events", "chi-a-c-0-2"),
+	)
+}
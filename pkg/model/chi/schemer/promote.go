@@ -0,0 +1,82 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/clickhouse"
+)
+
+// HostRestartReplicas reloads config of the host and reinitializes replicated tables,
+// so tables reconnect to ZooKeeper as specified by the current config
+func (s *ClusterSchemer) HostRestartReplicas(ctx context.Context, host *api.Host) error {
+	opts := clickhouse.NewQueryOptions().SetRetry(false).SetLogQueries(true)
+	return s.ExecHost(ctx, host, []string{"SYSTEM RELOAD CONFIG", "SYSTEM RESTART REPLICAS"}, opts)
+}
+
+// HostReadOnlyReplicas lists replicated tables of the host along with read-only ones
+func (s *ClusterSchemer) HostReadOnlyReplicas(ctx context.Context, host *api.Host) (tables, readOnly []string, err error) {
+	var databases, names, readOnlyFlags []string
+	if err := s.queryHostColumns(ctx, host, s.sqlReplicasReadOnly(), &databases, &names, &readOnlyFlags); err != nil {
+		return nil, nil, err
+	}
+	for i := range names {
+		table := quoteIdentifier(databases[i]) + "." + quoteIdentifier(names[i])
+		tables = append(tables, table)
+		if readOnlyFlags[i] == "1" {
+			readOnly = append(readOnly, table)
+		}
+	}
+	return tables, readOnly, nil
+}
+
+// HostRestoreReplicas restores metadata of the replicated tables of the host in ZooKeeper out of the local parts.
+// Tables are expected to be read-only, having no metadata in ZooKeeper
+func (s *ClusterSchemer) HostRestoreReplicas(ctx context.Context, host *api.Host, tables []string) error {
+	log.V(1).M(host).F().Info("Restore replicas of the tables: %v", tables)
+	var sqls []string
+	for _, table := range tables {
+		sqls = append(sqls, s.sqlRestoreReplica(table))
+	}
+	opts := clickhouse.NewQueryOptions().SetRetry(false).SetLogQueries(true)
+	return s.ExecHost(ctx, host, sqls, opts)
+}
+
+func (s *ClusterSchemer) sqlReplicasReadOnly() string {
+	return heredoc.Docf(`
+		SELECT
+			database,
+			table,
+			is_readonly
+		FROM
+			system.replicas
+		WHERE
+			database NOT IN (%s)
+		ORDER BY
+			database, table
+		`,
+		ignoredDBs,
+	)
+}
+
+func (s *ClusterSchemer) sqlRestoreReplica(table string) string {
+	return fmt.Sprintf("SYSTEM RESTORE REPLICA %s", table)
+}
@@ -0,0 +1,323 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/clickhouse"
+)
+
+// rebalanceQueryTimeout specifies timeout of data-moving queries
+const rebalanceQueryTimeout = 1 * time.Hour
+
+// RebalanceTable describes table which data can be rebalanced over shards
+type RebalanceTable struct {
+	Database     string
+	Name         string
+	Engine       string
+	PartitionKey string
+}
+
+// IsReplicated checks whether table is replicated
+func (t *RebalanceTable) IsReplicated() bool {
+	return strings.HasPrefix(t.Engine, "Replicated")
+}
+
+// IsPartitioned checks whether table has partition key
+func (t *RebalanceTable) IsPartitioned() bool {
+	return t.PartitionKey != ""
+}
+
+// RebalancePartition describes partition of a table on a host
+type RebalancePartition struct {
+	Partition   string
+	PartitionID string
+	Rows        int64
+	Bytes       int64
+	// IdleSeconds is number of seconds passed since the partition was modified last time
+	IdleSeconds int64
+}
+
+// HostRebalanceTables lists MergeTree tables of the host
+func (s *ClusterSchemer) HostRebalanceTables(ctx context.Context, host *api.Host) ([]RebalanceTable, error) {
+	var databases, names, engines, keys []string
+	if err := s.queryHostColumns(ctx, host, s.sqlRebalanceTables(), &databases, &names, &engines, &keys); err != nil {
+		return nil, err
+	}
+	var tables []RebalanceTable
+	for i := range names {
+		tables = append(tables, RebalanceTable{
+			Database:     databases[i],
+			Name:         names[i],
+			Engine:       engines[i],
+			PartitionKey: keys[i],
+		})
+	}
+	return tables, nil
+}
+
+// HostTablePartitions lists active partitions of the table on the host
+func (s *ClusterSchemer) HostTablePartitions(ctx context.Context, host *api.Host, table RebalanceTable) ([]RebalancePartition, error) {
+	var partitions, ids, rows, bytes, idle []string
+	if err := s.queryHostColumns(ctx, host, s.sqlTablePartitions(table), &partitions, &ids, &rows, &bytes, &idle); err != nil {
+		return nil, err
+	}
+	var res []RebalancePartition
+	for i := range ids {
+		p := RebalancePartition{
+			Partition:   partitions[i],
+			PartitionID: ids[i],
+		}
+		p.Rows, _ = strconv.ParseInt(rows[i], 10, 64)
+		p.Bytes, _ = strconv.ParseInt(bytes[i], 10, 64)
+		p.IdleSeconds, _ = strconv.ParseInt(idle[i], 10, 64)
+		res = append(res, p)
+	}
+	return res, nil
+}
+
+// HostPartitionRows counts rows in the partition of the table on the host
+func (s *ClusterSchemer) HostPartitionRows(ctx context.Context, host *api.Host, table RebalanceTable, partitionID string) (int64, error) {
+	var rows []string
+	if err := s.queryHostColumns(ctx, host, s.sqlPartitionRows(table, partitionID), &rows); err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("no result")
+	}
+	return strconv.ParseInt(rows[0], 10, 64)
+}
+
+// HostCopyPartition copies partition of the table from the specified shard of the cluster into the host
+func (s *ClusterSchemer) HostCopyPartition(ctx context.Context, host *api.Host, table RebalanceTable, fromShardIndex int, partition string) error {
+	log.V(1).M(host).F().Info("Copy partition %s of %s.%s from shard %d", partition, table.Database, table.Name, fromShardIndex)
+	opts := clickhouse.NewQueryOptions().SetRetry(false).SetLogQueries(true)
+	opts.SetQueryTimeout(rebalanceQueryTimeout)
+	return s.ExecHost(ctx, host, []string{s.sqlCopyPartition(host.Runtime.Address.ClusterName, table, fromShardIndex, partition)}, opts)
+}
+
+// HostDropPartition drops partition of the table on the host
+func (s *ClusterSchemer) HostDropPartition(ctx context.Context, host *api.Host, table RebalanceTable, partitionID string) error {
+	log.V(1).M(host).F().Info("Drop partition %s of %s.%s", partitionID, table.Database, table.Name)
+	opts := clickhouse.NewQueryOptions().SetRetry(false).SetLogQueries(true)
+	return s.ExecHost(ctx, host, []string{s.sqlDropPartition(table, partitionID)}, opts)
+}
+
+// HostReplicaZookeeperPath gets ZooKeeper path of the replicated table on the host
+func (s *ClusterSchemer) HostReplicaZookeeperPath(ctx context.Context, host *api.Host, table RebalanceTable) (string, error) {
+	var paths []string
+	if err := s.queryHostColumns(ctx, host, s.sqlReplicaZookeeperPath(table), &paths); err != nil {
+		return "", err
+	}
+	if len(paths) == 0 {
+		return "", fmt.Errorf("table %s.%s is not replicated on host %s", table.Database, table.Name, host.GetName())
+	}
+	return paths[0], nil
+}
+
+// HostMovePartitionToShard moves partition of the table from the host to the shard specified by ZooKeeper path
+func (s *ClusterSchemer) HostMovePartitionToShard(ctx context.Context, host *api.Host, table RebalanceTable, partitionID, zkPath string) error {
+	log.V(1).M(host).F().Info("Move partition %s of %s.%s to shard %s", partitionID, table.Database, table.Name, zkPath)
+	opts := clickhouse.NewQueryOptions().SetRetry(false).SetLogQueries(true)
+	return s.ExecHost(ctx, host, []string{s.sqlMovePartitionToShard(table, partitionID, zkPath)}, opts)
+}
+
+// HostPartMovesInProgress counts unfinished moves of parts of the partition to the shard specified by ZooKeeper path.
+// Last exception of the moves, if any, is returned as well
+func (s *ClusterSchemer) HostPartMovesInProgress(ctx context.Context, host *api.Host, table RebalanceTable, partitionID, zkPath string) (int, string, error) {
+	var counts, exceptions []string
+	if err := s.queryHostColumns(ctx, host, s.sqlPartMovesInProgress(table, partitionID, zkPath), &counts, &exceptions); err != nil {
+		return 0, "", err
+	}
+	if len(counts) == 0 {
+		return 0, "", fmt.Errorf("no result")
+	}
+	count, err := strconv.Atoi(counts[0])
+	return count, exceptions[0], err
+}
+
+// queryHostColumns runs query on the host and unzips result into columns.
+// As opposed to queryUnzipColumns, errors are reported
+func (s *ClusterSchemer) queryHostColumns(ctx context.Context, host *api.Host, sql string, columns ...*[]string) error {
+	query, err := s.QueryHost(ctx, host, sql)
+	defer query.Close()
+	if err != nil {
+		return err
+	}
+	if query == nil {
+		return fmt.Errorf("no result")
+	}
+	return query.UnzipColumnsAsStrings(columns...)
+}
+
+func (s *ClusterSchemer) sqlRebalanceTables() string {
+	return heredoc.Docf(`
+		SELECT
+			database,
+			name,
+			engine,
+			partition_key
+		FROM
+			system.tables
+		WHERE
+			database NOT IN (%s) AND
+			engine LIKE '%%MergeTree' AND
+			NOT is_temporary
+		ORDER BY
+			database, name
+		`,
+		ignoredDBs,
+	)
+}
+
+func (s *ClusterSchemer) sqlTablePartitions(table RebalanceTable) string {
+	return heredoc.Docf(`
+		SELECT
+			partition,
+			partition_id,
+			sum(rows),
+			sum(bytes_on_disk),
+			dateDiff('second', max(modification_time), now())
+		FROM
+			system.parts
+		WHERE
+			active AND
+			database = %s AND
+			table = %s
+		GROUP BY
+			partition, partition_id
+		ORDER BY
+			partition_id
+		`,
+		quoteString(table.Database),
+		quoteString(table.Name),
+	)
+}
+
+func (s *ClusterSchemer) sqlPartitionRows(table RebalanceTable, partitionID string) string {
+	return fmt.Sprintf(
+		`SELECT count() FROM %s.%s WHERE _partition_id = %s`,
+		quoteIdentifier(table.Database),
+		quoteIdentifier(table.Name),
+		quoteString(partitionID),
+	)
+}
+
+// sqlCopyPartition builds INSERT SELECT over cluster() table function. Shard is selected by 1-based _shard_num,
+// so shard numeration of the remote_servers has to follow shard indexes. Partition is selected by partition key,
+// as partition value reported by system.parts is a literal of the partition key.
+// Not partitioned table has the only partition, so the whole table is copied
+func (s *ClusterSchemer) sqlCopyPartition(cluster string, table RebalanceTable, fromShardIndex int, partition string) string {
+	if !table.IsPartitioned() {
+		return heredoc.Docf(`
+			INSERT INTO %s.%s
+			SELECT
+				*
+			FROM
+				cluster(%s, %s, %s)
+			WHERE
+				_shard_num = %d
+			`,
+			quoteIdentifier(table.Database),
+			quoteIdentifier(table.Name),
+			quoteString(cluster),
+			quoteString(table.Database),
+			quoteString(table.Name),
+			fromShardIndex+1,
+		)
+	}
+	return heredoc.Docf(`
+		INSERT INTO %s.%s
+		SELECT
+			*
+		FROM
+			cluster(%s, %s, %s)
+		WHERE
+			_shard_num = %d AND
+			(%s) = %s
+		`,
+		quoteIdentifier(table.Database),
+		quoteIdentifier(table.Name),
+		quoteString(cluster),
+		quoteString(table.Database),
+		quoteString(table.Name),
+		fromShardIndex+1,
+		table.PartitionKey,
+		partition,
+	)
+}
+
+func (s *ClusterSchemer) sqlDropPartition(table RebalanceTable, partitionID string) string {
+	return fmt.Sprintf(
+		`ALTER TABLE %s.%s DROP PARTITION ID %s`,
+		quoteIdentifier(table.Database),
+		quoteIdentifier(table.Name),
+		quoteString(partitionID),
+	)
+}
+
+func (s *ClusterSchemer) sqlReplicaZookeeperPath(table RebalanceTable) string {
+	return fmt.Sprintf(
+		`SELECT zookeeper_path FROM system.replicas WHERE database = %s AND table = %s`,
+		quoteString(table.Database),
+		quoteString(table.Name),
+	)
+}
+
+func (s *ClusterSchemer) sqlMovePartitionToShard(table RebalanceTable, partitionID, zkPath string) string {
+	return fmt.Sprintf(
+		`ALTER TABLE %s.%s MOVE PARTITION ID %s TO SHARD %s`,
+		quoteIdentifier(table.Database),
+		quoteIdentifier(table.Name),
+		quoteString(partitionID),
+		quoteString(zkPath),
+	)
+}
+
+func (s *ClusterSchemer) sqlPartMovesInProgress(table RebalanceTable, partitionID, zkPath string) string {
+	return heredoc.Docf(`
+		SELECT
+			countIf(state NOT IN ('DONE', 'CANCELLED')),
+			anyIf(last_exception, last_exception != '')
+		FROM
+			system.part_moves_between_shards
+		WHERE
+			database = %s AND
+			table = %s AND
+			to_shard = %s AND
+			startsWith(part_name, %s)
+		`,
+		quoteString(table.Database),
+		quoteString(table.Name),
+		quoteString(zkPath),
+		quoteString(partitionID+"_"),
+	)
+}
+
+// quoteString quotes string literal
+func quoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
@@ -0,0 +1,16 @@
+package schemer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLRebalanceQuotesIdentifiers(t *testing.T) {
+	s := &ClusterSchemer{}
+	table := RebalanceTable{Database: "db-1", Name: "ev`ents", Engine: "ReplicatedMergeTree"}
+
+	require.Equal(t, "ALTER TABLE `db-1`.`ev\\`ents` DROP PARTITION ID '202401'", s.sqlDropPartition(table, "202401"))
+	require.Equal(t, "SELECT count() FROM `db-1`.`ev\\`ents` WHERE _partition_id = '202401'", s.sqlPartitionRows(table, "202401"))
+	require.Contains(t, s.sqlCopyPartition("c1", table, 0, "202401"), "INSERT INTO `db-1`.`ev\\`ents`")
+}
@@ -0,0 +1,460 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// keywords are uppercased, so statements can be compared regardless of keywords case
+var keywords = map[string]bool{
+	"CREATE": true, "OR": true, "REPLACE": true, "TABLE": true, "DATABASE": true, "VIEW": true,
+	"MATERIALIZED": true, "DICTIONARY": true, "IF": true, "NOT": true, "EXISTS": true, "ON": true,
+	"CLUSTER": true, "UUID": true, "ENGINE": true, "PARTITION": true, "ORDER": true, "BY": true,
+	"PRIMARY": true, "KEY": true, "SAMPLE": true, "TTL": true, "SETTINGS": true, "COMMENT": true,
+	"TO": true, "AS": true, "SELECT": true, "FROM": true, "WHERE": true, "GROUP": true, "HAVING": true,
+	"LIMIT": true, "JOIN": true, "LEFT": true, "RIGHT": true, "INNER": true, "FULL": true, "ANY": true,
+	"ALL": true, "USING": true, "UNION": true, "DISTINCT": true, "AND": true, "IN": true, "IS": true,
+	"NULL": true, "DEFAULT": true, "ALIAS": true, "EPHEMERAL": true, "CODEC": true, "INDEX": true,
+	"TYPE": true, "GRANULARITY": true, "PROJECTION": true, "CONSTRAINT": true, "CHECK": true,
+	"ASSUME": true, "DELETE": true, "DISK": true, "VOLUME": true, "INTERVAL": true, "ASC": true,
+	"DESC": true, "WITH": true, "FINAL": true, "POPULATE": true, "SOURCE": true, "LAYOUT": true,
+	"LIFETIME": true, "RANGE": true, "MIN": true, "MAX": true, "CASE": true, "WHEN": true, "THEN": true,
+	"ELSE": true, "END": true, "ARRAY": true, "LIKE": true, "BETWEEN": true,
+}
+
+// clauses lists clauses of CREATE statements in the order they are reported in
+var clauses = []string{
+	"ENGINE", "PARTITION BY", "PRIMARY KEY", "ORDER BY", "SAMPLE BY", "TTL", "SETTINGS",
+	"TO", "AS", "SOURCE", "LAYOUT", "LIFETIME", "RANGE", "COMMENT",
+}
+
+// statement is a CREATE statement split into columns and clauses, so statements can be compared
+// regardless of formatting applied by ClickHouse
+type statement struct {
+	columns      map[string]string
+	columnsOrder []string
+	clauses      map[string]string
+}
+
+// tokenize splits SQL into tokens. Quoted identifiers are unquoted, keywords are uppercased
+func tokenize(sql string) (tokens []string) {
+	isWord := func(c byte) bool {
+		return (c == '_') || (c == '$') || (c == '{') || (c == '}') ||
+			((c >= 'a') && (c <= 'z')) || ((c >= 'A') && (c <= 'Z')) || ((c >= '0') && (c <= '9'))
+	}
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case (c == ' ') || (c == '\t') || (c == '\n') || (c == '\r'):
+			i++
+		case c == '\'':
+			j := i + 1
+			for ; j < len(sql); j++ {
+				if sql[j] == '\\' {
+					j++
+					continue
+				}
+				if sql[j] == '\'' {
+					break
+				}
+			}
+			tokens = append(tokens, sql[i:min(j+1, len(sql))])
+			i = j + 1
+		case (c == '`') || (c == '"'):
+			j := strings.IndexByte(sql[i+1:], c)
+			if j < 0 {
+				j = len(sql) - i - 1
+			}
+			tokens = append(tokens, sql[i+1:i+1+j])
+			i = i + j + 2
+		case isWord(c):
+			j := i
+			for ; (j < len(sql)) && isWord(sql[j]); j++ {
+			}
+			word := sql[i:j]
+			if keywords[strings.ToUpper(word)] {
+				word = strings.ToUpper(word)
+			}
+			tokens = append(tokens, word)
+			i = j
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens
+}
+
+// render joins tokens into SQL
+func render(tokens []string) string {
+	b := &strings.Builder{}
+	for i, token := range tokens {
+		if i > 0 {
+			prev := tokens[i-1]
+			switch {
+			case (token == ")") || (token == ",") || (token == ".") || (prev == "(") || (prev == "."):
+			case token == "(" && (prev != "," && prev != "=" && !keywords[prev]):
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(token)
+	}
+	return b.String()
+}
+
+// splitTopLevel splits tokens by commas which are not enclosed into parentheses
+func splitTopLevel(tokens []string) (parts [][]string) {
+	depth := 0
+	start := 0
+	for i, token := range tokens {
+		switch token {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		case ",":
+			if depth == 0 {
+				parts = append(parts, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(tokens) {
+		parts = append(parts, tokens[start:])
+	}
+	return parts
+}
+
+// matchClause checks whether clause starts at the tokens
+func matchClause(tokens []string) (string, int) {
+	for _, clause := range clauses {
+		words := strings.Split(clause, " ")
+		if len(tokens) < len(words) {
+			continue
+		}
+		matched := true
+		for i, word := range words {
+			if tokens[i] != word {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return clause, len(words)
+		}
+	}
+	return "", 0
+}
+
+// parseStatement splits CREATE statement into columns and clauses
+func parseStatement(sql string) *statement {
+	tokens := tokenize(sql)
+	st := &statement{
+		columns: make(map[string]string),
+		clauses: make(map[string]string),
+	}
+
+	// Skip statement head: CREATE [OR REPLACE] <kind> [IF NOT EXISTS] <name> [UUID '<uuid>'] [ON CLUSTER <cluster>]
+	i := 0
+	for (i < len(tokens)) && keywords[tokens[i]] && (tokens[i] != "ON") && (tokens[i] != "UUID") {
+		if clause, _ := matchClause(tokens[i:]); clause != "" {
+			break
+		}
+		i++
+	}
+	for (i < len(tokens)) && (tokens[i] != "(") && !keywords[tokens[i]] {
+		i++
+	}
+	if (i+1 < len(tokens)) && (tokens[i] == "UUID") {
+		i += 2
+	}
+	if (i+2 < len(tokens)) && (tokens[i] == "ON") && (tokens[i+1] == "CLUSTER") {
+		i += 3
+	}
+
+	current := ""
+	var value []string
+	flush := func() {
+		if current != "" {
+			st.clauses[current] = render(normalizeClause(current, value))
+		}
+		current = ""
+		value = nil
+	}
+
+	for depth := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if depth == 0 {
+			if (token == "(") && (current == "" || current == "TO") && (st.columnsOrder == nil) {
+				// Column list
+				end := matchingParenthesis(tokens, i)
+				flush()
+				st.parseColumns(tokens[i+1 : end])
+				i = end
+				continue
+			}
+			if (current != "AS") && (token == "POPULATE") {
+				// POPULATE is applied on creation only
+				continue
+			}
+			if current != "AS" {
+				if clause, n := matchClause(tokens[i:]); clause != "" {
+					flush()
+					current = clause
+					i += n - 1
+					continue
+				}
+			}
+		}
+		switch token {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		}
+		value = append(value, token)
+	}
+	flush()
+
+	return st
+}
+
+// matchingParenthesis finds index of parenthesis closing the one at start
+func matchingParenthesis(tokens []string, start int) int {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens)
+}
+
+// parseColumns parses entries of the column list
+func (st *statement) parseColumns(tokens []string) {
+	st.columnsOrder = []string{}
+	for _, entry := range splitTopLevel(tokens) {
+		if len(entry) == 0 {
+			continue
+		}
+		name := entry[0]
+		switch name {
+		case "INDEX", "PROJECTION", "CONSTRAINT":
+			if len(entry) > 1 {
+				name = name + " " + entry[1]
+			}
+		}
+		st.columns[name] = render(entry)
+		st.columnsOrder = append(st.columnsOrder, name)
+	}
+}
+
+// normalizeClause brings clause into the form it can be compared in
+func normalizeClause(clause string, value []string) []string {
+	switch clause {
+	case "ENGINE":
+		if (len(value) > 0) && (value[0] == "=") {
+			value = value[1:]
+		}
+	case "SETTINGS":
+		var settings []string
+		for _, setting := range splitTopLevel(value) {
+			settings = append(settings, render(setting))
+		}
+		sort.Strings(settings)
+		value = nil
+		for i, setting := range settings {
+			if i > 0 {
+				value = append(value, ",")
+			}
+			value = append(value, tokenize(setting)...)
+		}
+	case "SOURCE", "LAYOUT", "LIFETIME", "RANGE":
+		// Parameters of dictionary clauses are case-insensitive
+		var folded []string
+		for _, token := range value {
+			if !strings.HasPrefix(token, "'") {
+				token = strings.ToUpper(token)
+			}
+			folded = append(folded, token)
+		}
+		value = folded
+	}
+	return value
+}
+
+// engineName gets engine name without arguments
+func engineName(engine string) (string, bool) {
+	if i := strings.Index(engine, "("); i >= 0 {
+		args := strings.TrimSpace(strings.TrimSuffix(engine[i+1:], ")"))
+		return engine[:i], args != ""
+	}
+	return engine, false
+}
+
+// equalEngines compares engines. Engine arguments are compared only when both engines have them specified,
+// since ClickHouse fills omitted arguments with default values
+func equalEngines(expected, actual string) bool {
+	expectedName, expectedArgs := engineName(expected)
+	actualName, actualArgs := engineName(actual)
+	if expectedArgs && actualArgs {
+		return expected == actual
+	}
+	return expectedName == actualName
+}
+
+// DiffStatements lists differences of actual CREATE statement from the expected one
+func DiffStatements(expected, actual string) []string {
+	return diffStatements(parseStatement(expected), parseStatement(actual))
+}
+
+// DiffTableStatements lists differences of actual CREATE TABLE statement from the expected one.
+// Reference is the statement ClickHouse keeps for a table created out of the expected statement, see ReferenceTableSQL.
+// Settings and formatting ClickHouse adds into statements on its own vary between versions,
+// so columns and clauses are compared to the reference. Engine is compared to the expected one,
+// since reference table is not replicated
+func DiffTableStatements(expected, reference, actual string) []string {
+	e := parseStatement(expected)
+	r := parseStatement(reference)
+	r.clauses["ENGINE"] = e.clauses["ENGINE"]
+	return diffStatements(r, parseStatement(actual))
+}
+
+func diffStatements(e, a *statement) (diff []string) {
+	if e.columnsOrder != nil {
+		for _, name := range e.columnsOrder {
+			actualColumn, found := a.columns[name]
+			switch {
+			case !found:
+				diff = append(diff, fmt.Sprintf("%s: missing", name))
+			case actualColumn != e.columns[name]:
+				diff = append(diff, fmt.Sprintf("%s: expected '%s', actual '%s'", name, e.columns[name], actualColumn))
+			}
+		}
+		for _, name := range a.columnsOrder {
+			if _, found := e.columns[name]; !found {
+				diff = append(diff, fmt.Sprintf("%s: unexpected", name))
+			}
+		}
+	}
+
+	for _, clause := range clauses {
+		expectedClause, actualClause := e.clauses[clause], a.clauses[clause]
+		if clause == "ENGINE" && equalEngines(expectedClause, actualClause) {
+			continue
+		}
+		if expectedClause == actualClause {
+			continue
+		}
+		if expectedClause == "" && clause != "SETTINGS" {
+			// Clause is not specified, so it is up to ClickHouse
+			continue
+		}
+		diff = append(diff, fmt.Sprintf("%s: expected '%s', actual '%s'", clause, expectedClause, actualClause))
+	}
+
+	return diff
+}
+
+// ReferenceTableSQL builds statement which creates table named database.name out of the CREATE TABLE statement.
+// Replicated engine is replaced by its non-replicated counterpart, so reference table is not registered in ZooKeeper
+func ReferenceTableSQL(sql, database, name string) string {
+	st := parseStatement(sql)
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "CREATE TABLE %s.%s", quoteIdentifier(database), quoteIdentifier(name))
+	if st.columnsOrder != nil {
+		var columns []string
+		for _, column := range st.columnsOrder {
+			columns = append(columns, st.columns[column])
+		}
+		fmt.Fprintf(b, " (%s)", strings.Join(columns, ", "))
+	}
+	for _, clause := range clauses {
+		value, found := st.clauses[clause]
+		switch {
+		case !found:
+			continue
+		case clause == "ENGINE":
+			fmt.Fprintf(b, " ENGINE = %s", nonReplicatedEngine(value))
+		default:
+			fmt.Fprintf(b, " %s %s", clause, value)
+		}
+	}
+	return b.String()
+}
+
+// nonReplicatedEngine gets non-replicated counterpart of the Replicated*MergeTree engine.
+// ZooKeeper path and replica name arguments are dropped, engine-specific arguments are kept
+func nonReplicatedEngine(engine string) string {
+	tokens := tokenize(engine)
+	if (len(tokens) == 0) || !strings.HasPrefix(tokens[0], "Replicated") {
+		return engine
+	}
+	name := strings.TrimPrefix(tokens[0], "Replicated")
+	if (len(tokens) < 2) || (tokens[1] != "(") {
+		return name
+	}
+	args := splitTopLevel(tokens[2:matchingParenthesis(tokens, 1)])
+	isString := func(arg []string) bool {
+		return (len(arg) == 1) && strings.HasPrefix(arg[0], "'")
+	}
+	if (len(args) >= 2) && isString(args[0]) && isString(args[1]) {
+		args = args[2:]
+	}
+	var rendered []string
+	for _, arg := range args {
+		rendered = append(rendered, render(arg))
+	}
+	return name + "(" + strings.Join(rendered, ", ") + ")"
+}
+
+// ColumnAddition describes column which is missing in a table
+type ColumnAddition struct {
+	// Definition is column definition as it is specified in the column list
+	Definition string
+	// After is name of the column the column has to be added after. Empty for the first column
+	After string
+}
+
+// MissingColumns lists columns of the expected CREATE TABLE statement, which are missing in the actual one
+func MissingColumns(expected, actual string) (missing []ColumnAddition) {
+	e := parseStatement(expected)
+	a := parseStatement(actual)
+	after := ""
+	for _, name := range e.columnsOrder {
+		if strings.Contains(name, " ") {
+			// Indexes, projections and constraints are not columns
+			continue
+		}
+		if _, found := a.columns[name]; !found {
+			missing = append(missing, ColumnAddition{
+				Definition: e.columns[name],
+				After:      after,
+			})
+		}
+		after = name
+	}
+	return missing
+}
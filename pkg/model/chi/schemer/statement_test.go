@@ -0,0 +1,107 @@
+package schemer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+func TestDiffStatementsInSync(t *testing.T) {
+	table := &api.SchemaTable{
+		Database: "default",
+		Name:     "events",
+		Columns: []*api.SchemaColumn{
+			{Name: "ts", Type: "DateTime"},
+			{Name: "id", Type: "UInt64", Codec: "ZSTD(1)"},
+			{Name: "value", Type: "String", Default: "DEFAULT ''", Comment: "payload"},
+		},
+		Engine:      "ReplicatedMergeTree",
+		PartitionBy: "toYYYYMM(ts)",
+		OrderBy:     "(id, ts)",
+	}
+	reference := "CREATE TABLE _clickhouse_operator_schema_reference.table_0 (`ts` DateTime, `id` UInt64 CODEC(ZSTD(1)), `value` String DEFAULT '' COMMENT 'payload') " +
+		"ENGINE = MergeTree PARTITION BY toYYYYMM(ts) ORDER BY (id, ts) SETTINGS index_granularity = 8192"
+	actual := "CREATE TABLE default.events (`ts` DateTime, `id` UInt64 CODEC(ZSTD(1)), `value` String DEFAULT '' COMMENT 'payload') " +
+		"ENGINE = ReplicatedMergeTree('/clickhouse/tables/{uuid}/{shard}', '{replica}') PARTITION BY toYYYYMM(ts) ORDER BY (id, ts) " +
+		"SETTINGS index_granularity = 8192"
+	require.Empty(t, DiffTableStatements(sqlCreateTable(table), reference, actual))
+
+	view := &api.SchemaMaterializedView{
+		Database: "default",
+		Name:     "events_mv",
+		To:       "default.events_daily",
+		Query:    "select toDate(ts) as day, count() as c from default.events group by day",
+	}
+	actual = "CREATE MATERIALIZED VIEW default.events_mv TO default.events_daily (`day` Date, `c` UInt64) " +
+		"AS SELECT toDate(ts) AS day, count() AS c FROM default.events GROUP BY day"
+	require.Empty(t, DiffStatements(sqlCreateMaterializedView(view), actual))
+
+	dictionary := &api.SchemaDictionary{
+		Database:   "default",
+		Name:       "users",
+		Columns:    []*api.SchemaColumn{{Name: "id", Type: "UInt64"}, {Name: "name", Type: "String"}},
+		PrimaryKey: "id",
+		Source:     "clickhouse(table 'users_source')",
+		Layout:     "hashed()",
+		Lifetime:   "min 0 max 300",
+	}
+	actual = "CREATE DICTIONARY default.users (`id` UInt64, `name` String) PRIMARY KEY id " +
+		"SOURCE(CLICKHOUSE(TABLE 'users_source')) LIFETIME(MIN 0 MAX 300) LAYOUT(HASHED())"
+	require.Empty(t, DiffStatements(sqlCreateDictionary(dictionary), actual))
+
+	database := &api.SchemaDatabase{Name: "analytics", Engine: "Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}')"}
+	require.Empty(t, DiffStatements(sqlCreateDatabase(database), "CREATE DATABASE analytics ENGINE = Replicated"))
+}
+
+func TestDiffStatementsDrift(t *testing.T) {
+	expected := "CREATE TABLE IF NOT EXISTS db.t (a UInt64, b String, c Float64) ENGINE = MergeTree ORDER BY a SETTINGS ttl_only_drop_parts = 1"
+	reference := "CREATE TABLE r.table_0 (`a` UInt64, `b` String, `c` Float64) ENGINE = MergeTree ORDER BY a SETTINGS ttl_only_drop_parts = 1, index_granularity = 8192"
+	actual := "CREATE TABLE db.t (`a` UInt32, `b` String, `d` Int8) ENGINE = ReplacingMergeTree ORDER BY (a, b) SETTINGS index_granularity = 8192"
+	require.Equal(t, []string{
+		"a: expected 'a UInt64', actual 'a UInt32'",
+		"c: missing",
+		"d: unexpected",
+		"ENGINE: expected 'MergeTree', actual 'ReplacingMergeTree'",
+		"ORDER BY: expected 'a', actual '(a, b)'",
+		"SETTINGS: expected 'index_granularity = 8192, ttl_only_drop_parts = 1', actual 'index_granularity = 8192'",
+	}, DiffTableStatements(expected, reference, actual))
+}
+
+func TestDiffTableStatementsDefaults(t *testing.T) {
+	// Defaults added by ClickHouse are taken from the reference, so they are not reported as drift
+	expected := "CREATE TABLE db.t (a UInt64) ENGINE = MergeTree ORDER BY a"
+	reference := "CREATE TABLE r.table_0 (`a` UInt64) ENGINE = MergeTree ORDER BY a SETTINGS index_granularity = 8192, min_bytes_for_wide_part = 0"
+	actual := "CREATE TABLE db.t (`a` UInt64) ENGINE = MergeTree ORDER BY a SETTINGS min_bytes_for_wide_part = 0, index_granularity = 8192"
+	require.Empty(t, DiffTableStatements(expected, reference, actual))
+
+	actual = "CREATE TABLE db.t (`a` UInt64) ENGINE = MergeTree ORDER BY a SETTINGS index_granularity = 1024"
+	require.Equal(t, []string{
+		"SETTINGS: expected 'index_granularity = 8192, min_bytes_for_wide_part = 0', actual 'index_granularity = 1024'",
+	}, DiffTableStatements(expected, reference, actual))
+}
+
+func TestReferenceTableSQL(t *testing.T) {
+	sql := "CREATE TABLE IF NOT EXISTS db.t ON CLUSTER '{cluster}' (a UInt64, ver UInt32) " +
+		"ENGINE = ReplicatedReplacingMergeTree('/clickhouse/tables/{shard}/t', '{replica}', ver) ORDER BY a SETTINGS ttl_only_drop_parts = 1"
+	require.Equal(t,
+		"CREATE TABLE `r`.`table_0` (a UInt64, ver UInt32) ENGINE = ReplacingMergeTree(ver) ORDER BY a SETTINGS ttl_only_drop_parts = 1",
+		ReferenceTableSQL(sql, "r", "table_0"),
+	)
+
+	sql = "CREATE TABLE db.t (a UInt64) ENGINE = ReplicatedMergeTree PARTITION BY a % 10 ORDER BY a COMMENT 'events'"
+	require.Equal(t,
+		"CREATE TABLE `r`.`table_0` (a UInt64) ENGINE = MergeTree PARTITION BY a % 10 ORDER BY a COMMENT 'events'",
+		ReferenceTableSQL(sql, "r", "table_0"),
+	)
+}
+
+func TestMissingColumns(t *testing.T) {
+	expected := "CREATE TABLE db.t (a UInt64, b String DEFAULT 'x', c Float64, INDEX i b TYPE bloom_filter GRANULARITY 1) ENGINE = MergeTree ORDER BY a"
+	actual := "CREATE TABLE db.t (`a` UInt64) ENGINE = MergeTree ORDER BY a SETTINGS index_granularity = 8192"
+	require.Equal(t, []ColumnAddition{
+		{Definition: "b String DEFAULT 'x'", After: "a"},
+		{Definition: "c Float64", After: "b"},
+	}, MissingColumns(expected, actual))
+}
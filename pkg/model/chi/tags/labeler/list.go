@@ -54,6 +54,7 @@ var list = types.List{
 	labeler.LabelZookeeperConfigVersion: clickhouse_altinity_com.APIGroupName + "/" + "zookeeper-version",
 	labeler.LabelSettingsConfigVersion:  clickhouse_altinity_com.APIGroupName + "/" + "settings-version",
 	labeler.LabelObjectVersion:          clickhouse_altinity_com.APIGroupName + "/" + "object-version",
+	labeler.LabelTLSVersion:             clickhouse_altinity_com.APIGroupName + "/" + "tls-version",
 
 	// Optional labels
 
@@ -56,9 +56,15 @@ const (
 	// 5. operator-provided additional config files
 	DirPathConfigHost = DirPathConfigRoot + "/" + HostConfigDir + "/"
 
+	// DirPathTLS specifies full path to folder, where certificates provisioned by the operator are mounted
+	DirPathTLS = DirPathConfigRoot + "/" + "tls" + "/"
+
 	// DirPathDataStorage specifies full path of data folder where ClickHouse would place its data storage
 	DirPathDataStorage = "/var/lib/clickhouse-keeper"
 
+	// DirPathSnapshotStorage specifies full path of folder where Keeper places its snapshots
+	DirPathSnapshotStorage = DirPathDataStorage + "/coordination/snapshots"
+
 	// DirPathLogStorage  specifies full path of data folder where ClickHouse would place its log files
 	DirPathLogStorage = "/var/log/clickhouse-keeper"
 )
@@ -74,5 +80,6 @@ const (
 const (
 	configServerId = "server-id"
 	configRaft     = "raft"
+	configOpenSSL  = "openssl"
 	configSettings = "settings"
 )
@@ -85,7 +85,7 @@ func (c *Generator) getRaftConfig(selector *config.HostSelector) string {
 		msg := fmt.Sprintf("SKIP host from RAFT servers: %s", host.GetName())
 		if selector.Include(host) {
 			util.Iline(raft, i, "<server>")
-			util.Iline(raft, i, "    <id>%d</id>", getServerId(host))
+			util.Iline(raft, i, "    <id>%d</id>", GetServerId(host))
 			util.Iline(raft, i, "    <hostname>%s</hostname>", c.namer.Name(interfaces.NameInstanceHostname, host))
 			util.Iline(raft, i, "    <port>%d</port>", host.RaftPort.Value())
 			util.Iline(raft, i, "</server>")
@@ -95,14 +95,24 @@ func (c *Generator) getRaftConfig(selector *config.HostSelector) string {
 		return nil
 	})
 
-	return chi.NewSettings().Set("keeper_server/raft_configuration", chi.MustNewSettingScalarFromAny(raft).SetEmbed()).ClickHouseConfig()
+	// Raft configuration bootstraps the cluster, further membership changes are applied by the operator via reconfig
+	return chi.NewSettings().
+		Set("keeper_server/enable_reconfiguration", chi.MustNewSettingScalarFromAny(true)).
+		Set("keeper_server/raft_configuration", chi.MustNewSettingScalarFromAny(raft).SetEmbed()).
+		ClickHouseConfig()
 }
 
 // getHostServerId builds server id config for the host
 func (c *Generator) getHostServerId(host *chi.Host) string {
-	return chi.NewSettings().Set("keeper_server/server_id", chi.MustNewSettingScalarFromAny(getServerId(host))).ClickHouseConfig()
+	return chi.NewSettings().Set("keeper_server/server_id", chi.MustNewSettingScalarFromAny(GetServerId(host))).ClickHouseConfig()
 }
 
-func getServerId(host *chi.Host) int {
+// GetServerId returns raft server id of the host
+func GetServerId(host *chi.Host) int {
 	return host.GetRuntime().GetAddress().GetReplicaIndex()
 }
+
+// getOpenSSL builds openSSL config for the certificates provisioned by the operator
+func (c *Generator) getOpenSSL() string {
+	return config.OpenSSL(c.cr.GetSpec().GetConfiguration().GetTLS(), DirPathTLS).ClickHouseConfig()
+}
@@ -18,9 +18,11 @@ import (
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
 
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
 	chi "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/altinity/clickhouse-operator/pkg/interfaces"
 	"github.com/altinity/clickhouse-operator/pkg/model/chk/config"
+	"github.com/altinity/clickhouse-operator/pkg/model/chk/snapshot"
 	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
 )
 
@@ -48,6 +50,7 @@ func (cm *ContainerManager) GetAppImageTag(statefulSet *apps.StatefulSet) (strin
 
 func (cm *ContainerManager) EnsureAppContainer(statefulSet *apps.StatefulSet, host *chi.Host) {
 	cm.ensureContainerSpecifiedKeeper(statefulSet, host)
+	cm.ensureSnapshotRestoreContainer(statefulSet, host)
 }
 
 func (cm *ContainerManager) EnsureLogContainer(statefulSet *apps.StatefulSet) {
@@ -90,6 +93,35 @@ func (cm *ContainerManager) ensureContainerSpecifiedKeeper(statefulSet *apps.Sta
 	)
 }
 
+// ensureSnapshotRestoreContainer adds init container restoring Keeper snapshot into the data volume,
+// in case the CR is to be bootstrapped from a snapshot
+func (cm *ContainerManager) ensureSnapshotRestoreContainer(statefulSet *apps.StatefulSet, host *chi.Host) {
+	cr, ok := host.GetCR().(*apiChk.ClickHouseKeeperInstallation)
+	if !ok {
+		return
+	}
+	snapshots := cr.GetSpecT().GetSnapshots()
+	if !snapshots.HasRestore() {
+		return
+	}
+	dataVolume := host.GetTemplates().GetDataVolumeClaimTemplate()
+	if dataVolume == "" {
+		// Nothing to restore into, data is not persisted
+		return
+	}
+	for _, container := range statefulSet.Spec.Template.Spec.InitContainers {
+		if container.Name == snapshot.RestoreContainerName {
+			return
+		}
+	}
+
+	k8s.StatefulSetAppendVolumes(statefulSet, snapshot.Volumes(snapshots)...)
+	statefulSet.Spec.Template.Spec.InitContainers = append(
+		statefulSet.Spec.Template.Spec.InitContainers,
+		snapshot.NewRestoreContainer(snapshots, dataVolume),
+	)
+}
+
 // newDefaultContainerKeeper returns default ClickHouse Container
 func (cm *ContainerManager) newDefaultContainerKeeper(host *chi.Host) core.Container {
 	container := core.Container{
@@ -36,6 +36,12 @@ const (
 	// patternReplicaServiceName is a template of replica Service name. "shard-{chi}-{cluster}-{replica}"
 	patternReplicaServiceName = "shard- + macro.MacrosCRName + - + macro.MacrosClusterName + - + macro.MacrosReplicaName"
 
+	// patternTLSSecretCAName is a template of the Secret with CA certificate. "chk-{chk}-tls-ca"
+	patternTLSSecretCAName = "chk- + macro.List.Get(macroCommon.MacrosCRName) + -tls-ca"
+
+	// patternTLSSecretHostName is a template of the Secret with host certificate. "chk-{chk}-{cluster}-{host}-tls"
+	patternTLSSecretHostName = "chk- + macro.List.Get(macroCommon.MacrosCRName) + - + macro.List.Get(macroCommon.MacrosClusterName) + - + macro.List.Get(macroCommon.MacrosHostName) + -tls"
+
 	// patternStatefulSetName is a template of host StatefulSet's name. "chi-{chi}-{cluster}-{shard}-{host}"
 	patternStatefulSetName = "sts chk- + macro.MacrosCRName + - + macro.MacrosClusterName + - + macro.MacrosHostName"
 
@@ -38,6 +38,16 @@ func (n *Namer) createConfigMapNameHost(host *api.Host) string {
 	return n.macro.Scope(host).Line(patterns.Get(patternConfigMapHostName))
 }
 
+// createTLSSecretCAName returns a name of the Secret with CA certificate
+func (n *Namer) createTLSSecretCAName(cr api.ICustomResource) string {
+	return n.macro.Scope(cr).Line(patterns.Get(patternTLSSecretCAName))
+}
+
+// createTLSSecretHostName returns a name of the Secret with host certificate
+func (n *Namer) createTLSSecretHostName(host *api.Host) string {
+	return n.macro.Scope(host).Line(patterns.Get(patternTLSSecretHostName))
+}
+
 // createCRServiceName creates a name of a root ClickHouseInstallation Service resource
 func (n *Namer) createCRServiceName(cr api.ICustomResource, templates ...*api.ServiceTemplate) string {
 	// Name can be generated either from default name pattern,
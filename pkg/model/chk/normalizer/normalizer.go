@@ -288,6 +288,7 @@ func (n *Normalizer) normalizeConfigurationStage1(c *chk.Configuration) *chk.Con
 // normalizeConfigurationStage2 normalizes .spec.configuration
 func (n *Normalizer) normalizeConfigurationStage2(c *chk.Configuration) *chk.Configuration {
 	n.normalizeConfigurationAllSettingsBasedSections(c)
+	c.TLS = c.TLS.Normalize()
 
 	c.Clusters = n.normalizeClustersStage2(c.Clusters)
 	return c
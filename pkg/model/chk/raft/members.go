@@ -0,0 +1,172 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConfigPath is the path of the node, in which Keeper exposes its current raft configuration
+const ConfigPath = "/keeper/config"
+
+// Member describes one server of the raft configuration
+type Member struct {
+	ID      int
+	Host    string
+	Port    int
+	Learner bool
+	Weight  int
+}
+
+// NewMember creates new participant member
+func NewMember(id int, host string, port int) *Member {
+	return &Member{
+		ID:     id,
+		Host:   host,
+		Port:   port,
+		Weight: 1,
+	}
+}
+
+// String returns member in "server.<id>=<host>:<port>;<role>;<weight>" format, which is accepted by reconfig
+func (m *Member) String() string {
+	role := "participant"
+	if m.Learner {
+		role = "learner"
+	}
+	return fmt.Sprintf("server.%d=%s:%d;%s;%d", m.ID, m.Host, m.Port, role, m.Weight)
+}
+
+// ParseMember parses member from "server.<id>=<host>:<port>[;<role>[;<weight>]]" line
+func ParseMember(line string) (*Member, error) {
+	key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+	if !found || !strings.HasPrefix(key, "server.") {
+		return nil, fmt.Errorf("malformed raft member: %q", line)
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(key, "server."))
+	if err != nil {
+		return nil, fmt.Errorf("malformed raft member id: %q", line)
+	}
+
+	fields := strings.Split(value, ";")
+	idx := strings.LastIndex(fields[0], ":")
+	if idx < 0 {
+		return nil, fmt.Errorf("malformed raft member address: %q", line)
+	}
+	port, err := strconv.Atoi(fields[0][idx+1:])
+	if err != nil {
+		return nil, fmt.Errorf("malformed raft member port: %q", line)
+	}
+
+	member := NewMember(id, fields[0][:idx], port)
+	if len(fields) > 1 {
+		member.Learner = fields[1] == "learner"
+	}
+	if len(fields) > 2 {
+		if member.Weight, err = strconv.Atoi(fields[2]); err != nil {
+			return nil, fmt.Errorf("malformed raft member weight: %q", line)
+		}
+	}
+	return member, nil
+}
+
+// Members is a raft configuration
+type Members []*Member
+
+// ParseMembers parses raft configuration as exposed by Keeper in the ConfigPath node
+func ParseMembers(config string) (Members, error) {
+	var members Members
+	scanner := bufio.NewScanner(strings.NewReader(config))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "server.") {
+			// Skip empty lines and version line, if any
+			continue
+		}
+		member, err := ParseMember(line)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].ID < members[j].ID })
+	return members, nil
+}
+
+// Get returns member with the specified id, if any
+func (m Members) Get(id int) *Member {
+	for _, member := range m {
+		if member.ID == id {
+			return member
+		}
+	}
+	return nil
+}
+
+// Participants returns number of voting members
+func (m Members) Participants() int {
+	num := 0
+	for _, member := range m {
+		if !member.Learner {
+			num++
+		}
+	}
+	return num
+}
+
+// Diff returns members to be added and to be removed in order to move from the current configuration to the desired one.
+// Member which changed its address is listed both as leaving and joining.
+func Diff(current, desired Members) (joining, leaving Members) {
+	for _, member := range desired {
+		if cur := current.Get(member.ID); cur == nil || cur.Host != member.Host || cur.Port != member.Port {
+			joining = append(joining, member)
+		}
+	}
+	for _, member := range current {
+		if des := desired.Get(member.ID); des == nil || des.Host != member.Host || des.Port != member.Port {
+			leaving = append(leaving, member)
+		}
+	}
+	return joining, leaving
+}
+
+// Quorum returns minimal number of participants, required for the configuration of the specified size to operate
+func Quorum(participants int) int {
+	return participants/2 + 1
+}
+
+// CanAdd checks whether a participant can be added to the configuration of the specified size
+// having the specified number of participants in sync. Keeper lets joining participant catch up
+// before it is counted, so the current configuration has to have its quorum in sync to commit the change.
+func CanAdd(participants, synced int) bool {
+	return synced >= Quorum(participants)
+}
+
+// CanRemove checks whether a participant can be removed from the configuration of the specified size
+// having the specified number of participants in sync, without losing quorum of the shrunk configuration.
+func CanRemove(participants, synced int, leavingSynced bool) bool {
+	if participants <= 1 {
+		// Last participant can not be removed
+		return false
+	}
+	if leavingSynced {
+		synced--
+	}
+	return synced >= Quorum(participants-1)
+}
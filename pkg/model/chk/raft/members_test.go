@@ -0,0 +1,62 @@
+package raft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMembers(t *testing.T) {
+	config := "server.1=chk-a-0-0:9234;participant;1\nserver.3=chk-a-0-2:9234;learner;1\nserver.2=chk-a-0-1:9234;participant;1\n"
+	members, err := ParseMembers(config)
+	require.NoError(t, err)
+	require.Len(t, members, 3)
+	require.Equal(t, 2, members[1].ID)
+	require.Equal(t, "chk-a-0-1", members[1].Host)
+	require.Equal(t, 9234, members[1].Port)
+	require.True(t, members[2].Learner)
+	require.Equal(t, 2, members.Participants())
+	require.Equal(t, "server.1=chk-a-0-0:9234;participant;1", members[0].String())
+
+	_, err = ParseMembers("server.x=chk-a-0-0:9234")
+	require.Error(t, err)
+}
+
+func TestDiff(t *testing.T) {
+	current := Members{NewMember(1, "a", 9234), NewMember(2, "b", 9234), NewMember(3, "c", 9234)}
+	desired := Members{NewMember(1, "a", 9234), NewMember(3, "d", 9234), NewMember(4, "e", 9234)}
+
+	joining, leaving := Diff(current, desired)
+	require.Equal(t, []int{3, 4}, ids(joining))
+	require.Equal(t, []int{2, 3}, ids(leaving))
+
+	joining, leaving = Diff(current, current)
+	require.Empty(t, joining)
+	require.Empty(t, leaving)
+}
+
+func TestQuorum(t *testing.T) {
+	require.Equal(t, 1, Quorum(1))
+	require.Equal(t, 2, Quorum(3))
+	require.Equal(t, 3, Quorum(4))
+
+	// 1 -> 2 participants requires the only one to be in sync
+	require.True(t, CanAdd(1, 1))
+	// 3 -> 4 participants requires quorum of 3 in sync
+	require.True(t, CanAdd(3, 2))
+	require.False(t, CanAdd(3, 1))
+
+	// 3 -> 2 participants requires 2 remaining in sync
+	require.True(t, CanRemove(3, 3, true))
+	require.True(t, CanRemove(3, 2, false))
+	require.False(t, CanRemove(3, 2, true))
+	require.False(t, CanRemove(1, 1, true))
+}
+
+func ids(members Members) []int {
+	var res []int
+	for _, member := range members {
+		res = append(res, member.ID)
+	}
+	return res
+}
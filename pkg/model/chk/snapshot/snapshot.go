@@ -0,0 +1,189 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"path"
+	"time"
+
+	core "k8s.io/api/core/v1"
+
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+	"github.com/altinity/clickhouse-operator/pkg/model/chk/config"
+	"github.com/altinity/clickhouse-operator/pkg/model/k8s"
+)
+
+const (
+	// UploadContainerName specifies name of the container copying snapshot into the storage
+	UploadContainerName = "snapshot-upload"
+	// RestoreContainerName specifies name of the init container restoring snapshot from the storage
+	RestoreContainerName = "snapshot-restore"
+
+	// DirPathStorage specifies path PVC storage is mounted at
+	DirPathStorage = "/snapshots"
+	// volumeNameStorage specifies name of the volume of PVC storage
+	volumeNameStorage = "snapshot-storage"
+
+	// timeFormat specifies format of the time the snapshot path ends with
+	timeFormat = "20060102T150405Z"
+)
+
+// uploadScript waits for the snapshot file matching the pattern and copies it into the destination.
+// Snapshot is written by Keeper asynchronously, so it may show up with some delay.
+const uploadScript = `set -e
+SNAPSHOT=""
+for i in $(seq 60); do
+  SNAPSHOT=$(ls -1t "$SNAPSHOT_DIR"/$SNAPSHOT_PATTERN 2>/dev/null | head -n 1)
+  if [ -n "$SNAPSHOT" ]; then break; fi
+  sleep 5
+done
+if [ -z "$SNAPSHOT" ]; then
+  echo "no snapshot matching $SNAPSHOT_PATTERN found in $SNAPSHOT_DIR"
+  exit 1
+fi
+echo "copy $SNAPSHOT to $DESTINATION"
+`
+
+// restoreScript copies snapshot files from the source into the snapshots directory.
+// Hosts which have any coordination state already are not touched.
+const restoreScript = `set -e
+for f in "$COORDINATION_DIR"/*/*; do
+  if [ -e "$f" ]; then
+    echo "coordination state exists in $COORDINATION_DIR, skip restore"
+    exit 0
+  fi
+done
+mkdir -p "$SNAPSHOT_DIR"
+echo "restore $SOURCE into $SNAPSHOT_DIR"
+`
+
+// Path builds path of the snapshot of the cluster taken at the specified time, relative to the storage root
+func Path(namespace, name, cluster string, t time.Time) string {
+	return path.Join(namespace, name, cluster, t.UTC().Format(timeFormat))
+}
+
+// Pattern builds pattern of the snapshot file name with specified last committed log index.
+// Any snapshot file matches in case index is not known
+func Pattern(idx string) string {
+	if idx == "" {
+		idx = "*"
+	}
+	return "snapshot_" + idx + ".bin*"
+}
+
+// NewUploadContainer creates container copying snapshot file matching the pattern from the data volume into the storage
+func NewUploadContainer(snapshots *apiChk.Snapshots, dataVolume, snapshotPath, pattern string) core.Container {
+	storage := snapshots.Storage
+	var script string
+	switch {
+	case storage.S3 != nil:
+		script = uploadScript + `aws s3 cp "$SNAPSHOT" "$DESTINATION/$(basename "$SNAPSHOT")"`
+	default:
+		script = uploadScript + `mkdir -p "$DESTINATION" && cp "$SNAPSHOT" "$DESTINATION/"`
+	}
+
+	container := newContainer(UploadContainerName, snapshots, script, "DESTINATION", location(storage, snapshotPath))
+	container.Env = append(container.Env,
+		core.EnvVar{Name: "SNAPSHOT_PATTERN", Value: pattern},
+	)
+	mount := k8s.CreateVolumeMount(dataVolume, config.DirPathDataStorage)
+	mount.ReadOnly = true
+	container.VolumeMounts = append(container.VolumeMounts, mount)
+	return container
+}
+
+// NewRestoreContainer creates init container restoring snapshot specified in the restore section into the data volume
+func NewRestoreContainer(snapshots *apiChk.Snapshots, dataVolume string) core.Container {
+	storage := snapshots.Storage
+	var script string
+	switch {
+	case storage.S3 != nil:
+		script = restoreScript + `aws s3 cp --recursive "$SOURCE" "$SNAPSHOT_DIR"`
+	default:
+		script = restoreScript + `cp "$SOURCE"/* "$SNAPSHOT_DIR"/`
+	}
+	// Keep ownership of the restored files the same as of the data directory, so Keeper is able to read them
+	script += "\n" + `chown -R "$(stat -c %u:%g ` + config.DirPathDataStorage + `)" "$COORDINATION_DIR"`
+
+	container := newContainer(RestoreContainerName, snapshots, script, "SOURCE", location(storage, snapshots.Restore.Snapshot))
+	container.Env = append(container.Env,
+		core.EnvVar{Name: "COORDINATION_DIR", Value: path.Dir(config.DirPathSnapshotStorage)},
+	)
+	container.VolumeMounts = append(container.VolumeMounts, k8s.CreateVolumeMount(dataVolume, config.DirPathDataStorage))
+	return container
+}
+
+// Volumes creates volumes the snapshot containers need in addition to the data volume
+func Volumes(snapshots *apiChk.Snapshots) []core.Volume {
+	storage := snapshots.Storage
+	if storage.PVC == nil {
+		return nil
+	}
+	return []core.Volume{
+		k8s.CreateVolumeForPVC(volumeNameStorage, storage.PVC.ClaimName),
+	}
+}
+
+// newContainer creates container running the script with the storage location passed in the specified env var
+func newContainer(name string, snapshots *apiChk.Snapshots, script, locationEnv, location string) core.Container {
+	container := core.Container{
+		Name:    name,
+		Image:   snapshots.GetImage(),
+		Command: []string{"/bin/sh", "-c", script},
+		Env: []core.EnvVar{
+			{Name: "SNAPSHOT_DIR", Value: config.DirPathSnapshotStorage},
+			{Name: locationEnv, Value: location},
+		},
+	}
+
+	storage := snapshots.Storage
+	switch {
+	case storage.S3 != nil:
+		// AWS CLI picks settings up from the environment
+		if storage.S3.EndpointURL != "" {
+			container.Env = append(container.Env, core.EnvVar{Name: "AWS_ENDPOINT_URL", Value: storage.S3.EndpointURL})
+		}
+		if storage.S3.Region != "" {
+			container.Env = append(container.Env, core.EnvVar{Name: "AWS_DEFAULT_REGION", Value: storage.S3.Region})
+		}
+		container.Env = appendSecretEnv(container.Env, "AWS_ACCESS_KEY_ID", storage.S3.AccessKeyID)
+		container.Env = appendSecretEnv(container.Env, "AWS_SECRET_ACCESS_KEY", storage.S3.SecretAccessKey)
+	case storage.PVC != nil:
+		container.VolumeMounts = append(container.VolumeMounts, k8s.CreateVolumeMount(volumeNameStorage, DirPathStorage))
+	}
+	return container
+}
+
+// location builds location of the snapshot in the storage as seen from the snapshot containers
+func location(storage *apiChk.SnapshotStorage, snapshotPath string) string {
+	if storage.S3 != nil {
+		return storage.S3.GetURL() + "/" + snapshotPath
+	}
+	return path.Join(DirPathStorage, storage.PVC.Path, snapshotPath)
+}
+
+// appendSecretEnv appends env var referencing the secret the data source points to
+func appendSecretEnv(env []core.EnvVar, name string, source *types.DataSource) []core.EnvVar {
+	if (source == nil) || (source.SecretKeyRef == nil) {
+		return env
+	}
+	return append(env, core.EnvVar{
+		Name: name,
+		ValueFrom: &core.EnvVarSource{
+			SecretKeyRef: source.SecretKeyRef.DeepCopy(),
+		},
+	})
+}
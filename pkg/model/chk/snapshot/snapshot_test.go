@@ -0,0 +1,66 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+)
+
+func TestPath(t *testing.T) {
+	at := time.Date(2026, 10, 17, 2, 30, 0, 0, time.UTC)
+	require.Equal(t, "ns/keeper/main/20261017T023000Z", Path("ns", "keeper", "main", at))
+}
+
+func TestPattern(t *testing.T) {
+	require.Equal(t, "snapshot_100.bin*", Pattern("100"))
+	require.Equal(t, "snapshot_*.bin*", Pattern(""))
+}
+
+func TestNewUploadContainerS3(t *testing.T) {
+	snapshots := &apiChk.Snapshots{
+		Storage: &apiChk.SnapshotStorage{
+			S3: &apiChk.SnapshotS3Storage{
+				URL:         "s3://bucket/keeper/",
+				EndpointURL: "http://minio:9000",
+			},
+		},
+	}
+	container := NewUploadContainer(snapshots, "data", "ns/keeper/main/20261017T023000Z", Pattern("100"))
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	require.Equal(t, "s3://bucket/keeper/ns/keeper/main/20261017T023000Z", env["DESTINATION"])
+	require.Equal(t, "http://minio:9000", env["AWS_ENDPOINT_URL"])
+	require.Equal(t, "snapshot_100.bin*", env["SNAPSHOT_PATTERN"])
+	require.Len(t, container.VolumeMounts, 1)
+	require.True(t, container.VolumeMounts[0].ReadOnly)
+}
+
+func TestNewRestoreContainerPVC(t *testing.T) {
+	snapshots := &apiChk.Snapshots{
+		Storage: &apiChk.SnapshotStorage{
+			PVC: &apiChk.SnapshotPVCStorage{
+				ClaimName: "keeper-snapshots",
+				Path:      "prod",
+			},
+		},
+		Restore: &apiChk.SnapshotRestore{
+			Snapshot: "ns/keeper/main/20261017T023000Z",
+		},
+	}
+	container := NewRestoreContainer(snapshots, "data")
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	require.Equal(t, "/snapshots/prod/ns/keeper/main/20261017T023000Z", env["SOURCE"])
+	require.Equal(t, "/var/lib/clickhouse-keeper/coordination", env["COORDINATION_DIR"])
+	require.Len(t, container.VolumeMounts, 2)
+	require.Len(t, Volumes(snapshots), 1)
+}
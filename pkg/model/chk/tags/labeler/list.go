@@ -53,6 +53,7 @@ var list = types.List{
 	labeler.LabelZookeeperConfigVersion: clickhouse_keeper_altinity_com.APIGroupName + "/" + "zookeeper-version",
 	labeler.LabelSettingsConfigVersion:  clickhouse_keeper_altinity_com.APIGroupName + "/" + "settings-version",
 	labeler.LabelObjectVersion:          clickhouse_keeper_altinity_com.APIGroupName + "/" + "object-version",
+	labeler.LabelTLSVersion:             clickhouse_keeper_altinity_com.APIGroupName + "/" + "tls-version",
 
 	// Optional labels
 
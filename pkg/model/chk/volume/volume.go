@@ -43,6 +43,9 @@ func (m *Manager) SetupVolumes(what interfaces.VolumeType, statefulSet *apps.Sta
 	case interfaces.VolumesUserDataWithFixedPaths:
 		m.stsSetupVolumesUserDataWithFixedPaths(statefulSet, host)
 		return
+	case interfaces.VolumesForTLS:
+		m.stsSetupVolumesForTLS(statefulSet, host)
+		return
 	}
 	panic("unknown volume type")
 }
@@ -75,6 +78,19 @@ func (m *Manager) stsSetupVolumesForConfigMaps(statefulSet *apps.StatefulSet, ho
 	)
 }
 
+// stsSetupVolumesForTLS mounts Secret with host certificate provisioned by the operator into each container
+func (m *Manager) stsSetupVolumesForTLS(statefulSet *apps.StatefulSet, host *api.Host) {
+	secretName := m.namer.Name(interfaces.NameTLSSecretHost, host)
+	k8s.StatefulSetAppendVolumes(
+		statefulSet,
+		k8s.CreateVolumeForSecret(secretName),
+	)
+	k8s.StatefulSetAppendVolumeMountsInAllContainers(
+		statefulSet,
+		k8s.CreateVolumeMount(secretName, config.DirPathTLS),
+	)
+}
+
 // stsSetupVolumesUserDataWithFixedPaths
 // appends VolumeMounts for Data and Log VolumeClaimTemplates on all containers.
 // Creates VolumeMounts for Data and Log volumes in case these volume templates are specified in `templates`.
@@ -20,7 +20,6 @@ import (
 	"crypto/x509"
 	"database/sql"
 	"encoding/base64"
-	"encoding/pem"
 	"fmt"
 	goch "github.com/mailru/go-clickhouse/v2"
 
@@ -137,34 +136,31 @@ func (c *Connection) setupTLSAdvanced() {
 		certBytes = []byte(certString)
 	}
 
-	// Cert may be PEM-encoded - decode PEM block
-	block, _ := pem.Decode(certBytes)
-	if block != nil {
+	// Certificates pool
+	rootCAs := x509.NewCertPool()
+
+	// Cert may be PEM-encoded, possibly a bundle of several certs
+	if rootCAs.AppendCertsFromPEM(certBytes) {
 		// Yes, it is
 		c.l.V(1).F().Info("CERT is PEM-encoded")
-		certBytes = block.Bytes
 	} else {
 		// No, it is not
 		c.l.V(1).F().Info("CERT is not PEM-encoded")
 		// Treat cert string as DER-encoded
-		certBytes = []byte(certString)
+		cert, err := x509.ParseCertificate(certBytes)
+		if err != nil {
+			c.l.V(1).F().Error("unable to parse CERT specified in rootCA err: %v", err)
+			return
+		}
+		rootCAs.AddCert(cert)
 	}
 
-	// Parse DER-encoded cert
-	cert, err := x509.ParseCertificate(certBytes)
-	if err != nil {
-		c.l.V(1).F().Error("unable to parse CERT specified in rootCA err: %v", err)
-		return
-	}
-
-	// Certificates pool
-	rootCAs := x509.NewCertPool()
-	rootCAs.AddCert(cert)
-
 	// Setup TLS
-	err = goch.RegisterTLSConfig(tlsSettings, &tls.Config{
+	// Certificate is verified against the root CA in case it is requested explicitly, such as for CA provisioned
+	// by the operator. Otherwise, skip verification is kept as the fallback
+	err = goch.RegisterTLSConfig(c.params.tlsConfigName(), &tls.Config{
 		RootCAs:            rootCAs,
-		InsecureSkipVerify: true, // TODO: Make it configurable
+		InsecureSkipVerify: !c.params.verifyRootCA,
 	})
 	if err != nil {
 		c.l.V(1).F().Error("unable to register TLS config err: %v", err)
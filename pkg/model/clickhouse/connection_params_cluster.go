@@ -69,5 +69,5 @@ func (p *ClusterConnectionParams) NewEndpointConnectionParams(host string) *Endp
 		p.Password,
 		p.RootCA,
 		p.Port,
-	).SetTimeouts(p.Timeouts)
+	).SetTimeouts(p.Timeouts).SetVerifyRootCA(p.VerifyRootCA)
 }
@@ -36,3 +36,12 @@ func (p *EndpointConnectionParams) SetTimeouts(timeouts *Timeouts) *EndpointConn
 	p.Timeouts = timeouts
 	return p
 }
+
+// SetVerifyRootCA sets whether certificate of the endpoint is verified against root CA
+func (p *EndpointConnectionParams) SetVerifyRootCA(verify bool) *EndpointConnectionParams {
+	if p == nil {
+		return nil
+	}
+	p.setVerifyRootCA(verify)
+	return p
+}
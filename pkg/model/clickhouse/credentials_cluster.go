@@ -21,6 +21,9 @@ type ClusterCredentials struct {
 	Password string
 	RootCA   string
 	Port     int
+	// VerifyRootCA specifies certificates of the endpoints are verified against RootCA.
+	// Otherwise certificates are accepted as is, which is the fallback for RootCA specified by the operator config
+	VerifyRootCA bool
 }
 
 // NewClusterCredentials creates new ClusterCredentials
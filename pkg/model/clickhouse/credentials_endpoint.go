@@ -15,6 +15,8 @@
 package clickhouse
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strconv"
 )
@@ -43,6 +45,8 @@ type EndpointCredentials struct {
 	rootCA   string
 	port     int
 
+	verifyRootCA bool
+
 	// Internal generated data
 	dsn                  string
 	dsnHiddenCredentials string
@@ -60,13 +64,24 @@ func NewEndpointCredentials(scheme, hostname, username, password, rootCA string,
 		port:     port,
 	}
 
-	params.dsn = params.makeDSN(false)
-	params.dsnHiddenCredentials = params.makeDSN(true)
-	params.dsnLogQueries = params.makeDSNLogQueries(false)
+	params.makeDSNs()
 
 	return params
 }
 
+// setVerifyRootCA sets whether certificate of the endpoint is verified against rootCA
+func (c *EndpointCredentials) setVerifyRootCA(verify bool) {
+	c.verifyRootCA = verify
+	c.makeDSNs()
+}
+
+// makeDSNs makes all DSNs of the endpoint
+func (c *EndpointCredentials) makeDSNs() {
+	c.dsn = c.makeDSN(false)
+	c.dsnHiddenCredentials = c.makeDSN(true)
+	c.dsnLogQueries = c.makeDSNLogQueries(false)
+}
+
 // formatUsernamePassword formats username and password pair
 func (c *EndpointCredentials) formatUsernamePassword(username, password string) string {
 	// We may have neither username nor password
@@ -98,6 +113,21 @@ func (c *EndpointCredentials) makeUsernamePassword(hidden bool) string {
 	return c.formatUsernamePassword(c.username, c.password)
 }
 
+// tlsConfigName makes name of the TLS config the connection uses.
+// Each rootCA has its own TLS config registered, since endpoints may trust different CAs,
+// such as ones provisioned by the operator for each CHI.
+func (c *EndpointCredentials) tlsConfigName() string {
+	if c.rootCA == "" {
+		return tlsSettings
+	}
+	sum := sha256.Sum256([]byte(c.rootCA))
+	name := tlsSettings + "-" + hex.EncodeToString(sum[:8])
+	if c.verifyRootCA {
+		name += "-verify"
+	}
+	return name
+}
+
 // makeDSN makes ClickHouse DSN
 func (c *EndpointCredentials) makeDSN(hideCredentials bool) string {
 	baseUrl := fmt.Sprintf(
@@ -108,7 +138,7 @@ func (c *EndpointCredentials) makeDSN(hideCredentials bool) string {
 		strconv.Itoa(c.port),
 	)
 	if c.scheme == httpsScheme {
-		baseUrl += "?tls_config=" + tlsSettings
+		baseUrl += "?tls_config=" + c.tlsConfigName()
 	}
 	return baseUrl
 }
@@ -124,7 +154,7 @@ func (c *EndpointCredentials) makeDSNLogQueries(hideCredentials bool) string {
 	)
 	baseUrl += "?log_queries=1"
 	if c.scheme == httpsScheme {
-		baseUrl += "&tls_config=" + tlsSettings
+		baseUrl += "&tls_config=" + c.tlsConfigName()
 	}
 	return baseUrl
 }
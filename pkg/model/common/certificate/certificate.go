@@ -0,0 +1,253 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+	"time"
+)
+
+// Keys of the Secrets certificates are kept in
+const (
+	// SecretKeyCACert is a key of the CA certificate. Present in both CA and host Secrets
+	SecretKeyCACert = "ca.crt"
+	// SecretKeyCAKey is a key of the CA private key. Present in CA Secret only
+	SecretKeyCAKey = "ca.key"
+	// SecretKeyCACertPrevious is a key of the CA certificate replaced by the last CA rotation. Present in CA Secret only
+	SecretKeyCACertPrevious = "ca-previous.crt"
+	// SecretKeyRenewAt is a key of the earliest time any certificate of the CR has to be renewed. Present in CA Secret only
+	SecretKeyRenewAt = "renew-at"
+	// SecretKeyCert is a key of the host certificate
+	SecretKeyCert = "tls.crt"
+	// SecretKeyKey is a key of the host private key
+	SecretKeyKey = "tls.key"
+)
+
+const (
+	pemTypeCertificate = "CERTIFICATE"
+	pemTypePrivateKey  = "PRIVATE KEY"
+
+	// backdate protects from clock skew between the operator and the hosts
+	backdate = time.Hour
+)
+
+// Certificate is a PEM-encoded certificate along with its private key
+type Certificate struct {
+	Cert []byte
+	Key  []byte
+
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// NewCA issues self-signed CA certificate
+func NewCA(commonName string, validity time.Duration, now time.Time) (*Certificate, error) {
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber(),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-backdate),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return issue(template, nil)
+}
+
+// Issue issues certificate for the specified names signed by the CA.
+// Names may contain both DNS names and IP addresses.
+// Certificate is usable for both server and client authentication, as hosts talk to each other.
+func (ca *Certificate) Issue(commonName string, names []string, validity time.Duration, now time.Time) (*Certificate, error) {
+	if err := ca.parse(); err != nil {
+		return nil, err
+	}
+	notAfter := now.Add(validity)
+	if notAfter.After(ca.cert.NotAfter) {
+		// Certificate can not outlive its CA
+		notAfter = ca.cert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber(),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-backdate),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	for _, name := range names {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, name)
+		}
+	}
+	return issue(template, ca)
+}
+
+// Load loads PEM-encoded certificate and private key.
+// Key may be omitted, in this case certificate can not issue other certificates.
+func Load(cert, key []byte) (*Certificate, error) {
+	c := &Certificate{
+		Cert: cert,
+		Key:  key,
+	}
+	if err := c.parse(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NotAfter gets expiration time of the certificate
+func (c *Certificate) NotAfter() time.Time {
+	if c.parse() != nil {
+		return time.Time{}
+	}
+	return c.cert.NotAfter
+}
+
+// NeedsRenewal checks whether certificate has to be re-issued, as it expires within renewBefore
+func (c *Certificate) NeedsRenewal(renewBefore time.Duration, now time.Time) bool {
+	if c == nil || c.parse() != nil {
+		return true
+	}
+	return now.Add(renewBefore).After(c.cert.NotAfter)
+}
+
+// IsIssuedBy checks whether certificate is signed by the CA
+func (c *Certificate) IsIssuedBy(ca *Certificate) bool {
+	if c == nil || ca == nil || c.parse() != nil || ca.parse() != nil {
+		return false
+	}
+	return c.cert.CheckSignatureFrom(ca.cert) == nil
+}
+
+// HasNames checks whether certificate is issued for exactly the specified names
+func (c *Certificate) HasNames(names []string) bool {
+	if c == nil || c.parse() != nil {
+		return false
+	}
+	var have []string
+	have = append(have, c.cert.DNSNames...)
+	for _, ip := range c.cert.IPAddresses {
+		have = append(have, ip.String())
+	}
+	want := append([]string{}, names...)
+	sort.Strings(have)
+	sort.Strings(want)
+	if len(have) != len(want) {
+		return false
+	}
+	for i := range have {
+		if have[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Fingerprint gets short fingerprint of the certificate, suitable to be used as a label value
+func (c *Certificate) Fingerprint() string {
+	if c == nil {
+		return ""
+	}
+	sum := sha256.Sum256(c.Cert)
+	return hex.EncodeToString(sum[:8])
+}
+
+// parse parses PEM-encoded certificate and key, if not parsed yet
+func (c *Certificate) parse() error {
+	if c == nil {
+		return fmt.Errorf("no certificate")
+	}
+	if c.cert != nil {
+		return nil
+	}
+
+	block, _ := pem.Decode(c.Cert)
+	if block == nil || block.Type != pemTypeCertificate {
+		return fmt.Errorf("unable to decode PEM certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return err
+	}
+
+	if len(c.Key) > 0 {
+		block, _ = pem.Decode(c.Key)
+		if block == nil {
+			return fmt.Errorf("unable to decode PEM private key")
+		}
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return fmt.Errorf("unsupported private key type %T", key)
+		}
+		c.key = signer
+	}
+
+	c.cert = cert
+	return nil
+}
+
+// issue creates certificate by the template. Certificate is self-signed in case no CA provided
+func issue(template *x509.Certificate, ca *Certificate) (*Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, signer := template, crypto.Signer(key)
+	if ca != nil {
+		if ca.key == nil {
+			return nil, fmt.Errorf("CA has no private key")
+		}
+		parent, signer = ca.cert, ca.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(
+		pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: keyDER}),
+	)
+}
+
+// serialNumber makes random certificate serial number
+func serialNumber() *big.Int {
+	serial, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	return serial
+}
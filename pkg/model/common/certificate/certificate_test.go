@@ -0,0 +1,77 @@
+package certificate
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssue(t *testing.T) {
+	now := time.Now()
+	ca, err := NewCA("chi-test-ca", 365*24*time.Hour, now)
+	require.NoError(t, err)
+
+	names := []string{"chi-test-0-0.ns.svc.cluster.local", "chi-test-0-0", "localhost", "127.0.0.1"}
+	host, err := ca.Issue("chi-test-0-0", names, 30*24*time.Hour, now)
+	require.NoError(t, err)
+
+	// Host certificate has to be verifiable by the CA for both server and client usage
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(ca.Cert))
+	pair, err := tls.X509KeyPair(host.Cert, host.Key)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	require.NoError(t, err)
+	for _, usage := range []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth} {
+		_, err = leaf.Verify(x509.VerifyOptions{
+			DNSName:     names[0],
+			Roots:       pool,
+			CurrentTime: now,
+			KeyUsages:   []x509.ExtKeyUsage{usage},
+		})
+		require.NoError(t, err)
+	}
+
+	require.True(t, host.IsIssuedBy(ca))
+	require.True(t, host.HasNames([]string{"127.0.0.1", "localhost", "chi-test-0-0", "chi-test-0-0.ns.svc.cluster.local"}))
+	require.False(t, host.HasNames(names[:2]))
+
+	require.False(t, host.NeedsRenewal(7*24*time.Hour, now))
+	require.True(t, host.NeedsRenewal(7*24*time.Hour, now.Add(24*24*time.Hour)))
+
+	// Certificate can not outlive its CA
+	long, err := ca.Issue("long", names, 10*365*24*time.Hour, now)
+	require.NoError(t, err)
+	require.Equal(t, ca.NotAfter(), long.NotAfter())
+}
+
+func TestLoad(t *testing.T) {
+	now := time.Now()
+	ca, err := NewCA("ca", time.Hour, now)
+	require.NoError(t, err)
+
+	loaded, err := Load(ca.Cert, ca.Key)
+	require.NoError(t, err)
+	require.Equal(t, ca.Fingerprint(), loaded.Fingerprint())
+	_, err = loaded.Issue("host", []string{"host"}, time.Hour, now)
+	require.NoError(t, err)
+
+	// CA loaded without key can not issue certificates
+	public, err := Load(ca.Cert, nil)
+	require.NoError(t, err)
+	_, err = public.Issue("host", []string{"host"}, time.Hour, now)
+	require.Error(t, err)
+
+	other, err := NewCA("other", time.Hour, now)
+	require.NoError(t, err)
+	host, err := loaded.Issue("host", []string{"host"}, time.Hour, now)
+	require.NoError(t, err)
+	require.False(t, host.IsIssuedBy(other))
+
+	_, err = Load([]byte("garbage"), nil)
+	require.Error(t, err)
+	require.True(t, (*Certificate)(nil).NeedsRenewal(time.Hour, now))
+}
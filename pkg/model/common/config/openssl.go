@@ -0,0 +1,43 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/model/common/certificate"
+)
+
+// OpenSSL builds openSSL server and client sections, which point to the certificates provisioned by the operator
+// and mounted into the specified dir. Returns nil in case certificates provisioning is not enabled.
+func OpenSSL(tls *api.TLS, dir string) *api.Settings {
+	if !tls.IsEnabled() {
+		return nil
+	}
+
+	settings := api.NewSettings()
+	for _, side := range []string{"server", "client"} {
+		prefix := "openSSL/" + side + "/"
+		settings.Set(prefix+"certificateFile", api.NewSettingScalar(dir+certificate.SecretKeyCert))
+		settings.Set(prefix+"privateKeyFile", api.NewSettingScalar(dir+certificate.SecretKeyKey))
+		settings.Set(prefix+"caConfig", api.NewSettingScalar(dir+certificate.SecretKeyCACert))
+		settings.Set(prefix+"verificationMode", api.NewSettingScalar(tls.GetVerificationMode()))
+	}
+	// Server trusts only the CA provisioned by the operator,
+	// while client may talk to external services as well, such as object storages
+	settings.Set("openSSL/server/loadDefaultCAFile", api.NewSettingScalar("false"))
+	settings.Set("openSSL/client/loadDefaultCAFile", api.NewSettingScalar("true"))
+	settings.Set("openSSL/client/invalidCertificateHandler/name", api.NewSettingScalar("RejectCertificateHandler"))
+	return settings
+}
@@ -39,3 +39,31 @@ func (c *Creator) CreateClusterSecret(cluster api.ICluster) *core.Secret {
 		Type: core.SecretTypeOpaque,
 	}
 }
+
+// CreateTLSSecretCA creates Secret to keep CA certificate provisioned by the operator in
+func (c *Creator) CreateTLSSecretCA() *core.Secret {
+	return &core.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:       c.cr.GetNamespace(),
+			Name:            c.nm.Name(interfaces.NameTLSSecretCA, c.cr),
+			Labels:          c.macro.Scope(c.cr).Map(c.tagger.Label(interfaces.LabelSecret, c.cr)),
+			Annotations:     c.macro.Scope(c.cr).Map(c.tagger.Annotate(interfaces.AnnotateSecret, c.cr)),
+			OwnerReferences: c.or.CreateOwnerReferences(c.cr),
+		},
+		Type: core.SecretTypeOpaque,
+	}
+}
+
+// CreateTLSSecretHost creates Secret to keep host certificate provisioned by the operator in
+func (c *Creator) CreateTLSSecretHost(host *api.Host) *core.Secret {
+	return &core.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:       c.cr.GetNamespace(),
+			Name:            c.nm.Name(interfaces.NameTLSSecretHost, host),
+			Labels:          c.macro.Scope(host).Map(c.tagger.Label(interfaces.LabelSecret, host)),
+			Annotations:     c.macro.Scope(host).Map(c.tagger.Annotate(interfaces.AnnotateSecret, host)),
+			OwnerReferences: c.or.CreateOwnerReferences(c.cr),
+		},
+		Type: core.SecretTypeOpaque,
+	}
+}
@@ -44,6 +44,8 @@ func (c *Creator) stsSetupAppContainer(statefulSet *apps.StatefulSet, host *api.
 	c.stsEnsureAppContainerNamedPortsSpecified(statefulSet, host)
 	// Setup ENV vars for the app
 	c.stsAppContainerSetupEnvVars(statefulSet, host)
+	// Setup resources recommended for the host (if any)
+	c.stsAppContainerSetupResources(statefulSet, host)
 	// Setup app according to troubleshoot mode (if any)
 	c.stsAppContainerSetupTroubleshootingMode(statefulSet, host)
 }
@@ -97,6 +99,31 @@ func (c *Creator) logEnvVars(host *api.Host, container *core.Container) {
 	}
 }
 
+// stsAppContainerSetupResources overrides CPU and memory of the main application container with the recommended ones.
+// Other resources specified by the pod template are kept as is.
+func (c *Creator) stsAppContainerSetupResources(statefulSet *apps.StatefulSet, host *api.Host) {
+	if host.Runtime.Resources == nil {
+		return
+	}
+	container, ok := c.stsGetAppContainer(statefulSet)
+	if !ok {
+		return
+	}
+
+	if container.Resources.Requests == nil {
+		container.Resources.Requests = core.ResourceList{}
+	}
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = core.ResourceList{}
+	}
+	for name, quantity := range host.Runtime.Resources.Requests {
+		container.Resources.Requests[name] = quantity
+	}
+	for name, quantity := range host.Runtime.Resources.Limits {
+		container.Resources.Limits[name] = quantity
+	}
+}
+
 // stsEnsureAppContainerProbesSpecified
 func (c *Creator) stsEnsureAppContainerProbesSpecified(statefulSet *apps.StatefulSet, host *api.Host) {
 	container, ok := c.stsGetAppContainer(statefulSet)
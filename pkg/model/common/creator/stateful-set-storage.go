@@ -36,6 +36,7 @@ func (c *Creator) stsSetupStorage(statefulSet *apps.StatefulSet, host *api.Host)
 func (c *Creator) stsSetupVolumesSystem(statefulSet *apps.StatefulSet, host *api.Host) {
 	c.stsSetupVolumesForConfigMaps(statefulSet, host)
 	c.stsSetupVolumesForSecrets(statefulSet, host)
+	c.stsSetupVolumesForTLS(statefulSet, host)
 }
 
 func (c *Creator) stsSetupVolumesForConfigMaps(statefulSet *apps.StatefulSet, host *api.Host) {
@@ -47,6 +48,14 @@ func (c *Creator) stsSetupVolumes(what interfaces.VolumeType, statefulSet *apps.
 	c.vm.SetupVolumes(what, statefulSet, host)
 }
 
+// stsSetupVolumesForTLS mounts certificates provisioned by the operator
+func (c *Creator) stsSetupVolumesForTLS(statefulSet *apps.StatefulSet, host *api.Host) {
+	if !host.GetCR().GetSpec().GetConfiguration().GetTLS().IsEnabled() {
+		return
+	}
+	c.stsSetupVolumes(interfaces.VolumesForTLS, statefulSet, host)
+}
+
 // stsSetupVolumesForSecrets adds to each container in the Pod VolumeMount objects
 func (c *Creator) stsSetupVolumesForSecrets(statefulSet *apps.StatefulSet, host *api.Host) {
 	// Add all additional Volumes
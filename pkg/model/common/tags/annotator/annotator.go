@@ -109,10 +109,15 @@ func (a *Annotator) Annotate(what interfaces.AnnotateType, params ...any) map[st
 		}
 
 	case interfaces.AnnotateSecret:
-		var cluster api.ICluster
 		if len(params) > 0 {
-			cluster = params[0].(api.ICluster)
-			return a.getClusterScope(cluster)
+			switch typed := params[0].(type) {
+			case *api.Host:
+				return a.GetHostScope(typed)
+			case api.ICluster:
+				return a.getClusterScope(typed)
+			case api.ICustomResource:
+				return a.GetCRScope()
+			}
 		}
 
 	case interfaces.AnnotateSTS:
@@ -27,6 +27,10 @@ import (
 )
 
 func (l *Labeler) appendConfigLabels(host *api.Host, labels map[string]string) map[string]string {
+	if host.Runtime.TLSVersion != "" {
+		// Certificate provisioned by the operator is mounted into the pod, so pod has to be rolled on rotation
+		labels[l.Get(LabelTLSVersion)] = host.Runtime.TLSVersion
+	}
 	if !host.HasCurStatefulSet() {
 		return labels
 	}
@@ -158,10 +158,15 @@ func (l *Labeler) _labelPDB(cluster api.ICluster) map[string]string {
 }
 
 func (l *Labeler) labelSecret(params ...any) map[string]string {
-	var cluster api.ICluster
 	if len(params) > 0 {
-		cluster = params[0].(api.ICluster)
-		return l._labelSecret(cluster)
+		switch typed := params[0].(type) {
+		case *api.Host:
+			return l.GetHostScope(typed, false)
+		case api.ICluster:
+			return l._labelSecret(typed)
+		case api.ICustomResource:
+			return l.GetCRScope()
+		}
 	}
 	panic("not enough params for labeler")
 }
@@ -48,6 +48,7 @@ const (
 	LabelZookeeperConfigVersion = "APIGroupName" + "/" + "zookeeper-version"
 	LabelSettingsConfigVersion  = "APIGroupName" + "/" + "settings-version"
 	LabelObjectVersion          = "APIGroupName" + "/" + "object-version"
+	LabelTLSVersion             = "APIGroupName" + "/" + "tls-version"
 
 	// Optional labels
 
@@ -45,6 +45,20 @@ func CreateVolumeForConfigMap(volumeName string) core.Volume {
 	}
 }
 
+// CreateVolumeForSecret returns core.Volume object with defined name
+func CreateVolumeForSecret(volumeName string) core.Volume {
+	var defaultMode int32 = 0644
+	return core.Volume{
+		Name: volumeName,
+		VolumeSource: core.VolumeSource{
+			Secret: &core.SecretVolumeSource{
+				SecretName:  volumeName,
+				DefaultMode: &defaultMode,
+			},
+		},
+	}
+}
+
 // CreateVolumeMount returns core.VolumeMount object with name and mount path
 func CreateVolumeMount(name, mountPath string) core.VolumeMount {
 	return core.VolumeMount{
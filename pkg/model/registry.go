@@ -49,6 +49,8 @@ const (
 type Registry struct {
 	r  map[EntityType]*objectMetaSet
 	mu sync.RWMutex
+	// kubeContexts keeps registries of objects, which live in remote kube contexts
+	kubeContexts map[string]*Registry
 }
 
 // objectMetaIdentity is a simple subset of ObjectMeta used strictly within Registry for identifying
@@ -88,7 +90,41 @@ func newObjectMetaSet(entityType EntityType) *objectMetaSet {
 // NewRegistry creates new registry
 func NewRegistry() *Registry {
 	return &Registry{
-		r: make(map[EntityType]*objectMetaSet),
+		r:            make(map[EntityType]*objectMetaSet),
+		kubeContexts: make(map[string]*Registry),
+	}
+}
+
+// KubeContext gets registry of objects, which live in the named remote kube context.
+// Empty name stands for the local kube context, which objects are kept by the registry itself
+func (r *Registry) KubeContext(name string) *Registry {
+	if (r == nil) || (name == "") {
+		return r
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.kubeContexts[name]; !ok {
+		r.kubeContexts[name] = NewRegistry()
+	}
+	return r.kubeContexts[name]
+}
+
+// WalkKubeContexts walks over registries of remote kube contexts
+func (r *Registry) WalkKubeContexts(f func(name string, reg *Registry)) {
+	if r == nil {
+		return
+	}
+
+	r.mu.RLock()
+	kubeContexts := make(map[string]*Registry, len(r.kubeContexts))
+	for name, reg := range r.kubeContexts {
+		kubeContexts[name] = reg
+	}
+	r.mu.RUnlock()
+
+	for name, reg := range kubeContexts {
+		f(name, reg)
 	}
 }
 
@@ -152,6 +188,9 @@ func (r *Registry) String() string {
 	r.Walk(func(entityType EntityType, meta meta.Object) {
 		str += fmt.Sprintf("%s: %s/%s\n", entityType, meta.GetNamespace(), meta.GetName())
 	})
+	r.WalkKubeContexts(func(name string, reg *Registry) {
+		str += fmt.Sprintf("kube context %s:\n%s", name, reg)
+	})
 	return str
 }
 
@@ -318,8 +357,12 @@ func (r *Registry) WalkPDB(f func(meta meta.Object)) {
 	r.walkEntityType(PDB, f)
 }
 
-// Subtract subtracts specified registry from main
+// Subtract subtracts specified registry from main. Objects are subtracted within their kube contexts
 func (r *Registry) Subtract(sub *Registry) *Registry {
+	sub.WalkKubeContexts(func(name string, reg *Registry) {
+		r.KubeContext(name).Subtract(reg)
+	})
+
 	if sub.Len() == 0 {
 		// Nothing to subtract, return base
 		return r
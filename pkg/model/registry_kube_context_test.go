@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Registry_KubeContexts(t *testing.T) {
+	sts := &meta.ObjectMeta{
+		Name:      "chi-a-main-0-0",
+		Namespace: "ns",
+	}
+
+	existing := NewRegistry()
+	existing.RegisterStatefulSet(sts)
+	existing.KubeContext("east").RegisterStatefulSet(sts)
+	existing.KubeContext("west").RegisterStatefulSet(sts)
+
+	reconciled := NewRegistry()
+	reconciled.KubeContext("east").RegisterStatefulSet(sts)
+
+	existing.Subtract(reconciled)
+	require.True(t, existing.HasStatefulSet(sts))
+	require.False(t, existing.KubeContext("east").HasStatefulSet(sts))
+	require.True(t, existing.KubeContext("west").HasStatefulSet(sts))
+	require.Equal(t, existing, existing.KubeContext(""))
+
+	var names []string
+	existing.WalkKubeContexts(func(name string, reg *Registry) {
+		names = append(names, name)
+	})
+	require.ElementsMatch(t, []string{"east", "west"}, names)
+}
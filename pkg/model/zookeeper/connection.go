@@ -140,6 +140,22 @@ func (c *Connection) Delete(ctx context.Context, path string, version int32) err
 	})
 }
 
+// Reconfig changes ensemble membership - adds joining servers and removes leaving ones.
+// Joining servers are specified as "server.<id>=<host>:<port>;participant;<weight>", leaving ones as server ids.
+// Reconfig is not idempotent, thus it is attempted once and is not retried.
+func (c *Connection) Reconfig(ctx context.Context, joining, leaving []string) (stat *zk.Stat, err error) {
+	if err := c.sema.Acquire(ctx, 1); err != nil {
+		return nil, err
+	}
+	defer c.sema.Release(1)
+
+	connection, err := c.ensureConnection(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connection error: %w", err)
+	}
+	return connection.IncrementalReconfig(joining, leaving, -1)
+}
+
 // Close closes the Zookeeper connection if it exists. If the connection is nil, it does nothing.
 func (c *Connection) Close() error {
 	if c == nil {
@@ -660,6 +660,49 @@ func TestConnection_Delete(t *testing.T) {
 	}
 }
 
+func TestConnection_Reconfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		joining       []string
+		leaving       []string
+		setupMock     func(*MockZKClient)
+		expectedError error
+	}{
+		{
+			name:    "success: add member",
+			joining: []string{"server.3=keeper-2:9234;participant;1"},
+			setupMock: func(mockClient *MockZKClient) {
+				mockClient.On("IncrementalReconfig", []string{"server.3=keeper-2:9234;participant;1"}, []string(nil), int64(-1)).Return(&zk.Stat{}, nil).Once()
+			},
+		},
+		{
+			name:    "error: reconfig is not retried",
+			leaving: []string{"3"},
+			setupMock: func(mockClient *MockZKClient) {
+				mockClient.On("IncrementalReconfig", []string(nil), []string{"3"}, int64(-1)).Return((*zk.Stat)(nil), zk.ErrBadArguments).Once()
+			},
+			expectedError: zk.ErrBadArguments,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockZKClient)
+			tt.setupMock(mockClient)
+			conn := newTestConnection(api.ZookeeperNodes{}, mockClient, &ConnectionParams{MaxRetriesNum: 3})
+
+			_, err := conn.Reconfig(context.Background(), tt.joining, tt.leaving)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
 func TestConnection_Close(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -743,6 +786,11 @@ func (m *MockZKClient) AddAuth(scheme string, auth []byte) error {
 	return args.Error(0)
 }
 
+func (m *MockZKClient) IncrementalReconfig(joining, leaving []string, version int64) (*zk.Stat, error) {
+	args := m.Called(joining, leaving, version)
+	return args.Get(0).(*zk.Stat), args.Error(1)
+}
+
 func (m *MockZKClient) Close() {
 	m.Called()
 }
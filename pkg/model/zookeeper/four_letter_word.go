@@ -0,0 +1,200 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zookeeper
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FourLetterWordTimeout specifies default timeout for four-letter-word commands
+const FourLetterWordTimeout = 10 * time.Second
+
+// FourLetterWord sends four-letter-word command, such as "mntr" or "ruok", to the server at address
+// and returns raw response of the server
+func FourLetterWord(ctx context.Context, address, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, FourLetterWordTimeout)
+	defer cancel()
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", fmt.Errorf("unable to connect to %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if _, err := conn.Write([]byte(command)); err != nil {
+		return "", fmt.Errorf("unable to send %s to %s: %w", command, address, err)
+	}
+	response, err := io.ReadAll(conn)
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s response from %s: %w", command, address, err)
+	}
+	return string(response), nil
+}
+
+// Ruok checks whether the server at address is running in a non-error state
+func Ruok(ctx context.Context, address string) (bool, error) {
+	response, err := FourLetterWord(ctx, address, "ruok")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(response) == "imok", nil
+}
+
+// Mntr requests monitoring variables of the server at address.
+// Response lines of "<name>\t<value>" format are returned as a map
+func Mntr(ctx context.Context, address string) (map[string]string, error) {
+	response, err := FourLetterWord(ctx, address, "mntr")
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse(response, "\t"), nil
+}
+
+// Srvr requests full details of the server at address.
+// Response lines of "<name>: <value>" format are returned as a map
+func Srvr(ctx context.Context, address string) (map[string]string, error) {
+	response, err := FourLetterWord(ctx, address, "srvr")
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse(response, ":"), nil
+}
+
+// Lgif requests raft log information of the Keeper server at address.
+// Response lines of "<name>\t<value>" format are returned as a map
+func Lgif(ctx context.Context, address string) (map[string]string, error) {
+	response, err := FourLetterWord(ctx, address, "lgif")
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse(response, "\t"), nil
+}
+
+// Csnp schedules creation of a snapshot on the Keeper server at address.
+// Last committed log index the snapshot is created with is returned
+func Csnp(ctx context.Context, address string) (int64, error) {
+	response, err := FourLetterWord(ctx, address, "csnp")
+	if err != nil {
+		return 0, err
+	}
+	// Response looks like "Snapshot creation scheduled with last committed log index 100."
+	fields := strings.Fields(strings.TrimSuffix(strings.TrimSpace(response), "."))
+	if len(fields) > 0 {
+		if idx, err := strconv.ParseInt(fields[len(fields)-1], 10, 64); err == nil {
+			return idx, nil
+		}
+	}
+	return 0, fmt.Errorf("unable to create snapshot: %s", strings.TrimSpace(response))
+}
+
+func parseResponse(response, separator string) map[string]string {
+	res := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(response))
+	for scanner.Scan() {
+		name, value, found := strings.Cut(scanner.Text(), separator)
+		if !found {
+			continue
+		}
+		res[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return res
+}
+
+// ServerState is the state of the server as reported by four-letter-word commands
+type ServerState struct {
+	// OK specifies whether the server reports it is running in a non-error state
+	OK bool
+	// Mode is the role of the server, such as leader, follower or standalone
+	Mode                  string
+	Version               string
+	Zxid                  int64
+	OutstandingRequests   int64
+	EphemeralsCount       int64
+	ZNodeCount            int64
+	LastCommittedLogIdx   int64
+	LeaderCommittedLogIdx int64
+}
+
+// GetServerState queries state of the server at address with ruok, mntr, srvr and lgif commands
+func GetServerState(ctx context.Context, address string) (*ServerState, error) {
+	ok, err := Ruok(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	state := &ServerState{
+		OK: ok,
+	}
+	if !ok {
+		return state, nil
+	}
+
+	mntr, err := Mntr(ctx, address)
+	if err != nil {
+		return state, err
+	}
+	state.Mode = mntr["zk_server_state"]
+	state.Version = mntr["zk_version"]
+	state.OutstandingRequests = parseInt(mntr["zk_outstanding_requests"])
+	state.EphemeralsCount = parseInt(mntr["zk_ephemerals_count"])
+	state.ZNodeCount = parseInt(mntr["zk_znode_count"])
+
+	srvr, err := Srvr(ctx, address)
+	if err != nil {
+		return state, err
+	}
+	state.Zxid = parseInt(srvr["Zxid"])
+	if mode, ok := srvr["Mode"]; ok {
+		state.Mode = mode
+	}
+
+	lgif, err := Lgif(ctx, address)
+	if err != nil {
+		return state, err
+	}
+	state.LastCommittedLogIdx = parseInt(lgif["last_committed_log_idx"])
+	state.LeaderCommittedLogIdx = parseInt(lgif["leader_committed_log_idx"])
+
+	return state, nil
+}
+
+// IsLeader checks whether the server is the leader of the ensemble
+func (s *ServerState) IsLeader() bool {
+	return (s != nil) && (s.Mode == "leader" || s.Mode == "standalone")
+}
+
+// IsFollower checks whether the server is a follower of the ensemble
+func (s *ServerState) IsFollower() bool {
+	return (s != nil) && (s.Mode == "follower")
+}
+
+// parseInt parses decimal as well as "0x"-prefixed hex value. Malformed value is parsed as 0
+func parseInt(value string) int64 {
+	res, err := strconv.ParseInt(value, 0, 64)
+	if err != nil {
+		return 0
+	}
+	return res
+}
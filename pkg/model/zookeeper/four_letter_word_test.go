@@ -0,0 +1,101 @@
+package zookeeper
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// startFakeServer starts TCP server answering four-letter-word commands with the provided responses
+func startFakeServer(t *testing.T, responses map[string]string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				command := make([]byte, 4)
+				if _, err := io.ReadFull(conn, command); err != nil {
+					return
+				}
+				_, _ = conn.Write([]byte(responses[string(command)]))
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestMntr(t *testing.T) {
+	address := startFakeServer(t, map[string]string{
+		"mntr": "zk_version\tv24.8.1.1-stable\nzk_server_state\tleader\nzk_synced_followers\t2\n",
+	})
+
+	mntr, err := Mntr(context.Background(), address)
+	require.NoError(t, err)
+	require.Equal(t, "leader", mntr["zk_server_state"])
+	require.Equal(t, "2", mntr["zk_synced_followers"])
+	require.Len(t, mntr, 3)
+}
+
+func TestGetServerState(t *testing.T) {
+	address := startFakeServer(t, map[string]string{
+		"ruok": "imok",
+		"mntr": "zk_version\tv24.8.1.1-stable\nzk_server_state\tfollower\nzk_outstanding_requests\t3\nzk_ephemerals_count\t7\nzk_znode_count\t42\n",
+		"srvr": "ClickHouse Keeper version: v24.8.1.1-stable\nLatency min/avg/max: 0/0/1\nOutstanding: 3\nZxid: 0x1f\nMode: follower\nNode count: 42\n",
+		"lgif": "first_log_idx\t1\nlast_log_idx\t31\nlast_committed_log_idx\t30\nleader_committed_log_idx\t31\n",
+	})
+
+	state, err := GetServerState(context.Background(), address)
+	require.NoError(t, err)
+	require.True(t, state.OK)
+	require.True(t, state.IsFollower())
+	require.False(t, state.IsLeader())
+	require.Equal(t, "v24.8.1.1-stable", state.Version)
+	require.Equal(t, int64(31), state.Zxid)
+	require.Equal(t, int64(3), state.OutstandingRequests)
+	require.Equal(t, int64(7), state.EphemeralsCount)
+	require.Equal(t, int64(42), state.ZNodeCount)
+	require.Equal(t, int64(30), state.LastCommittedLogIdx)
+	require.Equal(t, int64(31), state.LeaderCommittedLogIdx)
+}
+
+func TestGetServerStateNotOK(t *testing.T) {
+	address := startFakeServer(t, map[string]string{
+		"ruok": "",
+	})
+
+	state, err := GetServerState(context.Background(), address)
+	require.NoError(t, err)
+	require.False(t, state.OK)
+	require.False(t, state.IsLeader())
+}
+
+func TestCsnp(t *testing.T) {
+	address := startFakeServer(t, map[string]string{
+		"csnp": "Snapshot creation scheduled with last committed log index 100.",
+	})
+	idx, err := Csnp(context.Background(), address)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), idx)
+
+	address = startFakeServer(t, map[string]string{
+		"csnp": "Failed to schedule snapshot creation task.",
+	})
+	_, err = Csnp(context.Background(), address)
+	require.Error(t, err)
+}
+
+func TestMntrUnreachable(t *testing.T) {
+	_, err := Mntr(context.Background(), "127.0.0.1:1")
+	require.Error(t, err)
+}
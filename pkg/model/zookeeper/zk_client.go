@@ -26,5 +26,6 @@ type ZKClient interface {
 	Delete(path string, version int32) error
 	Exists(path string) (bool, *zk.Stat, error)
 	AddAuth(scheme string, auth []byte) error
+	IncrementalReconfig(joining, leaving []string, version int64) (*zk.Stat, error)
 	Close()
 }
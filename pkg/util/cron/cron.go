@@ -0,0 +1,183 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field describes one field of the cron expression
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+// searchLimit limits how far in the future the next matching time is searched for
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// Schedule is a parsed standard 5-field cron expression: minute hour day-of-month month day-of-week
+type Schedule struct {
+	minute, hour, dom, month, dow map[int]bool
+	// domAny and dowAny specify whether day-of-month and day-of-week are not restricted
+	domAny, dowAny bool
+}
+
+// Parse parses standard 5-field cron expression, such as "30 2 * * 1-5".
+// Each field accepts '*', single values, ranges 'a-b', lists 'a,b' and steps '*/n', 'a-b/n'
+func Parse(spec string) (*Schedule, error) {
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron expression '%s' has %d fields, expected %d", spec, len(parts), len(fields))
+	}
+
+	sets := make([]map[int]bool, len(fields))
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression '%s': %v", spec, err)
+		}
+		sets[i] = set
+	}
+
+	// Sunday may be specified as both 0 and 7
+	if sets[4][7] {
+		sets[4][0] = true
+		delete(sets[4], 7)
+	}
+
+	return &Schedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: parts[2] == "*",
+		dowAny: parts[4] == "*",
+	}, nil
+}
+
+// parseField parses one field of the cron expression into the set of allowed values
+func parseField(spec string, f field) (map[int]bool, error) {
+	set := make(map[int]bool)
+	for _, item := range strings.Split(spec, ",") {
+		rng, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			rng = item[:i]
+			if step, err = strconv.Atoi(item[i+1:]); (err != nil) || (step < 1) {
+				return nil, fmt.Errorf("bad step in %s field: '%s'", f.name, item)
+			}
+		}
+
+		from, to := f.min, f.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			bounds := strings.SplitN(rng, "-", 2)
+			var err1, err2 error
+			from, err1 = strconv.Atoi(bounds[0])
+			to, err2 = strconv.Atoi(bounds[1])
+			if (err1 != nil) || (err2 != nil) {
+				return nil, fmt.Errorf("bad range in %s field: '%s'", f.name, item)
+			}
+		default:
+			value, err := strconv.Atoi(rng)
+			if err != nil {
+				return nil, fmt.Errorf("bad value in %s field: '%s'", f.name, item)
+			}
+			from, to = value, value
+			if step > 1 {
+				// 'a/n' means from 'a' till the end of the range
+				to = f.max
+			}
+		}
+
+		if (from < f.min) || (to > f.max) || (from > to) {
+			return nil, fmt.Errorf("%s field '%s' is out of range %d-%d", f.name, item, f.min, f.max)
+		}
+		for v := from; v <= to; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// matchDay checks whether day matches the schedule.
+// In case both day-of-month and day-of-week are restricted, either of them has to match
+func (s *Schedule) matchDay(t time.Time) bool {
+	dom := s.dom[t.Day()]
+	dow := s.dow[int(t.Weekday())]
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// Matches checks whether time matches the schedule up to a minute
+func (s *Schedule) Matches(t time.Time) bool {
+	if s == nil {
+		return false
+	}
+	return s.minute[t.Minute()] && s.hour[t.Hour()] && s.month[int(t.Month())] && s.matchDay(t)
+}
+
+// Next finds the first time not before t, which matches the schedule.
+// Time is searched in the location of t. Zero time is returned in case nothing matches within the search limit
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+
+	// Round up to the whole minute
+	if rounded := t.Truncate(time.Minute); rounded.Before(t) {
+		t = rounded.Add(time.Minute)
+	}
+
+	limit := t.Add(searchLimit)
+	loc := t.Location()
+	for t.Before(limit) {
+		switch {
+		case !s.month[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !s.hour[t.Hour()]:
+			// Step by the wall clock, as locations may be offset from UTC by a fraction of an hour
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !s.minute[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
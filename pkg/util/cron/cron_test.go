@@ -0,0 +1,88 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for _, spec := range []string{"* * * * *", "0 2 * * 6", "*/15 1-5 1,15 * 1-5", "0 0 * * 7", "5/10 * * * *"} {
+		_, err := Parse(spec)
+		require.NoError(t, err, spec)
+	}
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		_, err := Parse(spec)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestNext(t *testing.T) {
+	// Saturday
+	now := time.Date(2024, 3, 16, 10, 30, 20, 0, time.UTC)
+
+	for _, test := range []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2024, 3, 16, 10, 31, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2024, 3, 17, 2, 0, 0, 0, time.UTC)},
+		{"45 10 * * 6", time.Date(2024, 3, 16, 10, 45, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 3 1 * *", time.Date(2024, 4, 1, 3, 0, 0, 0, time.UTC)},
+		{"0 3 29 2 *", time.Date(2028, 2, 29, 3, 0, 0, 0, time.UTC)},
+		{"0 3 1 * 1", time.Date(2024, 3, 18, 3, 0, 0, 0, time.UTC)},
+	} {
+		schedule, err := Parse(test.spec)
+		require.NoError(t, err)
+		require.Equal(t, test.next, schedule.Next(now), test.spec)
+		require.True(t, schedule.Matches(test.next), test.spec)
+	}
+}
+
+func TestNextInLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	schedule, err := Parse("0 2 * * *")
+	require.NoError(t, err)
+
+	now := time.Date(2024, 3, 16, 22, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2024, 3, 16, 23, 0, 0, 0, time.UTC), schedule.Next(now.In(loc)).UTC())
+}
+
+func TestNextInFractionalOffsetLocation(t *testing.T) {
+	tests := []struct {
+		spec string
+		now  time.Time
+		next time.Time
+	}{
+		{
+			spec: "0 2 * * *",
+			now:  time.Date(2024, 3, 16, 10, 17, 0, 0, time.UTC),
+			next: time.Date(2024, 3, 17, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			spec: "30 * * * *",
+			now:  time.Date(2024, 3, 16, 10, 45, 0, 0, time.UTC),
+			next: time.Date(2024, 3, 16, 11, 30, 0, 0, time.UTC),
+		},
+		{
+			spec: "0 0 * * 1",
+			now:  time.Date(2024, 3, 16, 23, 50, 0, 0, time.UTC),
+			next: time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, name := range []string{"Asia/Kolkata", "Asia/Kathmandu", "Australia/Adelaide"} {
+		loc, err := time.LoadLocation(name)
+		require.NoError(t, err)
+		for _, test := range tests {
+			schedule, err := Parse(test.spec)
+			require.NoError(t, err)
+			// Wall clock of the location is expected to match the schedule
+			now := time.Date(test.now.Year(), test.now.Month(), test.now.Day(), test.now.Hour(), test.now.Minute(), 0, 0, loc)
+			next := time.Date(test.next.Year(), test.next.Month(), test.next.Day(), test.next.Hour(), test.next.Minute(), 0, 0, loc)
+			require.Equal(t, next, schedule.Next(now), name+" "+test.spec)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfSignedCertificate(t *testing.T) {
+	now := time.Now()
+	cert, err := NewSelfSignedCertificate([]string{"svc.ns.svc", "svc.ns"}, 30*24*time.Hour, now)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode(cert.CABundle)
+	require.NotNil(t, block)
+	ca, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	leaf, err := x509.ParseCertificate(cert.Cert.Certificate[0])
+	require.NoError(t, err)
+	_, err = leaf.Verify(x509.VerifyOptions{
+		DNSName:     "svc.ns.svc",
+		Roots:       pool,
+		CurrentTime: now,
+	})
+	require.NoError(t, err)
+
+	require.False(t, cert.NeedsRotation(now))
+	require.True(t, cert.NeedsRotation(now.Add(25*24*time.Hour)))
+}
@@ -0,0 +1,200 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	admission "k8s.io/api/admission/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+)
+
+const (
+	// ValidatePath is a path validating webhook is served at
+	ValidatePath = "/validate"
+	// MutatePath is a path mutating webhook is served at
+	MutatePath = "/mutate"
+
+	// maxReviewSize limits size of admission review request
+	maxReviewSize = 16 * 1024 * 1024
+)
+
+// Kinds of the objects served
+const (
+	kindCHI  = "ClickHouseInstallation"
+	kindCHIT = "ClickHouseInstallationTemplate"
+	kindCHK  = "ClickHouseKeeperInstallation"
+)
+
+// NewHandler creates http handler which serves both validating and mutating admission reviews
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ValidatePath, func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, validate)
+	})
+	mux.HandleFunc(MutatePath, func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, mutate)
+	})
+	return mux
+}
+
+// serve decodes admission review, reviews the request and writes the response
+func serve(w http.ResponseWriter, r *http.Request, review func(*admission.AdmissionRequest) *admission.AdmissionResponse) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxReviewSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var ar admission.AdmissionReview
+	if err := json.Unmarshal(body, &ar); err != nil {
+		http.Error(w, fmt.Sprintf("unable to decode admission review: %v", err), http.StatusBadRequest)
+		return
+	}
+	if ar.Request == nil {
+		http.Error(w, "admission review has no request", http.StatusBadRequest)
+		return
+	}
+
+	response := review(ar.Request)
+	response.UID = ar.Request.UID
+	ar.Response = response
+	ar.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&ar); err != nil {
+		log.V(1).F().Error("unable to write admission review response: %v", err)
+	}
+}
+
+// isReviewed checks whether the request has to be reviewed
+func isReviewed(req *admission.AdmissionRequest) bool {
+	switch req.Operation {
+	case admission.Create, admission.Update:
+	default:
+		return false
+	}
+	// Objects in namespaces not watched by the operator are not reconciled by it, so they are not reviewed
+	return chop.Config().IsNamespaceWatched(req.Namespace)
+}
+
+// validate reviews validating admission request
+func validate(req *admission.AdmissionRequest) *admission.AdmissionResponse {
+	if !isReviewed(req) {
+		return allowed()
+	}
+
+	var errs field.ErrorList
+	var name string
+	switch req.Kind.Kind {
+	case kindCHI:
+		chi := &api.ClickHouseInstallation{}
+		if err := json.Unmarshal(req.Object.Raw, chi); err != nil {
+			return denied(err)
+		}
+		name = chi.GetName()
+		errs = ValidateCHI(chi)
+	case kindCHIT:
+		chit := &api.ClickHouseInstallationTemplate{}
+		if err := json.Unmarshal(req.Object.Raw, chit); err != nil {
+			return denied(err)
+		}
+		name = chit.GetName()
+		errs = ValidateCHIT(chit)
+	case kindCHK:
+		chk := &apiChk.ClickHouseKeeperInstallation{}
+		if err := json.Unmarshal(req.Object.Raw, chk); err != nil {
+			return denied(err)
+		}
+		name = chk.GetName()
+		errs = ValidateCHK(chk)
+	default:
+		return allowed()
+	}
+
+	if len(errs) == 0 {
+		return allowed()
+	}
+
+	log.V(1).M(req.Namespace, name).F().Info("%s rejected: %v", req.Kind.Kind, errs.ToAggregate())
+	status := apiErrors.NewInvalid(schema.GroupKind{Group: req.Kind.Group, Kind: req.Kind.Kind}, name, errs).ErrStatus
+	return &admission.AdmissionResponse{
+		Allowed: false,
+		Result:  &status,
+	}
+}
+
+// mutate reviews mutating admission request
+func mutate(req *admission.AdmissionRequest) *admission.AdmissionResponse {
+	if !isReviewed(req) {
+		return allowed()
+	}
+	switch req.Kind.Kind {
+	case kindCHI, kindCHIT, kindCHK:
+	default:
+		return allowed()
+	}
+
+	patch, err := Mutate(req.Object.Raw)
+	if err != nil {
+		return denied(err)
+	}
+	if len(patch) == 0 {
+		return allowed()
+	}
+	bytes, err := json.Marshal(patch)
+	if err != nil {
+		return denied(err)
+	}
+	patchType := admission.PatchTypeJSONPatch
+	return &admission.AdmissionResponse{
+		Allowed:   true,
+		Patch:     bytes,
+		PatchType: &patchType,
+	}
+}
+
+func allowed() *admission.AdmissionResponse {
+	return &admission.AdmissionResponse{
+		Allowed: true,
+	}
+}
+
+func denied(err error) *admission.AdmissionResponse {
+	return &admission.AdmissionResponse{
+		Allowed: false,
+		Result: &meta.Status{
+			Status:  meta.StatusFailure,
+			Code:    http.StatusBadRequest,
+			Reason:  meta.StatusReasonBadRequest,
+			Message: err.Error(),
+		},
+	}
+}
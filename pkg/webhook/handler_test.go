@@ -0,0 +1,128 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admission "k8s.io/api/admission/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// review sends admission review of CHI to the handler and returns the response
+func review(t *testing.T, path string, chi string) *admission.AdmissionResponse {
+	body, err := json.Marshal(&admission.AdmissionReview{
+		TypeMeta: meta.TypeMeta{
+			APIVersion: "admission.k8s.io/v1",
+			Kind:       "AdmissionReview",
+		},
+		Request: &admission.AdmissionRequest{
+			UID:       types.UID("uid"),
+			Kind:      meta.GroupVersionKind{Group: "clickhouse.altinity.com", Version: "v1", Kind: kindCHI},
+			Operation: admission.Create,
+			Namespace: "test",
+			Object:    runtime.RawExtension{Raw: []byte(chi)},
+		},
+	})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	NewHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var ar admission.AdmissionReview
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &ar))
+	require.NotNil(t, ar.Response)
+	require.Equal(t, types.UID("uid"), ar.Response.UID)
+	return ar.Response
+}
+
+func TestHandlerValidate(t *testing.T) {
+	response := review(t, ValidatePath, `{
+		"apiVersion": "clickhouse.altinity.com/v1",
+		"kind": "ClickHouseInstallation",
+		"metadata": {"name": "valid", "namespace": "test"},
+		"spec": {"configuration": {"clusters": [{"name": "c1"}]}}
+	}`)
+	require.True(t, response.Allowed)
+
+	response = review(t, ValidatePath, `{
+		"apiVersion": "clickhouse.altinity.com/v1",
+		"kind": "ClickHouseInstallation",
+		"metadata": {"name": "invalid", "namespace": "test"},
+		"spec": {"configuration": {"clusters": [{"name": "c1"}, {"name": "c1"}]}}
+	}`)
+	require.False(t, response.Allowed)
+	require.Equal(t, meta.StatusReasonInvalid, response.Result.Reason)
+	require.Len(t, response.Result.Details.Causes, 1)
+	require.Equal(t, "spec.configuration.clusters[1].name", response.Result.Details.Causes[0].Field)
+}
+
+func TestHandlerMutate(t *testing.T) {
+	response := review(t, MutatePath, `{
+		"apiVersion": "clickhouse.altinity.com/v1",
+		"kind": "ClickHouseInstallation",
+		"metadata": {"name": "deprecated", "namespace": "test"},
+		"spec": {"reconciling": {"policy": "wait"}}
+	}`)
+	require.True(t, response.Allowed)
+	require.NotNil(t, response.PatchType)
+	require.Equal(t, admission.PatchTypeJSONPatch, *response.PatchType)
+	require.JSONEq(t, `[
+		{"op": "add", "path": "/spec/reconcile", "value": {"policy": "wait"}},
+		{"op": "remove", "path": "/spec/reconciling"}
+	]`, string(response.Patch))
+
+	response = review(t, MutatePath, `{
+		"apiVersion": "clickhouse.altinity.com/v1",
+		"kind": "ClickHouseInstallation",
+		"metadata": {"name": "current", "namespace": "test"},
+		"spec": {"reconcile": {"policy": "wait"}}
+	}`)
+	require.True(t, response.Allowed)
+	require.Empty(t, response.Patch)
+
+	response = review(t, MutatePath, `{
+		"apiVersion": "clickhouse.altinity.com/v1",
+		"kind": "ClickHouseInstallation",
+		"metadata": {"name": "volume-claim-template", "namespace": "test"},
+		"spec": {
+			"defaults": {"templates": {"volumeClaimTemplate": "data"}},
+			"configuration": {"clusters": [
+				{"name": "c1", "layout": {"shards": [
+					{"replicas": [{"templates": {"volumeClaimTemplate": "old", "dataVolumeClaimTemplate": "new"}}]}
+				]}}
+			]}
+		}
+	}`)
+	require.True(t, response.Allowed)
+	require.JSONEq(t, `[
+		{"op": "add", "path": "/spec/defaults/templates/dataVolumeClaimTemplate", "value": "data"},
+		{"op": "remove", "path": "/spec/defaults/templates/volumeClaimTemplate"},
+		{"op": "remove", "path": "/spec/configuration/clusters/0/layout/shards/0/replicas/0/templates/volumeClaimTemplate"},
+		{"op": "add", "path": "/spec/configuration/clusters/0/layout/shardsCount", "value": 1},
+		{"op": "add", "path": "/spec/configuration/clusters/0/layout/replicasCount", "value": 1}
+	]`, string(response.Patch))
+
+	response = review(t, MutatePath, `{
+		"apiVersion": "clickhouse-keeper.altinity.com/v1",
+		"kind": "ClickHouseKeeperInstallation",
+		"metadata": {"name": "layout", "namespace": "test"},
+		"spec": {
+			"configuration": {"clusters": [
+				{"name": "c1"},
+				{"name": "c2", "layout": {"replicasCount": 3}}
+			]}
+		}
+	}`)
+	require.True(t, response.Allowed)
+	require.JSONEq(t, `[
+		{"op": "add", "path": "/spec/configuration/clusters/0/layout", "value": {"shardsCount": 1, "replicasCount": 1}},
+		{"op": "add", "path": "/spec/configuration/clusters/1/layout/shardsCount", "value": 1}
+	]`, string(response.Patch))
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// patchOperation is a JSON patch operation, as specified by RFC 6902
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Mutate builds JSON patch which migrates deprecated fields of CHI, CHIT or CHK manifest to their current places
+// and sets defaults of the clusters' layouts.
+// Fields are migrated the same way the normalizer does it, so stored manifest reflects what the operator acts upon.
+// Only defaults which do not depend on runtime state are set. Clusters are not merged with CHITs' clusters,
+// so layout defaults of a cluster depend on the manifest only. The rest of the defaults are applied by the normalizer
+// on each reconcile and depend on the operator's configuration and CHITs, which may change after the manifest is stored.
+// Raw manifest is used in order not to touch fields which are not known to the operator
+func Mutate(raw []byte) ([]patchOperation, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	var patch []patchOperation
+
+	// .spec.reconciling is preferred over .spec.reconcile
+	if reconciling, found := spec["reconciling"]; found {
+		if reconciling != nil {
+			patch = append(patch, patchOperation{
+				Op:    "add",
+				Path:  "/spec/reconcile",
+				Value: reconciling,
+			})
+		}
+		patch = append(patch, patchOperation{
+			Op:   "remove",
+			Path: "/spec/reconciling",
+		})
+	}
+
+	// templates.volumeClaimTemplate is replaced by templates.dataVolumeClaimTemplate
+	walkTemplatesLists(spec, "/spec", func(templates map[string]interface{}, path string) {
+		patch = append(patch, migrateVolumeClaimTemplate(templates, path)...)
+	})
+
+	// Clusters have at least one shard and one replica
+	if configuration, ok := spec["configuration"].(map[string]interface{}); ok {
+		walkItems(configuration, "/spec/configuration", "clusters", func(cluster map[string]interface{}, path string) {
+			patch = append(patch, defaultClusterLayout(cluster, path)...)
+		})
+	}
+
+	return patch, nil
+}
+
+// defaultClusterLayout builds JSON patch which sets unspecified shards and replicas counters of the cluster layout.
+// The normalizer raises counters to the number of explicitly specified shards and replicas,
+// so default of 1 does not change the layout, whatever shards and replicas are specified later on
+func defaultClusterLayout(cluster map[string]interface{}, path string) (patch []patchOperation) {
+	layout, ok := cluster["layout"].(map[string]interface{})
+	if !ok {
+		return []patchOperation{
+			{
+				Op:    "add",
+				Path:  path + "/layout",
+				Value: map[string]interface{}{"shardsCount": 1, "replicasCount": 1},
+			},
+		}
+	}
+	for _, counter := range []string{"shardsCount", "replicasCount"} {
+		if count, _ := layout[counter].(float64); count == 0 {
+			patch = append(patch, patchOperation{
+				Op:    "add",
+				Path:  path + "/layout/" + counter,
+				Value: 1,
+			})
+		}
+	}
+	return patch
+}
+
+// migrateVolumeClaimTemplate builds JSON patch which moves deprecated volumeClaimTemplate of the templates list
+// into dataVolumeClaimTemplate, unless dataVolumeClaimTemplate is specified already
+func migrateVolumeClaimTemplate(templates map[string]interface{}, path string) (patch []patchOperation) {
+	volumeClaimTemplate, found := templates["volumeClaimTemplate"]
+	if !found {
+		return nil
+	}
+	if dataVolumeClaimTemplate, _ := templates["dataVolumeClaimTemplate"].(string); dataVolumeClaimTemplate == "" {
+		patch = append(patch, patchOperation{
+			Op:    "add",
+			Path:  path + "/dataVolumeClaimTemplate",
+			Value: volumeClaimTemplate,
+		})
+	}
+	return append(patch, patchOperation{
+		Op:   "remove",
+		Path: path + "/volumeClaimTemplate",
+	})
+}
+
+// walkTemplatesLists walks over templates lists of the spec, along with their JSON pointers:
+// defaults, clusters, shards, replicas and hosts
+func walkTemplatesLists(spec map[string]interface{}, path string, f func(templates map[string]interface{}, path string)) {
+	if defaults, ok := spec["defaults"].(map[string]interface{}); ok {
+		walkTemplatesList(defaults, path+"/defaults", f)
+	}
+	configuration, ok := spec["configuration"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	walkItems(configuration, path+"/configuration", "clusters", func(cluster map[string]interface{}, path string) {
+		walkTemplatesList(cluster, path, f)
+		layout, ok := cluster["layout"].(map[string]interface{})
+		if !ok {
+			return
+		}
+		path += "/layout"
+		walkItems(layout, path, "shards", func(shard map[string]interface{}, path string) {
+			walkTemplatesList(shard, path, f)
+			walkItems(shard, path, "replicas", func(host map[string]interface{}, path string) {
+				walkTemplatesList(host, path, f)
+			})
+		})
+		walkItems(layout, path, "replicas", func(replica map[string]interface{}, path string) {
+			walkTemplatesList(replica, path, f)
+			walkItems(replica, path, "shards", func(host map[string]interface{}, path string) {
+				walkTemplatesList(host, path, f)
+			})
+		})
+	})
+}
+
+// walkTemplatesList calls f over templates list of the object, in case it is specified
+func walkTemplatesList(obj map[string]interface{}, path string, f func(templates map[string]interface{}, path string)) {
+	if templates, ok := obj["templates"].(map[string]interface{}); ok {
+		f(templates, path+"/templates")
+	}
+}
+
+// walkItems calls f over objects listed in the array field of the object
+func walkItems(obj map[string]interface{}, path, field string, f func(item map[string]interface{}, path string)) {
+	items, _ := obj[field].([]interface{})
+	for i, item := range items {
+		if item, ok := item.(map[string]interface{}); ok {
+			f(item, fmt.Sprintf("%s/%s/%d", path, field, i))
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// portSettings maps host ports to the settings they may be specified by
+var portSettings = map[string]string{
+	"tcpPort":             "tcp_port",
+	"tlsPort":             "tcp_port_secure",
+	"httpPort":            "http_port",
+	"httpsPort":           "https_port",
+	"interserverHTTPPort": "interserver_http_port",
+	"zkPort":              "keeper_server/tcp_port",
+	"raftPort":            "keeper_server/raft_configuration/server/port",
+}
+
+// layoutItem is a shard or a replica of the manifest layout, as far as hosts origin is concerned
+type layoutItem struct {
+	hosts       []*api.Host
+	count       int
+	kubeContext string
+}
+
+// hostOrigin locates fields of the normalized host in the manifest, so errors point to the field to be fixed
+type hostOrigin struct {
+	// path is a path of the explicitly specified host, or of the layout the host is created by
+	path *field.Path
+	// host is the explicitly specified host, if any
+	host *api.Host
+	// kubeContextPath is a path of the kube context of the replica the host belongs to, if specified
+	kubeContextPath *field.Path
+
+	specPath  *field.Path
+	templates *api.Templates
+	settings  *api.Settings
+}
+
+// newHostOrigin finds the manifest host or the layout counter the normalized host is created by
+func newHostOrigin(layoutPath *field.Path, shards, replicas []layoutItem, shard, replica int) *hostOrigin {
+	origin := &hostOrigin{}
+	if (replica < len(replicas)) && (replicas[replica].kubeContext != "") {
+		origin.kubeContextPath = layoutPath.Child("replicas").Index(replica).Child("kubeContext")
+	}
+
+	switch {
+	case (shard < len(shards)) && (replica < len(shards[shard].hosts)):
+		origin.path = layoutPath.Child("shards").Index(shard).Child("replicas").Index(replica)
+		origin.host = shards[shard].hosts[replica]
+	case (replica < len(replicas)) && (shard < len(replicas[replica].hosts)):
+		origin.path = layoutPath.Child("replicas").Index(replica).Child("shards").Index(shard)
+		origin.host = replicas[replica].hosts[shard]
+	case (shard < len(shards)) && (shards[shard].count > 0):
+		origin.path = layoutPath.Child("shards").Index(shard).Child("replicasCount")
+	case (replica < len(replicas)) && (replicas[replica].count > 0):
+		origin.path = layoutPath.Child("replicas").Index(replica).Child("shardsCount")
+	case replica < len(replicas):
+		origin.path = layoutPath.Child("replicas").Index(replica)
+	case replica > 0:
+		origin.path = layoutPath.Child("replicasCount")
+	case shard < len(shards):
+		origin.path = layoutPath.Child("shards").Index(shard)
+	default:
+		origin.path = layoutPath.Child("shardsCount")
+	}
+	return origin
+}
+
+// withSpec specifies templates and settings of the manifest the normalized host may take its fields from
+func (o *hostOrigin) withSpec(specPath *field.Path, templates *api.Templates, settings *api.Settings) *hostOrigin {
+	o.specPath = specPath
+	o.templates = templates
+	o.settings = settings
+	return o
+}
+
+// hostTemplate finds the manifest host template used by the normalized host
+func (o *hostOrigin) hostTemplate(normalized *api.Host) (*field.Path, *api.HostTemplate) {
+	name := normalized.GetTemplates().GetHostTemplate()
+	if name == "" {
+		return nil, nil
+	}
+	templates := o.templates.GetHostTemplates()
+	for i := range templates {
+		if templates[i].Name == name {
+			return o.specPath.Child("templates", "hostTemplates").Index(i).Child("spec"), &templates[i]
+		}
+	}
+	return nil, nil
+}
+
+// portPath finds the manifest field the port of the normalized host is specified by.
+// In case the port is not specified in the manifest, the host origin is returned along with false
+func (o *hostOrigin) portPath(normalized *api.Host, name string) (*field.Path, bool) {
+	if port := findHostPort(o.host, name); port.HasValue() {
+		return o.path.Child(name), true
+	}
+	if path, template := o.hostTemplate(normalized); template != nil {
+		if port := findHostPort(&template.Spec, name); port.HasValue() {
+			return path.Child(name), true
+		}
+	}
+	if key := portSettings[name]; o.settings.Has(key) {
+		return o.specPath.Child("configuration", "settings").Key(key), true
+	}
+	return o.path, false
+}
+
+// kubeContext finds the manifest field the kube context of the normalized host is specified by
+func (o *hostOrigin) kubeContext(normalized *api.Host) *field.Path {
+	if (o.host != nil) && (o.host.KubeContext != "") {
+		return o.path.Child("kubeContext")
+	}
+	if o.kubeContextPath != nil {
+		return o.kubeContextPath
+	}
+	if path, template := o.hostTemplate(normalized); (template != nil) && (template.Spec.KubeContext != "") {
+		return path.Child("kubeContext")
+	}
+	return o.path
+}
+
+// validateNormalizedHostPorts checks ports of the normalized host do not collide with each other.
+// Collision is reported at the port specified in the manifest
+func validateNormalizedHostPorts(origin *hostOrigin, host *api.Host) (errs field.ErrorList) {
+	used := make(map[int32]string)
+	for _, p := range hostPorts(host) {
+		if !p.port.HasValue() || (p.port.Value() == 0) {
+			continue
+		}
+		other, found := used[p.port.Value()]
+		if !found {
+			used[p.port.Value()] = p.name
+			continue
+		}
+		path, specified := origin.portPath(host, p.name)
+		detail := "collides with " + other
+		if !specified {
+			if otherPath, otherSpecified := origin.portPath(host, other); otherSpecified {
+				path, detail = otherPath, "collides with "+p.name
+			}
+		}
+		errs = append(errs, field.Invalid(path, p.port.Value(), detail))
+	}
+	return errs
+}
+
+// uniqueErrors drops duplicated errors. Hosts of the same origin report the same errors
+func uniqueErrors(errs field.ErrorList) (unique field.ErrorList) {
+	seen := make(map[string]bool)
+	for _, err := range errs {
+		if key := err.Error(); !seen[key] {
+			seen[key] = true
+			unique = append(unique, err)
+		}
+	}
+	return unique
+}
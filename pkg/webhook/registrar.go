@@ -0,0 +1,186 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+
+	admissionRegistration "k8s.io/api/admissionregistration/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube "k8s.io/client-go/kubernetes"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// Registrar registers the webhook within API server by means of validating and mutating webhook configurations
+type Registrar struct {
+	kube kube.Interface
+	// name is a name of both webhook configurations
+	name          string
+	service       admissionRegistration.ServiceReference
+	failurePolicy admissionRegistration.FailurePolicyType
+}
+
+// NewRegistrar creates new registrar of the webhook served behind the service
+func NewRegistrar(
+	kubeClient kube.Interface,
+	name string,
+	namespace string,
+	service string,
+	port int32,
+	failurePolicy admissionRegistration.FailurePolicyType,
+) *Registrar {
+	return &Registrar{
+		kube: kubeClient,
+		name: name,
+		service: admissionRegistration.ServiceReference{
+			Namespace: namespace,
+			Name:      service,
+			Port:      &port,
+		},
+		failurePolicy: failurePolicy,
+	}
+}
+
+// DNSNames lists DNS names the webhook service is reachable by, serving certificate has to be issued for
+func (r *Registrar) DNSNames() []string {
+	return []string{
+		r.service.Name + "." + r.service.Namespace + ".svc",
+		r.service.Name + "." + r.service.Namespace + ".svc.cluster.local",
+		r.service.Name + "." + r.service.Namespace,
+		r.service.Name,
+	}
+}
+
+// Register creates or updates webhook configurations with the CA bundle
+func (r *Registrar) Register(ctx context.Context, caBundle []byte) error {
+	if err := r.registerValidating(ctx, caBundle); err != nil {
+		return err
+	}
+	return r.registerMutating(ctx, caBundle)
+}
+
+func (r *Registrar) registerValidating(ctx context.Context, caBundle []byte) error {
+	configuration := &admissionRegistration.ValidatingWebhookConfiguration{
+		ObjectMeta: meta.ObjectMeta{
+			Name: r.name,
+		},
+	}
+	for _, w := range r.webhooks(ValidatePath, "validate", caBundle) {
+		configuration.Webhooks = append(configuration.Webhooks, admissionRegistration.ValidatingWebhook{
+			Name:                    w.Name,
+			ClientConfig:            w.ClientConfig,
+			Rules:                   w.Rules,
+			FailurePolicy:           w.FailurePolicy,
+			SideEffects:             w.SideEffects,
+			AdmissionReviewVersions: w.AdmissionReviewVersions,
+		})
+	}
+
+	client := r.kube.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+	cur, err := client.Get(ctx, r.name, meta.GetOptions{})
+	switch {
+	case apiErrors.IsNotFound(err):
+		_, err = client.Create(ctx, configuration, meta.CreateOptions{})
+	case err == nil:
+		configuration.ResourceVersion = cur.ResourceVersion
+		_, err = client.Update(ctx, configuration, meta.UpdateOptions{})
+	}
+	if err == nil {
+		log.V(1).F().Info("Validating webhook configuration %s registered", r.name)
+	}
+	return err
+}
+
+func (r *Registrar) registerMutating(ctx context.Context, caBundle []byte) error {
+	configuration := &admissionRegistration.MutatingWebhookConfiguration{
+		ObjectMeta: meta.ObjectMeta{
+			Name: r.name,
+		},
+	}
+	configuration.Webhooks = r.webhooks(MutatePath, "mutate", caBundle)
+
+	client := r.kube.AdmissionregistrationV1().MutatingWebhookConfigurations()
+	cur, err := client.Get(ctx, r.name, meta.GetOptions{})
+	switch {
+	case apiErrors.IsNotFound(err):
+		_, err = client.Create(ctx, configuration, meta.CreateOptions{})
+	case err == nil:
+		configuration.ResourceVersion = cur.ResourceVersion
+		_, err = client.Update(ctx, configuration, meta.UpdateOptions{})
+	}
+	if err == nil {
+		log.V(1).F().Info("Mutating webhook configuration %s registered", r.name)
+	}
+	return err
+}
+
+// webhooks builds webhooks for CHI/CHIT and CHK API groups
+func (r *Registrar) webhooks(path, prefix string, caBundle []byte) []admissionRegistration.MutatingWebhook {
+	sideEffects := admissionRegistration.SideEffectClassNone
+	failurePolicy := r.failurePolicy
+	scope := admissionRegistration.NamespacedScope
+
+	groups := []struct {
+		group     string
+		version   string
+		resources []string
+	}{
+		{
+			group:     api.SchemeGroupVersion.Group,
+			version:   api.SchemeGroupVersion.Version,
+			resources: []string{"clickhouseinstallations", "clickhouseinstallationtemplates"},
+		},
+		{
+			group:     apiChk.SchemeGroupVersion.Group,
+			version:   apiChk.SchemeGroupVersion.Version,
+			resources: []string{"clickhousekeeperinstallations"},
+		},
+	}
+
+	var webhooks []admissionRegistration.MutatingWebhook
+	for _, g := range groups {
+		service := r.service
+		service.Path = &path
+		webhooks = append(webhooks, admissionRegistration.MutatingWebhook{
+			Name: prefix + "." + g.group,
+			ClientConfig: admissionRegistration.WebhookClientConfig{
+				Service:  &service,
+				CABundle: caBundle,
+			},
+			Rules: []admissionRegistration.RuleWithOperations{
+				{
+					Operations: []admissionRegistration.OperationType{
+						admissionRegistration.Create,
+						admissionRegistration.Update,
+					},
+					Rule: admissionRegistration.Rule{
+						APIGroups:   []string{g.group},
+						APIVersions: []string{g.version},
+						Resources:   g.resources,
+						Scope:       &scope,
+					},
+				},
+			},
+			FailurePolicy:           &failurePolicy,
+			SideEffects:             &sideEffects,
+			AdmissionReviewVersions: []string{"v1"},
+		})
+	}
+	return webhooks
+}
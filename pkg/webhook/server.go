@@ -0,0 +1,123 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+)
+
+const (
+	// certificateValidity specifies how long self-signed serving certificate is valid
+	certificateValidity = 365 * 24 * time.Hour
+	// rotationCheckInterval specifies how often certificate is checked to be rotated
+	rotationCheckInterval = time.Hour
+	// shutdownTimeout limits graceful shutdown of the server
+	shutdownTimeout = 10 * time.Second
+)
+
+// Server serves admission webhook over TLS with self-signed rotated certificate
+type Server struct {
+	address   string
+	registrar *Registrar
+	cert      atomic.Pointer[Certificate]
+}
+
+// NewServer creates new webhook server listening on the address
+func NewServer(address string, registrar *Registrar) *Server {
+	return &Server{
+		address:   address,
+		registrar: registrar,
+	}
+}
+
+// Run issues certificate, registers the webhook and serves admission reviews till context is done
+func (s *Server) Run(ctx context.Context) error {
+	if err := s.rotate(ctx); err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:    s.address,
+		Handler: NewHandler(),
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				return &s.cert.Load().Cert, nil
+			},
+		},
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go s.watchRotation(ctx)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.V(1).F().Info("Starting admission webhook at %s", s.address)
+	if err := server.ListenAndServeTLS("", ""); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// watchRotation periodically rotates certificate which is about to expire
+func (s *Server) watchRotation(ctx context.Context) {
+	ticker := time.NewTicker(rotationCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.cert.Load().NeedsRotation(time.Now()) {
+				continue
+			}
+			if err := s.rotate(ctx); err != nil {
+				log.V(1).F().Error("Unable to rotate webhook certificate. Err: %v", err)
+			}
+		}
+	}
+}
+
+// rotate issues new certificate and registers the webhook with it.
+// Old CA is kept in the bundle, so API server trusts both certificates while the new one is being picked up.
+func (s *Server) rotate(ctx context.Context) error {
+	cert, err := NewSelfSignedCertificate(s.registrar.DNSNames(), certificateValidity, time.Now())
+	if err != nil {
+		return err
+	}
+
+	caBundle := cert.CABundle
+	if cur := s.cert.Load(); cur != nil {
+		caBundle = append(append([]byte{}, caBundle...), cur.CABundle...)
+	}
+	if err := s.registrar.Register(ctx, caBundle); err != nil {
+		return err
+	}
+
+	s.cert.Store(cert)
+	log.V(1).F().Info("Webhook certificate issued, valid till %s", cert.NotAfter)
+	return nil
+}
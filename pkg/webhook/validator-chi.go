@@ -0,0 +1,301 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"time"
+
+	core "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	chiNormalizer "github.com/altinity/clickhouse-operator/pkg/model/chi/normalizer"
+	commonNormalizer "github.com/altinity/clickhouse-operator/pkg/model/common/normalizer"
+	"github.com/altinity/clickhouse-operator/pkg/util"
+)
+
+// dryRunSecretGetter does not read secrets, so normalization has no side effects.
+// Every secret is reported as not found, which the normalizer tolerates by leaving settings
+// referencing secrets as they are
+func dryRunSecretGetter(namespace, name string) (*core.Secret, error) {
+	return nil, apiErrors.NewNotFound(core.Resource("secrets"), name)
+}
+
+// ValidateCHI validates CHI. CHI is normalized in a dry mode, so templates provided by CHITs are known
+// and ports are checked along with their default values.
+// Errors are reported with paths of the fields in the manifest
+func ValidateCHI(chi *api.ClickHouseInstallation) field.ErrorList {
+	specPath := field.NewPath("spec")
+	normalized, err := chiNormalizer.New(dryRunSecretGetter, nil).CreateTemplated(chi.DeepCopy(), commonNormalizer.NewOptions[api.ClickHouseInstallation]())
+	if err != nil {
+		return field.ErrorList{field.Invalid(specPath, chi.GetName(), err.Error())}
+	}
+
+	errs := validateCHISpec(specPath, &chi.Spec, newTemplateNames(normalized.GetSpecT().Templates))
+	errs = append(errs, validateClone(specPath.Child("clone"), chi)...)
+	errs = append(errs, validateDisasterRecovery(specPath.Child("disasterRecovery"), chi)...)
+	errs = append(errs, validateKubeContexts(specPath.Child("kubeContexts"), chi.GetSpecT().GetKubeContexts())...)
+	if len(errs) == 0 {
+		errs = validateCHINormalizedHosts(specPath, chi, normalized)
+	}
+	return errs
+}
+
+// ValidateCHIT validates CHIT. Templates are applied on top of each other, so template references are not checked
+func ValidateCHIT(chit *api.ClickHouseInstallationTemplate) field.ErrorList {
+	return validateCHISpec(field.NewPath("spec"), &chit.Spec, noTemplateNames())
+}
+
+// validateCHISpec validates spec as it is specified in the manifest
+func validateCHISpec(path *field.Path, spec *api.ChiSpec, names *templateNames) (errs field.ErrorList) {
+	errs = append(errs, validateReconcile(path.Child("reconcile"), spec.Reconcile)...)
+	errs = append(errs, validateReconcile(path.Child("reconciling"), spec.Reconciling)...)
+	if spec.Defaults != nil {
+		errs = append(errs, validateTemplatesList(path.Child("defaults", "templates"), spec.Defaults.Templates, names)...)
+	}
+	errs = append(errs, validateHostTemplates(path.Child("templates"), spec.Templates)...)
+	if spec.Configuration == nil {
+		return errs
+	}
+
+	errs = append(errs, validateSchema(path.Child("configuration", "schema"), spec.Configuration.Schema)...)
+	errs = append(errs, validateTLS(path.Child("configuration", "tls"), spec.Configuration.TLS)...)
+
+	clustersPath := path.Child("configuration", "clusters")
+	var clusterNames []string
+	for _, cluster := range spec.Configuration.Clusters {
+		clusterNames = append(clusterNames, cluster.GetName())
+	}
+	errs = append(errs, validateClusterNames(clustersPath, clusterNames)...)
+	for i, cluster := range spec.Configuration.Clusters {
+		errs = append(errs, validateCHICluster(clustersPath.Index(i), cluster, names)...)
+	}
+	return errs
+}
+
+// validateCHICluster validates cluster as it is specified in the manifest
+func validateCHICluster(path *field.Path, cluster *api.Cluster, names *templateNames) (errs field.ErrorList) {
+	if cluster == nil {
+		return nil
+	}
+	errs = append(errs, validateTemplatesList(path.Child("templates"), cluster.Templates, names)...)
+	errs = append(errs, validateClusterReconcile(path.Child("reconcile"), cluster.Reconcile)...)
+	errs = append(errs, validateAutoscaling(path.Child("autoscaling"), cluster.Autoscaling, cluster.Layout)...)
+	errs = append(errs, validateResourcesRecommender(path.Child("resourcesRecommender"), cluster.ResourcesRecommender)...)
+	errs = append(errs, validateReplicaGC(path.Child("replicaGC"), cluster.ReplicaGC)...)
+	if cluster.Layout == nil {
+		return errs
+	}
+
+	layoutPath := path.Child("layout")
+	for i, shard := range cluster.Layout.Shards {
+		if shard == nil {
+			continue
+		}
+		shardPath := layoutPath.Child("shards").Index(i)
+		errs = append(errs, validateTemplatesList(shardPath.Child("templates"), shard.Templates, names)...)
+		for j, host := range shard.Hosts {
+			errs = append(errs, validateHost(shardPath.Child("replicas").Index(j), host, names)...)
+		}
+	}
+	for i, replica := range cluster.Layout.Replicas {
+		if replica == nil {
+			continue
+		}
+		replicaPath := layoutPath.Child("replicas").Index(i)
+		errs = append(errs, validateTemplatesList(replicaPath.Child("templates"), replica.Templates, names)...)
+		for j, host := range replica.Hosts {
+			errs = append(errs, validateHost(replicaPath.Child("shards").Index(j), host, names)...)
+		}
+	}
+	return errs
+}
+
+// validateAutoscaling validates autoscaling policy of the cluster
+func validateAutoscaling(path *field.Path, autoscaling *api.ClusterAutoscaling, layout *api.ChiClusterLayout) (errs field.ErrorList) {
+	if autoscaling == nil {
+		return nil
+	}
+	if autoscaling.IsEnabled() && (layout != nil) && ((len(layout.Shards) > 0) || (len(layout.Replicas) > 0)) {
+		errs = append(errs, field.Forbidden(path.Child("enabled"), "autoscaling requires layout specified by shardsCount and replicasCount"))
+	}
+	if autoscaling.MinReplicas.HasValue() && (autoscaling.MinReplicas.IntValue() < 1) {
+		errs = append(errs, field.Invalid(path.Child("minReplicas"), autoscaling.MinReplicas.IntValue(), "has to be positive"))
+	}
+	if autoscaling.MaxReplicas.HasValue() && (autoscaling.MaxReplicas.IntValue() < autoscaling.GetMinReplicas()) {
+		errs = append(errs, field.Invalid(path.Child("maxReplicas"), autoscaling.MaxReplicas.IntValue(), "has to be not less than minReplicas"))
+	}
+	if autoscaling.Cooldown != "" {
+		if _, err := time.ParseDuration(autoscaling.Cooldown); err != nil {
+			errs = append(errs, field.Invalid(path.Child("cooldown"), autoscaling.Cooldown, "duration, such as 5m, is expected"))
+		}
+	}
+	for i, metric := range autoscaling.Metrics {
+		metricPath := path.Child("metrics").Index(i)
+		if metric.Type == "" {
+			errs = append(errs, field.Required(metricPath.Child("type"), ""))
+		}
+		errs = append(errs, validateEnum(metricPath.Child("type"), metric.Type,
+			api.AutoscalingMetricConcurrentQueries,
+			api.AutoscalingMetricCPU,
+			api.AutoscalingMetricQueryQueue,
+			api.AutoscalingMetricCustom,
+		)...)
+		if (metric.Type == api.AutoscalingMetricCustom) && (metric.Name == "") {
+			errs = append(errs, field.Required(metricPath.Child("name"), "custom metric requires name"))
+		}
+		if metric.GetTarget() == 0 {
+			errs = append(errs, field.Invalid(metricPath.Child("target"), metric.Target, "positive number is expected"))
+		}
+	}
+	return errs
+}
+
+// validateResourcesRecommender validates resources recommender of the cluster
+func validateResourcesRecommender(path *field.Path, recommender *api.ClusterResourcesRecommender) (errs field.ErrorList) {
+	if recommender == nil {
+		return nil
+	}
+	errs = append(errs, validateEnum(path.Child("mode"), recommender.Mode,
+		api.ResourcesRecommenderModeRecommend,
+		api.ResourcesRecommenderModeApply,
+	)...)
+	if recommender.Window != "" {
+		if window, err := time.ParseDuration(recommender.Window); (err != nil) || (window <= 0) {
+			errs = append(errs, field.Invalid(path.Child("window"), recommender.Window, "positive duration, such as 24h, is expected"))
+		}
+	}
+	if recommender.Headroom.HasValue() && (recommender.Headroom.IntValue() < 0) {
+		errs = append(errs, field.Invalid(path.Child("headroom"), recommender.Headroom.IntValue(), "has to be not negative"))
+	}
+	if recommender.MinChange.HasValue() && (recommender.MinChange.IntValue() < 0) {
+		errs = append(errs, field.Invalid(path.Child("minChange"), recommender.MinChange.IntValue(), "has to be not negative"))
+	}
+	return errs
+}
+
+// validateReplicaGC validates replica garbage collector of the cluster
+func validateReplicaGC(path *field.Path, gc *api.ClusterReplicaGC) (errs field.ErrorList) {
+	if gc == nil {
+		return nil
+	}
+	errs = append(errs, validateEnum(path.Child("mode"), gc.Mode,
+		api.ReplicaGCModeReport,
+		api.ReplicaGCModeDrop,
+	)...)
+	if gc.Interval != "" {
+		if interval, err := time.ParseDuration(gc.Interval); (err != nil) || (interval <= 0) {
+			errs = append(errs, field.Invalid(path.Child("interval"), gc.Interval, "positive duration, such as 1h, is expected"))
+		}
+	}
+	return errs
+}
+
+// validateClone validates clone of the CHI, which is not expected to refer to the CHI itself
+func validateClone(path *field.Path, chi *api.ClickHouseInstallation) (errs field.ErrorList) {
+	clone := chi.GetSpecT().GetClone()
+	if clone == nil {
+		return nil
+	}
+	if !clone.HasSource() {
+		return field.ErrorList{field.Required(path.Child("source", "name"), "source CHI is expected")}
+	}
+	if (clone.Source.Name == chi.GetName()) && (clone.GetSourceNamespace(chi.GetNamespace()) == chi.GetNamespace()) {
+		errs = append(errs, field.Invalid(path.Child("source", "name"), clone.Source.Name, "CHI can not be a clone of itself"))
+	}
+	errs = append(errs, validateEnum(path.Child("data"), clone.Data,
+		api.CloneDataRemote,
+		api.CloneDataNone,
+	)...)
+	if clone.IsDataCopied() && (clone.User == "") {
+		errs = append(errs, field.Required(path.Child("user"), "user of the source is expected in order to copy data"))
+	}
+	return errs
+}
+
+// validateDisasterRecovery validates DR section of the CHI, which is not expected to replicate itself
+func validateDisasterRecovery(path *field.Path, chi *api.ClickHouseInstallation) (errs field.ErrorList) {
+	dr := chi.GetSpecT().GetDisasterRecovery()
+	if dr == nil {
+		return nil
+	}
+	if !dr.HasPrimary() {
+		return field.ErrorList{field.Required(path.Child("primary", "name"), "primary CHI is expected")}
+	}
+	if (dr.Primary.Name == chi.GetName()) && (dr.GetPrimaryNamespace(chi.GetNamespace()) == chi.GetNamespace()) {
+		errs = append(errs, field.Invalid(path.Child("primary", "name"), dr.Primary.Name, "CHI can not replicate itself"))
+	}
+	return errs
+}
+
+// validateKubeContexts validates remote kube contexts, which are referred to by name and have to be reachable by kubeconfig
+func validateKubeContexts(path *field.Path, kubeContexts []*api.KubeContext) (errs field.ErrorList) {
+	var names []string
+	for i, kubeContext := range kubeContexts {
+		name := kubeContext.GetName()
+		switch {
+		case name == "":
+			errs = append(errs, field.Required(path.Index(i).Child("name"), "kube context name is expected"))
+		case util.InArray(name, names):
+			errs = append(errs, field.Duplicate(path.Index(i).Child("name"), name))
+		default:
+			names = append(names, name)
+		}
+		if !kubeContext.HasKubeconfig() {
+			errs = append(errs, field.Required(path.Index(i).Child("kubeconfig", "secretKeyRef"), "kubeconfig secret is expected"))
+		}
+	}
+	return errs
+}
+
+// validateCHINormalizedHosts checks ports of the normalized hosts, so collisions with default ports are found,
+// and kube contexts hosts live in. Errors point to the fields of the manifest hosts are created from
+func validateCHINormalizedHosts(path *field.Path, chi, normalized *api.ClickHouseInstallation) (errs field.ErrorList) {
+	normalized.WalkHosts(func(host *api.Host) error {
+		origin := chiHostOrigin(path, chi, host).withSpec(path, chi.GetSpecT().Templates, chi.GetSpecT().Configuration.GetSettings())
+		errs = append(errs, validateNormalizedHostPorts(origin, host)...)
+		if kubeContext := host.GetKubeContext(); (kubeContext != "") && (normalized.GetSpecT().GetKubeContext(kubeContext) == nil) {
+			errs = append(errs, field.NotFound(origin.kubeContext(host), kubeContext))
+		}
+		return nil
+	})
+	return uniqueErrors(errs)
+}
+
+// chiHostOrigin finds the place in CHI manifest the normalized host is created from
+func chiHostOrigin(path *field.Path, chi *api.ClickHouseInstallation, host *api.Host) *hostOrigin {
+	address := host.Runtime.Address
+	clustersPath := path.Child("configuration", "clusters")
+	var clusters []*api.Cluster
+	if chi.GetSpecT().Configuration != nil {
+		clusters = chi.GetSpecT().Configuration.Clusters
+	}
+	if (address.ClusterIndex >= len(clusters)) || (clusters[address.ClusterIndex] == nil) {
+		// Cluster is not specified in the manifest
+		return newHostOrigin(clustersPath.Index(address.ClusterIndex).Child("layout"), nil, nil, address.ShardIndex, address.ReplicaIndex)
+	}
+
+	var shards, replicas []layoutItem
+	if layout := clusters[address.ClusterIndex].Layout; layout != nil {
+		for _, shard := range layout.Shards {
+			shards = append(shards, layoutItem{hosts: shard.Hosts, count: shard.ReplicasCount})
+		}
+		for _, replica := range layout.Replicas {
+			replicas = append(replicas, layoutItem{hosts: replica.Hosts, count: replica.ShardsCount, kubeContext: replica.KubeContext})
+		}
+	}
+	return newHostOrigin(clustersPath.Index(address.ClusterIndex).Child("layout"), shards, replicas, address.ShardIndex, address.ReplicaIndex)
+}
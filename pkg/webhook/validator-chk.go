@@ -0,0 +1,137 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	chkNormalizer "github.com/altinity/clickhouse-operator/pkg/model/chk/normalizer"
+	commonNormalizer "github.com/altinity/clickhouse-operator/pkg/model/common/normalizer"
+)
+
+// ValidateCHK validates CHK. CHK is normalized in a dry mode, so ports are checked along with their default values.
+// Errors are reported with paths of the fields in the manifest
+func ValidateCHK(chk *apiChk.ClickHouseKeeperInstallation) field.ErrorList {
+	specPath := field.NewPath("spec")
+	normalized, err := chkNormalizer.New().CreateTemplated(chk.DeepCopy(), commonNormalizer.NewOptions[apiChk.ClickHouseKeeperInstallation]())
+	if err != nil {
+		return field.ErrorList{field.Invalid(specPath, chk.GetName(), err.Error())}
+	}
+
+	errs := validateCHKSpec(specPath, &chk.Spec, newTemplateNames(normalized.GetSpecT().Templates))
+	if len(errs) == 0 {
+		errs = validateCHKNormalizedHosts(specPath, chk, normalized)
+	}
+	return errs
+}
+
+// validateCHKSpec validates spec as it is specified in the manifest
+func validateCHKSpec(path *field.Path, spec *apiChk.ChkSpec, names *templateNames) (errs field.ErrorList) {
+	errs = append(errs, validateReconcile(path.Child("reconcile"), spec.Reconcile)...)
+	errs = append(errs, validateReconcile(path.Child("reconciling"), spec.Reconciling)...)
+	if spec.Defaults != nil {
+		errs = append(errs, validateTemplatesList(path.Child("defaults", "templates"), spec.Defaults.Templates, names)...)
+	}
+	errs = append(errs, validateHostTemplates(path.Child("templates"), spec.Templates)...)
+	if spec.Configuration == nil {
+		return errs
+	}
+
+	errs = append(errs, validateTLS(path.Child("configuration", "tls"), spec.Configuration.TLS)...)
+
+	clustersPath := path.Child("configuration", "clusters")
+	var clusterNames []string
+	for _, cluster := range spec.Configuration.Clusters {
+		clusterNames = append(clusterNames, cluster.GetName())
+	}
+	errs = append(errs, validateClusterNames(clustersPath, clusterNames)...)
+	for i, cluster := range spec.Configuration.Clusters {
+		errs = append(errs, validateCHKCluster(clustersPath.Index(i), cluster, names)...)
+	}
+	return errs
+}
+
+// validateCHKCluster validates cluster as it is specified in the manifest
+func validateCHKCluster(path *field.Path, cluster *apiChk.Cluster, names *templateNames) (errs field.ErrorList) {
+	if cluster == nil {
+		return nil
+	}
+	errs = append(errs, validateTemplatesList(path.Child("templates"), cluster.Templates, names)...)
+	errs = append(errs, validateClusterReconcile(path.Child("reconcile"), cluster.Reconcile)...)
+	if cluster.Layout == nil {
+		return errs
+	}
+
+	layoutPath := path.Child("layout")
+	for i, shard := range cluster.Layout.Shards {
+		if shard == nil {
+			continue
+		}
+		shardPath := layoutPath.Child("shards").Index(i)
+		errs = append(errs, validateTemplatesList(shardPath.Child("templates"), shard.Templates, names)...)
+		for j, host := range shard.Hosts {
+			errs = append(errs, validateHost(shardPath.Child("replicas").Index(j), host, names)...)
+		}
+	}
+	for i, replica := range cluster.Layout.Replicas {
+		if replica == nil {
+			continue
+		}
+		replicaPath := layoutPath.Child("replicas").Index(i)
+		errs = append(errs, validateTemplatesList(replicaPath.Child("templates"), replica.Templates, names)...)
+		for j, host := range replica.Hosts {
+			errs = append(errs, validateHost(replicaPath.Child("shards").Index(j), host, names)...)
+		}
+	}
+	return errs
+}
+
+// validateCHKNormalizedHosts checks ports of the normalized hosts, so collisions with default ports are found.
+// Errors point to the fields of the manifest hosts are created from
+func validateCHKNormalizedHosts(path *field.Path, chk, normalized *apiChk.ClickHouseKeeperInstallation) (errs field.ErrorList) {
+	normalized.WalkHosts(func(host *api.Host) error {
+		origin := chkHostOrigin(path, chk, host).withSpec(path, chk.GetSpecT().Templates, chk.GetSpecT().Configuration.GetSettings())
+		errs = append(errs, validateNormalizedHostPorts(origin, host)...)
+		return nil
+	})
+	return uniqueErrors(errs)
+}
+
+// chkHostOrigin finds the place in CHK manifest the normalized host is created from
+func chkHostOrigin(path *field.Path, chk *apiChk.ClickHouseKeeperInstallation, host *api.Host) *hostOrigin {
+	address := host.Runtime.Address
+	clustersPath := path.Child("configuration", "clusters")
+	var clusters []*apiChk.Cluster
+	if chk.GetSpecT().Configuration != nil {
+		clusters = chk.GetSpecT().Configuration.Clusters
+	}
+	if (address.ClusterIndex >= len(clusters)) || (clusters[address.ClusterIndex] == nil) {
+		// Cluster is not specified in the manifest
+		return newHostOrigin(clustersPath.Index(address.ClusterIndex).Child("layout"), nil, nil, address.ShardIndex, address.ReplicaIndex)
+	}
+
+	var shards, replicas []layoutItem
+	if layout := clusters[address.ClusterIndex].Layout; layout != nil {
+		for _, shard := range layout.Shards {
+			shards = append(shards, layoutItem{hosts: shard.Hosts, count: shard.ReplicasCount})
+		}
+		for _, replica := range layout.Replicas {
+			replicas = append(replicas, layoutItem{hosts: replica.Hosts, count: replica.ShardsCount})
+		}
+	}
+	return newHostOrigin(clustersPath.Index(address.ClusterIndex).Child("layout"), shards, replicas, address.ShardIndex, address.ReplicaIndex)
+}
@@ -0,0 +1,342 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/apis/common/types"
+)
+
+// templateNames contains names of the templates available to CR after templates are applied
+type templateNames struct {
+	host           map[string]bool
+	pod            map[string]bool
+	volumeClaim    map[string]bool
+	service        map[string]bool
+	checkReference bool
+}
+
+// noTemplateNames makes names which do not check references. Used when not all templates are known,
+// as it is with templates, which are applied on top of each other
+func noTemplateNames() *templateNames {
+	return &templateNames{}
+}
+
+// newTemplateNames collects names of the templates references are checked against
+func newTemplateNames(templates *api.Templates) *templateNames {
+	names := &templateNames{
+		host:           make(map[string]bool),
+		pod:            make(map[string]bool),
+		volumeClaim:    make(map[string]bool),
+		service:        make(map[string]bool),
+		checkReference: true,
+	}
+	for _, template := range templates.GetHostTemplates() {
+		names.host[template.Name] = true
+	}
+	for _, template := range templates.GetPodTemplates() {
+		names.pod[template.Name] = true
+	}
+	for _, template := range templates.GetVolumeClaimTemplates() {
+		names.volumeClaim[template.Name] = true
+	}
+	for _, template := range templates.GetServiceTemplates() {
+		names.service[template.Name] = true
+	}
+	return names
+}
+
+// validateTemplatesList checks all templates referenced by the list are known
+func validateTemplatesList(path *field.Path, list *api.TemplatesList, names *templateNames) (errs field.ErrorList) {
+	if (list == nil) || !names.checkReference {
+		return nil
+	}
+	check := func(name string, known map[string]bool, path *field.Path) {
+		if (name != "") && !known[name] {
+			errs = append(errs, field.NotFound(path, name))
+		}
+	}
+	check(list.HostTemplate, names.host, path.Child("hostTemplate"))
+	check(list.PodTemplate, names.pod, path.Child("podTemplate"))
+	check(list.DataVolumeClaimTemplate, names.volumeClaim, path.Child("dataVolumeClaimTemplate"))
+	check(list.LogVolumeClaimTemplate, names.volumeClaim, path.Child("logVolumeClaimTemplate"))
+	check(list.VolumeClaimTemplate, names.volumeClaim, path.Child("volumeClaimTemplate"))
+	check(list.ServiceTemplate, names.service, path.Child("serviceTemplate"))
+	for i, name := range list.ServiceTemplates {
+		check(name, names.service, path.Child("serviceTemplates").Index(i))
+	}
+	check(list.ClusterServiceTemplate, names.service, path.Child("clusterServiceTemplate"))
+	check(list.ShardServiceTemplate, names.service, path.Child("shardServiceTemplate"))
+	check(list.ReplicaServiceTemplate, names.service, path.Child("replicaServiceTemplate"))
+	return errs
+}
+
+// validateClusterNames checks cluster names are unique
+func validateClusterNames(path *field.Path, names []string) (errs field.ErrorList) {
+	seen := make(map[string]bool)
+	for i, name := range names {
+		if name == "" {
+			continue
+		}
+		if seen[name] {
+			errs = append(errs, field.Duplicate(path.Index(i).Child("name"), name))
+		}
+		seen[name] = true
+	}
+	return errs
+}
+
+// hostPort is a named port of the host
+type hostPort struct {
+	name string
+	port *types.Int32
+}
+
+// hostPorts lists ports of the host along with their field names
+func hostPorts(host *api.Host) []hostPort {
+	if host == nil {
+		return nil
+	}
+	return []hostPort{
+		{"tcpPort", host.TCPPort},
+		{"tlsPort", host.TLSPort},
+		{"httpPort", host.HTTPPort},
+		{"httpsPort", host.HTTPSPort},
+		{"interserverHTTPPort", host.InterserverHTTPPort},
+		{"zkPort", host.ZKPort},
+		{"raftPort", host.RaftPort},
+	}
+}
+
+// findHostPort finds port of the host by its field name
+func findHostPort(host *api.Host, name string) *types.Int32 {
+	for _, p := range hostPorts(host) {
+		if p.name == name {
+			return p.port
+		}
+	}
+	return nil
+}
+
+// validateHostPorts checks ports of the host do not collide with each other
+func validateHostPorts(path *field.Path, host *api.Host) (errs field.ErrorList) {
+	used := make(map[int32]string)
+	for _, p := range hostPorts(host) {
+		if !p.port.HasValue() || (p.port.Value() == 0) {
+			continue
+		}
+		if other, found := used[p.port.Value()]; found {
+			errs = append(errs, field.Invalid(path.Child(p.name), p.port.Value(), fmt.Sprintf("collides with %s", other)))
+			continue
+		}
+		used[p.port.Value()] = p.name
+	}
+	return errs
+}
+
+// validateHost checks host templates references and ports
+func validateHost(path *field.Path, host *api.Host, names *templateNames) (errs field.ErrorList) {
+	if host == nil {
+		return nil
+	}
+	errs = append(errs, validateTemplatesList(path.Child("templates"), host.Templates, names)...)
+	errs = append(errs, validateHostPorts(path, host)...)
+	return errs
+}
+
+// validateHostTemplates checks ports of the host templates
+func validateHostTemplates(path *field.Path, templates *api.Templates) (errs field.ErrorList) {
+	for i, template := range templates.GetHostTemplates() {
+		errs = append(errs, validateHostPorts(path.Child("hostTemplates").Index(i).Child("spec"), &template.Spec)...)
+	}
+	return errs
+}
+
+// validateSchema checks objects of the declared schema are named and have either SQL or structured definition
+func validateSchema(path *field.Path, schema *api.Schema) (errs field.ErrorList) {
+	if schema == nil {
+		return nil
+	}
+	required := func(path *field.Path, value string) {
+		if value == "" {
+			errs = append(errs, field.Required(path, ""))
+		}
+	}
+	for i, database := range schema.Databases {
+		required(path.Child("databases").Index(i).Child("name"), database.Name)
+	}
+	for i, table := range schema.Tables {
+		tablePath := path.Child("tables").Index(i)
+		required(tablePath.Child("name"), table.Name)
+		if table.SQL == "" {
+			required(tablePath.Child("engine"), table.Engine)
+			if len(table.Columns) == 0 {
+				errs = append(errs, field.Required(tablePath.Child("columns"), "either sql or columns have to be specified"))
+			}
+		}
+	}
+	for i, view := range schema.MaterializedViews {
+		viewPath := path.Child("materializedViews").Index(i)
+		required(viewPath.Child("name"), view.Name)
+		if view.SQL == "" {
+			required(viewPath.Child("to"), view.To)
+			required(viewPath.Child("query"), view.Query)
+		}
+	}
+	for i, dictionary := range schema.Dictionaries {
+		dictionaryPath := path.Child("dictionaries").Index(i)
+		required(dictionaryPath.Child("name"), dictionary.Name)
+		if dictionary.SQL == "" {
+			required(dictionaryPath.Child("primaryKey"), dictionary.PrimaryKey)
+			required(dictionaryPath.Child("source"), dictionary.Source)
+			required(dictionaryPath.Child("layout"), dictionary.Layout)
+			required(dictionaryPath.Child("lifetime"), dictionary.Lifetime)
+		}
+	}
+	return errs
+}
+
+// validateTLS checks certificates provisioning settings
+func validateTLS(path *field.Path, tls *api.TLS) (errs field.ErrorList) {
+	if tls == nil {
+		return nil
+	}
+	duration := func(path *field.Path, value *types.String) time.Duration {
+		if !value.HasValue() {
+			return 0
+		}
+		d, err := time.ParseDuration(value.Value())
+		if err != nil || d <= 0 {
+			errs = append(errs, field.Invalid(path, value.Value(), "positive duration, such as 8760h, is expected"))
+			return 0
+		}
+		return d
+	}
+	validity := duration(path.Child("validity"), tls.Validity)
+	renewBefore := duration(path.Child("renewBefore"), tls.RenewBefore)
+	if validity == 0 && renewBefore > 0 {
+		validity = api.TLSDefaultValidity
+	}
+	if renewBefore == 0 && validity > 0 {
+		renewBefore = api.TLSDefaultRenewBefore
+	}
+	if validity > 0 && renewBefore >= validity {
+		errs = append(errs, field.Invalid(path.Child("renewBefore"), renewBefore.String(), "has to be less than validity"))
+	}
+	errs = append(errs, validateEnum(path.Child("verificationMode"), tls.VerificationMode.Value(),
+		api.TLSVerificationModeNone,
+		api.TLSVerificationModeRelaxed,
+		api.TLSVerificationModeStrict,
+	)...)
+	return errs
+}
+
+// validateEnum checks value is one of allowed values. Empty value is allowed and means default
+func validateEnum(path *field.Path, value string, allowed ...string) field.ErrorList {
+	if value == "" {
+		return nil
+	}
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return field.ErrorList{field.NotSupported(path, value, allowed)}
+}
+
+// validateReconcileStatefulSet checks StatefulSet reconcile actions
+func validateReconcileStatefulSet(path *field.Path, sts *api.ReconcileStatefulSet) (errs field.ErrorList) {
+	errs = append(errs, validateEnum(path.Child("create", "onFailure"), sts.Create.OnFailure,
+		api.OnStatefulSetCreateFailureActionAbort,
+		api.OnStatefulSetCreateFailureActionDelete,
+		api.OnStatefulSetCreateFailureActionIgnore,
+	)...)
+	errs = append(errs, validateEnum(path.Child("update", "onFailure"), sts.Update.OnFailure,
+		api.OnStatefulSetUpdateFailureActionAbort,
+		api.OnStatefulSetUpdateFailureActionRollback,
+		api.OnStatefulSetUpdateFailureActionIgnore,
+	)...)
+	errs = append(errs, validateEnum(path.Child("recreate", "onDataLoss"), sts.Recreate.OnDataLoss,
+		api.OnStatefulSetRecreateOnDataLossActionAbort,
+		api.OnStatefulSetRecreateOnDataLossActionRecreate,
+	)...)
+	errs = append(errs, validateEnum(path.Child("recreate", "onUpdateFailure"), sts.Recreate.OnUpdateFailure,
+		api.OnStatefulSetRecreateOnUpdateFailureActionAbort,
+		api.OnStatefulSetRecreateOnUpdateFailureActionRecreate,
+	)...)
+	return errs
+}
+
+// validateReconcile checks CR-level reconcile settings
+func validateReconcile(path *field.Path, reconcile *api.ChiReconcile) (errs field.ErrorList) {
+	if reconcile == nil {
+		return nil
+	}
+	errs = append(errs, validateReconcileStatefulSet(path.Child("statefulSet"), &reconcile.StatefulSet)...)
+	for i, window := range reconcile.MaintenanceWindows {
+		errs = append(errs, validateMaintenanceWindow(path.Child("maintenanceWindows").Index(i), window)...)
+	}
+	return errs
+}
+
+// validateMaintenanceWindow checks schedule, duration and timezone of the maintenance window
+func validateMaintenanceWindow(path *field.Path, window *api.MaintenanceWindow) (errs field.ErrorList) {
+	if window == nil {
+		return nil
+	}
+	if _, err := window.GetSchedule(); err != nil {
+		errs = append(errs, field.Invalid(path.Child("schedule"), window.Schedule, err.Error()))
+	}
+	if duration, err := window.GetDuration(); (err != nil) || (duration <= 0) {
+		errs = append(errs, field.Invalid(path.Child("duration"), window.Duration, "positive duration, such as 4h, is expected"))
+	}
+	if _, err := window.GetLocation(); err != nil {
+		errs = append(errs, field.Invalid(path.Child("timezone"), window.Timezone, "IANA time zone name, such as Europe/Berlin, is expected"))
+	}
+	return errs
+}
+
+// validateClusterReconcile checks cluster-level reconcile settings
+func validateClusterReconcile(path *field.Path, reconcile *api.ClusterReconcile) (errs field.ErrorList) {
+	if reconcile == nil {
+		return nil
+	}
+	errs = append(errs, validateReconcileStatefulSet(path.Child("statefulSet"), &reconcile.StatefulSet)...)
+	if rebalance := reconcile.Rebalance; rebalance != nil {
+		errs = append(errs, validateEnum(path.Child("rebalance", "strategy"), rebalance.Strategy,
+			api.RebalanceStrategyBytes,
+			api.RebalanceStrategyRows,
+		)...)
+		errs = append(errs, validateEnum(path.Child("rebalance", "method"), rebalance.Method,
+			api.RebalanceMethodInsertSelect,
+			api.RebalanceMethodMovePartition,
+		)...)
+	}
+	if upgrade := reconcile.Upgrade; upgrade != nil {
+		errs = append(errs, validateEnum(path.Child("upgrade", "canary"), upgrade.Canary,
+			api.UpgradeCanaryHost,
+			api.UpgradeCanaryShard,
+		)...)
+		if percent := upgrade.GetWavePercent(); (percent < 1) || (percent > 100) {
+			errs = append(errs, field.Invalid(path.Child("upgrade", "wavePercent"), percent, "has to be in range 1..100"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,367 @@
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	apiChk "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	api "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+)
+
+// Fixtures are normalized the same way the operator normalizes manifests before reconcile.
+// Valid fixtures are expected to pass validation, CHITs among them are registered first, so CHIs can refer to them.
+// Invalid fixtures list expected errors as '# expect: <field path>' lines
+const (
+	validFixturesDir   = "testdata/valid"
+	invalidFixturesDir = "testdata/invalid"
+)
+
+func init() {
+	chop.New(nil, nil, "")
+	files, _ := filepath.Glob(filepath.Join(validFixturesDir, "chit-*.yaml"))
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		chit := &api.ClickHouseInstallationTemplate{}
+		if err := yaml.Unmarshal(data, chit); err == nil {
+			chop.Config().AddCHITemplate((*api.ClickHouseInstallation)(chit))
+		}
+	}
+}
+
+// manifestsDir contains manifests used by e2e tests, all of them are expected to be valid
+const manifestsDir = "../../tests/e2e/manifests"
+
+// manifestTemplates are CHITs referenced by CHIs of e2e tests
+var manifestTemplates = []string{
+	"tpl-clickhouse-stable.yaml",
+	"tpl-persistent-volume-100Mi.yaml",
+}
+
+func TestValidateManifests(t *testing.T) {
+	for _, file := range manifestTemplates {
+		data, err := os.ReadFile(filepath.Join(manifestsDir, "chit", file))
+		require.NoError(t, err)
+		chit := &api.ClickHouseInstallationTemplate{}
+		require.NoError(t, yaml.Unmarshal(data, chit))
+		chop.Config().AddCHITemplate((*api.ClickHouseInstallation)(chit))
+	}
+
+	for _, dir := range []string{"chi", "chit", "chk"} {
+		files, err := filepath.Glob(filepath.Join(manifestsDir, dir, "*.yaml"))
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+		for _, file := range files {
+			t.Run(dir+"/"+filepath.Base(file), func(t *testing.T) {
+				data, err := os.ReadFile(file)
+				require.NoError(t, err)
+				if strings.Contains(string(data), "PlaceHolder}") {
+					t.Skip("manifest is a template to be rendered by e2e test")
+				}
+				for _, doc := range strings.Split(string(data), "\n---") {
+					require.Empty(t, validateManifest(t, doc))
+				}
+			})
+		}
+	}
+}
+
+func TestValidateValidFixtures(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(validFixturesDir, "*.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+			require.Empty(t, validateManifest(t, string(data)))
+		})
+	}
+}
+
+func TestValidateInvalidFixtures(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(invalidFixturesDir, "*.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+			var fields []string
+			for _, line := range strings.Split(string(data), "\n") {
+				if field, found := strings.CutPrefix(line, "# expect: "); found {
+					fields = append(fields, field)
+				}
+			}
+			require.NotEmpty(t, fields)
+			require.Equal(t, fields, validateManifest(t, string(data)))
+		})
+	}
+}
+
+// validateManifest validates manifest of any of the kinds served
+func validateManifest(t *testing.T, manifest string) []string {
+	var meta struct {
+		Kind string `json:"kind"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(manifest), &meta))
+
+	var errs []string
+	switch meta.Kind {
+	case kindCHI:
+		chi := &api.ClickHouseInstallation{}
+		require.NoError(t, yaml.Unmarshal([]byte(manifest), chi))
+		for _, err := range ValidateCHI(chi) {
+			errs = append(errs, err.Field)
+		}
+	case kindCHIT:
+		chit := &api.ClickHouseInstallationTemplate{}
+		require.NoError(t, yaml.Unmarshal([]byte(manifest), chit))
+		for _, err := range ValidateCHIT(chit) {
+			errs = append(errs, err.Field)
+		}
+	case kindCHK:
+		chk := &apiChk.ClickHouseKeeperInstallation{}
+		require.NoError(t, yaml.Unmarshal([]byte(manifest), chk))
+		for _, err := range ValidateCHK(chk) {
+			errs = append(errs, err.Field)
+		}
+	}
+	return errs
+}
+
+func TestValidateInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		fields   []string
+	}{
+		{
+			name: "incomplete schema",
+			manifest: `
+apiVersion: clickhouse.altinity.com/v1
+kind: ClickHouseInstallation
+metadata:
+  name: incomplete-schema
+spec:
+  configuration:
+    schema:
+      tables:
+        - name: events
+          columns:
+            - name: id
+              type: UInt64
+      materializedViews:
+        - sql: CREATE MATERIALIZED VIEW mv TO events AS SELECT 1 AS id
+`,
+			fields: []string{
+				"spec.configuration.schema.tables[0].engine",
+				"spec.configuration.schema.materializedViews[0].name",
+			},
+		},
+		{
+			name: "malformed tls",
+			manifest: `
+apiVersion: clickhouse.altinity.com/v1
+kind: ClickHouseInstallation
+metadata:
+  name: malformed-tls
+spec:
+  configuration:
+    tls:
+      enabled: "yes"
+      validity: 720h
+      renewBefore: 1000h
+      verificationMode: paranoid
+`,
+			fields: []string{
+				"spec.configuration.tls.renewBefore",
+				"spec.configuration.tls.verificationMode",
+			},
+		},
+		{
+			name: "malformed autoscaling",
+			manifest: `
+apiVersion: clickhouse.altinity.com/v1
+kind: ClickHouseInstallation
+metadata:
+  name: malformed-autoscaling
+spec:
+  configuration:
+    clusters:
+      - name: c1
+        autoscaling:
+          enabled: "yes"
+          minReplicas: 3
+          maxReplicas: 2
+          cooldown: soon
+          metrics:
+            - type: custom
+              target: "10"
+            - type: cpu
+              target: high
+`,
+			fields: []string{
+				"spec.configuration.clusters[0].autoscaling.maxReplicas",
+				"spec.configuration.clusters[0].autoscaling.cooldown",
+				"spec.configuration.clusters[0].autoscaling.metrics[0].name",
+				"spec.configuration.clusters[0].autoscaling.metrics[1].target",
+			},
+		},
+		{
+			name: "malformed resources recommender",
+			manifest: `
+apiVersion: clickhouse.altinity.com/v1
+kind: ClickHouseInstallation
+metadata:
+  name: malformed-recommender
+spec:
+  configuration:
+    clusters:
+      - name: c1
+        resourcesRecommender:
+          enabled: "yes"
+          mode: auto
+          window: -1h
+          headroom: -5
+`,
+			fields: []string{
+				"spec.configuration.clusters[0].resourcesRecommender.mode",
+				"spec.configuration.clusters[0].resourcesRecommender.window",
+				"spec.configuration.clusters[0].resourcesRecommender.headroom",
+			},
+		},
+		{
+			name: "malformed maintenance windows",
+			manifest: `
+apiVersion: clickhouse.altinity.com/v1
+kind: ClickHouseInstallation
+metadata:
+  name: malformed-maintenance-windows
+spec:
+  reconcile:
+    maintenanceWindows:
+      - schedule: "0 2 * * 6"
+        duration: 4h
+        timezone: Europe/Berlin
+      - schedule: "0 25 * * *"
+        duration: 0s
+        timezone: Mars/Olympus
+`,
+			fields: []string{
+				"spec.reconcile.maintenanceWindows[1].schedule",
+				"spec.reconcile.maintenanceWindows[1].duration",
+				"spec.reconcile.maintenanceWindows[1].timezone",
+			},
+		},
+		{
+			name: "malformed staged upgrade",
+			manifest: `
+apiVersion: clickhouse.altinity.com/v1
+kind: ClickHouseInstallation
+metadata:
+  name: malformed-staged-upgrade
+spec:
+  configuration:
+    clusters:
+      - name: default
+        reconcile:
+          upgrade:
+            enabled: "true"
+            canary: replica
+            wavePercent: 150
+`,
+			fields: []string{
+				"spec.configuration.clusters[0].reconcile.upgrade.canary",
+				"spec.configuration.clusters[0].reconcile.upgrade.wavePercent",
+			},
+		},
+		{
+			name: "malformed clone",
+			manifest: `
+apiVersion: clickhouse.altinity.com/v1
+kind: ClickHouseInstallation
+metadata:
+  name: malformed-clone
+spec:
+  clone:
+    source:
+      name: malformed-clone
+    data: backup
+`,
+			fields: []string{
+				"spec.clone.source.name",
+				"spec.clone.data",
+				"spec.clone.user",
+			},
+		},
+		{
+			name: "malformed disaster recovery",
+			manifest: `
+apiVersion: clickhouse.altinity.com/v1
+kind: ClickHouseInstallation
+metadata:
+  name: malformed-dr
+spec:
+  disasterRecovery:
+    primary:
+      name: malformed-dr
+`,
+			fields: []string{
+				"spec.disasterRecovery.primary.name",
+			},
+		},
+		{
+			name: "malformed kube contexts",
+			manifest: `
+apiVersion: clickhouse.altinity.com/v1
+kind: ClickHouseInstallation
+metadata:
+  name: malformed-kube-contexts
+spec:
+  kubeContexts:
+    - name: remote
+      kubeconfig:
+        secretKeyRef:
+          name: remote-kubeconfig
+          key: kubeconfig
+    - name: remote
+`,
+			fields: []string{
+				"spec.kubeContexts[1].name",
+				"spec.kubeContexts[1].kubeconfig.secretKeyRef",
+			},
+		},
+		{
+			name: "unknown kube context",
+			manifest: `
+apiVersion: clickhouse.altinity.com/v1
+kind: ClickHouseInstallation
+metadata:
+  name: unknown-kube-context
+spec:
+  configuration:
+    clusters:
+      - name: c1
+        layout:
+          replicas:
+            - name: r0
+            - name: r1
+              kubeContext: remote
+`,
+			fields: []string{
+				"spec.configuration.clusters[0].layout.replicas[1].kubeContext",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.fields, validateManifest(t, tt.manifest))
+		})
+	}
+}